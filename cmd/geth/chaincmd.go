@@ -42,6 +42,19 @@ import (
 )
 
 var (
+	mpsdbUpgradeSplitFlag = cli.BoolFlag{
+		Name:  "split",
+		Usage: "Build one private state per resident group by replaying the private transactions from genesis",
+	}
+	privateStatePSIFlag = cli.StringFlag{
+		Name:  "psi",
+		Usage: "Identifier of the private state",
+	}
+	privateStateBlockFlag = cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Number of the block to export the private state at (default = current head)",
+	}
+
 	initCommand = cli.Command{
 		Action:    utils.MigrateFlags(initGenesis),
 		Name:      "init",
@@ -114,11 +127,19 @@ processing will proceed even if an individual RLP-file import failure occurs.`,
 		ArgsUsage: "",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			mpsdbUpgradeSplitFlag,
 		},
 		Description: `
 Checks if the chain config isMPS parameter value.
 If false, it upgrades the DB to be MPS enabled (builds the trie of private states) and if successful sets isMPS to true.
-If true, exits displaying an error message that the DB is already MPS.`,
+If true, exits displaying an error message that the DB is already MPS.
+
+By default the existing private state becomes the "private" private state.
+With --split, the chain is replayed from genesis to build one private state per resident group
+of the private transaction manager, which must be configured and reachable. Every block is
+executed again to verify the resulting private state roots, and the accounts touched by its
+private transactions are compared with the single private state. The receipts are only replaced
+and isMPS set to true once the whole chain has been verified.`,
 		Category: "BLOCKCHAIN COMMANDS",
 	}
 	exportCommand = cli.Command{
@@ -138,6 +159,28 @@ Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
 if already existing. If the file ends with .gz, the output will
 be gzipped.`,
+	}
+	checkPrivatePayloadsCommand = cli.Command{
+		Action:    utils.MigrateFlags(checkPrivatePayloads),
+		Name:      "checkprivatepayloads",
+		Usage:     "Find private transactions whose payload is missing from the private transaction manager",
+		ArgsUsage: "<blockNumFirst> [<blockNumLast>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			utils.QuorumPTMUnixSocketFlag,
+			utils.QuorumPTMUrlFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+Walks the blocks from the first to the last block number (defaults to the current head)
+and checks every private transaction and privacy marker transaction against the private
+transaction manager. A payload is reported as missing when the private transaction manager
+cannot return it although the node is the sender, one of its keys is a participant or the
+transaction produced logs when it was executed.
+
+The report of the affected transactions, blocks and contracts is written to stdout as JSON.`,
 	}
 	importPreimagesCommand = cli.Command{
 		Action:    utils.MigrateFlags(importPreimages),
@@ -166,6 +209,55 @@ be gzipped.`,
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 The export-preimages command export hash preimages to an RLP encoded stream`,
+	}
+	exportPrivateStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportPrivateState),
+		Name:      "export-private-state",
+		Usage:     "Export a single private state of an MPS database into an RLP stream",
+		ArgsUsage: "<dumpfile>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			privateStatePSIFlag,
+			privateStateBlockFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-private-state command exports the private state identified by --psi, as of
+the given block, including the account extra data (privacy metadata and managed parties),
+so that a tenant can be moved to another node running multiple private states.
+If the file ends with .gz, the output will be gzipped.`,
+	}
+	importPrivateStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(importPrivateState),
+		Name:      "import-private-state",
+		Usage:     "Import a private state exported by export-private-state",
+		ArgsUsage: "<dumpfile>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The import-private-state command verifies the exported private state against its root
+and grafts it into the trie of private states of the block the state was exported at,
+which must be a canonical block of the node. The private state must be the one of a
+resident group of the private transaction manager of the node, so register the resident
+group first and import the state while the node is stopped. If the state was exported at
+an older block, rewind the chain to that block so that the state is carried forward.`,
+	}
+	privateStateDiffCommand = cli.Command{
+		Action:    utils.MigrateFlags(privateStateDiff),
+		Name:      "private-state-diff",
+		Usage:     "Report the changes of a private state between two blocks",
+		ArgsUsage: "<fromBlockNum> <toBlockNum>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			privateStatePSIFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The private-state-diff command prints, as JSON, the accounts of the private state identified
+by --psi (default = private) which changed between the two blocks: balance, nonce, code hash,
+storage slots and privacy metadata, along with the private transactions which touched them.`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -371,8 +463,11 @@ func mpsdbUpgrade(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
 
-	// initialise the tx manager with the dummy tx mgr
-	private.P = &notinuse.DBUpgradePrivateTransactionManager{}
+	split := ctx.Bool(mpsdbUpgradeSplitFlag.Name)
+	if !split {
+		// initialise the tx manager with the dummy tx mgr
+		private.P = &notinuse.DBUpgradePrivateTransactionManager{}
+	}
 
 	chain, db := utils.MakeChain(ctx, stack, true)
 
@@ -383,6 +478,10 @@ func mpsdbUpgrade(ctx *cli.Context) error {
 	currentBlockNumber := chain.CurrentBlock().Number().Int64()
 	fmt.Printf("Current block number %v\n", currentBlockNumber)
 
+	if split {
+		defer chain.Stop()
+		return core.SplitPrivateState(chain)
+	}
 	return mps.UpgradeDB(db, chain)
 }
 
@@ -424,6 +523,44 @@ func exportChain(ctx *cli.Context) error {
 	return nil
 }
 
+// Quorum
+// checkPrivatePayloads reports the private transactions of a range of blocks whose payload
+// the private transaction manager should have but is not able to return.
+func checkPrivatePayloads(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, _ := utils.MakeChain(ctx, stack, true)
+	defer chain.Stop()
+
+	first, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid first block number: %v", err)
+	}
+	last := chain.CurrentBlock().NumberU64()
+	if len(ctx.Args()) > 1 {
+		if last, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			utils.Fatalf("Invalid last block number: %v", err)
+		}
+	}
+	start := time.Now()
+	report, err := private.FindMissingPayloads(chain, private.P, first, last)
+	if err != nil {
+		utils.Fatalf("Check error: %v", err)
+	}
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	log.Info("Private payloads checked", "transactions", report.PrivateTransactions, "missing", len(report.Missing), "failed", len(report.Failed), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 // importPreimages imports preimage data from the specified file.
 func importPreimages(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
@@ -462,6 +599,113 @@ func exportPreimages(ctx *cli.Context) error {
 	return nil
 }
 
+// Quorum
+func exportPrivateState(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	if !ctx.IsSet(privateStatePSIFlag.Name) {
+		utils.Fatalf("The --%s flag is required.", privateStatePSIFlag.Name)
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	if config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0)); config == nil || !config.IsMPS {
+		utils.Fatalf("The database does not support multiple private states.")
+	}
+	block := rawdb.ReadHeadBlock(db)
+	if ctx.IsSet(privateStateBlockFlag.Name) {
+		number := ctx.Uint64(privateStateBlockFlag.Name)
+		block = rawdb.ReadBlock(db, rawdb.ReadCanonicalHash(db, number), number)
+	}
+	if block == nil {
+		utils.Fatalf("Block not found.")
+	}
+	start := time.Now()
+
+	psi := types.ToPrivateStateIdentifier(ctx.String(privateStatePSIFlag.Name))
+	if err := utils.ExportPrivateState(db, block, psi, ctx.Args().First()); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+// Quorum
+func importPrivateState(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	if config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0)); config == nil || !config.IsMPS {
+		utils.Fatalf("The database does not support multiple private states.")
+	}
+	residentPSIs, err := core.ResidentPrivateStateIdentifiers()
+	if err != nil {
+		utils.Fatalf("Unable to retrieve the resident groups: %v", err)
+	}
+	start := time.Now()
+
+	if err := utils.ImportPrivateState(db, residentPSIs, ctx.Args().First()); err != nil {
+		utils.Fatalf("Import error: %v\n", err)
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
+// Quorum
+func privateStateDiff(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires two arguments.")
+	}
+	fromNumber, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid from block number: %v", err)
+	}
+	toNumber, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid to block number: %v", err)
+	}
+	psi := types.DefaultPrivateStateIdentifier
+	if ctx.IsSet(privateStatePSIFlag.Name) {
+		psi = types.ToPrivateStateIdentifier(ctx.String(privateStatePSIFlag.Name))
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	fromBlock := rawdb.ReadBlock(db, rawdb.ReadCanonicalHash(db, fromNumber), fromNumber)
+	toBlock := rawdb.ReadBlock(db, rawdb.ReadCanonicalHash(db, toNumber), toNumber)
+	if fromBlock == nil || toBlock == nil {
+		utils.Fatalf("Block not found.")
+	}
+	from, err := mps.OpenPrivateState(db, fromBlock, psi)
+	if err != nil {
+		utils.Fatalf("Could not open the private state: %v", err)
+	}
+	to, err := mps.OpenPrivateState(db, toBlock, psi)
+	if err != nil {
+		utils.Fatalf("Could not open the private state: %v", err)
+	}
+	diff, err := mps.DiffPrivateState(db, psi, fromBlock, toBlock, from, to)
+	if err != nil {
+		utils.Fatalf("Diff error: %v", err)
+	}
+	out, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
 func dump(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"os/signal"
 	"reflect"
 	"strings"
+	"syscall"
 	"unicode"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
@@ -409,6 +411,20 @@ func quorumInitialisePrivacy(ctx *cli.Context) error {
 	return nil
 }
 
+// reloadPrivateTransactionManagerTLSOnSignal reloads the TLS material of the connection to the
+// private transaction manager whenever the process receives SIGHUP
+func reloadPrivateTransactionManagerTLSOnSignal() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+	for range sighup {
+		log.Info("Got SIGHUP, reloading TLS material for private transaction manager")
+		if err := private.ReloadTLS(); err != nil {
+			log.Error("Failed to reload TLS material for private transaction manager", "err", err)
+		}
+	}
+}
+
 // Get private transaction manager configuration
 func QuorumSetupPrivacyConfiguration(ctx *cli.Context) (http.Config, error) {
 	// get default configuration
@@ -454,6 +470,18 @@ func QuorumSetupPrivacyConfiguration(ctx *cli.Context) (http.Config, error) {
 	if ctx.GlobalIsSet(utils.QuorumPTMTlsInsecureSkipVerify.Name) {
 		cfg.SetTlsInsecureSkipVerify(ctx.Bool(utils.QuorumPTMTlsInsecureSkipVerify.Name))
 	}
+	if ctx.GlobalIsSet(utils.QuorumPTMTlsReloadIntervalFlag.Name) {
+		cfg.SetTlsReloadInterval(ctx.GlobalUint(utils.QuorumPTMTlsReloadIntervalFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.QuorumPTMHealthCheckIntervalFlag.Name) {
+		cfg.SetHealthCheckInterval(ctx.GlobalUint(utils.QuorumPTMHealthCheckIntervalFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.QuorumPTMCircuitBreakerThresholdFlag.Name) {
+		cfg.SetCircuitBreakerThreshold(ctx.GlobalUint(utils.QuorumPTMCircuitBreakerThresholdFlag.Name))
+	}
+	if ctx.GlobalIsSet(utils.QuorumPTMCircuitBreakerCooldownFlag.Name) {
+		cfg.SetCircuitBreakerCooldown(ctx.GlobalUint(utils.QuorumPTMCircuitBreakerCooldownFlag.Name))
+	}
 
 	if err = cfg.Validate(); err != nil {
 		return cfg, err
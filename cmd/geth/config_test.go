@@ -184,6 +184,19 @@ func TestFlagsConfig(t *testing.T) {
 		utils.IstanbulRequestTimeoutFlag,
 		utils.IstanbulBlockPeriodFlag,
 		utils.MultitenancyFlag,
+		utils.RPCAuditHTTPFlag,
+		utils.RPCAuditWSFlag,
+		utils.RPCAuditIPCFlag,
+		utils.RPCAuditLogFlag,
+		utils.RPCAuditLogMaxSizeFlag,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitKeyFlag,
+		utils.RPCJWTJWKSFlag,
+		utils.RPCJWTSecretFlag,
+		utils.RPCJWTIssuerFlag,
+		utils.RPCJWTAudienceFlag,
+		utils.RPCJWTAuthoritiesClaimFlag,
+		utils.RPCJWTClockSkewFlag,
 		utils.RevertReasonFlag,
 		utils.QuorumEnablePrivacyMarker,
 		utils.QuorumPTMUnixSocketFlag,
@@ -198,6 +211,15 @@ func TestFlagsConfig(t *testing.T) {
 		utils.QuorumPTMTlsClientCertFlag,
 		utils.QuorumPTMTlsClientKeyFlag,
 		utils.QuorumPTMTlsInsecureSkipVerify,
+		utils.QuorumPTMTlsReloadIntervalFlag,
+		utils.QuorumPTMHealthCheckIntervalFlag,
+		utils.QuorumPTMCircuitBreakerThresholdFlag,
+		utils.QuorumPTMCircuitBreakerCooldownFlag,
+		utils.QuorumPTMPersistentCacheFlag,
+		utils.QuorumPTMPersistentCacheTTLFlag,
+		utils.QuorumPTMPersistentCacheMaxSizeFlag,
+		utils.QuorumPTMRefreshIntervalFlag,
+		utils.QuorumPTMGroupsRefreshIntervalFlag,
 	}
 	nodeKeyFile, err := ioutil.TempFile("/tmp", "nodekey")
 	require.NoError(t, err)
@@ -42,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/permission"
 	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/ethereum/go-ethereum/private"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -174,6 +175,19 @@ var (
 		utils.AllowedFutureBlockTimeFlag,
 		utils.EVMCallTimeOutFlag,
 		utils.MultitenancyFlag,
+		utils.RPCAuditHTTPFlag,
+		utils.RPCAuditWSFlag,
+		utils.RPCAuditIPCFlag,
+		utils.RPCAuditLogFlag,
+		utils.RPCAuditLogMaxSizeFlag,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitKeyFlag,
+		utils.RPCJWTJWKSFlag,
+		utils.RPCJWTSecretFlag,
+		utils.RPCJWTIssuerFlag,
+		utils.RPCJWTAudienceFlag,
+		utils.RPCJWTAuthoritiesClaimFlag,
+		utils.RPCJWTClockSkewFlag,
 		utils.RevertReasonFlag,
 		utils.QuorumEnablePrivateTrieCache,
 		utils.QuorumEnablePrivacyMarker,
@@ -189,6 +203,15 @@ var (
 		utils.QuorumPTMTlsClientCertFlag,
 		utils.QuorumPTMTlsClientKeyFlag,
 		utils.QuorumPTMTlsInsecureSkipVerify,
+		utils.QuorumPTMTlsReloadIntervalFlag,
+		utils.QuorumPTMHealthCheckIntervalFlag,
+		utils.QuorumPTMCircuitBreakerThresholdFlag,
+		utils.QuorumPTMCircuitBreakerCooldownFlag,
+		utils.QuorumPTMPersistentCacheFlag,
+		utils.QuorumPTMPersistentCacheTTLFlag,
+		utils.QuorumPTMPersistentCacheMaxSizeFlag,
+		utils.QuorumPTMRefreshIntervalFlag,
+		utils.QuorumPTMGroupsRefreshIntervalFlag,
 		utils.QuorumLightServerFlag,
 		utils.QuorumLightServerP2PListenPortFlag,
 		utils.QuorumLightServerP2PMaxPeersFlag,
@@ -272,8 +295,12 @@ func init() {
 		mpsdbUpgradeCommand,
 		importCommand,
 		exportCommand,
+		checkPrivatePayloadsCommand,
 		importPreimagesCommand,
 		exportPreimagesCommand,
+		exportPrivateStateCommand,
+		importPrivateStateCommand,
+		privateStateDiffCommand,
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
@@ -298,6 +325,10 @@ func init() {
 		utils.ShowDeprecated,
 		// See snapshot.go
 		snapshotCommand,
+		// See privatetxauditcmd.go
+		privateTxAuditCommand,
+		// See permissioncmd.go
+		permissionCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -424,8 +455,11 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend) {
 	ethClient := ethclient.NewClient(rpcClient)
 
 	// Quorum
-	if ctx.GlobalBool(utils.MultitenancyFlag.Name) && !stack.PluginManager().IsEnabled(plugin.SecurityPluginInterfaceName) {
-		utils.Fatalf("multitenancy requires RPC Security Plugin to be configured")
+	if ctx.GlobalBool(utils.MultitenancyFlag.Name) && !stack.IsAuthenticationEnabled() {
+		utils.Fatalf("multitenancy requires RPC Security Plugin or the built-in JWT authentication to be configured")
+	}
+	if private.IsQuorumPrivacyEnabled() {
+		go reloadPrivateTransactionManagerTLSOnSignal()
 	}
 	// End Quorum
 
@@ -0,0 +1,342 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/permission"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	PermissionEndpointFlag = cli.StringFlag{
+		Name:  "endpoint",
+		Usage: "Endpoint of the node to query (defaults to the IPC endpoint in the data directory)",
+	}
+	PermissionBlockFlag = cli.StringFlag{
+		Name:  "block",
+		Usage: "Compare with the permission contracts at this block number, 'latest' or 'pending' instead of the node caches",
+	}
+	PermissionFromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Unlocked account of the node submitting the permission transactions",
+	}
+	PermissionJSONFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result as JSON",
+	}
+	MigrationBlockFlag = cli.StringFlag{
+		Name:  "block",
+		Usage: "Block number or 'latest' to read the v1 permissions at (default: latest)",
+	}
+	MigrationDeployerFlag = cli.StringFlag{
+		Name:  "deployer",
+		Usage: "Account deploying and booting the v2 permission contracts",
+	}
+	MigrationNonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "Nonce of the first deployment transaction (defaults to the pending nonce of the deployer)",
+	}
+	MigrationGuardianFlag = cli.StringFlag{
+		Name:  "guardian",
+		Usage: "Guardian account of the v2 upgradable contract (defaults to the deployer)",
+	}
+	permissionCommand = cli.Command{
+		Name:     "permission",
+		Usage:    "Reconcile the permission model with a declarative file (connect to node)",
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The permission commands compare the orgs, sub orgs, roles, accounts and nodes declared in a
+JSON file with the permission model of a running node:
+
+{
+  "orgs": [{
+    "orgId": "ORG1",
+    "roles": [{"roleId": "TRADER", "access": 1, "isVoter": false, "isAdmin": false}],
+    "accounts": [{"account": "0x...", "roleId": "ORGADMIN"}, {"account": "0x...", "roleId": "TRADER"}],
+    "nodes": ["enode://...@10.0.0.1:21000?discport=0&raftport=50401"],
+    "subOrgs": [{"orgId": "DESK1", "nodes": ["enode://..."]}]
+  }]
+}
+
+Only the declared orgs are reconciled. A new top level org is proposed with its first node and
+its first account having the org admin role.`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(planPermissions),
+				Name:      "plan",
+				Usage:     "Print the permission transactions reconciling the permission model with a file",
+				ArgsUsage: "<specFile>",
+				Flags:     append([]cli.Flag{utils.DataDirFlag, PermissionEndpointFlag, PermissionBlockFlag, PermissionJSONFlag}, rpcClientFlags...),
+				Category:  "MISCELLANEOUS COMMANDS",
+				Description: `
+The plan command prints in order the permission transactions to submit, the account they must
+be signed by, whether they need the votes of the network admins and the steps they wait for.
+The differences which cannot be reconciled with a transaction are reported as warnings.`,
+			},
+			{
+				Action:    utils.MigrateFlags(applyPermissions),
+				Name:      "apply",
+				Usage:     "Submit the permission transactions reconciling the permission model with a file",
+				ArgsUsage: "<specFile>",
+				Flags:     append([]cli.Flag{utils.DataDirFlag, PermissionEndpointFlag, PermissionFromFlag, PermissionJSONFlag}, rpcClientFlags...),
+				Category:  "MISCELLANEOUS COMMANDS",
+				Description: `
+The apply command submits from the --from account the permission transactions which do not
+wait for other steps. Apply the file again, possibly from other admin accounts, once those
+transactions are mined and approved to submit the next steps.`,
+			},
+			{
+				Name:     "migrate",
+				Usage:    "Migrate the v1 permission model to the v2 model",
+				Category: "MISCELLANEOUS COMMANDS",
+				Description: `
+The migrate commands move a network from the v1 permission model to the v2 model:
+
+ 1. plan prints the transactions deploying the v2 contracts and populating them with the v1
+    orgs, sub orgs, roles, accounts and nodes at a block;
+ 2. once they are mined, set the printed contract addresses and a future switch block as
+    "migration" in permission-config.json of every node and restart them;
+ 3. verify compares the v1 permissions with the v2 contracts, and with the v2 caches once the
+    nodes switched to the v2 model at the migration block.`,
+				Subcommands: []cli.Command{
+					{
+						Action:   utils.MigrateFlags(planMigration),
+						Name:     "plan",
+						Usage:    "Print the transactions migrating the v1 permissions to the v2 contracts",
+						Flags:    append([]cli.Flag{utils.DataDirFlag, PermissionEndpointFlag, MigrationBlockFlag, MigrationDeployerFlag, MigrationNonceFlag, MigrationGuardianFlag, PermissionJSONFlag}, rpcClientFlags...),
+						Category: "MISCELLANEOUS COMMANDS",
+						Description: `
+The plan command prints in order the transactions to submit, the account they must be signed by
+and whether they need the votes of the network admins. Each transaction must be mined before the
+next one is sent. The v1 permissions which cannot be migrated as is are reported as warnings.`,
+					},
+					{
+						Action:   utils.MigrateFlags(verifyMigration),
+						Name:     "verify",
+						Usage:    "Compare the v1 permissions with the v2 permissions they were migrated to",
+						Flags:    append([]cli.Flag{utils.DataDirFlag, PermissionEndpointFlag, MigrationBlockFlag, PermissionJSONFlag}, rpcClientFlags...),
+						Category: "MISCELLANEOUS COMMANDS",
+						Description: `
+The verify command requires the migration to be configured in permission-config.json of the
+node. It exits with an error if the v1 and v2 permissions differ.`,
+					},
+				},
+			},
+		},
+	}
+)
+
+// readPermissionSpec decodes the declarative file given as argument
+func readPermissionSpec(ctx *cli.Context) *permission.PermissionSpec {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires a file argument.")
+	}
+	f, err := os.Open(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Unable to open the permission file: %v", err)
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	spec := new(permission.PermissionSpec)
+	if err := decoder.Decode(spec); err != nil {
+		utils.Fatalf("Invalid permission file: %v", err)
+	}
+	return spec
+}
+
+func dialPermissionEndpoint(ctx *cli.Context) *rpc.Client {
+	endpoint := ctx.String(PermissionEndpointFlag.Name)
+	if endpoint == "" {
+		path := node.DefaultDataDir()
+		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+			path = ctx.GlobalString(utils.DataDirFlag.Name)
+		}
+		endpoint = filepath.Join(path, "geth.ipc")
+	}
+	client, err := dialRPC(endpoint, ctx)
+	if err != nil {
+		utils.Fatalf("Unable to attach to remote geth: %v", err)
+	}
+	return client
+}
+
+func planPermissions(ctx *cli.Context) error {
+	spec := readPermissionSpec(ctx)
+	var blockNumber interface{}
+	switch block := ctx.String(PermissionBlockFlag.Name); block {
+	case "":
+	case "latest", "pending":
+		blockNumber = block
+	default:
+		number, err := strconv.ParseUint(block, 10, 64)
+		if err != nil {
+			utils.Fatalf("Invalid block number: %v", err)
+		}
+		blockNumber = hexutil.Uint64(number)
+	}
+	client := dialPermissionEndpoint(ctx)
+	defer client.Close()
+
+	var plan permission.PermissionPlan
+	if err := client.Call(&plan, "quorumPermission_planPermissions", spec, blockNumber); err != nil {
+		return fmt.Errorf("unable to plan the permission transactions: %v", err)
+	}
+	if ctx.Bool(PermissionJSONFlag.Name) {
+		return printPermissionJSON(&plan)
+	}
+	printPermissionPlan(&plan, nil)
+	return nil
+}
+
+func applyPermissions(ctx *cli.Context) error {
+	spec := readPermissionSpec(ctx)
+	from := ctx.String(PermissionFromFlag.Name)
+	if !common.IsHexAddress(from) {
+		utils.Fatalf("A valid --%s account is required", PermissionFromFlag.Name)
+	}
+	client := dialPermissionEndpoint(ctx)
+	defer client.Close()
+
+	var result permission.PermissionApplyResult
+	if err := client.Call(&result, "quorumPermission_applyPermissions", spec, ethapi.SendTxArgs{From: common.HexToAddress(from)}); err != nil {
+		return fmt.Errorf("unable to apply the permission transactions: %v", err)
+	}
+	if ctx.Bool(PermissionJSONFlag.Name) {
+		return printPermissionJSON(&result)
+	}
+	printPermissionPlan(result.Plan, result.Results)
+	return nil
+}
+
+// migrationBlockNumber returns the block given by --block, nil for the latest block
+func migrationBlockNumber(ctx *cli.Context) *rpc.BlockNumber {
+	var blockNumber rpc.BlockNumber
+	switch block := ctx.String(MigrationBlockFlag.Name); block {
+	case "", "latest":
+		return nil
+	default:
+		number, err := strconv.ParseUint(block, 10, 64)
+		if err != nil {
+			utils.Fatalf("Invalid block number: %v", err)
+		}
+		blockNumber = rpc.BlockNumber(number)
+	}
+	return &blockNumber
+}
+
+func planMigration(ctx *cli.Context) error {
+	deployer := ctx.String(MigrationDeployerFlag.Name)
+	if !common.IsHexAddress(deployer) {
+		utils.Fatalf("A valid --%s account is required", MigrationDeployerFlag.Name)
+	}
+	args := permission.MigrationArgs{Block: migrationBlockNumber(ctx), Deployer: common.HexToAddress(deployer)}
+	if ctx.IsSet(MigrationNonceFlag.Name) {
+		nonce := hexutil.Uint64(ctx.Uint64(MigrationNonceFlag.Name))
+		args.Nonce = &nonce
+	}
+	if guardian := ctx.String(MigrationGuardianFlag.Name); guardian != "" {
+		if !common.IsHexAddress(guardian) {
+			utils.Fatalf("Invalid --%s account: %s", MigrationGuardianFlag.Name, guardian)
+		}
+		address := common.HexToAddress(guardian)
+		args.Guardian = &address
+	}
+	client := dialPermissionEndpoint(ctx)
+	defer client.Close()
+
+	var plan permission.MigrationPlan
+	if err := client.Call(&plan, "quorumPermission_migrationPlan", args); err != nil {
+		return fmt.Errorf("unable to plan the migration: %v", err)
+	}
+	if ctx.Bool(PermissionJSONFlag.Name) {
+		return printPermissionJSON(&plan)
+	}
+	fmt.Printf("Migration of the v1 permissions at block %d\n", plan.Block)
+	for i, step := range plan.Steps {
+		fmt.Printf("%3d  %s\n", i, step)
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Printf("WARNING: %s\n", warning)
+	}
+	fmt.Println("Migration contracts for permission-config.json, set the switch block with a v2PermissionMigrated transition of the genesis config:")
+	return printPermissionJSON(plan.Contracts)
+}
+
+func verifyMigration(ctx *cli.Context) error {
+	client := dialPermissionEndpoint(ctx)
+	defer client.Close()
+
+	var result permission.MigrationVerification
+	if err := client.Call(&result, "quorumPermission_verifyMigration", migrationBlockNumber(ctx)); err != nil {
+		return fmt.Errorf("unable to verify the migration: %v", err)
+	}
+	if ctx.Bool(PermissionJSONFlag.Name) {
+		if err := printPermissionJSON(&result); err != nil {
+			return err
+		}
+	} else {
+		for _, difference := range result.Differences {
+			fmt.Println(difference)
+		}
+	}
+	if !result.Matches {
+		return fmt.Errorf("the %s differ from the v1 permissions at block %d", result.Source, result.Block)
+	}
+	if !ctx.Bool(PermissionJSONFlag.Name) {
+		fmt.Printf("The %s match the v1 permissions at block %d.\n", result.Source, result.Block)
+	}
+	return nil
+}
+
+func printPermissionJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func printPermissionPlan(plan *permission.PermissionPlan, results []*permission.PermissionStepResult) {
+	if len(plan.Steps) == 0 {
+		fmt.Println("The permission model matches the file.")
+	}
+	for i, step := range plan.Steps {
+		if i < len(results) {
+			status := results[i].Status
+			if results[i].Error != "" {
+				status += ": " + results[i].Error
+			}
+			fmt.Printf("%3d  %s\n     %s\n", i, step, status)
+			continue
+		}
+		fmt.Printf("%3d  %s\n", i, step)
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Printf("WARNING: %s\n", warning)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/node"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	PrivateTxAuditAddressFlag = cli.StringFlag{
+		Name:  "address",
+		Usage: "Only report the private transactions which called, created or emitted logs from this contract",
+	}
+	PrivateTxAuditEndpointFlag = cli.StringFlag{
+		Name:  "endpoint",
+		Usage: "Endpoint of the node to query (defaults to the IPC endpoint in the data directory)",
+	}
+	privateTxAuditCommand = cli.Command{
+		Action:    utils.MigrateFlags(privateTxAudit),
+		Name:      "privatetxaudit",
+		Usage:     "Report the private transactions of a range of blocks (connect to node)",
+		ArgsUsage: "<blockNumFirst> [<blockNumLast>]",
+		Flags:     append([]cli.Flag{utils.DataDirFlag, PrivateTxAuditAddressFlag, PrivateTxAuditEndpointFlag}, rpcClientFlags...),
+		Category:  "MISCELLANEOUS COMMANDS",
+		Description: `
+The privatetxaudit command connects to a running node and writes one JSON object per line
+for each private transaction and privacy marker transaction from the first to the last
+block (defaults to the current head) the caller is party to. Each entry contains the privacy
+flag, managed parties, mandatory recipients, affected contract transactions, sender key and
+receipt status of the private transaction.
+
+Under multitenancy, the transactions of the private state of the --rpcclitoken are reported.`,
+	}
+)
+
+// privateTxAudit retrieves the audit trail in chunks using eth_getPrivateTransactionAuditTrail,
+// so that the entries are written as soon as they are available
+func privateTxAudit(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	first, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid first block number: %v", err)
+	}
+	var address *common.Address
+	if ctx.IsSet(PrivateTxAuditAddressFlag.Name) {
+		hex := ctx.String(PrivateTxAuditAddressFlag.Name)
+		if !common.IsHexAddress(hex) {
+			utils.Fatalf("Invalid contract address: %s", hex)
+		}
+		a := common.HexToAddress(hex)
+		address = &a
+	}
+
+	endpoint := ctx.String(PrivateTxAuditEndpointFlag.Name)
+	if endpoint == "" {
+		path := node.DefaultDataDir()
+		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+			path = ctx.GlobalString(utils.DataDirFlag.Name)
+		}
+		endpoint = filepath.Join(path, "geth.ipc")
+	}
+	client, err := dialRPC(endpoint, ctx)
+	if err != nil {
+		utils.Fatalf("Unable to attach to remote geth: %v", err)
+	}
+	defer client.Close()
+
+	var last uint64
+	if len(ctx.Args()) > 1 {
+		if last, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			utils.Fatalf("Invalid last block number: %v", err)
+		}
+	} else {
+		var head hexutil.Uint64
+		if err := client.Call(&head, "eth_blockNumber"); err != nil {
+			utils.Fatalf("Unable to retrieve the current block number: %v", err)
+		}
+		last = uint64(head)
+	}
+	if first > last {
+		utils.Fatalf("Invalid block range %d-%d", first, last)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for from := first; from <= last; from += ethapi.MaxPrivateTransactionAuditBlockRange {
+		to := from + ethapi.MaxPrivateTransactionAuditBlockRange - 1
+		if to > last {
+			to = last
+		}
+		var entries []*ethapi.PrivateTransactionAuditEntry
+		if err := client.CallContext(context.Background(), &entries, "eth_getPrivateTransactionAuditTrail", hexutil.Uint64(from), hexutil.Uint64(to), address); err != nil {
+			return fmt.Errorf("unable to retrieve the private transactions of blocks %d-%d: %v", from, to, err)
+		}
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
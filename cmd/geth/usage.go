@@ -254,6 +254,19 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.PluginPublicKeyFlag,
 			utils.AllowedFutureBlockTimeFlag,
 			utils.MultitenancyFlag,
+			utils.RPCAuditHTTPFlag,
+			utils.RPCAuditWSFlag,
+			utils.RPCAuditIPCFlag,
+			utils.RPCAuditLogFlag,
+			utils.RPCAuditLogMaxSizeFlag,
+			utils.RPCRateLimitFlag,
+			utils.RPCRateLimitKeyFlag,
+			utils.RPCJWTJWKSFlag,
+			utils.RPCJWTSecretFlag,
+			utils.RPCJWTIssuerFlag,
+			utils.RPCJWTAudienceFlag,
+			utils.RPCJWTAuthoritiesClaimFlag,
+			utils.RPCJWTClockSkewFlag,
 			utils.RevertReasonFlag,
 			utils.QuorumEnablePrivateTrieCache,
 			utils.QuorumEnablePrivacyMarker,
@@ -303,6 +316,15 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.QuorumPTMTlsClientCertFlag,
 			utils.QuorumPTMTlsClientKeyFlag,
 			utils.QuorumPTMTlsInsecureSkipVerify,
+			utils.QuorumPTMTlsReloadIntervalFlag,
+			utils.QuorumPTMHealthCheckIntervalFlag,
+			utils.QuorumPTMCircuitBreakerThresholdFlag,
+			utils.QuorumPTMCircuitBreakerCooldownFlag,
+			utils.QuorumPTMPersistentCacheFlag,
+			utils.QuorumPTMPersistentCacheTTLFlag,
+			utils.QuorumPTMPersistentCacheMaxSizeFlag,
+			utils.QuorumPTMRefreshIntervalFlag,
+			utils.QuorumPTMGroupsRefreshIntervalFlag,
 		},
 	},
 	{
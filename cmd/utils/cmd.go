@@ -30,6 +30,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/mps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -344,3 +345,54 @@ func ExportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Exported preimages", "file", fn)
 	return nil
 }
+
+// ExportPrivateState exports the private state identified by psi, as of the given block, into the
+// specified file, to be imported into another node with ImportPrivateState.
+func ExportPrivateState(db ethdb.Database, block *types.Block, psi types.PrivateStateIdentifier, fn string) error {
+	log.Info("Exporting private state", "psi", psi, "number", block.NumberU64(), "file", fn)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	if err := mps.ExportPrivateState(db, block, psi, writer); err != nil {
+		return err
+	}
+	log.Info("Exported private state", "psi", psi, "number", block.NumberU64(), "file", fn)
+	return nil
+}
+
+// ImportPrivateState imports a private state exported by ExportPrivateState from the specified
+// file into the trie of private states of the block it was exported at. The private state must be
+// one of residentPSIs.
+func ImportPrivateState(db ethdb.Database, residentPSIs []types.PrivateStateIdentifier, fn string) error {
+	log.Info("Importing private state", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	header, err := mps.ImportPrivateState(db, residentPSIs, reader)
+	if err != nil {
+		return err
+	}
+	log.Info("Imported private state", "psi", header.PSI, "number", header.BlockNumber, "root", header.Root)
+	return nil
+}
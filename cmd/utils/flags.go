@@ -76,8 +76,11 @@ import (
 	"github.com/ethereum/go-ethereum/permission"
 	"github.com/ethereum/go-ethereum/permission/core/types"
 	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/ethereum/go-ethereum/plugin/audit"
+	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/raft"
+	"github.com/ethereum/go-ethereum/rpc"
 	pcsclite "github.com/gballet/go-libpcsclite"
 	gopsutil "github.com/shirou/gopsutil/mem"
 	"gopkg.in/urfave/cli.v1"
@@ -896,7 +899,65 @@ var (
 	// Multitenancy setting
 	MultitenancyFlag = cli.BoolFlag{
 		Name:  "multitenancy",
-		Usage: "Enable multitenancy support for this node. This requires RPC Security Plugin to also be configured.",
+		Usage: "Enable multitenancy support for this node. This requires RPC Security Plugin or the built-in JWT authentication to also be configured.",
+	}
+	// RPC audit log settings
+	RPCAuditHTTPFlag = cli.BoolFlag{
+		Name:  "rpc.audit.http",
+		Usage: "Record the authentication and authorization decisions of the HTTP-RPC server to the RPC audit log",
+	}
+	RPCAuditWSFlag = cli.BoolFlag{
+		Name:  "rpc.audit.ws",
+		Usage: "Record the authentication and authorization decisions of the WS-RPC server to the RPC audit log",
+	}
+	RPCAuditIPCFlag = cli.BoolFlag{
+		Name:  "rpc.audit.ipc",
+		Usage: "Record the calls made to the IPC-RPC server to the RPC audit log",
+	}
+	RPCAuditLogFlag = cli.StringFlag{
+		Name:  "rpc.audit.log",
+		Usage: "Path of the RPC audit log, records are written as JSON lines (default: rpc-audit.log in the data directory)",
+	}
+	RPCAuditLogMaxSizeFlag = cli.IntFlag{
+		Name:  "rpc.audit.log.maxsize",
+		Usage: "Size in megabytes after which the RPC audit log is rotated, 0 disables the rotation",
+		Value: node.DefaultConfig.RPCAuditLogMaxSize,
+	}
+	// RPC rate limiting settings
+	RPCRateLimitFlag = cli.StringFlag{
+		Name:  "rpc.ratelimit",
+		Usage: "Per principal limits of the HTTP and WS calls, e.g. 'debug_trace*:rate=1,concurrent=1;eth_getLogs:rate=10,burst=20'. The first rule matching a method applies",
+	}
+	RPCRateLimitKeyFlag = cli.StringFlag{
+		Name:  "rpc.ratelimit.key",
+		Usage: "Identifies the principals of the rate limits by the access token subject, the PSI or both (subject, psi, subject+psi)",
+		Value: rpc.RateLimitKeySubjectPSI,
+	}
+	// Built-in JWT authentication settings
+	RPCJWTJWKSFlag = cli.StringFlag{
+		Name:  "rpc.jwt.jwks",
+		Usage: "Enable the built-in authentication of RPC requests with JWT access tokens, signed with one of the keys of this JSON Web Key Set file",
+	}
+	RPCJWTSecretFlag = cli.StringFlag{
+		Name:  "rpc.jwt.secret",
+		Usage: "Enable the built-in authentication of RPC requests with JWT access tokens, signed with the HMAC secret in this file",
+	}
+	RPCJWTIssuerFlag = cli.StringFlag{
+		Name:  "rpc.jwt.issuer",
+		Usage: "Issuer (iss claim) of the JWT access tokens",
+	}
+	RPCJWTAudienceFlag = cli.StringFlag{
+		Name:  "rpc.jwt.audience",
+		Usage: "Audience (aud claim) the JWT access tokens must be intended for",
+	}
+	RPCJWTAuthoritiesClaimFlag = cli.StringFlag{
+		Name:  "rpc.jwt.authoritiesclaim",
+		Usage: "Claim of the JWT access tokens holding the granted rpc:// and psi:// scopes",
+		Value: security.DefaultJWTAuthoritiesClaim,
+	}
+	RPCJWTClockSkewFlag = cli.DurationFlag{
+		Name:  "rpc.jwt.clockskew",
+		Usage: "Tolerance when checking the expiry of the JWT access tokens",
 	}
 
 	// Revert Reason
@@ -922,7 +983,7 @@ var (
 	}
 	QuorumPTMUrlFlag = cli.StringFlag{
 		Name:  "ptm.url",
-		Usage: "URL when using http connection to private transaction manager",
+		Usage: "URL when using http connection to private transaction manager. A comma separated list of URLs is used as an ordered set of failover endpoints",
 	}
 	QuorumPTMTimeoutFlag = cli.UintFlag{
 		Name:  "ptm.timeout",
@@ -969,6 +1030,46 @@ var (
 		Name:  "ptm.tls.insecureskipverify",
 		Usage: "Disable verification of server's TLS certificate on connection to private transaction manager",
 	}
+	QuorumPTMTlsReloadIntervalFlag = cli.UintFlag{
+		Name:  "ptm.tls.reloadinterval",
+		Usage: "Interval (seconds) between checks for modified TLS certificate and key files, which are reloaded without restarting. Zero value disables the checks, the files can still be reloaded on SIGHUP or using admin.reloadPrivateTransactionManagerTLS.",
+	}
+	QuorumPTMPersistentCacheFlag = cli.BoolFlag{
+		Name:  "ptm.cache.persistent",
+		Usage: "Keep decrypted private payloads in an encrypted cache in the node's database, so they survive restarts",
+	}
+	QuorumPTMPersistentCacheTTLFlag = cli.DurationFlag{
+		Name:  "ptm.cache.persistent.ttl",
+		Usage: "Time after which payloads are evicted from the persistent private payload cache. Zero value means payloads do not expire.",
+		Value: ethconfig.Defaults.PrivatePayloadCache.TTL,
+	}
+	QuorumPTMPersistentCacheMaxSizeFlag = cli.Uint64Flag{
+		Name:  "ptm.cache.persistent.maxsize",
+		Usage: "Maximum size (bytes) of the persistent private payload cache, oldest payloads are evicted first. Zero value means unbounded.",
+		Value: ethconfig.Defaults.PrivatePayloadCache.MaxSize,
+	}
+	QuorumPTMRefreshIntervalFlag = cli.DurationFlag{
+		Name:  "ptm.refreshinterval",
+		Usage: "Interval between detections of the private transaction manager version and features, to take upgrades into account without restarting. Zero value disables the periodic detection.",
+	}
+	QuorumPTMGroupsRefreshIntervalFlag = cli.DurationFlag{
+		Name:  "ptm.groups.refreshinterval",
+		Usage: "Interval between refreshes of the resident groups from the private transaction manager, to onboard new private states without restarting. Zero value disables the periodic refresh.",
+	}
+	QuorumPTMHealthCheckIntervalFlag = cli.UintFlag{
+		Name:  "ptm.healthcheckinterval",
+		Usage: "Interval (seconds) between health checks of the private transaction manager failover endpoints. Zero value means endpoints are only checked on startup.",
+		Value: http2.DefaultConfig.HealthCheckInterval,
+	}
+	QuorumPTMCircuitBreakerThresholdFlag = cli.UintFlag{
+		Name:  "ptm.circuitbreaker.threshold",
+		Usage: "Number of consecutive failed requests to the private transaction manager after which private transactions sent over RPC fail fast. Zero value disables the circuit breaker.",
+	}
+	QuorumPTMCircuitBreakerCooldownFlag = cli.UintFlag{
+		Name:  "ptm.circuitbreaker.cooldown",
+		Usage: "Time (seconds) private transactions sent over RPC fail fast once the circuit breaker is open, before the private transaction manager is tried again",
+		Value: http2.DefaultConfig.CircuitBreakerCooldown,
+	}
 	QuorumLightServerFlag = cli.BoolFlag{
 		Name:  "qlight.server",
 		Usage: "If enabled, the quorum light P2P protocol is started in addition to the other P2P protocols",
@@ -1580,6 +1681,73 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(MultitenancyFlag.Name) {
 		cfg.EnableMultitenancy = ctx.GlobalBool(MultitenancyFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCAuditHTTPFlag.Name) {
+		cfg.HTTPAudit = ctx.GlobalBool(RPCAuditHTTPFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCAuditWSFlag.Name) {
+		cfg.WSAudit = ctx.GlobalBool(RPCAuditWSFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCAuditIPCFlag.Name) {
+		cfg.IPCAudit = ctx.GlobalBool(RPCAuditIPCFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCAuditLogFlag.Name) {
+		cfg.RPCAuditLog = ctx.GlobalString(RPCAuditLogFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCAuditLogMaxSizeFlag.Name) {
+		cfg.RPCAuditLogMaxSize = ctx.GlobalInt(RPCAuditLogMaxSizeFlag.Name)
+	}
+	setJWTAuthentication(ctx, cfg)
+	if err := setRPCRateLimit(ctx, cfg); err != nil {
+		Fatalf("Option %q: %v", RPCRateLimitFlag.Name, err)
+	}
+}
+
+// Quorum
+// setJWTAuthentication configures the built-in JWT authentication if a JWKS or a secret file is set
+func setJWTAuthentication(ctx *cli.Context, cfg *node.Config) {
+	if !ctx.GlobalIsSet(RPCJWTJWKSFlag.Name) && !ctx.GlobalIsSet(RPCJWTSecretFlag.Name) {
+		return
+	}
+	if cfg.JWTAuthentication == nil {
+		cfg.JWTAuthentication = &security.JWTAuthenticationConfig{}
+	}
+	if ctx.GlobalIsSet(RPCJWTJWKSFlag.Name) {
+		cfg.JWTAuthentication.JWKSFile = ctx.GlobalString(RPCJWTJWKSFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCJWTSecretFlag.Name) {
+		cfg.JWTAuthentication.HMACSecretFile = ctx.GlobalString(RPCJWTSecretFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCJWTIssuerFlag.Name) {
+		cfg.JWTAuthentication.Issuer = ctx.GlobalString(RPCJWTIssuerFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCJWTAudienceFlag.Name) {
+		cfg.JWTAuthentication.Audience = ctx.GlobalString(RPCJWTAudienceFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCJWTAuthoritiesClaimFlag.Name) {
+		cfg.JWTAuthentication.AuthoritiesClaim = ctx.GlobalString(RPCJWTAuthoritiesClaimFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCJWTClockSkewFlag.Name) {
+		cfg.JWTAuthentication.ClockSkew = ctx.GlobalDuration(RPCJWTClockSkewFlag.Name)
+	}
+}
+
+// Quorum
+// setRPCRateLimit configures the per principal limits of the HTTP and WS calls
+func setRPCRateLimit(ctx *cli.Context, cfg *node.Config) error {
+	if ctx.GlobalIsSet(RPCRateLimitFlag.Name) {
+		rules, err := rpc.ParseRateLimitRules(ctx.GlobalString(RPCRateLimitFlag.Name))
+		if err != nil {
+			return err
+		}
+		if cfg.RPCRateLimit == nil {
+			cfg.RPCRateLimit = &rpc.RateLimitConfig{}
+		}
+		cfg.RPCRateLimit.Rules = rules
+	}
+	if ctx.GlobalIsSet(RPCRateLimitKeyFlag.Name) && cfg.RPCRateLimit != nil {
+		cfg.RPCRateLimit.KeyBy = ctx.GlobalString(RPCRateLimitKeyFlag.Name)
+	}
+	return nil
 }
 
 func setSmartCard(ctx *cli.Context, cfg *node.Config) {
@@ -1843,6 +2011,18 @@ func setRaft(ctx *cli.Context, cfg *eth.Config) {
 	cfg.RaftMode = ctx.GlobalBool(RaftModeFlag.Name)
 }
 
+func setPrivatePayloadCache(ctx *cli.Context, cfg *eth.Config) {
+	if ctx.GlobalIsSet(QuorumPTMPersistentCacheFlag.Name) {
+		cfg.PrivatePayloadCache.Enabled = ctx.GlobalBool(QuorumPTMPersistentCacheFlag.Name)
+	}
+	if ctx.GlobalIsSet(QuorumPTMPersistentCacheTTLFlag.Name) {
+		cfg.PrivatePayloadCache.TTL = ctx.GlobalDuration(QuorumPTMPersistentCacheTTLFlag.Name)
+	}
+	if ctx.GlobalIsSet(QuorumPTMPersistentCacheMaxSizeFlag.Name) {
+		cfg.PrivatePayloadCache.MaxSize = ctx.GlobalUint64(QuorumPTMPersistentCacheMaxSizeFlag.Name)
+	}
+}
+
 func setQuorumConfig(ctx *cli.Context, cfg *eth.Config) error {
 	cfg.EVMCallTimeOut = time.Duration(ctx.GlobalInt(EVMCallTimeOutFlag.Name)) * time.Second
 	cfg.QuorumChainConfig = core.NewQuorumChainConfig(ctx.GlobalBool(MultitenancyFlag.Name),
@@ -1850,6 +2030,13 @@ func setQuorumConfig(ctx *cli.Context, cfg *eth.Config) error {
 		ctx.GlobalBool(QuorumEnablePrivateTrieCache.Name))
 	setIstanbul(ctx, cfg)
 	setRaft(ctx, cfg)
+	setPrivatePayloadCache(ctx, cfg)
+	if ctx.GlobalIsSet(QuorumPTMRefreshIntervalFlag.Name) {
+		cfg.PrivateTxManagerRefreshInterval = ctx.GlobalDuration(QuorumPTMRefreshIntervalFlag.Name)
+	}
+	if ctx.GlobalIsSet(QuorumPTMGroupsRefreshIntervalFlag.Name) {
+		cfg.ResidentGroupsRefreshInterval = ctx.GlobalDuration(QuorumPTMGroupsRefreshIntervalFlag.Name)
+	}
 	return nil
 }
 
@@ -2315,6 +2502,18 @@ func RegisterPluginService(stack *node.Node, cfg *node.Config, skipVerify bool,
 		Fatalf("plugins: Failed to register the Plugins service: %v", err)
 	}
 	stack.SetPluginManager(pluginManager)
+	if pluginManager.IsEnabled(plugin.AuditPluginInterfaceName) {
+		template := new(plugin.AuditPluginTemplate)
+		if err := pluginManager.GetPluginTemplate(plugin.AuditPluginInterfaceName, template); err != nil {
+			Fatalf("plugins: unable to load the audit plugin due to %s", err)
+		}
+		sink, err := template.Get()
+		if err != nil {
+			Fatalf("plugins: unable to load the audit plugin due to %s", err)
+		}
+		stack.SetRPCAuditSink(audit.NewRPCAuditSink(sink))
+		log.Info("RPC audit records are sent to the audit plugin")
+	}
 	stack.RegisterAPIs(pluginManager.APIs())
 	stack.RegisterLifecycle(pluginManager)
 	log.Info("plugin service registered")
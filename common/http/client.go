@@ -20,26 +20,61 @@ func CreateClient(cfg Config) (*engine.Client, error) {
 			BaseURL: "http+unix://c",
 		}
 	} else {
+		var reloadTLS func() error
+		var stops []func()
 		transport := httpTransport(cfg)
 		if cfg.TlsMode == TlsOff {
 			log.Info("Connecting to private tx manager using HTTP")
 		} else {
 			log.Info("Connecting to private tx manager using HTTPS")
-			tlsConfig, err := newTLSConfig(cfg)
+			reloader, err := newTLSReloader(cfg, transport)
 			if err != nil {
 				return nil, fmt.Errorf("unable to create http.client to private tx manager due to: %s", err)
 			}
-			transport.TLSClientConfig = tlsConfig
+			transport.TLSClientConfig = reloader.tlsConfig()
+			if cfg.TlsReloadInterval > 0 {
+				go reloader.watch(time.Duration(cfg.TlsReloadInterval) * time.Second)
+				stops = append(stops, reloader.Close)
+			}
+			reloadTLS = reloader.Reload
 		}
 
+		var roundTripper http.RoundTripper = transport
+		baseURL, urls := cfg.HttpUrl, cfg.HttpUrls()
+		if len(urls) > 0 {
+			baseURL = urls[0]
+		}
+		if len(urls) > 1 {
+			log.Info("Using failover endpoints for private tx manager", "urls", urls)
+			failover, err := newFailoverTransport(urls, transport, time.Duration(cfg.HealthCheckInterval)*time.Second)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create http.client to private tx manager due to: %s", err)
+			}
+			roundTripper = failover
+			stops = append(stops, failover.Close)
+		}
+		var stop func()
+		if len(stops) > 0 {
+			stop = func() {
+				for _, f := range stops {
+					f()
+				}
+			}
+		}
 		client = &engine.Client{
 			HttpClient: &http.Client{
 				Timeout:   time.Duration(cfg.Timeout) * time.Second,
-				Transport: transport,
+				Transport: roundTripper,
 			},
-			BaseURL: cfg.HttpUrl,
+			BaseURL:   baseURL,
+			ReloadTLS: reloadTLS,
+			Stop:      stop,
 		}
 	}
+	if cfg.CircuitBreakerThreshold > 0 {
+		log.Info("Using circuit breaker for private tx manager", "threshold", cfg.CircuitBreakerThreshold, "cooldown", cfg.CircuitBreakerCooldown)
+		client.CircuitBreaker = engine.NewCircuitBreaker(int(cfg.CircuitBreakerThreshold), time.Duration(cfg.CircuitBreakerCooldown)*time.Second)
+	}
 
 	return client, nil
 }
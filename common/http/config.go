@@ -21,20 +21,24 @@ const (
 )
 
 type Config struct {
-	ConnectionType        string `toml:"-"` // connection type is not loaded from toml
-	Socket                string // filename for unix domain socket
-	WorkDir               string // directory for unix domain socket
-	HttpUrl               string // transaction manager URL for HTTP connection
-	Timeout               uint   // timeout for overall client call (seconds), zero means timeout disabled
-	DialTimeout           uint   // timeout for connecting to unix socket (seconds)
-	HttpIdleConnTimeout   uint   // timeout for idle http connection (seconds), zero means timeout disabled
-	HttpWriteBufferSize   int    // size of http connection write buffer (bytes), if zero then uses http.Transport default
-	HttpReadBufferSize    int    // size of http connection read buffer (bytes), if zero then uses http.Transport default
-	TlsMode               string // whether TLS is enabled on HTTP connection (can be "off" or "strict")
-	TlsRootCA             string // path to file containing certificate for root CA (defaults to host's certificates)
-	TlsClientCert         string // path to file containing client certificate (or chain of certs)
-	TlsClientKey          string // path to file containing client's private key
-	TlsInsecureSkipVerify bool   // if true then does not verify that server certificate is CA signed
+	ConnectionType          string `toml:"-"` // connection type is not loaded from toml
+	Socket                  string // filename for unix domain socket
+	WorkDir                 string // directory for unix domain socket
+	HttpUrl                 string // transaction manager URL for HTTP connection, a comma separated list is used as ordered failover endpoints
+	Timeout                 uint   // timeout for overall client call (seconds), zero means timeout disabled
+	DialTimeout             uint   // timeout for connecting to unix socket (seconds)
+	HttpIdleConnTimeout     uint   // timeout for idle http connection (seconds), zero means timeout disabled
+	HttpWriteBufferSize     int    // size of http connection write buffer (bytes), if zero then uses http.Transport default
+	HttpReadBufferSize      int    // size of http connection read buffer (bytes), if zero then uses http.Transport default
+	TlsMode                 string // whether TLS is enabled on HTTP connection (can be "off" or "strict")
+	TlsRootCA               string // path to file containing certificate for root CA (defaults to host's certificates)
+	TlsClientCert           string // path to file containing client certificate (or chain of certs)
+	TlsClientKey            string // path to file containing client's private key
+	TlsInsecureSkipVerify   bool   // if true then does not verify that server certificate is CA signed
+	HealthCheckInterval     uint   // interval between health checks of failover endpoints (seconds), zero means only checked on startup
+	TlsReloadInterval       uint   // interval between checks for modified TLS files (seconds), zero means TLS files are not watched
+	CircuitBreakerThreshold uint   // consecutive failed requests after which sends from RPC fail fast, zero means the circuit breaker is disabled
+	CircuitBreakerCooldown  uint   // time the circuit breaker stays open before requests are allowed again (seconds)
 }
 
 var NoConnectionConfig = Config{
@@ -43,16 +47,29 @@ var NoConnectionConfig = Config{
 }
 
 var DefaultConfig = Config{
-	Timeout:             5,
-	DialTimeout:         1,
-	HttpIdleConnTimeout: 10,
-	TlsMode:             TlsOff,
+	Timeout:                5,
+	DialTimeout:            1,
+	HttpIdleConnTimeout:    10,
+	TlsMode:                TlsOff,
+	HealthCheckInterval:    5,
+	CircuitBreakerCooldown: 10,
 }
 
 func IsSocketConfigured(cfg Config) bool {
 	return cfg.ConnectionType == UnixDomainSocketConnection
 }
 
+// HttpUrls returns the ordered list of endpoints configured in HttpUrl
+func (cfg *Config) HttpUrls() []string {
+	var urls []string
+	for _, u := range strings.Split(cfg.HttpUrl, ",") {
+		if u = strings.TrimSpace(u); len(u) != 0 {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
 // This will accept path as any of the following and return relevant configuration:
 //   - path set to "ignore"
 //   - path to an ipc file
@@ -133,8 +150,10 @@ func (cfg *Config) Validate() error {
 		case TlsOff:
 			//no action needed
 		case TlsStrict:
-			if !strings.Contains(strings.ToLower(cfg.HttpUrl), "https") {
-				return fmt.Errorf("connection is configured with TLS but HTTPS url is not specified")
+			for _, u := range cfg.HttpUrls() {
+				if !strings.Contains(strings.ToLower(u), "https") {
+					return fmt.Errorf("connection is configured with TLS but HTTPS url is not specified")
+				}
 			}
 			if (len(cfg.TlsClientCert) == 0 && len(cfg.TlsClientKey) != 0) || (len(cfg.TlsClientCert) != 0 && len(cfg.TlsClientKey) == 0) {
 				return fmt.Errorf("invalid details for HTTP connection with TLS, configuration must specify both clientCert and clientKey, or neither one")
@@ -204,3 +223,19 @@ func (cfg *Config) SetTlsClientKey(tlsClientKey string) {
 func (cfg *Config) SetTlsInsecureSkipVerify(tlsInsecureSkipVerify bool) {
 	cfg.TlsInsecureSkipVerify = tlsInsecureSkipVerify
 }
+
+func (cfg *Config) SetHealthCheckInterval(healthCheckInterval uint) {
+	cfg.HealthCheckInterval = healthCheckInterval
+}
+
+func (cfg *Config) SetTlsReloadInterval(tlsReloadInterval uint) {
+	cfg.TlsReloadInterval = tlsReloadInterval
+}
+
+func (cfg *Config) SetCircuitBreakerThreshold(circuitBreakerThreshold uint) {
+	cfg.CircuitBreakerThreshold = circuitBreakerThreshold
+}
+
+func (cfg *Config) SetCircuitBreakerCooldown(circuitBreakerCooldown uint) {
+	cfg.CircuitBreakerCooldown = circuitBreakerCooldown
+}
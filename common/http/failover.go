@@ -0,0 +1,234 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// upcheckTimeout bounds every probe of an endpoint, so that an endpoint which accepts connections
+// but never answers cannot hang the node on startup
+const upcheckTimeout = 5 * time.Second
+
+var (
+	activeEndpointGauge = metrics.NewRegisteredGauge("ptm/endpoint/active", nil)
+	failoverCounter     = metrics.NewRegisteredCounter("ptm/endpoint/failover", nil)
+)
+
+// failoverTransport sends every request to the currently active private transaction manager endpoint.
+// Requests are rewritten from the primary (first) endpoint to the active one, so callers can keep
+// building URLs from a single base URL.
+//
+// The endpoints are probed periodically using /upcheck. The transport fails over to the next healthy
+// endpoint when the active one cannot be reached, and fails back to the most preferred healthy endpoint
+// once it recovers. A server error only fails idempotent requests over: a POST such as /send may have
+// been processed before a proxy answered with a 502 or 504, replaying it would send the payload twice.
+type failoverTransport struct {
+	next      http.RoundTripper
+	endpoints []*url.URL
+	prober    *http.Client
+
+	mu      sync.RWMutex
+	active  int
+	healthy []bool
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+func newFailoverTransport(urls []string, next http.RoundTripper, interval time.Duration) (*failoverTransport, error) {
+	endpoints := make([]*url.URL, len(urls))
+	for i, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private transaction manager URL '%s': %v", u, err)
+		}
+		endpoints[i] = parsed
+	}
+	t := &failoverTransport{
+		next:      next,
+		endpoints: endpoints,
+		prober:    &http.Client{Transport: next, Timeout: upcheckTimeout},
+		healthy:   make([]bool, len(endpoints)),
+		quit:      make(chan struct{}),
+	}
+	// assume all endpoints are healthy until proven otherwise, the first probe runs synchronously so that
+	// the node starts against the most preferred endpoint that is actually up
+	for i := range t.healthy {
+		t.healthy[i] = true
+	}
+	t.probe()
+	if interval > 0 {
+		go t.loop(interval)
+	}
+	return t, nil
+}
+
+func (t *failoverTransport) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.probe()
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+// Close stops the periodic health checks, it is safe to call more than once
+func (t *failoverTransport) Close() {
+	t.closeOnce.Do(func() {
+		close(t.quit)
+	})
+}
+
+// probe checks /upcheck on every endpoint and selects the most preferred healthy one
+func (t *failoverTransport) probe() {
+	results := make([]bool, len(t.endpoints))
+	for i, endpoint := range t.endpoints {
+		results[i] = t.upcheck(endpoint)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, up := range results {
+		if up != t.healthy[i] {
+			log.Info("Private transaction manager endpoint health changed", "url", t.endpoints[i].String(), "healthy", up)
+		}
+	}
+	t.healthy = results
+	for i, up := range results {
+		if up {
+			t.activate(i)
+			return
+		}
+	}
+}
+
+func (t *failoverTransport) upcheck(endpoint *url.URL) bool {
+	res, err := t.prober.Get(strings.TrimSuffix(endpoint.String(), "/") + "/upcheck")
+	if err != nil {
+		log.Debug("Private transaction manager endpoint upcheck failed", "url", endpoint.String(), "err", err)
+		return false
+	}
+	defer res.Body.Close()
+	return res.StatusCode == http.StatusOK
+}
+
+// activate must be called while holding the write lock
+func (t *failoverTransport) activate(idx int) {
+	if idx == t.active {
+		return
+	}
+	log.Warn("Switching private transaction manager endpoint", "from", t.endpoints[t.active].String(), "to", t.endpoints[idx].String())
+	t.active = idx
+	activeEndpointGauge.Update(int64(idx))
+	failoverCounter.Inc(1)
+}
+
+// markDown records that the given endpoint could not be reached and returns the next endpoint to try,
+// or -1 if all endpoints have been tried already
+func (t *failoverTransport) markDown(idx int, tried []bool) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.healthy[idx] = false
+	for i := range t.endpoints {
+		if !tried[i] && t.healthy[i] {
+			t.activate(i)
+			return i
+		}
+	}
+	for i := range t.endpoints {
+		if !tried[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	idx := t.active
+	t.mu.RUnlock()
+
+	// every attempt reads a fresh copy of a replayable body, so the original one is never consumed
+	if req.GetBody != nil && req.Body != nil {
+		defer req.Body.Close()
+	}
+	tried := make([]bool, len(t.endpoints))
+	for {
+		tried[idx] = true
+		r, err := t.rewrite(req, t.endpoints[idx])
+		if err != nil {
+			return nil, err
+		}
+		res, err := t.next.RoundTrip(r)
+		if err == nil && res.StatusCode < http.StatusInternalServerError {
+			return res, nil
+		}
+		if err != nil {
+			log.Warn("Unable to reach private transaction manager endpoint", "url", t.endpoints[idx].String(), "err", err)
+		} else {
+			log.Warn("Private transaction manager endpoint failed", "url", t.endpoints[idx].String(), "status", res.Status)
+			if !isIdempotent(req) {
+				return res, nil
+			}
+		}
+		// the request can only be replayed against another endpoint if its body can be read again,
+		// otherwise the outcome of the last attempt is returned as is
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return res, err
+		}
+		next := t.markDown(idx, tried)
+		if next < 0 {
+			return res, err
+		}
+		if res != nil {
+			res.Body.Close()
+		}
+		idx = next
+	}
+}
+
+// isIdempotent returns true if the request can be sent again after an endpoint answered it with a
+// server error, i.e. if it only reads from the private transaction manager
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// rewrite returns a copy of the request targeting the given endpoint instead of the primary one
+func (t *failoverTransport) rewrite(req *http.Request, endpoint *url.URL) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	// work on the escaped form of the path, the payload hashes in it may contain escaped slashes
+	primaryPath := strings.TrimSuffix(t.endpoints[0].EscapedPath(), "/")
+	escapedPath := strings.TrimSuffix(endpoint.EscapedPath(), "/") + strings.TrimPrefix(req.URL.EscapedPath(), primaryPath)
+	path, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return nil, err
+	}
+	r.URL.Scheme = endpoint.Scheme
+	r.URL.Host = endpoint.Host
+	r.URL.Path = path
+	r.URL.RawPath = escapedPath
+	r.Host = endpoint.Host
+	return r, nil
+}
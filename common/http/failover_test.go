@@ -0,0 +1,191 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEndpoint(name string, up *int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/upcheck", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(up) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("I'm up!"))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write([]byte(name + ":" + r.URL.EscapedPath() + ":" + string(body)))
+	})
+	return httptest.NewServer(mux)
+}
+
+func doRequest(t *testing.T, client *http.Client, url string, body string) string {
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	out, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestFailoverTransport_SelectsFirstHealthyEndpointOnStartup(t *testing.T) {
+	primaryUp, secondaryUp := int32(0), int32(1)
+	primary, secondary := newTestEndpoint("primary", &primaryUp), newTestEndpoint("secondary", &secondaryUp)
+	defer primary.Close()
+	defer secondary.Close()
+
+	transport, err := newFailoverTransport([]string{primary.URL, secondary.URL}, &http.Transport{}, 0)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	assert.Equal(t, "secondary:/transaction/abc%2Fdef:payload", doRequest(t, client, primary.URL+"/transaction/abc%2Fdef", "payload"))
+}
+
+func TestFailoverTransport_FailsOverWhenEndpointUnreachable(t *testing.T) {
+	primaryUp, secondaryUp := int32(1), int32(1)
+	primary, secondary := newTestEndpoint("primary", &primaryUp), newTestEndpoint("secondary", &secondaryUp)
+	defer secondary.Close()
+
+	transport, err := newFailoverTransport([]string{primary.URL, secondary.URL}, &http.Transport{}, 0)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	primaryURL := primary.URL
+
+	assert.Equal(t, "primary:/send:payload", doRequest(t, client, primaryURL+"/send", "payload"))
+
+	primary.Close()
+
+	assert.Equal(t, "secondary:/send:payload", doRequest(t, client, primaryURL+"/send", "payload"))
+	assert.Equal(t, 1, transport.active)
+}
+
+func TestFailoverTransport_FailsBackWhenPreferredEndpointRecovers(t *testing.T) {
+	primaryUp, secondaryUp := int32(0), int32(1)
+	primary, secondary := newTestEndpoint("primary", &primaryUp), newTestEndpoint("secondary", &secondaryUp)
+	defer primary.Close()
+	defer secondary.Close()
+
+	transport, err := newFailoverTransport([]string{primary.URL, secondary.URL}, &http.Transport{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, transport.active)
+
+	atomic.StoreInt32(&primaryUp, 1)
+	transport.probe()
+
+	assert.Equal(t, 0, transport.active)
+}
+
+func TestFailoverTransport_FailsOverOnServerError(t *testing.T) {
+	secondaryUp := int32(1)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/upcheck" {
+			w.Write([]byte("I'm up!"))
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	secondary := newTestEndpoint("secondary", &secondaryUp)
+	defer secondary.Close()
+
+	transport, err := newFailoverTransport([]string{failing.URL, secondary.URL}, &http.Transport{}, 0)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	res, err := client.Get(failing.URL + "/transaction/abc")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	out, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "secondary:/transaction/abc:", string(out))
+	assert.Equal(t, 1, transport.active)
+}
+
+func TestFailoverTransport_ReturnsServerErrorOfNonIdempotentRequest(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/upcheck" {
+			w.Write([]byte("I'm up!"))
+			return
+		}
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer failing.Close()
+	var secondaryRequests int32
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/upcheck" {
+			atomic.AddInt32(&secondaryRequests, 1)
+		}
+		w.Write([]byte("I'm up!"))
+	}))
+	defer secondary.Close()
+
+	transport, err := newFailoverTransport([]string{failing.URL, secondary.URL}, &http.Transport{}, 0)
+	require.NoError(t, err)
+	res, err := (&http.Client{Transport: transport}).Post(failing.URL+"/send", "text/plain", strings.NewReader("payload"))
+
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusGatewayTimeout, res.StatusCode)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&secondaryRequests))
+	assert.Equal(t, 0, transport.active)
+}
+
+func TestFailoverTransport_ReturnsServerErrorWhenAllEndpointsFail(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	transport, err := newFailoverTransport([]string{failing.URL, failing.URL + "/other"}, &http.Transport{}, 0)
+	require.NoError(t, err)
+	res, err := (&http.Client{Transport: transport}).Post(failing.URL+"/send", "text/plain", strings.NewReader("payload"))
+
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+}
+
+func TestFailoverTransport_Close(t *testing.T) {
+	primaryUp, secondaryUp := int32(1), int32(1)
+	primary, secondary := newTestEndpoint("primary", &primaryUp), newTestEndpoint("secondary", &secondaryUp)
+	defer primary.Close()
+	defer secondary.Close()
+
+	transport, err := newFailoverTransport([]string{primary.URL, secondary.URL}, &http.Transport{}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, upcheckTimeout, transport.prober.Timeout)
+
+	transport.Close()
+	transport.Close()
+
+	select {
+	case <-transport.quit:
+	default:
+		t.Fatal("expected the health checks to be stopped")
+	}
+}
+
+func TestHttpUrls_CommaSeparatedList(t *testing.T) {
+	cfg := Config{HttpUrl: "https://tm1:9101, https://tm2:9101,"}
+
+	assert.Equal(t, []string{"https://tm1:9101", "https://tm2:9101"}, cfg.HttpUrls())
+}
+
+func TestValidate_FailoverUrlsRequireHttpsWithTls(t *testing.T) {
+	cfg := Config{ConnectionType: HttpConnection, HttpUrl: "https://tm1:9101,http://tm2:9101", TlsMode: TlsStrict}
+
+	assert.EqualError(t, cfg.Validate(), "connection is configured with TLS but HTTPS url is not specified")
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// tlsMaterial is the TLS material loaded from the files referenced by the config
+type tlsMaterial struct {
+	rootCAs    *x509.CertPool
+	clientCert *tls.Certificate
+}
+
+// tlsReloader keeps the TLS material used by the connections to the private transaction manager.
+// The material can be reloaded at runtime, e.g. after short-lived certificates have been rotated.
+// Reloading only affects new connections, in-flight requests complete on their existing connection.
+type tlsReloader struct {
+	cfg       Config
+	transport *http.Transport
+
+	material atomic.Value // *tlsMaterial
+	mu       sync.Mutex   // serializes reloads
+	modTimes map[string]time.Time
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+func newTLSReloader(cfg Config, transport *http.Transport) (*tlsReloader, error) {
+	r := &tlsReloader{
+		cfg:       cfg,
+		transport: transport,
+		quit:      make(chan struct{}),
+	}
+	material, err := loadTLSMaterial(cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.material.Store(material)
+	r.modTimes = r.readModTimes()
+	return r, nil
+}
+
+func loadTLSMaterial(cfg Config) (*tlsMaterial, error) {
+	rootCAPool, err := loadRootCaCerts(cfg.TlsRootCA)
+	if err != nil {
+		return nil, err
+	}
+	material := &tlsMaterial{rootCAs: rootCAPool}
+	if len(cfg.TlsClientCert) != 0 && len(cfg.TlsClientKey) != 0 {
+		c, err := tls.LoadX509KeyPair(cfg.TlsClientCert, cfg.TlsClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client key pair from '%v', '%v': %v", cfg.TlsClientCert, cfg.TlsClientKey, err)
+		}
+		material.clientCert = &c
+	}
+	return material, nil
+}
+
+// tlsConfig returns a TLS config which always uses the current material
+func (r *tlsReloader) tlsConfig() *tls.Config {
+	config := &tls.Config{
+		InsecureSkipVerify: r.cfg.TlsInsecureSkipVerify,
+	}
+	if len(r.cfg.TlsClientCert) != 0 && len(r.cfg.TlsClientKey) != 0 {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.current().clientCert, nil
+		}
+	}
+	if !r.cfg.TlsInsecureSkipVerify {
+		// RootCAs cannot be changed once the transport uses the config, so the standard verification
+		// is replaced by an equivalent one against the current pool
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = r.verifyConnection
+	}
+	return config
+}
+
+func (r *tlsReloader) current() *tlsMaterial {
+	return r.material.Load().(*tlsMaterial)
+}
+
+func (r *tlsReloader) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("private transaction manager did not present a certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         r.current().rootCAs,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// Reload loads the TLS material again. If it cannot be loaded the previous material is kept.
+func (r *tlsReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reload()
+}
+
+func (r *tlsReloader) reload() error {
+	modTimes := r.readModTimes()
+	material, err := loadTLSMaterial(r.cfg)
+	if err != nil {
+		log.Error("Failed to reload TLS material for private transaction manager, keeping the previous material", "err", err)
+		return fmt.Errorf("unable to reload TLS material for private transaction manager, previous material is kept: %v", err)
+	}
+	r.material.Store(material)
+	r.modTimes = modTimes
+	// idle connections were established with the previous material
+	r.transport.CloseIdleConnections()
+	log.Info("Reloaded TLS material for private transaction manager")
+	return nil
+}
+
+// tlsFiles returns the files the TLS material is loaded from, directories are watched as a whole
+func (r *tlsReloader) tlsFiles() []string {
+	var files []string
+	if len(r.cfg.TlsRootCA) != 0 {
+		files = append(files, strings.Split(r.cfg.TlsRootCA, ",")...)
+	}
+	if len(r.cfg.TlsClientCert) != 0 && len(r.cfg.TlsClientKey) != 0 {
+		files = append(files, r.cfg.TlsClientCert, r.cfg.TlsClientKey)
+	}
+	return files
+}
+
+func (r *tlsReloader) readModTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.tlsFiles() {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+// watch reloads the TLS material whenever one of its files has been modified
+func (r *tlsReloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.reloadIfModified()
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// Close stops watching the TLS files, it is safe to call more than once
+func (r *tlsReloader) Close() {
+	r.closeOnce.Do(func() {
+		close(r.quit)
+	})
+}
+
+func (r *tlsReloader) reloadIfModified() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTimes := r.readModTimes()
+	changed := len(modTimes) != len(r.modTimes)
+	for file, modTime := range modTimes {
+		if !r.modTimes[file].Equal(modTime) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	log.Info("TLS material for private transaction manager has been modified, reloading")
+	// the error has been logged, the reload is attempted again once the files are modified again
+	if err := r.reload(); err != nil {
+		r.modTimes = modTimes
+	}
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key signed by the CA
+func (ca *testCA) issue(t *testing.T, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func startTLSServer(t *testing.T, ca *testCA) *httptest.Server {
+	certPEM, keyPEM := ca.issue(t, x509.ExtKeyUsageServerAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].SerialNumber.String()))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeTLSFiles(t *testing.T, cfg Config, rootCA, clientCert, clientKey []byte) {
+	require.NoError(t, ioutil.WriteFile(cfg.TlsRootCA, rootCA, 0600))
+	require.NoError(t, ioutil.WriteFile(cfg.TlsClientCert, clientCert, 0600))
+	require.NoError(t, ioutil.WriteFile(cfg.TlsClientKey, clientKey, 0600))
+}
+
+func newTLSTestConfig(t *testing.T, url string) Config {
+	dir, err := ioutil.TempDir("", "tls-reload")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	cfg := DefaultConfig
+	cfg.SetHttpUrl(url)
+	cfg.TlsMode = TlsStrict
+	cfg.TlsRootCA = filepath.Join(dir, "rootca.pem")
+	cfg.TlsClientCert = filepath.Join(dir, "client.pem")
+	cfg.TlsClientKey = filepath.Join(dir, "client.key")
+	return cfg
+}
+
+func TestCreateClient_ReloadTLS(t *testing.T) {
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+	server := startTLSServer(t, newCA)
+	cfg := newTLSTestConfig(t, server.URL)
+
+	clientCert, clientKey := oldCA.issue(t, x509.ExtKeyUsageClientAuth)
+	writeTLSFiles(t, cfg, oldCA.pem, clientCert, clientKey)
+	client, err := CreateClient(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, client.ReloadTLS)
+
+	_, err = client.Get("/")
+	assert.Error(t, err, "server certificate must not be trusted before the root CA is rotated")
+
+	clientCert, clientKey = newCA.issue(t, x509.ExtKeyUsageClientAuth)
+	writeTLSFiles(t, cfg, newCA.pem, clientCert, clientKey)
+	require.NoError(t, client.ReloadTLS())
+
+	res, err := client.Get("/")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestCreateClient_FailedReloadKeepsPreviousMaterial(t *testing.T) {
+	ca := newTestCA(t)
+	server := startTLSServer(t, ca)
+	cfg := newTLSTestConfig(t, server.URL)
+
+	clientCert, clientKey := ca.issue(t, x509.ExtKeyUsageClientAuth)
+	writeTLSFiles(t, cfg, ca.pem, clientCert, clientKey)
+	client, err := CreateClient(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(cfg.TlsClientKey, []byte("not a key"), 0600))
+	err = client.ReloadTLS()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "previous material is kept")
+
+	// force a new connection, which must still use the previous material
+	client.HttpClient.CloseIdleConnections()
+	res, err := client.Get("/")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestTLSReloader_ReloadsModifiedFiles(t *testing.T) {
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+	cfg := newTLSTestConfig(t, "https://localhost")
+	clientCert, clientKey := oldCA.issue(t, x509.ExtKeyUsageClientAuth)
+	writeTLSFiles(t, cfg, oldCA.pem, clientCert, clientKey)
+	reloader, err := newTLSReloader(cfg, &http.Transport{})
+	require.NoError(t, err)
+	previous := reloader.current()
+
+	reloader.reloadIfModified()
+	assert.Same(t, previous, reloader.current(), "material must not be reloaded when files are unchanged")
+
+	clientCert, clientKey = newCA.issue(t, x509.ExtKeyUsageClientAuth)
+	writeTLSFiles(t, cfg, newCA.pem, clientCert, clientKey)
+	future := time.Now().Add(time.Minute)
+	for _, file := range reloader.tlsFiles() {
+		require.NoError(t, os.Chtimes(file, future, future))
+	}
+	reloader.reloadIfModified()
+	assert.NotSame(t, previous, reloader.current())
+}
+
+func TestCreateClient_TLSNotInUse(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.SetHttpUrl("http://localhost")
+
+	client, err := CreateClient(cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, client.ReloadTLS)
+}
+
+func TestCreateClient_StopsWatchingTLSFiles(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := newTLSTestConfig(t, "https://localhost")
+	cfg.TlsReloadInterval = 3600
+	clientCert, clientKey := ca.issue(t, x509.ExtKeyUsageClientAuth)
+	writeTLSFiles(t, cfg, ca.pem, clientCert, clientKey)
+	client, err := CreateClient(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, client.Stop)
+
+	client.Stop()
+	client.Stop()
+}
+
+func TestTLSReloader_Close(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := newTLSTestConfig(t, "https://localhost")
+	clientCert, clientKey := ca.issue(t, x509.ExtKeyUsageClientAuth)
+	writeTLSFiles(t, cfg, ca.pem, clientCert, clientKey)
+	reloader, err := newTLSReloader(cfg, &http.Transport{})
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		reloader.watch(time.Hour)
+		close(done)
+	}()
+
+	reloader.Close()
+	reloader.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the watch of the TLS files to be stopped")
+	}
+}
@@ -1,8 +1,6 @@
 package http
 
 import (
-	"crypto/tls"
-	"fmt"
 	"net/http"
 	"path/filepath"
 	"time"
@@ -29,27 +27,3 @@ func httpTransport(cfg Config) *http.Transport {
 	}
 	return t
 }
-
-func newTLSConfig(cfg Config) (*tls.Config, error) {
-	rootCAPool, err := loadRootCaCerts(cfg.TlsRootCA)
-	if err != nil {
-		return nil, err
-	}
-
-	var getClientCertFunc func(*tls.CertificateRequestInfo) (*tls.Certificate, error) = nil
-	if len(cfg.TlsClientCert) != 0 && len(cfg.TlsClientKey) != 0 {
-		getClientCertFunc = func(info *tls.CertificateRequestInfo) (certificate *tls.Certificate, e error) {
-			c, err := tls.LoadX509KeyPair(cfg.TlsClientCert, cfg.TlsClientKey)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load client key pair from '%v', '%v': %v", cfg.TlsClientCert, cfg.TlsClientKey, err)
-			}
-			return &c, nil
-		}
-	}
-
-	return &tls.Config{
-		RootCAs:              rootCAPool,
-		InsecureSkipVerify:   cfg.TlsInsecureSkipVerify,
-		GetClientCertificate: getClientCertFunc,
-	}, nil
-}
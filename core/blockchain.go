@@ -45,6 +45,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/qlight"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
@@ -82,6 +83,7 @@ var (
 
 	blockPrefetchExecuteTimer   = metrics.NewRegisteredTimer("chain/prefetch/executes", nil)
 	blockPrefetchInterruptMeter = metrics.NewRegisteredMeter("chain/prefetch/interrupts", nil)
+	blockPrefetchPrivateTimer   = metrics.NewRegisteredTimer("chain/prefetch/private", nil) // Quorum
 
 	errInsertionInterrupted = errors.New("insertion is interrupted")
 )
@@ -1873,6 +1875,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 	var (
 		stats     = insertStats{startTime: mclock.Now()}
 		lastCanon *types.Block
+		ptm       = private.P // Quorum: the private transaction manager the payloads are prefetched from
 	)
 	// Fire a single chain head event if we've progressed the chain
 	defer func() {
@@ -2068,7 +2071,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 
 					// Quorum: add privateStateThrowaway argument
 					go func(start time.Time, followup *types.Block, throwaway *state.StateDB, privateStateThrowaway mps.PrivateStateRepository, interrupt *uint32) {
-						bc.prefetcher.Prefetch(followup, throwaway, throwawayPrivateStateRepo, bc.vmConfig, &followupInterrupt)
+						// retrieve the private payloads of the followup block while the current one executes
+						private.PrefetchPayloads(followup.Transactions(), ptm, interrupt)
+						bc.prefetcher.Prefetch(followup, throwaway, throwawayPrivateStateRepo, bc.vmConfig, interrupt)
 
 						blockPrefetchExecuteTimer.Update(time.Since(start))
 						if atomic.LoadUint32(interrupt) == 1 {
@@ -2081,8 +2086,14 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 				// End Quorum
 			}
 		}
-		// Process block using the parent state as reference point
+		// Quorum: retrieve the private payloads of the block concurrently, instead of one at a time during execution
 		substart := time.Now()
+		private.PrefetchPayloads(block.Transactions(), ptm, nil)
+		blockPrefetchPrivateTimer.Update(time.Since(substart))
+		// End Quorum
+
+		// Process block using the parent state as reference point
+		substart = time.Now()
 
 		receipts, privateReceipts, logs, usedGas, err := bc.processor.Process(block, statedb, privateStateRepo, bc.vmConfig)
 		if err != nil {
@@ -0,0 +1,329 @@
+package mps
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// PrivateStateDiff reports the accounts of a private state which differ between two blocks
+type PrivateStateDiff struct {
+	PSI       types.PrivateStateIdentifier `json:"psi"`
+	FromBlock hexutil.Uint64               `json:"fromBlock"`
+	ToBlock   hexutil.Uint64               `json:"toBlock"`
+	FromRoot  common.Hash                  `json:"fromRoot"`
+	ToRoot    common.Hash                  `json:"toRoot"`
+	Accounts  []*AccountDiff               `json:"accounts"`
+}
+
+// AccountDiff reports the changes of an account. Only the fields which changed are set.
+type AccountDiff struct {
+	// Address is nil if it can be neither found in the preimages nor in the private transactions of the range
+	Address         *common.Address        `json:"address,omitempty"`
+	AddressHash     common.Hash            `json:"addressHash"`
+	Created         bool                   `json:"created,omitempty"`
+	Deleted         bool                   `json:"deleted,omitempty"`
+	Balance         *BalanceChange         `json:"balance,omitempty"`
+	Nonce           *NonceChange           `json:"nonce,omitempty"`
+	CodeHash        *HashChange            `json:"codeHash,omitempty"`
+	Storage         []*StorageChange       `json:"storage,omitempty"`
+	PrivacyMetadata *PrivacyMetadataChange `json:"privacyMetadata,omitempty"`
+	// Transactions are the private transactions of the range which touched the account
+	Transactions []common.Hash `json:"transactions,omitempty"`
+}
+
+type BalanceChange struct {
+	From *hexutil.Big `json:"from"`
+	To   *hexutil.Big `json:"to"`
+}
+
+type NonceChange struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+type HashChange struct {
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+// StorageChange reports the change of a storage slot, Key is nil if its preimage is unknown
+type StorageChange struct {
+	Key     *common.Hash `json:"key,omitempty"`
+	KeyHash common.Hash  `json:"keyHash"`
+	From    common.Hash  `json:"from"`
+	To      common.Hash  `json:"to"`
+}
+
+type PrivacyMetadataChange struct {
+	From *state.PrivacyMetadata `json:"from"`
+	To   *state.PrivacyMetadata `json:"to"`
+}
+
+// OpenPrivateState opens the private state identified by psi, as of the given block, from the database.
+// The legacy private state of a database without multiple private states is identified by the default PSI.
+func OpenPrivateState(db ethdb.Database, block *types.Block, psi types.PrivateStateIdentifier) (*state.StateDB, error) {
+	cache := state.NewDatabase(db)
+	privateStatesTrieRoot := rawdb.GetPrivateStatesTrieRoot(db, block.Root())
+	if privateStatesTrieRoot == (common.Hash{}) {
+		if psi != types.DefaultPrivateStateIdentifier {
+			return nil, fmt.Errorf("private state %s does not exist at block %d", psi, block.NumberU64())
+		}
+		return state.New(rawdb.GetPrivateStateRoot(db, block.Root()), cache, nil)
+	}
+	privateStatesTrie, err := cache.OpenTrie(privateStatesTrieRoot)
+	if err != nil {
+		return nil, err
+	}
+	root, err := privateStatesTrie.TryGet([]byte(psi))
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("private state %s does not exist at block %d", psi, block.NumberU64())
+	}
+	return state.New(common.BytesToHash(root), cache, nil)
+}
+
+// DiffPrivateState compares the private state identified by psi as of fromBlock (from) and as of
+// toBlock (to). Each account change is attributed to the private transactions of the blocks after
+// fromBlock up to toBlock which touched the account, according to their receipts in the private state.
+func DiffPrivateState(db ethdb.Reader, psi types.PrivateStateIdentifier, fromBlock, toBlock *types.Block, from, to *state.StateDB) (*PrivateStateDiff, error) {
+	if fromBlock.NumberU64() > toBlock.NumberU64() {
+		return nil, fmt.Errorf("from block %d is after to block %d", fromBlock.NumberU64(), toBlock.NumberU64())
+	}
+	touched, err := touchedByPrivateTransactions(db, psi, fromBlock.NumberU64()+1, toBlock.NumberU64())
+	if err != nil {
+		return nil, err
+	}
+	addresses := make(map[common.Hash]common.Address, len(touched))
+	for address := range touched {
+		addresses[crypto.Keccak256Hash(address.Bytes())] = address
+	}
+	diff := &PrivateStateDiff{
+		PSI:       psi,
+		FromBlock: hexutil.Uint64(fromBlock.NumberU64()),
+		ToBlock:   hexutil.Uint64(toBlock.NumberU64()),
+		FromRoot:  from.IntermediateRoot(false),
+		ToRoot:    to.IntermediateRoot(false),
+		Accounts:  make([]*AccountDiff, 0),
+	}
+	fromTrie, err := from.Database().OpenTrie(diff.FromRoot)
+	if err != nil {
+		return nil, err
+	}
+	toTrie, err := to.Database().OpenTrie(diff.ToRoot)
+	if err != nil {
+		return nil, err
+	}
+	fromLeaves, toLeaves, err := diffLeaves(fromTrie, toTrie)
+	if err != nil {
+		return nil, err
+	}
+	for _, addrHash := range sortedKeys(fromLeaves, toLeaves) {
+		accountDiff := &AccountDiff{AddressHash: addrHash}
+		if address, ok := addresses[addrHash]; ok {
+			accountDiff.Address = &address
+		} else if key := preimage(addrHash, toTrie, fromTrie); key != nil {
+			address := common.BytesToAddress(key)
+			accountDiff.Address = &address
+		}
+		fromAccount, err := decodeAccount(fromLeaves[addrHash])
+		if err != nil {
+			return nil, err
+		}
+		toAccount, err := decodeAccount(toLeaves[addrHash])
+		if err != nil {
+			return nil, err
+		}
+		accountDiff.Created, accountDiff.Deleted = fromAccount == nil, toAccount == nil
+		if fromAccount == nil {
+			fromAccount = &state.Account{Balance: new(big.Int), Root: emptyRoot, CodeHash: emptyCodeHash}
+		}
+		if toAccount == nil {
+			toAccount = &state.Account{Balance: new(big.Int), Root: emptyRoot, CodeHash: emptyCodeHash}
+		}
+		if fromAccount.Balance.Cmp(toAccount.Balance) != 0 {
+			accountDiff.Balance = &BalanceChange{From: (*hexutil.Big)(fromAccount.Balance), To: (*hexutil.Big)(toAccount.Balance)}
+		}
+		if fromAccount.Nonce != toAccount.Nonce {
+			accountDiff.Nonce = &NonceChange{From: hexutil.Uint64(fromAccount.Nonce), To: hexutil.Uint64(toAccount.Nonce)}
+		}
+		if !bytes.Equal(fromAccount.CodeHash, toAccount.CodeHash) {
+			accountDiff.CodeHash = &HashChange{From: common.BytesToHash(fromAccount.CodeHash), To: common.BytesToHash(toAccount.CodeHash)}
+		}
+		if fromAccount.Root != toAccount.Root {
+			if accountDiff.Storage, err = diffStorage(from.Database(), to.Database(), addrHash, fromAccount.Root, toAccount.Root); err != nil {
+				return nil, err
+			}
+		}
+		if accountDiff.Address != nil {
+			accountDiff.Transactions = touched[*accountDiff.Address]
+			fromMetadata, _ := from.GetPrivacyMetadata(*accountDiff.Address)
+			toMetadata, _ := to.GetPrivacyMetadata(*accountDiff.Address)
+			if !samePrivacyMetadata(fromMetadata, toMetadata) {
+				accountDiff.PrivacyMetadata = &PrivacyMetadataChange{From: fromMetadata, To: toMetadata}
+			}
+		}
+		diff.Accounts = append(diff.Accounts, accountDiff)
+	}
+	return diff, nil
+}
+
+// touchedByPrivateTransactions returns the private transactions of the blocks in the range which touched
+// each account of the private state: the contract called or created by the transaction and the contracts
+// which emitted its logs
+func touchedByPrivateTransactions(db ethdb.Reader, psi types.PrivateStateIdentifier, first, last uint64) (map[common.Address][]common.Hash, error) {
+	touched := make(map[common.Address][]common.Hash)
+	touch := func(address common.Address, tx common.Hash) {
+		if txs := touched[address]; len(txs) == 0 || txs[len(txs)-1] != tx {
+			touched[address] = append(txs, tx)
+		}
+	}
+	for number := first; number <= last; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		block := rawdb.ReadBlock(db, hash, number)
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		receipts := rawdb.ReadRawReceipts(db, hash, number)
+		for i, tx := range block.Transactions() {
+			if (!tx.IsPrivate() && !tx.IsPrivacyMarker()) || i >= len(receipts) {
+				continue
+			}
+			receipt := receipts[i]
+			if receipt.PSReceipts != nil {
+				if receipt = receipt.PSReceipts[psi]; receipt == nil {
+					continue
+				}
+			}
+			if tx.IsPrivate() && tx.To() != nil {
+				touch(*tx.To(), tx.Hash())
+			}
+			if receipt.ContractAddress != (common.Address{}) {
+				touch(receipt.ContractAddress, tx.Hash())
+			}
+			for _, log := range receipt.Logs {
+				touch(log.Address, tx.Hash())
+			}
+		}
+	}
+	return touched, nil
+}
+
+// diffLeaves returns the leaves which differ between two tries, keyed by their hashed key
+func diffLeaves(from, to state.Trie) (map[common.Hash][]byte, map[common.Hash][]byte, error) {
+	collect := func(a, b state.Trie) (map[common.Hash][]byte, error) {
+		leaves := make(map[common.Hash][]byte)
+		diff, _ := trie.NewDifferenceIterator(a.NodeIterator(nil), b.NodeIterator(nil))
+		it := trie.NewIterator(diff)
+		for it.Next() {
+			leaves[common.BytesToHash(it.Key)] = it.Value
+		}
+		return leaves, it.Err
+	}
+	toLeaves, err := collect(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	fromLeaves, err := collect(to, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fromLeaves, toLeaves, nil
+}
+
+func diffStorage(fromDB, toDB state.Database, addrHash, fromRoot, toRoot common.Hash) ([]*StorageChange, error) {
+	fromTrie, err := fromDB.OpenStorageTrie(addrHash, fromRoot)
+	if err != nil {
+		return nil, err
+	}
+	toTrie, err := toDB.OpenStorageTrie(addrHash, toRoot)
+	if err != nil {
+		return nil, err
+	}
+	fromLeaves, toLeaves, err := diffLeaves(fromTrie, toTrie)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*StorageChange
+	for _, keyHash := range sortedKeys(fromLeaves, toLeaves) {
+		change := &StorageChange{KeyHash: keyHash}
+		if key := preimage(keyHash, toTrie, fromTrie); key != nil {
+			slot := common.BytesToHash(key)
+			change.Key = &slot
+		}
+		if change.From, err = decodeStorageValue(fromLeaves[keyHash]); err != nil {
+			return nil, err
+		}
+		if change.To, err = decodeStorageValue(toLeaves[keyHash]); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func decodeAccount(blob []byte) (*state.Account, error) {
+	if blob == nil {
+		return nil, nil
+	}
+	account := new(state.Account)
+	if err := rlp.DecodeBytes(blob, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func decodeStorageValue(blob []byte) (common.Hash, error) {
+	if blob == nil {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(blob)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
+
+// preimage looks up the preimage of a hashed trie key, nil if it has not been recorded
+func preimage(hash common.Hash, tries ...state.Trie) []byte {
+	for _, tr := range tries {
+		if key := tr.GetKey(hash.Bytes()); key != nil {
+			return key
+		}
+	}
+	return nil
+}
+
+func sortedKeys(a, b map[common.Hash][]byte) []common.Hash {
+	keys := make([]common.Hash, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+	return keys
+}
+
+func samePrivacyMetadata(a, b *state.PrivacyMetadata) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
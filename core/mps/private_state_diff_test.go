@@ -0,0 +1,101 @@
+package mps
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPrivateState(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	var (
+		psi      = types.PrivateStateIdentifier("psi1")
+		contract = common.HexToAddress("0x1932c48b2bf8102ba33b4a6b545c32236e342f34")
+		created  = common.HexToAddress("0x9d13c6d3afe1721beef56b55d303b09e021e27ab")
+		slot     = common.HexToHash("0x01")
+	)
+	// newExportTestRepository opens the private states of exportTestBlock
+	fromBlock := exportTestBlock
+	psr := newExportTestRepository(t, db)
+	privateState, _ := psr.StatePSI(psi)
+	privateState.SetCode(contract, []byte{0x60, 0x80})
+	privateState.SetState(contract, slot, common.HexToHash("0x02"))
+	assert.NoError(t, psr.CommitAndWrite(false, fromBlock))
+
+	tx := types.NewTransaction(0, contract, big.NewInt(0), 0, big.NewInt(0), nil)
+	tx.SetPrivate()
+	creation := types.NewContractCreation(1, big.NewInt(0), 0, big.NewInt(0), nil)
+	creation.SetPrivate()
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash(), Logs: []*types.Log{}},
+		{Status: types.ReceiptStatusSuccessful, TxHash: creation.Hash(), Logs: []*types.Log{}},
+	}
+	receipts[0].PSReceipts = map[types.PrivateStateIdentifier]*types.Receipt{
+		psi: {Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash(), Logs: []*types.Log{}},
+	}
+	receipts[1].PSReceipts = map[types.PrivateStateIdentifier]*types.Receipt{
+		psi: {Status: types.ReceiptStatusSuccessful, TxHash: creation.Hash(), ContractAddress: created, Logs: []*types.Log{}},
+	}
+	toBlock := types.NewBlock(&types.Header{Number: big.NewInt(2), Root: common.HexToHash("0x02")}, []*types.Transaction{tx, creation}, nil, receipts, trie.NewStackTrie(nil))
+	rawdb.WriteBlock(db, toBlock)
+	rawdb.WriteCanonicalHash(db, toBlock.Hash(), 2)
+	rawdb.WriteReceipts(db, toBlock.Hash(), 2, receipts)
+
+	psr = newExportTestRepository(t, db)
+	privateState, _ = psr.StatePSI(psi)
+	privateState.SetState(contract, slot, common.HexToHash("0x03"))
+	privateState.SetCode(created, []byte{0x60})
+	privateState.SetNonce(created, 1)
+	privateState.SetPrivacyMetadata(created, &state.PrivacyMetadata{PrivacyFlag: engine.PrivacyFlagPartyProtection})
+	assert.NoError(t, psr.CommitAndWrite(false, toBlock))
+
+	from, err := OpenPrivateState(db, fromBlock, psi)
+	assert.NoError(t, err)
+	to, err := OpenPrivateState(db, toBlock, psi)
+	assert.NoError(t, err)
+
+	diff, err := DiffPrivateState(db, psi, fromBlock, toBlock, from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Uint64(1), diff.FromBlock)
+	assert.Equal(t, hexutil.Uint64(2), diff.ToBlock)
+	if assert.Len(t, diff.Accounts, 2) {
+		changes := make(map[common.Address]*AccountDiff)
+		for _, account := range diff.Accounts {
+			if assert.NotNil(t, account.Address) {
+				assert.Equal(t, crypto.Keccak256Hash(account.Address.Bytes()), account.AddressHash)
+				changes[*account.Address] = account
+			}
+		}
+		updated := changes[contract]
+		assert.False(t, updated.Created)
+		assert.Nil(t, updated.CodeHash)
+		if assert.Len(t, updated.Storage, 1) {
+			assert.Equal(t, crypto.Keccak256Hash(slot.Bytes()), updated.Storage[0].KeyHash)
+			assert.Equal(t, common.HexToHash("0x02"), updated.Storage[0].From)
+			assert.Equal(t, common.HexToHash("0x03"), updated.Storage[0].To)
+		}
+		assert.Equal(t, []common.Hash{tx.Hash()}, updated.Transactions)
+
+		createdDiff := changes[created]
+		assert.True(t, createdDiff.Created)
+		assert.Equal(t, &NonceChange{From: 0, To: 1}, createdDiff.Nonce)
+		assert.Equal(t, crypto.Keccak256Hash([]byte{0x60}), createdDiff.CodeHash.To)
+		assert.Equal(t, &PrivacyMetadataChange{To: &state.PrivacyMetadata{PrivacyFlag: engine.PrivacyFlagPartyProtection}}, createdDiff.PrivacyMetadata)
+		assert.Equal(t, []common.Hash{creation.Hash()}, createdDiff.Transactions)
+	}
+
+	_, err = DiffPrivateState(db, psi, toBlock, fromBlock, to, from)
+	assert.EqualError(t, err, "from block 2 is after to block 1")
+	_, err = OpenPrivateState(db, toBlock, types.PrivateStateIdentifier("other"))
+	assert.EqualError(t, err, "private state other does not exist at block 2")
+}
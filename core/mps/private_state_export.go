@@ -0,0 +1,232 @@
+package mps
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// privateStateExportVersion is the version of the stream written by ExportPrivateState
+const privateStateExportVersion = 1
+
+// PrivateStateExportHeader is the first item of the stream written by ExportPrivateState
+type PrivateStateExportHeader struct {
+	Version       uint64
+	PSI           types.PrivateStateIdentifier
+	BlockNumber   uint64
+	BlockHash     common.Hash
+	Root          common.Hash // root of the private state trie
+	ExtraDataRoot common.Hash // root of the account extra data trie (privacy metadata and managed parties)
+}
+
+// privateStateExportEntry is a trie node or a contract code following the header in the stream
+type privateStateExportEntry struct {
+	Code bool
+	Blob []byte
+}
+
+// ExportPrivateState writes the private state identified by psi, as of the given block, to w as an
+// RLP stream: a PrivateStateExportHeader followed by the nodes of the state, storage and account
+// extra data tries and the contract codes.
+func ExportPrivateState(db ethdb.Database, block *types.Block, psi types.PrivateStateIdentifier, w io.Writer) error {
+	cache := state.NewDatabase(db)
+	privateStatesTrie, err := cache.OpenTrie(rawdb.GetPrivateStatesTrieRoot(db, block.Root()))
+	if err != nil {
+		return err
+	}
+	root, err := privateStatesTrie.TryGet([]byte(psi))
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		return fmt.Errorf("private state %s does not exist at block %d", psi, block.NumberU64())
+	}
+	header := &PrivateStateExportHeader{
+		Version:       privateStateExportVersion,
+		PSI:           psi,
+		BlockNumber:   block.NumberU64(),
+		BlockHash:     block.Hash(),
+		Root:          common.BytesToHash(root),
+		ExtraDataRoot: rawdb.GetAccountExtraDataRoot(db, common.BytesToHash(root)),
+	}
+	if err := rlp.Encode(w, header); err != nil {
+		return err
+	}
+	return walkPrivateState(cache, header.Root, header.ExtraDataRoot, func(code bool, blob []byte) error {
+		return rlp.Encode(w, &privateStateExportEntry{Code: code, Blob: blob})
+	})
+}
+
+// ImportPrivateState reads a private state written by ExportPrivateState from r and grafts it into
+// the trie of private states of the block the state was exported at, which must be a canonical block
+// of db. The private state must be the one of a resident group of the target node, residentPSIs.
+// Blocks after the target block are not changed, the imported private state is only carried forward
+// once they are processed again, e.g. after rewinding the chain to the target block.
+//
+// The nodes and contract codes are written to db as they are read from the stream, they are keyed by
+// their hash and remain unreferenced until the state has been resolved from the exported root, so that
+// an incomplete or tampered stream is rejected before the private state is grafted. An existing private
+// state with the same identifier is only accepted if it has the same root.
+func ImportPrivateState(db ethdb.Database, residentPSIs []types.PrivateStateIdentifier, r io.Reader) (*PrivateStateExportHeader, error) {
+	stream := rlp.NewStream(r, 0)
+	header := new(PrivateStateExportHeader)
+	if err := stream.Decode(header); err != nil {
+		return nil, err
+	}
+	if header.Version != privateStateExportVersion {
+		return nil, fmt.Errorf("unsupported private state export version %d", header.Version)
+	}
+	if !containsPSI(residentPSIs, header.PSI) {
+		return nil, fmt.Errorf("private state %s is not the private state of a resident group", header.PSI)
+	}
+	if canonical := rawdb.ReadCanonicalHash(db, header.BlockNumber); canonical != header.BlockHash {
+		return nil, fmt.Errorf("private state was exported at block %d (%s) which is not a canonical block", header.BlockNumber, header.BlockHash.Hex())
+	}
+	blockHeader := rawdb.ReadHeader(db, header.BlockHash, header.BlockNumber)
+	if blockHeader == nil {
+		return nil, fmt.Errorf("block %d (%s) not found", header.BlockNumber, header.BlockHash.Hex())
+	}
+
+	cache := state.NewDatabase(db)
+	privateStatesTrie, err := cache.OpenTrie(rawdb.GetPrivateStatesTrieRoot(db, blockHeader.Root))
+	if err != nil {
+		return nil, err
+	}
+	existing, err := privateStatesTrie.TryGet([]byte(header.PSI))
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && common.BytesToHash(existing) != header.Root {
+		return nil, fmt.Errorf("private state %s already exists at block %d with a different root %s", header.PSI, header.BlockNumber, common.BytesToHash(existing).Hex())
+	}
+
+	batch := db.NewBatch()
+	for {
+		var entry privateStateExportEntry
+		if err := stream.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if entry.Code {
+			rawdb.WriteCode(batch, crypto.Keccak256Hash(entry.Blob), entry.Blob)
+		} else {
+			rawdb.WriteTrieNode(batch, crypto.Keccak256Hash(entry.Blob), entry.Blob)
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	if err := walkPrivateState(state.NewDatabase(db), header.Root, header.ExtraDataRoot, func(bool, []byte) error { return nil }); err != nil {
+		return nil, fmt.Errorf("incomplete private state for root %s: %v", header.Root.Hex(), err)
+	}
+	if err := rawdb.WriteRootHashMapping(db, header.Root, header.ExtraDataRoot); err != nil {
+		return nil, err
+	}
+
+	if err := privateStatesTrie.TryUpdate([]byte(header.PSI), header.Root.Bytes()); err != nil {
+		return nil, err
+	}
+	privateStatesTrieRoot, err := privateStatesTrie.Commit(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.TrieDB().Commit(privateStatesTrieRoot, false, nil); err != nil {
+		return nil, err
+	}
+	if err := rawdb.WritePrivateStatesTrieRoot(db, blockHeader.Root, privateStatesTrieRoot); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+func containsPSI(psis []types.PrivateStateIdentifier, psi types.PrivateStateIdentifier) bool {
+	for _, candidate := range psis {
+		if candidate == psi {
+			return true
+		}
+	}
+	return false
+}
+
+// walkPrivateState resolves every node of the state, storage and account extra data tries of a
+// private state as well as its contract codes, passing each of them to onEntry
+func walkPrivateState(cache state.Database, root, extraDataRoot common.Hash, onEntry func(code bool, blob []byte) error) error {
+	codes := make(map[common.Hash]struct{})
+	onLeaf := func(key, leaf []byte) error {
+		var account state.Account
+		if err := rlp.Decode(bytes.NewReader(leaf), &account); err != nil {
+			return err
+		}
+		if err := walkTrie(cache, common.BytesToHash(key), account.Root, true, onEntry, nil); err != nil {
+			return err
+		}
+		codeHash := common.BytesToHash(account.CodeHash)
+		if _, done := codes[codeHash]; done || bytes.Equal(account.CodeHash, emptyCodeHash) {
+			return nil
+		}
+		codes[codeHash] = struct{}{}
+		code, err := cache.ContractCode(common.BytesToHash(key), codeHash)
+		if err != nil {
+			return fmt.Errorf("code %x: %v", account.CodeHash, err)
+		}
+		return onEntry(true, code)
+	}
+	if err := walkTrie(cache, common.Hash{}, root, false, onEntry, onLeaf); err != nil {
+		return err
+	}
+	return walkTrie(cache, common.Hash{}, extraDataRoot, false, onEntry, nil)
+}
+
+// walkTrie passes the blob of every standalone node of a trie to onEntry and the leaves to onLeaf
+func walkTrie(cache state.Database, addrHash, root common.Hash, storage bool, onEntry func(code bool, blob []byte) error, onLeaf func(key, leaf []byte) error) error {
+	if root == (common.Hash{}) || root == emptyRoot {
+		return nil
+	}
+	var (
+		tr  state.Trie
+		err error
+	)
+	if storage {
+		tr, err = cache.OpenStorageTrie(addrHash, root)
+	} else {
+		tr, err = cache.OpenTrie(root)
+	}
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			blob, err := cache.TrieDB().Node(hash)
+			if err != nil {
+				return err
+			}
+			if err := onEntry(false, blob); err != nil {
+				return err
+			}
+		}
+		if it.Leaf() && onLeaf != nil {
+			if err := onLeaf(it.LeafKey(), it.LeafBlob()); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
+
+var emptyCodeHash = crypto.Keccak256(nil)
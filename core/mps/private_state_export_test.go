@@ -0,0 +1,133 @@
+package mps
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/privatecache"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	exportTestBlock    = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Root: common.HexToHash("0x01")})
+	exportTestPSI      = types.PrivateStateIdentifier("psi1")
+	exportTestContract = common.HexToAddress("0x1932c48b2bf8102ba33b4a6b545c32236e342f34")
+)
+
+func newExportTestRepository(t *testing.T, db ethdb.Database) *MultiplePrivateStateRepository {
+	cache := state.NewDatabase(db)
+	psr, err := NewMultiplePrivateStateRepository(db, cache, rawdb.GetPrivateStatesTrieRoot(db, exportTestBlock.Root()), privatecache.NewPrivateCacheProvider(db, nil, cache, false))
+	assert.NoError(t, err)
+	return psr
+}
+
+// newExportTestTargetDatabase returns a database in which exportTestBlock is canonical, followed by another block
+func newExportTestTargetDatabase() ethdb.Database {
+	db := rawdb.NewMemoryDatabase()
+	for _, header := range []*types.Header{exportTestBlock.Header(), {Number: big.NewInt(2), ParentHash: exportTestBlock.Hash()}} {
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+	}
+	return db
+}
+
+func TestExportImportPrivateState(t *testing.T) {
+	sourceDB := rawdb.NewMemoryDatabase()
+	psr := newExportTestRepository(t, sourceDB)
+	privateState, _ := psr.StatePSI(exportTestPSI)
+	privateState.SetCode(exportTestContract, []byte{0x60, 0x80})
+	privateState.SetState(exportTestContract, common.HexToHash("0x01"), common.HexToHash("0x02"))
+	privateState.SetManagedParties(exportTestContract, []string{"AAA"})
+	otherState, _ := psr.StatePSI(types.PrivateStateIdentifier("psi2"))
+	otherState.SetNonce(common.HexToAddress("0x02"), 1)
+	assert.NoError(t, psr.CommitAndWrite(false, exportTestBlock))
+
+	var buf bytes.Buffer
+	assert.NoError(t, ExportPrivateState(sourceDB, exportTestBlock, exportTestPSI, &buf))
+
+	targetDB := newExportTestTargetDatabase()
+	header, err := ImportPrivateState(targetDB, []types.PrivateStateIdentifier{exportTestPSI}, bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	sourceRoot, _ := psr.PrivateStateRoot(exportTestPSI)
+	assert.Equal(t, sourceRoot, header.Root)
+
+	imported, err := newExportTestRepository(t, targetDB).StatePSI(exportTestPSI)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x60, 0x80}, imported.GetCode(exportTestContract))
+	assert.Equal(t, common.HexToHash("0x02"), imported.GetState(exportTestContract, common.HexToHash("0x01")))
+	managedParties, err := imported.GetManagedParties(exportTestContract)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AAA"}, managedParties)
+	root, _ := newExportTestRepository(t, targetDB).PrivateStateRoot(types.PrivateStateIdentifier("psi2"))
+	assert.Equal(t, common.Hash{}, root)
+
+	// importing the same state again is a no-op
+	_, err = ImportPrivateState(targetDB, []types.PrivateStateIdentifier{exportTestPSI}, bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+}
+
+func TestImportPrivateState_rejectsUnknownBlockAndNonResidentState(t *testing.T) {
+	sourceDB := rawdb.NewMemoryDatabase()
+	psr := newExportTestRepository(t, sourceDB)
+	privateState, _ := psr.StatePSI(exportTestPSI)
+	privateState.SetNonce(common.HexToAddress("0x02"), 1)
+	assert.NoError(t, psr.CommitAndWrite(false, exportTestBlock))
+
+	var buf bytes.Buffer
+	assert.NoError(t, ExportPrivateState(sourceDB, exportTestBlock, exportTestPSI, &buf))
+
+	_, err := ImportPrivateState(rawdb.NewMemoryDatabase(), []types.PrivateStateIdentifier{exportTestPSI}, bytes.NewReader(buf.Bytes()))
+	assert.EqualError(t, err, "private state was exported at block 1 ("+exportTestBlock.Hash().Hex()+") which is not a canonical block")
+
+	targetDB := newExportTestTargetDatabase()
+	_, err = ImportPrivateState(targetDB, []types.PrivateStateIdentifier{"psi2"}, bytes.NewReader(buf.Bytes()))
+	assert.EqualError(t, err, "private state psi1 is not the private state of a resident group")
+	assert.Equal(t, common.Hash{}, rawdb.GetPrivateStatesTrieRoot(targetDB, exportTestBlock.Root()))
+}
+
+func TestImportPrivateState_incompleteStream(t *testing.T) {
+	sourceDB := rawdb.NewMemoryDatabase()
+	psr := newExportTestRepository(t, sourceDB)
+	privateState, _ := psr.StatePSI(exportTestPSI)
+	for i := byte(0); i < 100; i++ {
+		privateState.SetNonce(common.BytesToAddress([]byte{i}), 1)
+	}
+	assert.NoError(t, psr.CommitAndWrite(false, exportTestBlock))
+
+	var buf bytes.Buffer
+	assert.NoError(t, ExportPrivateState(sourceDB, exportTestBlock, exportTestPSI, &buf))
+
+	// drop the last trie node of the stream
+	var (
+		stream  = rlp.NewStream(bytes.NewReader(buf.Bytes()), 0)
+		header  PrivateStateExportHeader
+		entries []privateStateExportEntry
+	)
+	assert.NoError(t, stream.Decode(&header))
+	for {
+		var entry privateStateExportEntry
+		if stream.Decode(&entry) != nil {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	var truncated bytes.Buffer
+	assert.NoError(t, rlp.Encode(&truncated, &header))
+	for _, entry := range entries[:len(entries)-1] {
+		assert.NoError(t, rlp.Encode(&truncated, &entry))
+	}
+
+	targetDB := newExportTestTargetDatabase()
+	_, err := ImportPrivateState(targetDB, []types.PrivateStateIdentifier{exportTestPSI}, &truncated)
+	assert.Error(t, err)
+	assert.Equal(t, common.Hash{}, rawdb.GetPrivateStatesTrieRoot(targetDB, exportTestBlock.Root()))
+
+	assert.EqualError(t, ExportPrivateState(sourceDB, exportTestBlock, types.PrivateStateIdentifier("other"), &buf), "private state other does not exist at block 1")
+}
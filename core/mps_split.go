@@ -0,0 +1,325 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/privatecache"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// splitStagedPrefix holds the receipts and private blooms of the split until the whole chain is verified
+	splitStagedPrefix = "mpsSplitStaged-"
+	// splitLegacyPrefix holds the receipts of the single private state replaced by the split
+	splitLegacyPrefix = "mpsSplitLegacy-"
+)
+
+// SplitPrivateState upgrades the database of a chain running with a single private state to
+// multiple private states. Unlike mps.UpgradeDB, which re-labels the single private state as the
+// "private" PSI, it builds one private state per resident group of the private transaction manager.
+//
+// The chain is replayed from genesis. Each private transaction is applied to the private states
+// of the resident groups its managed parties belong to, as a node running with multiple private
+// states from genesis would have done. Every replayed block is then verified, see verifySplitBlock.
+//
+// The receipts of the split are staged apart from the receipts of the single private state, which
+// they only replace once the whole chain has been split and verified. The replaced receipts are
+// backed up until ChainConfig.IsMPS is set, so an interrupted split can simply be started again.
+func SplitPrivateState(bc *BlockChain) error {
+	if bc.chainConfig.IsMPS {
+		return errors.New("the database is already upgraded to support multiple private states")
+	}
+	psm, err := newPrivateStateManager(bc.db, privatecache.NewPrivateCacheProvider(bc.db, nil, nil, false), true)
+	if err != nil {
+		return err
+	}
+	bc.SetPrivateStateManager(psm)
+
+	var (
+		current = bc.CurrentBlock().NumberU64()
+		parent  = bc.GetBlockByNumber(0)
+		triedb  = bc.stateCache.TrieDB()
+		// the tables are not backed by the ancient store, which holds the receipts of the single private state
+		staged = rawdb.NewDatabase(rawdb.NewTable(bc.db, splitStagedPrefix))
+		legacy = rawdb.NewDatabase(rawdb.NewTable(bc.db, splitLegacyPrefix))
+		// the single private state is read through its own cache, it is not shared with the split
+		legacyCache = state.NewDatabase(bc.db)
+		start       = time.Now()
+		logged      = time.Now()
+	)
+	log.Info("Splitting the private state", "privateStates", len(psm.PSIs()), "blocks", current)
+	for number := uint64(1); number <= current; number++ {
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block %d is missing from the database", number)
+		}
+		receipts, privateReceipts, err := bc.splitBlock(psm, parent, block)
+		if err != nil {
+			return fmt.Errorf("unable to split the private state at block %d: %v", number, err)
+		}
+		if err := bc.verifySplitBlock(psm, legacyCache, parent, block, receipts, legacySplitReceipts(bc.db, legacy, block)); err != nil {
+			return fmt.Errorf("verification of the private states at block %d failed: %v", number, err)
+		}
+		rawdb.WriteReceipts(staged, block.Hash(), number, receipts)
+		if err := rawdb.WritePrivateBlockBloom(staged, number, privateReceipts); err != nil {
+			return err
+		}
+		// the public state of the parent is not needed anymore
+		if parent.Root() != block.Root() {
+			triedb.Dereference(parent.Root())
+		}
+		parent = block
+		if time.Since(logged) > statsReportLimit {
+			log.Info("Splitting the private state", "number", number, "hash", block.Hash(), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := replaceSplitReceipts(bc, staged, legacy, current); err != nil {
+		return err
+	}
+
+	config := bc.chainConfig
+	config.IsMPS = true
+	rawdb.WriteChainConfig(bc.db, rawdb.ReadCanonicalHash(bc.db, 0), config)
+	deleteSplitReceipts(bc, staged, legacy, current)
+	log.Info("Split the private state", "privateStates", len(psm.PSIs()), "blocks", current, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// legacySplitReceipts returns the receipts of the block in the single private state, from their backup
+// if a previous split already replaced them
+func legacySplitReceipts(db, legacy ethdb.Database, block *types.Block) types.Receipts {
+	if receipts := rawdb.ReadRawReceipts(legacy, block.Hash(), block.NumberU64()); receipts != nil {
+		return receipts
+	}
+	return rawdb.ReadRawReceipts(db, block.Hash(), block.NumberU64())
+}
+
+// replaceSplitReceipts replaces the receipts and private blooms of the single private state with the
+// staged ones of the split, backing up the replaced receipts first
+func replaceSplitReceipts(bc *BlockChain, staged, legacy ethdb.Database, current uint64) error {
+	for number := uint64(1); number <= current; number++ {
+		hash := rawdb.ReadCanonicalHash(bc.db, number)
+		if !rawdb.HasReceipts(legacy, hash, number) {
+			rawdb.WriteReceipts(legacy, hash, number, rawdb.ReadRawReceipts(bc.db, hash, number))
+		}
+		batch := bc.db.NewBatch()
+		rawdb.WriteReceipts(batch, hash, number, rawdb.ReadRawReceipts(staged, hash, number))
+		if err := rawdb.WritePrivateBloom(batch, number, rawdb.GetPrivateBlockBloom(staged, number)); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSplitReceipts removes the staged receipts of the split and the backup of the receipts they replaced
+func deleteSplitReceipts(bc *BlockChain, staged, legacy ethdb.Database, current uint64) {
+	for number := uint64(1); number <= current; number++ {
+		hash := rawdb.ReadCanonicalHash(bc.db, number)
+		rawdb.DeleteReceipts(staged, hash, number)
+		rawdb.DeleteReceipts(legacy, hash, number)
+		if err := rawdb.DeletePrivateBlockBloom(staged, number); err != nil {
+			log.Warn("Failed to delete the staged private bloom of the split", "number", number, "err", err)
+		}
+	}
+}
+
+// splitBlock replays the block on top of the split private states of its parent and persists the
+// resulting private states. It returns the merged receipts of the block and its private receipts.
+func (bc *BlockChain) splitBlock(psm mps.PrivateStateManager, parent, block *types.Block) (types.Receipts, types.Receipts, error) {
+	statedb, err := state.New(parent.Root(), bc.stateCache, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateStateRepo, err := psm.StateRepository(parent.Root())
+	if err != nil {
+		return nil, nil, err
+	}
+	receipts, privateReceipts, _, usedGas, err := bc.processor.Process(block, statedb, privateStateRepo, bc.vmConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+		return nil, nil, err
+	}
+	root, err := statedb.Commit(bc.chainConfig.IsEIP158(block.Number()))
+	if err != nil {
+		return nil, nil, err
+	}
+	bc.stateCache.TrieDB().Reference(root, common.Hash{})
+	if err := privateStateRepo.CommitAndWrite(bc.chainConfig.IsEIP158(block.Number()), block); err != nil {
+		return nil, nil, err
+	}
+	return privateStateRepo.MergeReceipts(receipts, privateReceipts), privateReceipts, nil
+}
+
+// verifySplitBlock checks the split private states of the block:
+//
+//   - the block is executed again on top of the split private states of the parent, each resulting
+//     private state root must be the root persisted by the split
+//   - the accounts touched by the private transactions applied to a split private state, i.e. their
+//     recipients, the contracts they created and the contracts which emitted their logs, must have the
+//     same nonce, balance, code and storage in the single private state, which the split leaves untouched
+//   - a contract created in the single private state must exist in at least one split private state
+func (bc *BlockChain) verifySplitBlock(psm mps.PrivateStateManager, legacyCache state.Database, parent, block *types.Block, receipts, legacyReceipts types.Receipts) error {
+	privateStateRepo, err := psm.StateRepository(block.Root())
+	if err != nil {
+		return err
+	}
+	if err := bc.verifySplitRoots(psm, privateStateRepo, parent, block); err != nil {
+		return err
+	}
+	legacyState, err := state.New(rawdb.GetPrivateStateRoot(bc.db, block.Root()), legacyCache, nil)
+	if err != nil {
+		return err
+	}
+	legacyCreated := make(map[common.Address]struct{})
+	for i, tx := range block.Transactions() {
+		if (!tx.IsPrivate() && !tx.IsPrivacyMarker()) || i >= len(legacyReceipts) {
+			continue
+		}
+		for _, address := range createdContracts(legacyReceipts[i]) {
+			if legacyState.GetCodeSize(address) > 0 {
+				legacyCreated[address] = struct{}{}
+			}
+		}
+	}
+
+	created := make(map[common.Address]struct{})
+	for _, psi := range psm.PSIs() {
+		psiState, err := privateStateRepo.StatePSI(psi)
+		if err != nil {
+			return err
+		}
+		for i, tx := range block.Transactions() {
+			if (!tx.IsPrivate() && !tx.IsPrivacyMarker()) || i >= len(receipts) {
+				continue
+			}
+			psReceipt, ok := receipts[i].PSReceipts[psi]
+			if !ok {
+				continue
+			}
+			for _, address := range touchedAccounts(tx, psReceipt) {
+				if err := compareSplitAccount(psi, psiState, legacyState, address); err != nil {
+					return err
+				}
+			}
+			if psReceipt.ContractAddress != (common.Address{}) && psiState.GetCodeSize(psReceipt.ContractAddress) > 0 {
+				created[psReceipt.ContractAddress] = struct{}{}
+			}
+		}
+	}
+	for address := range legacyCreated {
+		if _, ok := created[address]; !ok {
+			return fmt.Errorf("contract %s was created in the single private state but in none of the split private states", address.Hex())
+		}
+	}
+	return nil
+}
+
+// verifySplitRoots executes the block again on top of the split private states of the parent and
+// compares the resulting private state roots with the ones of privateStateRepo
+func (bc *BlockChain) verifySplitRoots(psm mps.PrivateStateManager, privateStateRepo mps.PrivateStateRepository, parent, block *types.Block) error {
+	statedb, err := state.New(parent.Root(), bc.stateCache, nil)
+	if err != nil {
+		return err
+	}
+	replayRepo, err := psm.StateRepository(parent.Root())
+	if err != nil {
+		return err
+	}
+	if _, _, _, _, err := bc.processor.Process(block, statedb, replayRepo, bc.vmConfig); err != nil {
+		return err
+	}
+	isEIP158 := bc.chainConfig.IsEIP158(block.Number())
+	for _, psi := range append(psm.PSIs(), mps.EmptyPrivateStateMetadata.ID) {
+		replayed, err := replayRepo.StatePSI(psi)
+		if err != nil {
+			return err
+		}
+		split, err := privateStateRepo.StatePSI(psi)
+		if err != nil {
+			return err
+		}
+		if want, got := split.IntermediateRoot(isEIP158), replayed.IntermediateRoot(isEIP158); want != got {
+			return fmt.Errorf("root of private state %s is %s but executing the block again gives %s", psi, want.Hex(), got.Hex())
+		}
+	}
+	return nil
+}
+
+// touchedAccounts returns the recipient of the private transaction, the contract it created and the
+// contracts which emitted its logs according to its receipt in a private state
+func touchedAccounts(tx *types.Transaction, receipt *types.Receipt) []common.Address {
+	addresses := make([]common.Address, 0)
+	if tx.IsPrivate() && tx.To() != nil {
+		addresses = append(addresses, *tx.To())
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		addresses = append(addresses, receipt.ContractAddress)
+	}
+	for _, l := range receipt.Logs {
+		addresses = append(addresses, l.Address)
+	}
+	return addresses
+}
+
+// compareSplitAccount returns an error if the account differs between the split private state psi
+// and the single private state
+func compareSplitAccount(psi types.PrivateStateIdentifier, psiState, legacyState *state.StateDB, address common.Address) error {
+	if !psiState.Exist(address) {
+		return nil
+	}
+	if !legacyState.Exist(address) {
+		return fmt.Errorf("account %s of private state %s is not in the single private state", address.Hex(), psi)
+	}
+	diff := func(field string) error {
+		return fmt.Errorf("%s of account %s in private state %s differs from the single private state", field, address.Hex(), psi)
+	}
+	switch {
+	case psiState.GetNonce(address) != legacyState.GetNonce(address):
+		return diff("nonce")
+	case psiState.GetBalance(address).Cmp(legacyState.GetBalance(address)) != 0:
+		return diff("balance")
+	case psiState.GetCodeHash(address) != legacyState.GetCodeHash(address):
+		return diff("code")
+	}
+	root, err := psiState.GetStorageRoot(address)
+	if err != nil {
+		return err
+	}
+	legacyRoot, err := legacyState.GetStorageRoot(address)
+	if err != nil {
+		return err
+	}
+	if root != legacyRoot {
+		return diff("storage")
+	}
+	return nil
+}
+
+// createdContracts returns the addresses of the contracts created according to the receipt, including the
+// receipts of the private transactions of a privacy marker transaction
+func createdContracts(receipt *types.Receipt) []common.Address {
+	addresses := make([]common.Address, 0)
+	if receipt.ContractAddress != (common.Address{}) {
+		addresses = append(addresses, receipt.ContractAddress)
+	}
+	for _, psReceipt := range receipt.PSReceipts {
+		if psReceipt != nil && psReceipt.ContractAddress != (common.Address{}) {
+			addresses = append(addresses, psReceipt.ContractAddress)
+		}
+	}
+	return addresses
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/mps"
@@ -11,6 +13,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -21,6 +25,7 @@ type MultiplePrivateStateManager struct {
 	privateStatesTrieCache state.Database
 	privateCacheProvider   privatecache.Provider
 
+	mu                 sync.RWMutex // Protects the groups below, which can be refreshed while the node runs
 	residentGroupByKey map[string]*mps.PrivateStateMetadata
 	privacyGroupById   map[types.PrivateStateIdentifier]*mps.PrivateStateMetadata
 }
@@ -41,6 +46,8 @@ func (m *MultiplePrivateStateManager) StateRepository(blockHash common.Hash) (mp
 }
 
 func (m *MultiplePrivateStateManager) ResolveForManagedParty(managedParty string) (*mps.PrivateStateMetadata, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	psm, found := m.residentGroupByKey[managedParty]
 	if !found {
 		return nil, fmt.Errorf("unable to find private state metadata for managed party %s", managedParty)
@@ -53,6 +60,8 @@ func (m *MultiplePrivateStateManager) ResolveForUserContext(ctx context.Context)
 	if !ok {
 		psi = types.DefaultPrivateStateIdentifier
 	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	psm, found := m.privacyGroupById[psi]
 	if !found {
 		return nil, fmt.Errorf("unable to find private state for context psi %s", psi)
@@ -61,6 +70,8 @@ func (m *MultiplePrivateStateManager) ResolveForUserContext(ctx context.Context)
 }
 
 func (m *MultiplePrivateStateManager) PSIs() []types.PrivateStateIdentifier {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	psis := make([]types.PrivateStateIdentifier, 0, len(m.privacyGroupById))
 	for psi := range m.privacyGroupById {
 		psis = append(psis, psi)
@@ -80,3 +91,45 @@ func (m *MultiplePrivateStateManager) CheckAt(root common.Hash) error {
 func (m *MultiplePrivateStateManager) TrieDB() *trie.Database {
 	return m.privateStatesTrieCache.TrieDB()
 }
+
+// RefreshResidentGroups registers the privacy groups which have been added to the transaction
+// manager since the node started, as well as new members of the existing resident groups.
+// The private state of a new group branches from the empty private state the first time
+// it is used. It returns the identifiers of the new private states.
+//
+// Removing a privacy group, or a member of a resident group, is refused as it would orphan
+// an existing private state. The groups in use are left untouched in that case.
+func (m *MultiplePrivateStateManager) RefreshResidentGroups(groups []engine.PrivacyGroup) ([]types.PrivateStateIdentifier, error) {
+	residentGroupByKey, privacyGroupById, err := privateStateMetadataFromGroups(groups)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for psi, existing := range m.privacyGroupById {
+		if _, found := privacyGroupById[psi]; !found {
+			return nil, fmt.Errorf("removing private states is not supported: privacy group %s (%s) is no longer known by the transaction manager", existing.Name, psi)
+		}
+	}
+	for key, existing := range m.residentGroupByKey {
+		updated, found := residentGroupByKey[key]
+		if !found {
+			return nil, fmt.Errorf("removing members of resident groups is not supported: address=%s group.Name=%s", key, existing.Name)
+		}
+		if updated.ID != existing.ID {
+			return nil, fmt.Errorf("reassigning members of resident groups is not supported: address=%s existing.Name=%s new.Name=%s", key, existing.Name, updated.Name)
+		}
+	}
+	added := make([]types.PrivateStateIdentifier, 0)
+	for psi, metadata := range privacyGroupById {
+		if _, found := m.privacyGroupById[psi]; !found {
+			log.Info("Registering new private state", "psi", psi, "name", metadata.Name)
+			added = append(added, psi)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i] < added[j] })
+	m.residentGroupByKey = residentGroupByKey
+	m.privacyGroupById = privacyGroupById
+	return added, nil
+}
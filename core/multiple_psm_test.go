@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -271,6 +272,206 @@ func TestPrivateStateMetadataResolver(t *testing.T) {
 	assert.Contains(t, mpsm.PSIs(), types.PrivateStateIdentifier("LEGACY1"))
 }
 
+func TestMultiplePrivateStateManager_RefreshResidentGroups(t *testing.T) {
+	residentGroupByKey, privacyGroupById, err := privateStateMetadataFromGroups(PrivacyGroups)
+	assert.NoError(t, err)
+	mpsm, err := newMultiplePrivateStateManager(rawdb.NewMemoryDatabase(), privatecache.NewPrivateCacheProvider(rawdb.NewMemoryDatabase(), nil, nil, false), residentGroupByKey, privacyGroupById)
+	assert.NoError(t, err)
+
+	rg3 := engine.PrivacyGroup{
+		Type:           "RESIDENT",
+		Name:           "RG3",
+		PrivacyGroupId: base64.StdEncoding.EncodeToString([]byte("RG3")),
+		Description:    "Resident Group 3",
+		Members:        []string{"EEE"},
+	}
+	groups := append(append([]engine.PrivacyGroup{}, PrivacyGroups...), rg3)
+	groups[0].Members = []string{"AAA", "BBB", "FFF"}
+
+	added, err := mpsm.RefreshResidentGroups(groups)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PrivateStateIdentifier{"RG3"}, added)
+	assert.Contains(t, mpsm.PSIs(), types.PrivateStateIdentifier("RG3"))
+	psm, err := mpsm.ResolveForManagedParty("EEE")
+	assert.NoError(t, err)
+	assert.Equal(t, types.PrivateStateIdentifier("RG3"), psm.ID)
+	psm, err = mpsm.ResolveForManagedParty("FFF")
+	assert.NoError(t, err)
+	assert.Equal(t, types.PrivateStateIdentifier("RG1"), psm.ID)
+
+	added, err = mpsm.RefreshResidentGroups(groups)
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+}
+
+func TestMultiplePrivateStateManager_RefreshResidentGroups_refusesRemovalAndReassignment(t *testing.T) {
+	residentGroupByKey, privacyGroupById, err := privateStateMetadataFromGroups(PrivacyGroups)
+	assert.NoError(t, err)
+	mpsm, err := newMultiplePrivateStateManager(rawdb.NewMemoryDatabase(), privatecache.NewPrivateCacheProvider(rawdb.NewMemoryDatabase(), nil, nil, false), residentGroupByKey, privacyGroupById)
+	assert.NoError(t, err)
+
+	_, err = mpsm.RefreshResidentGroups(PrivacyGroups[1:])
+	assert.EqualError(t, err, "removing private states is not supported: privacy group RG1 (RG1) is no longer known by the transaction manager")
+
+	groups := append([]engine.PrivacyGroup{}, PrivacyGroups...)
+	groups[0].Members = []string{"AAA"}
+	groups[1].Members = []string{"BBB", "CCC", "DDD"}
+	_, err = mpsm.RefreshResidentGroups(groups)
+	assert.EqualError(t, err, "reassigning members of resident groups is not supported: address=BBB existing.Name=RG1 new.Name=RG2")
+
+	groups[1].Members = []string{"CCC", "DDD"}
+	_, err = mpsm.RefreshResidentGroups(groups)
+	assert.EqualError(t, err, "removing members of resident groups is not supported: address=BBB group.Name=RG1")
+
+	// the groups in use are kept
+	psm, err := mpsm.ResolveForManagedParty("BBB")
+	assert.NoError(t, err)
+	assert.Equal(t, types.PrivateStateIdentifier("RG1"), psm.ID)
+	assert.Len(t, mpsm.PSIs(), 3)
+}
+
+func TestResidentPrivateStateIdentifiers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+
+	saved := private.P
+	defer func() {
+		private.P = saved
+	}()
+	private.P = mockptm
+
+	mockptm.EXPECT().Groups().Return(PrivacyGroups, nil)
+
+	psis, err := ResidentPrivateStateIdentifiers()
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []types.PrivateStateIdentifier{"RG1", "RG2"}, psis)
+}
+
+func TestSplitPrivateState(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+
+	saved := private.P
+	defer func() {
+		private.P = saved
+	}()
+	private.P = mockptm
+
+	mockptm.EXPECT().Receive(gomock.Not(common.EncryptedPayloadHash{})).Return("", []string{"AAA"}, common.FromHex(testCode), nil, nil).AnyTimes()
+	mockptm.EXPECT().Receive(common.EncryptedPayloadHash{}).Return("", []string{}, common.EncryptedPayloadHash{}.Bytes(), nil, nil).AnyTimes()
+	mockptm.EXPECT().HasFeature(engine.MultiplePrivateStates).Return(true)
+	mockptm.EXPECT().Groups().Return(PrivacyGroups, nil).AnyTimes()
+
+	config := *params.QuorumTestChainConfig
+	blocks, _, blockchain := buildTestChain(2, &config)
+	defer blockchain.Stop()
+	_, err := blockchain.InsertChain(blocks)
+	assert.NoError(t, err)
+
+	assert.NoError(t, SplitPrivateState(blockchain))
+	assert.True(t, rawdb.ReadChainConfig(blockchain.db, rawdb.ReadCanonicalHash(blockchain.db, 0)).IsMPS)
+
+	privateStateRepo, err := blockchain.PrivateStateManager().StateRepository(blocks[1].Root())
+	assert.NoError(t, err)
+	receipts := rawdb.ReadRawReceipts(blockchain.db, blocks[1].Hash(), blocks[1].NumberU64())
+	contractAddress := receipts[0].PSReceipts[types.ToPrivateStateIdentifier("RG1")].ContractAddress
+
+	rg1, err := privateStateRepo.StatePSI(types.ToPrivateStateIdentifier("RG1"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, rg1.GetCodeSize(contractAddress))
+	rg2, err := privateStateRepo.StatePSI(types.ToPrivateStateIdentifier("RG2"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rg2.GetCodeSize(contractAddress))
+	emptyState, err := privateStateRepo.DefaultState()
+	assert.NoError(t, err)
+	assert.True(t, emptyState.Exist(contractAddress))
+	assert.Equal(t, 0, emptyState.GetCodeSize(contractAddress))
+
+	assert.EqualError(t, SplitPrivateState(blockchain), "the database is already upgraded to support multiple private states")
+}
+
+func TestSplitPrivateState_whenVerificationFails(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockptm := private.NewMockPrivateTransactionManager(mockCtrl)
+
+	saved := private.P
+	defer func() {
+		private.P = saved
+	}()
+	private.P = mockptm
+
+	// the managed parties change after the first split of a block, so executing it again gives other roots
+	calls, stable := 0, true
+	mockptm.EXPECT().Receive(gomock.Not(common.EncryptedPayloadHash{})).DoAndReturn(func(common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error) {
+		calls++
+		if stable || calls == 1 {
+			return "", []string{"AAA"}, common.FromHex(testCode), nil, nil
+		}
+		return "", []string{"CCC"}, common.FromHex(testCode), nil, nil
+	}).AnyTimes()
+	mockptm.EXPECT().Receive(common.EncryptedPayloadHash{}).Return("", []string{}, common.EncryptedPayloadHash{}.Bytes(), nil, nil).AnyTimes()
+	mockptm.EXPECT().HasFeature(engine.MultiplePrivateStates).Return(true).AnyTimes()
+	mockptm.EXPECT().Groups().Return(PrivacyGroups, nil).AnyTimes()
+
+	config := *params.QuorumTestChainConfig
+	blocks, _, blockchain := buildTestChain(2, &config)
+	defer blockchain.Stop()
+	_, err := blockchain.InsertChain(blocks)
+	assert.NoError(t, err)
+	receipts := rawdb.ReadRawReceipts(blockchain.db, blocks[0].Hash(), blocks[0].NumberU64())
+	bloom := rawdb.GetPrivateBlockBloom(blockchain.db, blocks[0].NumberU64())
+
+	calls, stable = 0, false
+	err = SplitPrivateState(blockchain)
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "verification of the private states at block 1 failed: root of private state")
+	}
+	assert.False(t, rawdb.ReadChainConfig(blockchain.db, rawdb.ReadCanonicalHash(blockchain.db, 0)).IsMPS)
+	// the receipts of the single private state are left untouched
+	assert.Equal(t, receipts, rawdb.ReadRawReceipts(blockchain.db, blocks[0].Hash(), blocks[0].NumberU64()))
+	assert.Equal(t, bloom, rawdb.GetPrivateBlockBloom(blockchain.db, blocks[0].NumberU64()))
+
+	stable = true
+	assert.NoError(t, SplitPrivateState(blockchain))
+	splitReceipts := rawdb.ReadRawReceipts(blockchain.db, blocks[0].Hash(), blocks[0].NumberU64())
+	assert.Contains(t, splitReceipts[0].PSReceipts, types.ToPrivateStateIdentifier("RG1"))
+}
+
+func TestCompareSplitAccount(t *testing.T) {
+	address := common.HexToAddress("0x1")
+	newState := func() *state.StateDB {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		return statedb
+	}
+	psiState, legacyState := newState(), newState()
+
+	assert.NoError(t, compareSplitAccount("RG1", psiState, legacyState, address))
+
+	psiState.SetNonce(address, 1)
+	assert.EqualError(t, compareSplitAccount("RG1", psiState, legacyState, address), fmt.Sprintf("account %s of private state RG1 is not in the single private state", address.Hex()))
+
+	legacyState.SetNonce(address, 1)
+	psiState.SetState(address, common.Hash{1}, common.Hash{2})
+	psiState.IntermediateRoot(true)
+	legacyState.IntermediateRoot(true)
+	assert.EqualError(t, compareSplitAccount("RG1", psiState, legacyState, address), fmt.Sprintf("storage of account %s in private state RG1 differs from the single private state", address.Hex()))
+
+	legacyState.SetState(address, common.Hash{1}, common.Hash{2})
+	legacyState.IntermediateRoot(true)
+	assert.NoError(t, compareSplitAccount("RG1", psiState, legacyState, address))
+
+	legacyState.AddBalance(address, big.NewInt(1))
+	assert.EqualError(t, compareSplitAccount("RG1", psiState, legacyState, address), fmt.Sprintf("balance of account %s in private state RG1 differs from the single private state", address.Hex()))
+}
+
 var PSI1PSM = mps.PrivateStateMetadata{
 	ID:          "psi1",
 	Name:        "psi1",
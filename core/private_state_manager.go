@@ -27,32 +27,9 @@ func newPrivateStateManager(db ethdb.Database, privateCacheProvider privatecache
 		if err != nil {
 			return nil, err
 		}
-		residentGroupByKey := make(map[string]*mps.PrivateStateMetadata)
-		privacyGroupById := make(map[types.PrivateStateIdentifier]*mps.PrivateStateMetadata)
-		for _, group := range groups {
-			if group.Type == engine.PrivacyGroupResident {
-				// Resident group IDs come in base64 encoded, so revert to original ID
-				decoded, err := base64.StdEncoding.DecodeString(group.PrivacyGroupId)
-				if err != nil {
-					return nil, err
-				}
-				group.PrivacyGroupId = string(decoded)
-			}
-			psi := types.ToPrivateStateIdentifier(group.PrivacyGroupId)
-			existing, found := privacyGroupById[psi]
-			if found {
-				return nil, fmt.Errorf("privacy groups id clash id=%s existing.Name=%s duplicate.Name=%s", existing.ID, existing.Name, group.Name)
-			}
-			privacyGroupById[psi] = privacyGroupToPrivateStateMetadata(group)
-			if group.Type == engine.PrivacyGroupResident {
-				for _, address := range group.Members {
-					existing, found := residentGroupByKey[address]
-					if found {
-						return nil, fmt.Errorf("same address is part of two different groups: address=%s existing.Name=%s duplicate.Name=%s", address, existing.Name, group.Name)
-					}
-					residentGroupByKey[address] = privacyGroupToPrivateStateMetadata(group)
-				}
-			}
+		residentGroupByKey, privacyGroupById, err := privateStateMetadataFromGroups(groups)
+		if err != nil {
+			return nil, err
 		}
 		return newMultiplePrivateStateManager(db, privateCacheProvider, residentGroupByKey, privacyGroupById)
 	} else {
@@ -60,6 +37,62 @@ func newPrivateStateManager(db ethdb.Database, privateCacheProvider privatecache
 	}
 }
 
+// ResidentPrivateStateIdentifiers returns the identifiers of the private states of the resident groups
+// of the private transaction manager
+func ResidentPrivateStateIdentifiers() ([]types.PrivateStateIdentifier, error) {
+	groups, err := private.P.Groups()
+	if err != nil {
+		return nil, err
+	}
+	residentGroupByKey, _, err := privateStateMetadataFromGroups(groups)
+	if err != nil {
+		return nil, err
+	}
+	psis := make([]types.PrivateStateIdentifier, 0)
+	seen := make(map[types.PrivateStateIdentifier]struct{})
+	for _, metadata := range residentGroupByKey {
+		if _, ok := seen[metadata.ID]; ok {
+			continue
+		}
+		seen[metadata.ID] = struct{}{}
+		psis = append(psis, metadata.ID)
+	}
+	return psis, nil
+}
+
+// privateStateMetadataFromGroups indexes the private state metadata of the given privacy groups
+// by resident key and by private state identifier
+func privateStateMetadataFromGroups(groups []engine.PrivacyGroup) (map[string]*mps.PrivateStateMetadata, map[types.PrivateStateIdentifier]*mps.PrivateStateMetadata, error) {
+	residentGroupByKey := make(map[string]*mps.PrivateStateMetadata)
+	privacyGroupById := make(map[types.PrivateStateIdentifier]*mps.PrivateStateMetadata)
+	for _, group := range groups {
+		if group.Type == engine.PrivacyGroupResident {
+			// Resident group IDs come in base64 encoded, so revert to original ID
+			decoded, err := base64.StdEncoding.DecodeString(group.PrivacyGroupId)
+			if err != nil {
+				return nil, nil, err
+			}
+			group.PrivacyGroupId = string(decoded)
+		}
+		psi := types.ToPrivateStateIdentifier(group.PrivacyGroupId)
+		existing, found := privacyGroupById[psi]
+		if found {
+			return nil, nil, fmt.Errorf("privacy groups id clash id=%s existing.Name=%s duplicate.Name=%s", existing.ID, existing.Name, group.Name)
+		}
+		privacyGroupById[psi] = privacyGroupToPrivateStateMetadata(group)
+		if group.Type == engine.PrivacyGroupResident {
+			for _, address := range group.Members {
+				existing, found := residentGroupByKey[address]
+				if found {
+					return nil, nil, fmt.Errorf("same address is part of two different groups: address=%s existing.Name=%s duplicate.Name=%s", address, existing.Name, group.Name)
+				}
+				residentGroupByKey[address] = privacyGroupToPrivateStateMetadata(group)
+			}
+		}
+	}
+	return residentGroupByKey, privacyGroupById, nil
+}
+
 func privacyGroupToPrivateStateMetadata(group engine.PrivacyGroup) *mps.PrivateStateMetadata {
 	return mps.NewPrivateStateMetadata(
 		types.ToPrivateStateIdentifier(group.PrivacyGroupId),
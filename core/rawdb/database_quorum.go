@@ -81,8 +81,17 @@ func WriteRootHashMapping(db ethdb.KeyValueWriter, stateRoot, extraDataRoot comm
 // WritePrivateBlockBloom creates a bloom filter for the given receipts and saves it to the database
 // with the number given as identifier (i.e. block number).
 func WritePrivateBlockBloom(db ethdb.Database, number uint64, receipts types.Receipts) error {
-	rbloom := types.CreateBloom(receipts.Flatten())
-	return db.Put(append(privateBloomPrefix, encodeBlockNumber(number)...), rbloom[:])
+	return WritePrivateBloom(db, number, types.CreateBloom(receipts.Flatten()))
+}
+
+// WritePrivateBloom saves the private bloom of the block with the given number
+func WritePrivateBloom(db ethdb.KeyValueWriter, number uint64, bloom types.Bloom) error {
+	return db.Put(append(privateBloomPrefix, encodeBlockNumber(number)...), bloom[:])
+}
+
+// DeletePrivateBlockBloom removes the private bloom of the block with the given number
+func DeletePrivateBlockBloom(db ethdb.KeyValueWriter, number uint64) error {
+	return db.Delete(append(privateBloomPrefix, encodeBlockNumber(number)...))
 }
 
 // GetPrivateBlockBloom retrieves the private bloom associated with the given number.
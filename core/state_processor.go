@@ -19,6 +19,7 @@ package core
 import (
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
@@ -105,8 +106,8 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, pri
 		privateStateDBToUse := PrivateStateDBForTxn(p.config.IsQuorum, tx, statedb, privateStateDB)
 
 		// Quorum - check for account permissions to execute the transaction
-		if core.IsV2Permission() {
-			if err := core.CheckAccountPermission(tx.From(), tx.To(), tx.Value(), tx.Data(), tx.Gas(), tx.GasPrice()); err != nil {
+		if isV2PermissionAt(p.config, header.Number) {
+			if err := core.CheckTransactionPermission(tx, p.config.IsCallPermissionsEnabled(header.Number)); err != nil {
 				return nil, nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
 			}
 		}
@@ -202,6 +203,17 @@ func PrivateStateDBForTxn(isQuorum bool, tx *types.Transaction, stateDb, private
 	return stateDb
 }
 
+// Quorum
+// returns true if the transactions of the block number are validated against the v2 permissions
+// model. A v1 network migrated to v2 is validated against v2 from the migration transition of the
+// chain config on, the same at every node.
+func isV2PermissionAt(config *params.ChainConfig, num *big.Int) bool {
+	if config.V2PermissionMigrationBlock() != nil {
+		return config.IsV2PermissionMigrated(num)
+	}
+	return core.IsV2Permission()
+}
+
 // Quorum
 // handling MPS scenario for a private transaction
 //
@@ -404,8 +416,8 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	// End Quorum
 
 	// Quorum - check for account permissions to execute the transaction
-	if core.IsV2Permission() {
-		if err := core.CheckAccountPermission(tx.From(), tx.To(), tx.Value(), tx.Data(), tx.Gas(), tx.GasPrice()); err != nil {
+	if isV2PermissionAt(config, header.Number) {
+		if err := core.CheckTransactionPermission(tx, config.IsCallPermissionsEnabled(header.Number)); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
 	"github.com/ethereum/go-ethereum/trie"
 	"golang.org/x/crypto/sha3"
 )
@@ -156,3 +157,34 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	// Assemble and return the final block for sealing
 	return types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
 }
+
+// Quorum
+func TestIsV2PermissionAt(t *testing.T) {
+	defer func(model pcore.PermissionModelType) {
+		pcore.PermissionModel = model
+	}(pcore.PermissionModel)
+	migrated := true
+	config := *params.QuorumTestChainConfig
+	config.Transitions = []params.Transition{{Block: big.NewInt(10), V2PermissionMigrated: &migrated}}
+
+	// the migration transition decides, not the model the node switched to
+	for _, model := range []pcore.PermissionModelType{pcore.V1, pcore.V2} {
+		pcore.PermissionModel = model
+		if isV2PermissionAt(&config, big.NewInt(9)) {
+			t.Errorf("model %v: block 9 validated against the v2 permissions model", model)
+		}
+		if !isV2PermissionAt(&config, big.NewInt(10)) {
+			t.Errorf("model %v: block 10 not validated against the v2 permissions model", model)
+		}
+	}
+
+	// without migration the model of the node decides
+	pcore.PermissionModel = pcore.V2
+	if !isV2PermissionAt(params.QuorumTestChainConfig, big.NewInt(9)) {
+		t.Error("block 9 not validated against the v2 permissions model")
+	}
+	pcore.PermissionModel = pcore.V1
+	if isV2PermissionAt(params.QuorumTestChainConfig, big.NewInt(9)) {
+		t.Error("block 9 validated against the v2 permissions model")
+	}
+}
@@ -591,8 +591,10 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		if tx.IsPrivate() && (len(tx.Data()) == 0 || tx.Value().Sign() != 0) {
 			return ErrEtherValueUnsupported
 		}
-		// Quorum - check if the sender account is authorized to perform the transaction
-		if err := pcore.CheckAccountPermission(tx.From(), tx.To(), tx.Value(), tx.Data(), tx.Gas(), tx.GasPrice()); err != nil {
+		// Quorum - check if the sender account is authorized to perform the transaction in the next block
+		nextBlock := new(big.Int).Add(pool.chain.CurrentBlock().Number(), common.Big1)
+		callPermissionsEnabled := isV2PermissionAt(pool.chainconfig, nextBlock) && pool.chainconfig.IsCallPermissionsEnabled(nextBlock)
+		if err := pcore.CheckTransactionPermission(tx, callPermissionsEnabled); err != nil {
 			return err
 		}
 	}
@@ -31,10 +31,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/mps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/multitenancy"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/private/cache"
+	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
@@ -201,6 +206,52 @@ func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	return true
 }
 
+// Quorum
+// PurgePrivatePayloadCache removes all decrypted private payloads from the persistent cache
+// and returns the number of removed payloads.
+func (api *PrivateAdminAPI) PurgePrivatePayloadCache() (int, error) {
+	if api.eth.privatePayloadCache == nil {
+		return 0, cache.ErrPersistentCacheDisabled
+	}
+	return api.eth.privatePayloadCache.Purge()
+}
+
+// Quorum
+// RefreshPrivateTransactionManager detects the version and features of the private transaction manager again,
+// e.g. after it has been upgraded. Features the chain config depends on cannot be lost, the other features
+// detected before can only be lost if allowDowngrade is set.
+func (api *PrivateAdminAPI) RefreshPrivateTransactionManager(allowDowngrade *bool) (*engine.FeatureUpdate, error) {
+	return api.eth.RefreshPrivateTransactionManager(allowDowngrade != nil && *allowDowngrade)
+}
+
+// Quorum
+// RefreshResidentGroups registers the privacy groups added to the private transaction manager since the node started,
+// returning the identifiers of the new private states. Removing or reassigning existing resident keys is refused.
+func (api *PrivateAdminAPI) RefreshResidentGroups() ([]types.PrivateStateIdentifier, error) {
+	return api.eth.RefreshResidentGroups()
+}
+
+// Quorum
+// ReloadPrivateTransactionManagerTLS reloads the TLS material of the connection to the private transaction manager,
+// e.g. after certificates have been rotated. If the material cannot be loaded the previous material is kept.
+func (api *PrivateAdminAPI) ReloadPrivateTransactionManagerTLS() (bool, error) {
+	if err := private.ReloadTLS(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Quorum
+// CheckPrivatePayloads reports the private transactions from first to last (defaults to the current head)
+// whose payload the private transaction manager should have but is not able to return.
+func (api *PrivateAdminAPI) CheckPrivatePayloads(first uint64, last *uint64) (*private.MissingPayloadReport, error) {
+	if last == nil {
+		head := api.eth.BlockChain().CurrentBlock().NumberU64()
+		last = &head
+	}
+	return private.FindMissingPayloads(api.eth.BlockChain(), private.P, first, *last)
+}
+
 // ImportChain imports a blockchain from a local file.
 func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	// Make sure the can access the file to import
@@ -563,6 +614,94 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 
 // Quorum
 
+// MaxPrivateStateDiffBlockRange is the maximum number of blocks debug_privateStateDiff scans
+// for the private transactions which changed the private state
+const MaxPrivateStateDiffBlockRange = 10000
+
+// PrivateStateDiff reports the accounts of the private state identified by psi which changed between
+// fromBlock and toBlock: balance, nonce, code hash, storage slots and privacy metadata. Each change is
+// attributed to the private transactions of the range which touched the account.
+//
+// When multitenancy is enabled, the caller must be granted the private state and only the contracts
+// it is allowed to read are reported.
+func (api *PrivateDebugAPI) PrivateStateDiff(ctx context.Context, psi types.PrivateStateIdentifier, fromBlock, toBlock rpc.BlockNumber) (*mps.PrivateStateDiff, error) {
+	token, isMultitenant := api.eth.APIBackend.SupportsMultitenancy(ctx)
+	if isMultitenant {
+		if isAuthorized, err := multitenancy.IsPSIAuthorized(token, psi); err != nil {
+			return nil, err
+		} else if !isAuthorized {
+			return nil, multitenancy.ErrNotAuthorized
+		}
+	}
+	if !containsPSI(api.eth.blockchain.PrivateStateManager().PSIs(), psi) {
+		return nil, fmt.Errorf("private state %s not found", psi)
+	}
+	from, err := api.blockByNumber(fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.blockByNumber(toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from.NumberU64() > to.NumberU64() {
+		return nil, fmt.Errorf("invalid block range %d-%d", from.NumberU64(), to.NumberU64())
+	}
+	if to.NumberU64()-from.NumberU64() > MaxPrivateStateDiffBlockRange {
+		return nil, fmt.Errorf("block range exceeds the maximum of %d blocks", MaxPrivateStateDiffBlockRange)
+	}
+	_, fromState, err := api.eth.blockchain.StateAtPSI(from.Root(), psi)
+	if err != nil {
+		return nil, err
+	}
+	_, toState, err := api.eth.blockchain.StateAtPSI(to.Root(), psi)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := mps.DiffPrivateState(api.eth.ChainDb(), psi, from, to, fromState, toState)
+	if err != nil || !isMultitenant {
+		return diff, err
+	}
+	authorized := make([]*mps.AccountDiff, 0, len(diff.Accounts))
+	for _, account := range diff.Accounts {
+		if account.Address == nil {
+			continue
+		}
+		secAttr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(psi).WithContractRead(*account.Address)
+		if isAuthorized, _ := multitenancy.IsAuthorized(token, secAttr); isAuthorized {
+			authorized = append(authorized, account)
+		}
+	}
+	diff.Accounts = authorized
+	return diff, nil
+}
+
+func containsPSI(psis []types.PrivateStateIdentifier, psi types.PrivateStateIdentifier) bool {
+	for _, p := range psis {
+		if p == psi {
+			return true
+		}
+	}
+	return false
+}
+
+// blockByNumber returns the canonical block of the given number, pending blocks are not supported
+func (api *PrivateDebugAPI) blockByNumber(number rpc.BlockNumber) (*types.Block, error) {
+	var block *types.Block
+	switch number {
+	case rpc.PendingBlockNumber:
+		return nil, errors.New("pending block is not supported")
+	case rpc.LatestBlockNumber:
+		block = api.eth.blockchain.CurrentBlock()
+	default:
+		block = api.eth.blockchain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return block, nil
+}
+
 // StorageRoot returns the storage root of an account on the the given (optional) block height.
 // If block number is not given the latest block is used.
 func (s *PublicEthereumAPI) StorageRoot(ctx context.Context, addr common.Address, blockNr *rpc.BlockNumber) (common.Hash, error) {
@@ -206,6 +206,12 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	return EthAPIState{stateDb, privateState}, header, err
 }
 
+// Quorum
+// PrivateStateRepository returns the private states at the block of the header
+func (b *EthAPIBackend) PrivateStateRepository(ctx context.Context, header *types.Header) (mps.PrivateStateRepository, error) {
+	return b.eth.blockchain.PrivateStateManager().StateRepository(header.Root)
+}
+
 func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (vm.MinimalApiState, *types.Header, error) {
 	if blockNr, ok := blockNrOrHash.Number(); ok {
 		return b.StateAndHeaderByNumber(ctx, blockNr)
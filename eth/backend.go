@@ -60,6 +60,8 @@ import (
 	"github.com/ethereum/go-ethereum/plugin"
 	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/private/cache"
+	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/qlight"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -109,6 +111,8 @@ type Ethereum struct {
 	qlightServerHandler             *handler
 	qlightP2pServer                 *p2p.Server
 	qlightTokenHolder               *qlight.TokenHolder
+	privatePayloadCache             *cache.Persistent
+	closePrivateTxManagerRefresh    chan struct{}
 }
 
 // New creates a new Ethereum object (including the
@@ -193,6 +197,14 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		// Quorum
 		qlightP2pServer:                 stack.QServer(),
 		consensusServicePendingLogsFeed: new(event.Feed),
+		closePrivateTxManagerRefresh:    make(chan struct{}),
+	}
+
+	// Quorum: keep decrypted private payloads across restarts if configured
+	if config.PrivatePayloadCache.Enabled {
+		if eth.privatePayloadCache, err = openPrivatePayloadCache(stack, chainDb, config.PrivatePayloadCache); err != nil {
+			return nil, err
+		}
 	}
 
 	// Quorum: Set protocol Name/Version
@@ -820,6 +832,14 @@ func (s *Ethereum) Start() error {
 		}
 	}
 
+	// Quorum
+	if s.config.PrivateTxManagerRefreshInterval > 0 {
+		go s.privateTxManagerRefreshLoop(s.config.PrivateTxManagerRefreshInterval)
+	}
+	if s.config.ResidentGroupsRefreshInterval > 0 {
+		go s.residentGroupsRefreshLoop(s.config.ResidentGroupsRefreshInterval)
+	}
+
 	return nil
 }
 
@@ -841,10 +861,15 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	close(s.closePrivateTxManagerRefresh) // Quorum
+	private.Close()                       // Quorum
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.blockchain.Stop()
 	s.engine.Close()
+	if s.privatePayloadCache != nil {
+		s.privatePayloadCache.Close()
+	}
 	rawdb.PopUncleanShutdownMarker(s.chainDb)
 	s.chainDb.Close()
 	s.eventMux.Stop()
@@ -887,3 +912,109 @@ func (s *Ethereum) NotifyRegisteredPluginService(pluginManager *plugin.PluginMan
 	}
 	return nil
 }
+
+// Quorum
+// name of the file in the data directory holding the key used to encrypt the persistent payload cache
+const privatePayloadCacheKeyFile = "ptmcache.key"
+
+// openPrivatePayloadCache opens the persistent cache of decrypted private payloads and hands it to the
+// private transaction manager. The payloads are encrypted using a key kept in the node's data directory.
+func openPrivatePayloadCache(stack *node.Node, db ethdb.KeyValueStore, config cache.PersistentConfig) (*cache.Persistent, error) {
+	cacheSetter, ok := private.P.(private.HasPersistentCache)
+	if !ok {
+		log.Warn("Private transaction manager does not support a persistent payload cache")
+		return nil, nil
+	}
+	key, err := cache.LoadOrCreatePersistentKey(stack.ResolvePath(privatePayloadCacheKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	persistentCache, err := cache.NewPersistent(db, key, config)
+	if err != nil {
+		return nil, err
+	}
+	cacheSetter.SetPersistentCache(persistentCache)
+	return persistentCache, nil
+}
+
+// Quorum
+// requiredPrivateTxManagerFeatures returns the features of the private transaction manager the chain config
+// and the node config depend on, they must not be lost when the features are detected again
+func (s *Ethereum) requiredPrivateTxManagerFeatures() []engine.PrivateTransactionManagerFeature {
+	chainConfig := s.blockchain.Config()
+	required := make([]engine.PrivateTransactionManagerFeature, 0)
+	privacyEnhancements := chainConfig.PrivacyEnhancementsBlock != nil
+	for _, transition := range chainConfig.Transitions {
+		if transition.PrivacyEnhancementsEnabled != nil && *transition.PrivacyEnhancementsEnabled {
+			privacyEnhancements = true
+		}
+	}
+	if privacyEnhancements {
+		required = append(required, engine.PrivacyEnhancements)
+	}
+	if chainConfig.IsMPS {
+		required = append(required, engine.MultiplePrivateStates)
+	}
+	if s.config.QuorumChainConfig.MultiTenantEnabled() {
+		required = append(required, engine.MultiTenancy)
+	}
+	return required
+}
+
+// RefreshPrivateTransactionManager detects the version and features of the private transaction manager again,
+// refusing any downgrade of the features the node depends on, and of the other features unless allowDowngrade is set
+func (s *Ethereum) RefreshPrivateTransactionManager(allowDowngrade bool) (*engine.FeatureUpdate, error) {
+	return private.Refresh(allowDowngrade, s.requiredPrivateTxManagerFeatures()...)
+}
+
+func (s *Ethereum) privateTxManagerRefreshLoop(interval time.Duration) {
+	if _, ok := private.P.(private.Refreshable); !ok {
+		log.Warn("Private transaction manager does not support detecting its features again, periodic refresh disabled")
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.RefreshPrivateTransactionManager(false); err != nil {
+				log.Warn("Unable to refresh the private transaction manager features", "err", err)
+			}
+		case <-s.closePrivateTxManagerRefresh:
+			return
+		}
+	}
+}
+
+// RefreshResidentGroups registers the privacy groups which have been added to the private transaction manager
+// since the node started, refusing any removal or reassignment of the existing resident keys
+func (s *Ethereum) RefreshResidentGroups() ([]types.PrivateStateIdentifier, error) {
+	psm, ok := s.blockchain.PrivateStateManager().(*core.MultiplePrivateStateManager)
+	if !ok {
+		return nil, errors.New("resident groups can only be refreshed when multiple private states are enabled")
+	}
+	groups, err := private.P.Groups()
+	if err != nil {
+		return nil, err
+	}
+	return psm.RefreshResidentGroups(groups)
+}
+
+func (s *Ethereum) residentGroupsRefreshLoop(interval time.Duration) {
+	if !s.blockchain.Config().IsMPS {
+		log.Warn("Multiple private states are not enabled, periodic refresh of the resident groups disabled")
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.RefreshResidentGroups(); err != nil {
+				log.Warn("Unable to refresh the resident groups", "err", err)
+			}
+		case <-s.closePrivateTxManagerRefresh:
+			return
+		}
+	}
+}
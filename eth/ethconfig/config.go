@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private/cache"
 )
 
 // FullNodeGPO contains default gasprice oracle settings for full node.
@@ -93,7 +94,8 @@ var Defaults = Config{
 	RPCTxFeeCap: 1, // 1 ether
 
 	// Quorum
-	Istanbul: *istanbul.DefaultConfig, // Quorum
+	Istanbul:            *istanbul.DefaultConfig, // Quorum
+	PrivatePayloadCache: cache.DefaultPersistentConfig,
 }
 
 func init() {
@@ -226,6 +228,15 @@ type Config struct {
 	// QuorumLight
 	QuorumLightServer bool               `toml:",omitempty"`
 	QuorumLightClient *QuorumLightClient `toml:",omitempty"`
+
+	// Persistent cache of decrypted private payloads
+	PrivatePayloadCache cache.PersistentConfig `toml:",omitempty"`
+
+	// Interval between detections of the private transaction manager features, zero disables it
+	PrivateTxManagerRefreshInterval time.Duration `toml:",omitempty"`
+
+	// Interval between refreshes of the resident groups of multiple private states, zero disables it
+	ResidentGroupsRefreshInterval time.Duration `toml:",omitempty"`
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain configuration.
@@ -30,7 +30,9 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/multitenancy"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 )
 
 // filter is a helper struct that holds meta information over the filter type
@@ -253,6 +255,7 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 		return nil, err
 	}
 	crit.PSI = psm.ID
+	authorizedLogs := api.authorizedLogs(ctx, psm.ID)
 
 	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), matchedLogs)
 	if err != nil {
@@ -263,7 +266,7 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 		for {
 			select {
 			case logs := <-matchedLogs:
-				for _, log := range logs {
+				for _, log := range authorizedLogs(logs) {
 					notifier.Notify(rpcSub.ID, &log)
 				}
 			case <-rpcSub.Err(): // client send an unsubscribe request
@@ -279,6 +282,47 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 	return rpcSub, nil
 }
 
+// Quorum
+// multitenancyBackend is implemented by the backends supporting multitenancy
+type multitenancyBackend interface {
+	SupportsMultitenancy(rpcCtx context.Context) (*proto.PreAuthenticatedAuthenticationToken, bool)
+}
+
+// Quorum
+// authorizedLogs returns a function dropping the logs emitted by contracts the tenant is not
+// allowed to read, according to the contract scopes granted in the access token
+func (api *PublicFilterAPI) authorizedLogs(ctx context.Context, psi types.PrivateStateIdentifier) func([]*types.Log) []*types.Log {
+	all := func(logs []*types.Log) []*types.Log { return logs }
+	backend, ok := api.backend.(multitenancyBackend)
+	if !ok {
+		return all
+	}
+	token, ok := backend.SupportsMultitenancy(ctx)
+	if !ok {
+		return all
+	}
+	var (
+		mu         sync.Mutex
+		authorized = make(map[common.Address]bool)
+	)
+	return func(logs []*types.Log) []*types.Log {
+		mu.Lock()
+		defer mu.Unlock()
+		filtered := make([]*types.Log, 0, len(logs))
+		for _, log := range logs {
+			isAuthorized, found := authorized[log.Address]
+			if !found {
+				isAuthorized, _ = multitenancy.IsAuthorized(token, (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(psi).WithContractRead(log.Address))
+				authorized[log.Address] = isAuthorized
+			}
+			if isAuthorized {
+				filtered = append(filtered, log)
+			}
+		}
+		return filtered
+	}
+}
+
 // FilterCriteria represents a request to create a new filter.
 // Same as ethereum.FilterQuery but with UnmarshalJSON() method.
 type FilterCriteria ethereum.FilterQuery
@@ -303,6 +347,7 @@ func (api *PublicFilterAPI) NewFilter(ctx context.Context, crit FilterCriteria)
 		return "", err
 	}
 	crit.PSI = psm.ID
+	authorizedLogs := api.authorizedLogs(ctx, psm.ID)
 	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), logs)
 	if err != nil {
 		return rpc.ID(""), err // Quorum
@@ -318,7 +363,7 @@ func (api *PublicFilterAPI) NewFilter(ctx context.Context, crit FilterCriteria)
 			case l := <-logs:
 				api.filtersMu.Lock()
 				if f, found := api.filters[logsSub.ID]; found {
-					f.logs = append(f.logs, l...)
+					f.logs = append(f.logs, authorizedLogs(l)...)
 				}
 				api.filtersMu.Unlock()
 			case <-logsSub.Err():
@@ -363,7 +408,32 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	if err != nil {
 		return nil, err
 	}
-	return returnLogs(logs), err
+	return returnLogs(api.authorizedLogs(ctx, psm.ID)(logs)), err
+}
+
+// Quorum
+// GetLogsForPSIs returns the logs matching the given argument in each of the private states identified by psis,
+// or all the private states granted to the caller if psis is empty.
+func (api *PublicFilterAPI) GetLogsForPSIs(ctx context.Context, psis []types.PrivateStateIdentifier, crit FilterCriteria) ([]*multitenancy.PrivateStateResult, error) {
+	var token *proto.PreAuthenticatedAuthenticationToken
+	if backend, ok := api.backend.(multitenancyBackend); ok {
+		token, _ = backend.SupportsMultitenancy(ctx)
+	}
+	authorizedPSIs, err := multitenancy.AuthorizePSIs(token, psis)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*multitenancy.PrivateStateResult, 0, len(authorizedPSIs))
+	for _, psi := range authorizedPSIs {
+		result := &multitenancy.PrivateStateResult{PSI: psi}
+		if logs, err := api.GetLogs(rpc.WithPrivateStateIdentifier(ctx, psi), crit); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Result = logs
+		}
+		results = append(results, result)
+	}
+	return results, nil
 }
 
 // UninstallFilter removes the filter with the given filter id.
@@ -429,7 +499,7 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 	if err != nil {
 		return nil, err
 	}
-	return returnLogs(logs), nil
+	return returnLogs(api.authorizedLogs(ctx, psm.ID)(logs)), nil
 }
 
 // GetFilterChanges returns the logs for the filter with the given id since
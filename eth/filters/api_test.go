@@ -17,12 +17,18 @@
 package filters
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/multitenancy"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 )
 
 func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
@@ -183,3 +189,65 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+type multitenantTestBackend struct {
+	*testBackend
+	token *proto.PreAuthenticatedAuthenticationToken
+}
+
+func (b *multitenantTestBackend) SupportsMultitenancy(context.Context) (*proto.PreAuthenticatedAuthenticationToken, bool) {
+	return b.token, true
+}
+
+func TestAuthorizedLogs_whenContractScoped(t *testing.T) {
+	var (
+		granted = common.HexToAddress("0x9d13c6d3afe1721beef56b55d303b09e021e27ab")
+		other   = common.HexToAddress("0x1932c48b2bf8102ba33b4a6b545c32236e342f34")
+		logs    = []*types.Log{{Address: granted}, {Address: other}, {Address: granted}}
+	)
+	api := &PublicFilterAPI{backend: &testBackend{}}
+	if filtered := api.authorizedLogs(context.Background(), "PS1")(logs); len(filtered) != 3 {
+		t.Fatalf("expected all logs without multitenancy, got %d", len(filtered))
+	}
+
+	api.backend = &multitenantTestBackend{
+		testBackend: &testBackend{},
+		token: &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+			{Raw: "psi://PS1?node.eoa=0x0&contract.read=0x9d13c6d3afe1721beef56b55d303b09e021e27ab"},
+		}},
+	}
+	filtered := api.authorizedLogs(context.Background(), "PS1")(logs)
+	if len(filtered) != 2 || filtered[0].Address != granted || filtered[1].Address != granted {
+		t.Fatalf("expected only the logs of the granted contract, got %v", filtered)
+	}
+	if filtered := api.authorizedLogs(context.Background(), "PS2")(logs); len(filtered) != 0 {
+		t.Fatalf("expected no logs for another private state, got %d", len(filtered))
+	}
+}
+
+func TestGetLogsForPSIs(t *testing.T) {
+	api := NewPublicFilterAPI(&multitenantTestBackend{
+		testBackend: &testBackend{db: rawdb.NewMemoryDatabase()},
+		token: &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+			{Raw: "psi://PS1"},
+			{Raw: "psi://PS2?contract.read=0x0"},
+		}},
+	}, false, deadline)
+
+	results, err := api.GetLogsForPSIs(context.Background(), nil, FilterCriteria{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].PSI != "PS1" || results[1].PSI != "PS2" {
+		t.Fatalf("expected the logs of each granted private state, got %v", results)
+	}
+	for _, result := range results {
+		if result.Error != "" {
+			t.Fatalf("unexpected error for %s: %s", result.PSI, result.Error)
+		}
+	}
+
+	if _, err := api.GetLogsForPSIs(context.Background(), []types.PrivateStateIdentifier{"PS3"}, FilterCriteria{}); !errors.Is(err, multitenancy.ErrNotAuthorized) {
+		t.Fatalf("expected an authorization error, got %v", err)
+	}
+}
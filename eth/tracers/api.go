@@ -854,6 +854,12 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 // top of the provided block and returns them as a JSON object.
 // You can provide -2 as a block number to trace on top of the pending block.
 func (api *API) TraceCall(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+	// Quorum
+	if backend, ok := api.backend.(ethapi.MultitenancyBackend); ok {
+		if err := ethapi.CheckCallAccess(ctx, backend, args.To); err != nil {
+			return nil, err
+		}
+	}
 	// Try to retrieve the specified block
 	var (
 		err   error
@@ -26,12 +26,15 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/mps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -112,6 +115,10 @@ func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
 }
 
 func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash }) (common.Hash, error) {
+	// Quorum
+	if err := ethapi.CheckContractReadAccess(ctx, a.backend, a.address); err != nil {
+		return common.Hash{}, err
+	}
 	state, err := a.getState(ctx)
 	if err != nil {
 		return common.Hash{}, err
@@ -832,6 +839,8 @@ func runFilter(ctx context.Context, be ethapi.Backend, filter *filters.Filter) (
 	if err != nil || logs == nil {
 		return nil, err
 	}
+	// Quorum - drop the logs of the contracts the tenant is not allowed to read
+	logs = authorizedLogs(ctx, be, logs)
 	ret := make([]*Log, 0, len(logs))
 	for _, log := range logs {
 		ret = append(ret, &Log{
@@ -843,6 +852,23 @@ func runFilter(ctx context.Context, be ethapi.Backend, filter *filters.Filter) (
 	return ret, nil
 }
 
+// (Quorum) authorizedLogs drops the logs of the contracts the tenant is not allowed to read
+func authorizedLogs(ctx context.Context, be ethapi.Backend, logs []*types.Log) []*types.Log {
+	ret := make([]*types.Log, 0, len(logs))
+	authorized := make(map[common.Address]bool)
+	for _, log := range logs {
+		isAuthorized, found := authorized[log.Address]
+		if !found {
+			isAuthorized = ethapi.CheckContractReadAccess(ctx, be, log.Address) == nil
+			authorized[log.Address] = isAuthorized
+		}
+		if isAuthorized {
+			ret = append(ret, log)
+		}
+	}
+	return ret
+}
+
 func (b *Block) Logs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) ([]*Log, error) {
 	var addresses []common.Address
 	if args.Filter.Addresses != nil {
@@ -927,6 +953,12 @@ func (b *Block) Call(ctx context.Context, args struct {
 		}
 	}
 
+	// Quorum
+	if args.Data.To != nil {
+		if err := ethapi.CheckContractReadAccess(ctx, b.backend, *args.Data.To); err != nil {
+			return nil, err
+		}
+	}
 	// Quorum - replaced the default 5s time out with the value passed in vm.calltimeout
 	result, err := ethapi.DoCall(ctx, b.backend, args.Data, *b.numberOrHash, nil, vm.Config{}, b.backend.CallTimeOut(), b.backend.RPCGasCap())
 	if err != nil {
@@ -999,6 +1031,12 @@ func (p *Pending) Call(ctx context.Context, args struct {
 }) (*CallResult, error) {
 	pendingBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 
+	// Quorum
+	if args.Data.To != nil {
+		if err := ethapi.CheckContractReadAccess(ctx, p.backend, *args.Data.To); err != nil {
+			return nil, err
+		}
+	}
 	// Quorum - replaced the default 5s time out with the value passed in vm.calltimeout
 	result, err := ethapi.DoCall(ctx, p.backend, args.Data, pendingBlockNr, nil, vm.Config{}, p.backend.CallTimeOut(), p.backend.RPCGasCap())
 	if err != nil {
@@ -1289,3 +1327,197 @@ func (t *Transaction) PrivateInputData(ctx context.Context) (*hexutil.Bytes, err
 	}
 	return &hexutil.Bytes{}, nil
 }
+
+// PrivateState represents the private state selected by the request (see rpc.HttpPrivateStateIdentifierHeader)
+// at a particular block
+type PrivateState struct {
+	block *Block
+	psm   *mps.PrivateStateMetadata
+}
+
+// PrivateState returns the private state the request is authorized for
+func (b *Block) PrivateState(ctx context.Context) (*PrivateState, error) {
+	psm, err := b.backend.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateState{block: b, psm: psm}, nil
+}
+
+func (p *PrivateState) PSI() string {
+	return p.psm.ID.String()
+}
+
+func (p *PrivateState) Name() string {
+	return p.psm.Name
+}
+
+func (p *PrivateState) Description() string {
+	return p.psm.Description
+}
+
+// privateStateRepositoryBackend is implemented by the backends keeping the private states
+type privateStateRepositoryBackend interface {
+	PrivateStateRepository(ctx context.Context, header *types.Header) (mps.PrivateStateRepository, error)
+}
+
+// Root returns the root of the private state at the block, read from the private state repository
+func (p *PrivateState) Root(ctx context.Context) (common.Hash, error) {
+	backend, ok := p.block.backend.(privateStateRepositoryBackend)
+	if !ok {
+		return common.Hash{}, errors.New("private state root is not available from this node")
+	}
+	header, err := p.block.resolveHeader(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	repo, err := backend.PrivateStateRepository(ctx, header)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return repo.PrivateStateRoot(p.psm.ID)
+}
+
+func (p *PrivateState) Block() *Block {
+	return p.block
+}
+
+// PrivateReceipt represents the receipt of a private transaction in the private state selected by the request
+type PrivateReceipt struct {
+	tx      *Transaction
+	psi     types.PrivateStateIdentifier
+	receipt *types.Receipt
+}
+
+// PrivateReceipt returns the receipt of a private transaction, or of the private transaction of a privacy marker
+// transaction, in the private state selected by the request
+func (t *Transaction) PrivateReceipt(ctx context.Context) (*PrivateReceipt, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil || t.block == nil {
+		return nil, err
+	}
+	psm, err := t.backend.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var receipt *types.Receipt
+	switch {
+	case tx.IsPrivacyMarker():
+		receipts, err := t.block.resolveReceipts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		receipt = receipts[t.index].PSReceipts[psm.ID]
+	case tx.IsPrivate():
+		if receipt, err = t.getReceipt(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if receipt == nil {
+		return nil, nil
+	}
+	return &PrivateReceipt{tx: t, psi: psm.ID, receipt: receipt}, nil
+}
+
+func (r *PrivateReceipt) PSI() string {
+	return r.psi.String()
+}
+
+func (r *PrivateReceipt) Status() Long {
+	return Long(r.receipt.Status)
+}
+
+func (r *PrivateReceipt) GasUsed() Long {
+	return Long(r.receipt.GasUsed)
+}
+
+func (r *PrivateReceipt) CreatedContract(args BlockNumberArgs) *Account {
+	if r.receipt.ContractAddress == (common.Address{}) {
+		return nil
+	}
+	return &Account{
+		backend:       r.tx.backend,
+		address:       r.receipt.ContractAddress,
+		blockNrOrHash: args.NumberOrLatest(),
+	}
+}
+
+func (r *PrivateReceipt) Logs(ctx context.Context) []*Log {
+	logs := authorizedLogs(ctx, r.tx.backend, r.receipt.Logs)
+	ret := make([]*Log, 0, len(logs))
+	for _, log := range logs {
+		ret = append(ret, &Log{
+			backend:     r.tx.backend,
+			transaction: r.tx,
+			log:         log,
+		})
+	}
+	return ret
+}
+
+// PrivacyMetadata represents the privacy metadata of a private contract
+type PrivacyMetadata struct {
+	metadata            *state.PrivacyMetadata
+	mandatoryRecipients []string
+}
+
+func (m *PrivacyMetadata) CreationTransactionHash() hexutil.Bytes {
+	return m.metadata.CreationTxHash.Bytes()
+}
+
+func (m *PrivacyMetadata) PrivacyFlag() int32 {
+	return int32(m.metadata.PrivacyFlag)
+}
+
+func (m *PrivacyMetadata) MandatoryFor() *[]string {
+	if m.mandatoryRecipients == nil {
+		return nil
+	}
+	return &m.mandatoryRecipients
+}
+
+// PrivacyMetadata returns the privacy metadata of the account if it is a private contract in the private state
+// of the request, nil otherwise
+func (a *Account) PrivacyMetadata(ctx context.Context) (*PrivacyMetadata, error) {
+	if err := ethapi.CheckContractReadAccess(ctx, a.backend, a.address); err != nil {
+		return nil, err
+	}
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := state.GetPrivacyMetadata(a.address)
+	if errors.Is(err, common.ErrNotPrivateContract) || (err == nil && metadata == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := &PrivacyMetadata{metadata: metadata}
+	if metadata.PrivacyFlag == engine.PrivacyFlagMandatoryRecipients {
+		if ret.mandatoryRecipients, err = private.P.GetMandatory(metadata.CreationTxHash); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// ManagedParties returns the parties managed by this node which the account is shared with if it is a private
+// contract in the private state of the request, nil otherwise
+func (a *Account) ManagedParties(ctx context.Context) (*[]string, error) {
+	if err := ethapi.CheckContractReadAccess(ctx, a.backend, a.address); err != nil {
+		return nil, err
+	}
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	managedParties, err := state.GetManagedParties(a.address)
+	if errors.Is(err, common.ErrNotPrivateContract) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &managedParties, nil
+}
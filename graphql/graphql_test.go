@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -34,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -46,10 +48,12 @@ import (
 	"github.com/ethereum/go-ethereum/multitenancy"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/private/engine/notinuse"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
 	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/sha3"
@@ -584,6 +588,176 @@ func TestQuorumTransaction_getReceipt_defaultReceiptGetter(t *testing.T) {
 	}
 }
 
+func TestQuorumSchema_PrivateState(t *testing.T) {
+	block := &Block{backend: &StubBackend{}}
+
+	privateState, err := block.PrivateState(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, mps.DefaultPrivateStateMetadata.ID.String(), privateState.PSI())
+	assert.Equal(t, mps.DefaultPrivateStateMetadata.Name, privateState.Name())
+	assert.Equal(t, block, privateState.Block())
+}
+
+type privateStateRepositoryStubBackend struct {
+	StubBackend
+	repo mps.PrivateStateRepository
+}
+
+func (sb *privateStateRepositoryStubBackend) PrivateStateRepository(ctx context.Context, header *types.Header) (mps.PrivateStateRepository, error) {
+	return sb.repo, nil
+}
+
+func TestQuorumSchema_PrivateStateRoot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mps.NewMockPrivateStateRepository(ctrl)
+	repo.EXPECT().PrivateStateRoot(mps.DefaultPrivateStateMetadata.ID).Return(common.HexToHash("0x1"), nil)
+	header := &types.Header{Number: big.NewInt(1)}
+	block := &Block{backend: &privateStateRepositoryStubBackend{repo: repo}, hash: header.Hash(), header: header}
+
+	privateState, err := block.PrivateState(context.Background())
+	assert.NoError(t, err)
+	root, err := privateState.Root(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x1"), root)
+}
+
+func TestQuorumSchema_PrivateStateRoot_whenNotAvailable(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1)}
+	block := &Block{backend: &StubBackend{}, hash: header.Hash(), header: header}
+
+	privateState, err := block.PrivateState(context.Background())
+	assert.NoError(t, err)
+	_, err = privateState.Root(context.Background())
+
+	assert.EqualError(t, err, "private state root is not available from this node")
+}
+
+func TestQuorumTransaction_PrivateReceipt(t *testing.T) {
+	contract, otherContract := common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	backend := &privateStateStubBackend{token: &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+		{Raw: "psi://private?node.eoa=0x0&contract.read=0x0000000000000000000000000000000000000002"},
+	}}}
+	privateReceipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, GasUsed: 21000, ContractAddress: contract, Logs: []*types.Log{{Address: contract}, {Address: otherContract}}}
+	block := &Block{backend: backend, receipts: []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful, QuorumReceiptExtraData: types.QuorumReceiptExtraData{PSReceipts: map[types.PrivateStateIdentifier]*types.Receipt{mps.DefaultPrivateStateMetadata.ID: privateReceipt}}},
+		{Status: types.ReceiptStatusSuccessful},
+	}}
+	pmt := &Transaction{backend: backend, tx: types.NewTransaction(0, common.QuorumPrivacyPrecompileContractAddress(), big.NewInt(0), 0, big.NewInt(0), nil), block: block, index: 0}
+	publicTx := &Transaction{backend: backend, tx: types.NewTransaction(1, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil), block: block, index: 1}
+
+	receipt, err := pmt.PrivateReceipt(context.Background())
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, receipt) {
+		assert.Equal(t, mps.DefaultPrivateStateMetadata.ID.String(), receipt.PSI())
+		assert.Equal(t, Long(types.ReceiptStatusSuccessful), receipt.Status())
+		assert.Equal(t, Long(21000), receipt.GasUsed())
+		assert.Equal(t, contract, receipt.CreatedContract(BlockNumberArgs{}).address)
+		logs := receipt.Logs(context.Background())
+		if assert.Len(t, logs, 1, "logs of the contracts not granted must be dropped") {
+			assert.Equal(t, contract, logs[0].log.Address)
+			assert.Equal(t, pmt, logs[0].transaction)
+		}
+	}
+
+	receipt, err = publicTx.PrivateReceipt(context.Background())
+
+	assert.NoError(t, err)
+	assert.Nil(t, receipt, "public transactions have no private receipt")
+}
+
+func TestQuorumSchema_AccountPrivacyMetadata(t *testing.T) {
+	contract, account := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	backend := &privateStateStubBackend{state: &stubPrivateState{
+		metadata:       map[common.Address]*state.PrivacyMetadata{contract: {PrivacyFlag: engine.PrivacyFlagPartyProtection}},
+		managedParties: map[common.Address][]string{contract: {"party1"}},
+	}}
+
+	metadata, err := (&Account{backend: backend, address: contract}).PrivacyMetadata(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(engine.PrivacyFlagPartyProtection), metadata.PrivacyFlag())
+	assert.Nil(t, metadata.MandatoryFor())
+	managedParties, err := (&Account{backend: backend, address: contract}).ManagedParties(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"party1"}, *managedParties)
+
+	metadata, err = (&Account{backend: backend, address: account}).PrivacyMetadata(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+	managedParties, err = (&Account{backend: backend, address: account}).ManagedParties(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, managedParties)
+}
+
+func TestQuorumSchema_AccountPrivacyMetadata_whenContractNotGranted(t *testing.T) {
+	backend := &privateStateStubBackend{
+		state: &stubPrivateState{},
+		token: &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+			{Raw: "psi://private?node.eoa=0x0&contract.read=0x0000000000000000000000000000000000000001"},
+		}},
+	}
+
+	_, err := (&Account{backend: backend, address: common.HexToAddress("0x2")}).PrivacyMetadata(context.Background())
+
+	assert.Equal(t, multitenancy.ErrNotAuthorized, err)
+}
+
+func TestQuorumSecureHandler_whenPSIHeader(t *testing.T) {
+	var psi types.PrivateStateIdentifier
+	handler := &secureHandler{
+		authManagerFunc: func() (security.AuthenticationManager, error) {
+			return security.NewDisabledAuthenticationManager(), nil
+		},
+		protectedMethod: "graphql_*",
+		delegate: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			psi, _ = rpc.PrivateStateIdentifierFromContext(r.Context())
+		}),
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("{}"))
+	req.Header.Set(rpc.HttpPrivateStateIdentifierHeader, "PS1")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, types.PrivateStateIdentifier("PS1"), psi)
+}
+
+type privateStateStubBackend struct {
+	StubBackend
+	state *stubPrivateState
+	token *proto.PreAuthenticatedAuthenticationToken
+}
+
+func (b *privateStateStubBackend) SupportsMultitenancy(context.Context) (*proto.PreAuthenticatedAuthenticationToken, bool) {
+	return b.token, b.token != nil
+}
+
+func (b *privateStateStubBackend) StateAndHeaderByNumberOrHash(context.Context, rpc.BlockNumberOrHash) (vm.MinimalApiState, *types.Header, error) {
+	return b.state, nil, nil
+}
+
+type stubPrivateState struct {
+	vm.MinimalApiState
+	metadata       map[common.Address]*state.PrivacyMetadata
+	managedParties map[common.Address][]string
+}
+
+func (s *stubPrivateState) GetPrivacyMetadata(addr common.Address) (*state.PrivacyMetadata, error) {
+	if metadata, ok := s.metadata[addr]; ok {
+		return metadata, nil
+	}
+	return nil, fmt.Errorf("%x: %w", addr, common.ErrNotPrivateContract)
+}
+
+func (s *stubPrivateState) GetManagedParties(addr common.Address) ([]string, error) {
+	if managedParties, ok := s.managedParties[addr]; ok {
+		return managedParties, nil
+	}
+	return nil, fmt.Errorf("%x: %w", addr, common.ErrNotPrivateContract)
+}
+
 type ptmResponse struct {
 	body []byte
 	err  error
@@ -52,6 +52,59 @@ const schema string = `
         # Storage provides access to the storage of a contract account, indexed
         # by its 32 byte slot identifier.
         storage(slot: Bytes32!): Bytes32!
+        # PrivacyMetadata is the privacy metadata of a Quorum private contract
+        # in the private state of the request, null for other accounts.
+        privacyMetadata: PrivacyMetadata
+        # ManagedParties lists the parties managed by this node which a Quorum
+        # private contract is shared with, null for other accounts.
+        managedParties: [String!]
+    }
+
+    # PrivacyMetadata is the privacy metadata of a Quorum private contract.
+    type PrivacyMetadata {
+        # CreationTransactionHash is the hash of the encrypted payload of the
+        # transaction which created the contract.
+        creationTransactionHash: Bytes!
+        # PrivacyFlag is the privacy enhancement of the contract: 0 for
+        # standard private, 1 for party protection, 2 for mandatory recipients
+        # and 3 for private state validation.
+        privacyFlag: Int!
+        # MandatoryFor lists the recipients which must be included in the
+        # transactions to the contract when the privacy flag is 2.
+        mandatoryFor: [String!]
+    }
+
+    # PrivateState is the private state selected by the request at a
+    # particular block.
+    type PrivateState {
+        # PSI is the identifier of the private state.
+        psi: String!
+        # Name is the name of the private state.
+        name: String!
+        # Description is the description of the private state.
+        description: String!
+        # Root is the root of the private state at the block.
+        root: Bytes32!
+        # Block is the block the private state is read at.
+        block: Block!
+    }
+
+    # PrivateReceipt is the receipt of a Quorum private transaction in the
+    # private state selected by the request.
+    type PrivateReceipt {
+        # PSI is the identifier of the private state the receipt belongs to.
+        psi: String!
+        # Status is the return status of the private transaction: 1 if it
+        # succeeded, or 0 if it failed.
+        status: Long!
+        # GasUsed is the amount of gas used by the private transaction.
+        gasUsed: Long!
+        # CreatedContract is the account created by a private contract creation
+        # transaction, null otherwise.
+        createdContract(block: Long): Account
+        # Logs is a list of log entries emitted by the private transaction,
+        # excluding those of the contracts the request is not allowed to read.
+        logs: [Log!]!
     }
 
     # Log is an Ethereum event log.
@@ -128,6 +181,12 @@ const schema string = `
 		# PrivateTransaction is the internal private transaction of a public 
         # marker transaction when privacy precompile is enabled
 		privateTransaction: Transaction
+		# PrivateReceipt is the receipt of a private transaction, or of the
+		# private transaction of a marker transaction, in the private state
+		# selected by the request. It is null for public transactions, if the
+		# transaction has not yet been mined, or if the private state is not
+		# a party to the transaction.
+		privateReceipt: PrivateReceipt
         r: BigInt!
         s: BigInt!
         v: BigInt!
@@ -225,6 +284,9 @@ const schema string = `
         # EstimateGas estimates the amount of gas that will be required for
         # successful execution of a transaction at the current block's state.
         estimateGas(data: CallData!): Long!
+        # PrivateState is the private state selected by the request at this
+        # block.
+        privateState: PrivateState!
     }
 
     # CallData represents the data associated with a local contract call.
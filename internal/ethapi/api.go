@@ -55,6 +55,7 @@ import (
 	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
 	"github.com/tyler-smith/go-bip39"
 )
 
@@ -445,6 +446,12 @@ func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args SendTxArgs
 		defer s.nonceLock.UnlockAddr(args.From)
 	}
 
+	// Quorum
+	if err := args.resolvePrivacyGroup(ctx, s.b, nil); err != nil {
+		return common.Hash{}, err
+	}
+	// /Quorum
+
 	// Set some sanity defaults and terminate on failure
 	if err := args.setDefaults(ctx, s.b); err != nil {
 		return common.Hash{}, err
@@ -852,6 +859,9 @@ func (s *PublicBlockChainAPI) GetUncleCountByBlockHash(ctx context.Context, bloc
 
 // GetCode returns the code stored at the given address in the state for the given block number.
 func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if err := CheckContractReadAccess(ctx, s.b, address); err != nil {
+		return nil, err
+	}
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
@@ -864,6 +874,9 @@ func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Addres
 // block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
 // numbers are also allowed.
 func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if err := CheckContractReadAccess(ctx, s.b, address); err != nil {
+		return nil, err
+	}
 	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
@@ -872,6 +885,99 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// Quorum
+// CheckContractReadAccess makes sure the tenant is allowed to read the contract when multitenancy is enabled
+func CheckContractReadAccess(ctx context.Context, b MultitenancyBackend, contract common.Address) error {
+	token, ok := b.SupportsMultitenancy(ctx)
+	if !ok {
+		return nil
+	}
+	psm, err := b.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return err
+	}
+	secAttr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(psm.ID).WithContractRead(contract)
+	return authorize(ctx, token, psm.ID, secAttr)
+}
+
+// Quorum
+// authorize checks the security attributes against the token and records the decision in the RPC
+// audit log, returning multitenancy.ErrNotAuthorized if any attribute is not granted.
+func authorize(ctx context.Context, token *proto.PreAuthenticatedAuthenticationToken, psi types.PrivateStateIdentifier, secAttributes ...*multitenancy.PrivateStateSecurityAttribute) error {
+	scope := make([]string, len(secAttributes))
+	for i, attr := range secAttributes {
+		scope[i] = attr.String()
+	}
+	var decision error
+	if isAuthorized, _ := multitenancy.IsAuthorized(token, secAttributes...); !isAuthorized {
+		decision = multitenancy.ErrNotAuthorized
+	}
+	rpc.AuditDecision(ctx, rpc.AuditEventMultitenancy, psi, strings.Join(scope, "; "), decision)
+	return decision
+}
+
+// Quorum
+// CheckCallAccess makes sure the tenant is allowed to read the contract called when multitenancy is enabled.
+// A call without a recipient runs contract creation code, which requires read access to any contract.
+func CheckCallAccess(ctx context.Context, b MultitenancyBackend, to *common.Address) error {
+	if to == nil {
+		return CheckContractReadAccess(ctx, b, common.Address{})
+	}
+	return CheckContractReadAccess(ctx, b, *to)
+}
+
+// Quorum
+// authorizedReceipt returns the receipt without the logs emitted by contracts the tenant is not allowed
+// to read when multitenancy is enabled
+func authorizedReceipt(ctx context.Context, b MultitenancyBackend, receipt *types.Receipt) *types.Receipt {
+	if _, ok := b.SupportsMultitenancy(ctx); !ok || len(receipt.Logs) == 0 {
+		return receipt
+	}
+	var (
+		logs       = make([]*types.Log, 0, len(receipt.Logs))
+		authorized = make(map[common.Address]bool)
+	)
+	for _, log := range receipt.Logs {
+		isAuthorized, found := authorized[log.Address]
+		if !found {
+			isAuthorized = CheckContractReadAccess(ctx, b, log.Address) == nil
+			authorized[log.Address] = isAuthorized
+		}
+		if isAuthorized {
+			logs = append(logs, log)
+		}
+	}
+	filtered := *receipt
+	filtered.Logs = logs
+	return &filtered
+}
+
+// Quorum
+// forEachPSI performs a read-only call for each of the PSIs of a multi-PSI read, tagging the outcome
+// with the PSI. The call fails as a whole if any of the PSIs is not authorized.
+func forEachPSI(ctx context.Context, b Backend, psis []types.PrivateStateIdentifier, read func(ctx context.Context) (interface{}, error)) ([]*multitenancy.PrivateStateResult, error) {
+	token, _ := b.SupportsMultitenancy(ctx)
+	authorizedPSIs, err := multitenancy.AuthorizePSIs(token, psis)
+	if err != nil {
+		rpc.AuditDecision(ctx, rpc.AuditEventMultitenancy, "", fmt.Sprintf("psis=%v", psis), err)
+		return nil, err
+	}
+	for _, psi := range authorizedPSIs {
+		rpc.AuditDecision(ctx, rpc.AuditEventMultitenancy, psi, "psi", nil)
+	}
+	results := make([]*multitenancy.PrivateStateResult, 0, len(authorizedPSIs))
+	for _, psi := range authorizedPSIs {
+		result := &multitenancy.PrivateStateResult{PSI: psi}
+		if value, err := read(rpc.WithPrivateStateIdentifier(ctx, psi)); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Result = value
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
 	From       *common.Address   `json:"from"`
@@ -1071,6 +1177,9 @@ func (e *revertError) ErrorData() interface{} {
 // - replaced the default 5s time out with the value passed in vm.calltimeout
 // - multi tenancy verification
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
+	if err := CheckCallAccess(ctx, s.b, args.To); err != nil {
+		return nil, err
+	}
 	var accounts map[common.Address]OverrideAccount
 	if overrides != nil {
 		accounts = *overrides
@@ -1087,6 +1196,15 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOr
 	return result.Return(), result.Err
 }
 
+// Quorum
+// CallForPSIs executes the given call against each of the private states identified by psis, or all the
+// private states granted to the caller if psis is empty.
+func (s *PublicBlockChainAPI) CallForPSIs(ctx context.Context, psis []types.PrivateStateIdentifier, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) ([]*multitenancy.PrivateStateResult, error) {
+	return forEachPSI(ctx, s.b, psis, func(ctx context.Context) (interface{}, error) {
+		return s.Call(ctx, args, blockNrOrHash, overrides)
+	})
+}
+
 func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
@@ -1243,6 +1361,10 @@ func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNrOrHash
 // EstimateGas returns an estimate of the amount of gas needed to execute the
 // given transaction against the current pending block.
 func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	// Quorum
+	if err := CheckCallAccess(ctx, s.b, args.To); err != nil {
+		return 0, err
+	}
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
@@ -1754,7 +1876,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if len(receipts) <= int(index) {
 		return nil, nil
 	}
-	receipt := receipts[index]
+	receipt := authorizedReceipt(ctx, s.b, receipts[index])
 
 	// Quorum: note that upstream code has been refactored into this method
 	return getTransactionReceiptCommonCode(tx, blockHash, blockNumber, hash, index, receipt)
@@ -1879,7 +2001,17 @@ func (s *PublicTransactionPoolAPI) GetPrivateTransactionReceipt(ctx context.Cont
 		return nil, errors.New("could not find receipt for private transaction")
 	}
 
-	return getTransactionReceiptCommonCode(tx, blockHash, blockNumber, hash, index, receipt)
+	return getTransactionReceiptCommonCode(tx, blockHash, blockNumber, hash, index, authorizedReceipt(ctx, s.b, receipt))
+}
+
+// Quorum
+// GetPrivateTransactionReceiptForPSIs returns the receipts of the private transaction associated with the
+// privacy marker transaction in each of the private states identified by psis, or all the private states
+// granted to the caller if psis is empty.
+func (s *PublicTransactionPoolAPI) GetPrivateTransactionReceiptForPSIs(ctx context.Context, psis []types.PrivateStateIdentifier, hash common.Hash) ([]*multitenancy.PrivateStateResult, error) {
+	return forEachPSI(ctx, s.b, psis, func(ctx context.Context) (interface{}, error) {
+		return s.GetPrivateTransactionReceipt(ctx, hash)
+	})
 }
 
 // Quorum: if signing a private TX, set with tx.SetPrivate() before calling this method.
@@ -1949,6 +2081,9 @@ type PrivateTxArgs struct {
 	PrivateTxType       string                 `json:"restriction"`
 	PrivacyFlag         engine.PrivacyFlagType `json:"privacyFlag"`
 	MandatoryRecipients []string               `json:"mandatoryFor"`
+	// PrivacyGroupId is the id of a privacy group of the Private Transaction Manager, the transaction is
+	// private for the members of the group. It cannot be used together with PrivateFor.
+	PrivacyGroupId string `json:"privacyGroupId"`
 }
 
 func (args *PrivateTxArgs) SetDefaultPrivateFrom(ctx context.Context, b Backend) error {
@@ -2136,14 +2271,15 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction, pr
 			if err != nil {
 				return common.Hash{}, err
 			}
-			eoaSecAttr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(psm.ID).WithSelfEOAIf(isRaw, innerFrom)
+			userPSI := psm.ID
+			eoaSecAttr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(psm.ID).WithSelfEOAIf(isRaw, innerFrom).WithContract(tx.To())
 			psm, err = b.PSMR().ResolveForManagedParty(privateFrom)
 			if err != nil {
 				return common.Hash{}, err
 			}
-			privateFromSecAttr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(psm.ID).WithSelfEOAIf(isRaw, innerFrom)
-			if isAuthorized, _ := multitenancy.IsAuthorized(token, eoaSecAttr, privateFromSecAttr); !isAuthorized {
-				return common.Hash{}, multitenancy.ErrNotAuthorized
+			privateFromSecAttr := (&multitenancy.PrivateStateSecurityAttribute{}).WithPSI(psm.ID).WithSelfEOAIf(isRaw, innerFrom).WithContract(tx.To())
+			if err := authorize(ctx, token, userPSI, eoaSecAttr, privateFromSecAttr); err != nil {
+				return common.Hash{}, err
 			}
 		}
 	}
@@ -2248,6 +2384,12 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 		defer s.nonceLock.UnlockAddr(args.From)
 	}
 
+	// Quorum
+	if err := args.resolvePrivacyGroup(ctx, s.b, nil); err != nil {
+		return common.Hash{}, err
+	}
+	// /Quorum
+
 	// Set some sanity defaults and terminate on failure
 	if err := args.setDefaults(ctx, s.b); err != nil {
 		return common.Hash{}, err
@@ -2307,6 +2449,11 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 // FillTransaction fills the defaults (nonce, gas, gasPrice) on a given unsigned transaction,
 // and returns it to the caller for further processing (signing + broadcast)
 func (s *PublicTransactionPoolAPI) FillTransaction(ctx context.Context, args SendTxArgs) (*SignTransactionResult, error) {
+	// Quorum
+	if err := args.resolvePrivacyGroup(ctx, s.b, nil); err != nil {
+		return nil, err
+	}
+	// /Quorum
 	// Set some sanity defaults and terminate on failure
 	if err := args.setDefaults(ctx, s.b); err != nil {
 		return nil, err
@@ -2358,6 +2505,9 @@ func (s *PublicTransactionPoolAPI) SendRawPrivateTransaction(ctx context.Context
 	}
 
 	// Quorum
+	if err := args.resolvePrivacyGroup(ctx, s.b, tx); err != nil {
+		return common.Hash{}, err
+	}
 	if err := args.SetRawTransactionPrivateFrom(ctx, s.b, tx); err != nil {
 		return common.Hash{}, err
 	}
@@ -2387,6 +2537,9 @@ func (s *PublicTransactionPoolAPI) DistributePrivateTransaction(ctx context.Cont
 	log.Debug("deserialised raw private tx", "hash", tx.Hash())
 
 	// Quorum
+	if err := args.resolvePrivacyGroup(ctx, s.b, tx); err != nil {
+		return "", err
+	}
 	if err := args.SetRawTransactionPrivateFrom(ctx, s.b, tx); err != nil {
 		return "", err
 	}
@@ -2458,6 +2611,9 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 		return nil, fmt.Errorf("nonce not specified")
 	}
 	// Quorum
+	if err := args.resolvePrivacyGroup(ctx, s.b, nil); err != nil {
+		return nil, err
+	}
 	// setDefaults calls DoEstimateGas in ethereum1.9.0, private transaction is not supported for that feature
 	// set gas to constant if nil
 	if args.IsPrivate() && args.Gas == nil {
@@ -3026,6 +3182,10 @@ func handlePrivateTransaction(ctx context.Context, b Backend, tx *types.Transact
 	defer func(start time.Time) {
 		log.Debug("Handle Private Transaction finished", "took", time.Since(start))
 	}(time.Now())
+	// fail fast rather than tying up the RPC request until the private transaction manager times out
+	if err = private.CheckAvailable(); err != nil {
+		return
+	}
 
 	data := tx.Data()
 
@@ -3125,6 +3285,9 @@ func handleNormalPrivateTransaction(ctx context.Context, b Backend, tx *types.Tr
 // privacy manager hash for the private tx.
 func createPrivacyMarkerTransaction(b Backend, privateTx *types.Transaction, privateTxArgs *PrivateTxArgs) (*types.Transaction, error) {
 	log.Trace("creating privacy marker transaction", "from", privateTx.From(), "to", privateTx.To())
+	if err := private.CheckAvailable(); err != nil {
+		return nil, err
+	}
 
 	data := new(bytes.Buffer)
 	err := json.NewEncoder(data).Encode(privateTx)
@@ -879,6 +879,373 @@ func TestSetRawTransactionPrivateFrom_ResolvePrivateFromIsNotMPSTenantAddr(t *te
 	require.EqualError(t, err, "The PrivateFrom address does not match the specified private state (myPSI)")
 }
 
+func TestResolvePrivacyGroup_setsPrivateForToGroupMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	savedPTM := private.P
+	defer func() { private.P = savedPTM }()
+	mockPTM := private.NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().RetrievePrivacyGroup("group-id").Return(&engine.PrivacyGroup{
+		Type:           engine.PrivacyGroupPantheon,
+		PrivacyGroupId: "group-id",
+		Members:        []string{"party-1", "party-2", "party-3"},
+	}, nil).Times(1)
+	private.P = mockPTM
+
+	args := &PrivateTxArgs{
+		PrivateFrom:    "party-2",
+		PrivacyGroupId: "group-id",
+	}
+
+	require.NoError(t, args.resolvePrivacyGroup(arbitraryCtx, &StubBackend{}, nil))
+	assert.Equal(t, []string{"party-1", "party-3"}, args.PrivateFor)
+}
+
+func TestResolvePrivacyGroup_defaultsPrivateFromToPrivateStateOfCaller(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	savedPTM := private.P
+	defer func() { private.P = savedPTM }()
+	mockPTM := private.NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().RetrievePrivacyGroup("group-id").Return(&engine.PrivacyGroup{
+		PrivacyGroupId: "group-id",
+		Members:        []string{"party-1"},
+	}, nil).Times(1)
+	private.P = mockPTM
+	mockPSMR := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	mockPSMR.EXPECT().ResolveForUserContext(gomock.Any()).Return(mps.NewPrivateStateMetadata("myPSI", "", "", mps.Resident, []string{"party-2"}), nil).Times(1)
+
+	args := &PrivateTxArgs{PrivacyGroupId: "group-id"}
+
+	require.EqualError(t, args.resolvePrivacyGroup(arbitraryCtx, &MPSStubBackend{psmr: mockPSMR}, nil), "the privateFrom (party-2) address is not a member of the privacy group group-id")
+}
+
+func TestResolvePrivacyGroup_requiresPrivateFromWithoutMultiplePrivateStates(t *testing.T) {
+	args := &PrivateTxArgs{PrivacyGroupId: "group-id"}
+
+	require.EqualError(t, args.resolvePrivacyGroup(arbitraryCtx, &StubBackend{}, nil), "privateFrom must be given with privacyGroupId")
+}
+
+func TestResolvePrivacyGroup_defaultsPrivateFromToSenderOfRawTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	savedPTM := private.P
+	defer func() { private.P = savedPTM }()
+	hash := common.BytesToEncryptedPayloadHash([]byte("raw"))
+	mockPTM := private.NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().ReceiveRaw(hash).Return(nil, "party-1", nil, nil).Times(1)
+	mockPTM.EXPECT().RetrievePrivacyGroup("group-id").Return(&engine.PrivacyGroup{
+		PrivacyGroupId: "group-id",
+		Members:        []string{"party-1", "party-2"},
+	}, nil).Times(1)
+	private.P = mockPTM
+	rawTx := types.NewTransaction(0, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), hash.Bytes())
+
+	args := &PrivateTxArgs{PrivacyGroupId: "group-id"}
+
+	require.NoError(t, args.resolvePrivacyGroup(arbitraryCtx, &StubBackend{}, rawTx))
+	assert.Equal(t, "party-1", args.PrivateFrom)
+	assert.Equal(t, []string{"party-2"}, args.PrivateFor)
+}
+
+func TestResolvePrivacyGroup_whenPrivateFromIsNotMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	savedPTM := private.P
+	defer func() { private.P = savedPTM }()
+	mockPTM := private.NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().RetrievePrivacyGroup("group-id").Return(&engine.PrivacyGroup{
+		PrivacyGroupId: "group-id",
+		Members:        []string{"party-1"},
+	}, nil).Times(1)
+	private.P = mockPTM
+
+	args := &PrivateTxArgs{
+		PrivateFrom:    "party-2",
+		PrivacyGroupId: "group-id",
+	}
+
+	require.EqualError(t, args.resolvePrivacyGroup(arbitraryCtx, &StubBackend{}, nil), "the privateFrom (party-2) address is not a member of the privacy group group-id")
+}
+
+func TestResolvePrivacyGroup_whenPrivateForIsAlsoGiven(t *testing.T) {
+	args := &PrivateTxArgs{
+		PrivateFor:     []string{"party-1"},
+		PrivacyGroupId: "group-id",
+	}
+
+	require.EqualError(t, args.resolvePrivacyGroup(arbitraryCtx, &StubBackend{}, nil), "privateFor and privacyGroupId cannot be used together")
+}
+
+func TestFindPrivacyGroup_onlyReturnsGroupsOfCallerPrivateState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	savedPTM := private.P
+	defer func() { private.P = savedPTM }()
+	mine := engine.PrivacyGroup{PrivacyGroupId: "mine", Members: []string{"my-addr", "other-addr"}}
+	others := engine.PrivacyGroup{PrivacyGroupId: "others", Members: []string{"other-addr"}}
+	mockPTM := private.NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().FindPrivacyGroup([]string{"other-addr"}).Return([]engine.PrivacyGroup{mine, others}, nil).Times(1)
+	private.P = mockPTM
+
+	psm := mps.NewPrivateStateMetadata("myPSI", "", "", mps.Resident, []string{"my-addr"})
+	mockPSMR := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	mockPSMR.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil).Times(1)
+	mockPSMR.EXPECT().NotIncludeAny(psm, gomock.Any()).DoAndReturn(func(psm *mps.PrivateStateMetadata, managedParties ...string) bool {
+		return psm.NotIncludeAny(managedParties...)
+	}).AnyTimes()
+
+	groups, err := NewPrivatePrivacyGroupAPI(&MPSStubBackend{psmr: mockPSMR}).FindPrivacyGroup(arbitraryCtx, []string{"other-addr"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []engine.PrivacyGroup{mine}, groups)
+}
+
+type auditStubBackend struct {
+	MPSStubBackend
+	blocks   map[uint64]*types.Block
+	receipts map[common.Hash]types.Receipts
+}
+
+func (sb *auditStubBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	return sb.blocks[uint64(blockNr)], nil
+}
+
+func (sb *auditStubBackend) GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error) {
+	return sb.receipts[blockHash], nil
+}
+
+func (sb *auditStubBackend) addBlock(number uint64, txs types.Transactions, receipts types.Receipts) {
+	block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number)}).WithBody(txs, nil)
+	sb.blocks[number] = block
+	sb.receipts[block.Hash()] = receipts
+}
+
+func TestGetPrivateTransactionAuditTrail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	contract, created := common.Address{1}, common.Address{2}
+	mineHash := common.BytesToEncryptedPayloadHash([]byte("mine"))
+	notPartyHash := common.BytesToEncryptedPayloadHash([]byte("not party"))
+	otherPSIHash := common.BytesToEncryptedPayloadHash([]byte("other psi"))
+	markerHash := common.BytesToEncryptedPayloadHash([]byte("marker"))
+	innerHash := common.BytesToEncryptedPayloadHash([]byte("inner"))
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	newPrivateTx := func(to *common.Address, hash common.EncryptedPayloadHash) *types.Transaction {
+		tx := types.NewContractCreation(0, big.NewInt(0), 0, big.NewInt(0), hash.Bytes())
+		if to != nil {
+			tx = types.NewTransaction(0, *to, big.NewInt(0), 0, big.NewInt(0), hash.Bytes())
+		}
+		tx, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+		require.NoError(t, err)
+		tx.SetPrivate()
+		return tx
+	}
+	innerTx := newPrivateTx(nil, innerHash)
+	innerTxData, err := innerTx.MarshalJSON()
+	require.NoError(t, err)
+	pmt := types.NewTransaction(0, common.QuorumPrivacyPrecompileContractAddress(), big.NewInt(0), 0, big.NewInt(0), markerHash.Bytes())
+
+	backend := &auditStubBackend{
+		blocks:   make(map[uint64]*types.Block),
+		receipts: make(map[common.Hash]types.Receipts),
+	}
+	backend.addBlock(1, types.Transactions{
+		types.NewTransaction(0, contract, big.NewInt(0), 0, big.NewInt(0), []byte("public")),
+		newPrivateTx(&contract, mineHash),
+		newPrivateTx(&contract, notPartyHash),
+		newPrivateTx(&contract, otherPSIHash),
+	}, types.Receipts{{}, {Status: types.ReceiptStatusSuccessful}, {}, {}})
+	pmtReceipt := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	pmtReceipt.PSReceipts = map[types.PrivateStateIdentifier]*types.Receipt{
+		"myPSI": {Status: types.ReceiptStatusFailed, ContractAddress: created},
+	}
+	backend.addBlock(2, types.Transactions{pmt}, types.Receipts{pmtReceipt})
+
+	savedPTM := private.P
+	defer func() { private.P = savedPTM }()
+	mineExtra := &engine.ExtraMetadata{
+		ACHashes:            common.EncryptedPayloadHashes{markerHash: struct{}{}},
+		PrivacyFlag:         engine.PrivacyFlagMandatoryRecipients,
+		ManagedParties:      []string{"my-addr"},
+		MandatoryRecipients: []string{"my-addr"},
+	}
+	mockPTM := private.NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().Receive(mineHash).Return("sender-key", []string{"my-addr"}, []byte("payload"), mineExtra, nil).AnyTimes()
+	mockPTM.EXPECT().Receive(notPartyHash).Return("", nil, nil, nil, nil).AnyTimes()
+	mockPTM.EXPECT().Receive(otherPSIHash).Return("sender-key", []string{"other-addr"}, []byte("payload"), &engine.ExtraMetadata{}, nil).AnyTimes()
+	mockPTM.EXPECT().Receive(markerHash).Return("sender-key", []string{"my-addr"}, innerTxData, &engine.ExtraMetadata{}, nil).AnyTimes()
+	mockPTM.EXPECT().Receive(innerHash).Return("sender-key", []string{"my-addr"}, []byte("payload"), &engine.ExtraMetadata{PrivacyFlag: engine.PrivacyFlagStateValidation}, nil).AnyTimes()
+	private.P = mockPTM
+
+	psm := mps.NewPrivateStateMetadata("myPSI", "", "", mps.Resident, []string{"my-addr"})
+	mockPSMR := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	mockPSMR.EXPECT().ResolveForUserContext(gomock.Any()).Return(psm, nil).AnyTimes()
+	mockPSMR.EXPECT().NotIncludeAny(psm, gomock.Any()).DoAndReturn(func(psm *mps.PrivateStateMetadata, managedParties ...string) bool {
+		return psm.NotIncludeAny(managedParties...)
+	}).AnyTimes()
+	backend.psmr = mockPSMR
+	api := NewPublicBlockChainAPI(backend)
+
+	entries, err := api.privateTransactionAuditTrail(arbitraryCtx, 1, 2, nil)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, hexutil.Uint64(1), entries[0].BlockNumber)
+	assert.Equal(t, hexutil.Uint64(1), entries[0].TransactionIndex)
+	assert.Equal(t, &contract, entries[0].To)
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), entries[0].Status)
+	assert.Equal(t, "sender-key", entries[0].SenderKey)
+	assert.Equal(t, engine.PrivacyFlagMandatoryRecipients, entries[0].PrivacyFlag)
+	assert.Equal(t, []string{"my-addr"}, entries[0].ManagedParties)
+	assert.Equal(t, []string{"my-addr"}, entries[0].MandatoryRecipients)
+	assert.Equal(t, []string{markerHash.ToBase64()}, entries[0].ACHashes)
+	assert.Nil(t, entries[0].PrivateTransactionHash)
+
+	assert.Equal(t, pmt.Hash(), entries[1].TransactionHash)
+	assert.Equal(t, innerTx.Hash(), *entries[1].PrivateTransactionHash)
+	assert.Equal(t, &created, entries[1].ContractAddress)
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusFailed), entries[1].Status)
+	assert.Equal(t, engine.PrivacyFlagStateValidation, entries[1].PrivacyFlag)
+
+	entries, err = api.privateTransactionAuditTrail(arbitraryCtx, 1, 2, &created)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, pmt.Hash(), entries[0].TransactionHash)
+}
+
+func TestGetPrivateTransactionAuditTrail_invalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPSMR := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	mockPSMR.EXPECT().ResolveForUserContext(gomock.Any()).Return(mps.DefaultPrivateStateMetadata, nil).AnyTimes()
+	api := NewPublicBlockChainAPI(&MPSStubBackend{psmr: mockPSMR})
+
+	_, err := api.privateTransactionAuditTrail(arbitraryCtx, 2, 1, nil)
+	assert.EqualError(t, err, "invalid block range 2-1")
+
+	_, err = api.privateTransactionAuditTrail(arbitraryCtx, 0, MaxPrivateTransactionAuditBlockRange, nil)
+	assert.EqualError(t, err, "block range exceeds the maximum of 10000 blocks")
+}
+
+func TestGetPrivateTransactionAuditTrail_whenPrivacyIsNotEnabled(t *testing.T) {
+	api := NewPublicBlockChainAPI(&StubBackend{})
+
+	_, err := api.GetPrivateTransactionAuditTrail(arbitraryCtx, 1, 2, nil)
+
+	assert.EqualError(t, err, "PrivateTransactionManager is not enabled")
+}
+
+type multitenantStubBackend struct {
+	MPSStubBackend
+	token *proto.PreAuthenticatedAuthenticationToken
+}
+
+func (b *multitenantStubBackend) SupportsMultitenancy(context.Context) (*proto.PreAuthenticatedAuthenticationToken, bool) {
+	return b.token, true
+}
+
+func TestContractScopedMultitenancy_read(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPSMR := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	mockPSMR.EXPECT().ResolveForUserContext(gomock.Any()).Return(mps.NewPrivateStateMetadata("PS1", "", "", mps.Resident, nil), nil).AnyTimes()
+	granted := common.HexToAddress("0x9d13c6d3afe1721beef56b55d303b09e021e27ab")
+	other := common.HexToAddress("0x1932c48b2bf8102ba33b4a6b545c32236e342f34")
+	b := &multitenantStubBackend{
+		MPSStubBackend: MPSStubBackend{psmr: mockPSMR},
+		token: &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+			{Raw: "psi://PS1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab"},
+		}},
+	}
+
+	assert.NoError(t, CheckContractReadAccess(arbitraryCtx, b, granted))
+	assert.Equal(t, multitenancy.ErrNotAuthorized, CheckContractReadAccess(arbitraryCtx, b, other))
+
+	api := NewPublicBlockChainAPI(b)
+	_, err := api.GetStorageAt(arbitraryCtx, other, "0x0", rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	assert.Equal(t, multitenancy.ErrNotAuthorized, err)
+	_, err = api.Call(arbitraryCtx, CallArgs{To: &other}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	assert.Equal(t, multitenancy.ErrNotAuthorized, err)
+	_, err = api.Call(arbitraryCtx, CallArgs{}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	assert.Equal(t, multitenancy.ErrNotAuthorized, err)
+	_, err = api.EstimateGas(arbitraryCtx, CallArgs{To: &other}, nil)
+	assert.Equal(t, multitenancy.ErrNotAuthorized, err)
+	_, err = api.GetCode(arbitraryCtx, other, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	assert.Equal(t, multitenancy.ErrNotAuthorized, err)
+
+	receipt := &types.Receipt{Logs: []*types.Log{{Address: granted}, {Address: other}}}
+	assert.Equal(t, []*types.Log{{Address: granted}}, authorizedReceipt(arbitraryCtx, b, receipt).Logs)
+	assert.Len(t, receipt.Logs, 2, "the receipt itself is not modified")
+}
+
+func TestContractScopedMultitenancy_readWithEOAScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPSMR := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	mockPSMR.EXPECT().ResolveForUserContext(gomock.Any()).Return(mps.NewPrivateStateMetadata("PS1", "", "", mps.Resident, nil), nil).AnyTimes()
+	contract := common.HexToAddress("0x9d13c6d3afe1721beef56b55d303b09e021e27ab")
+	b := &multitenantStubBackend{
+		MPSStubBackend: MPSStubBackend{psmr: mockPSMR},
+		token: &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+			{Raw: "psi://PS1?self.eoa=0x1932c48b2bf8102ba33b4a6b545c32236e342f34"},
+		}},
+	}
+
+	// a scope restricted to an EOA only still reads every contract
+	assert.NoError(t, CheckContractReadAccess(arbitraryCtx, b, contract))
+	assert.NoError(t, CheckCallAccess(arbitraryCtx, b, nil))
+
+	b.token.Authorities[0].Raw = "psi://PS1?self.eoa=0x1932c48b2bf8102ba33b4a6b545c32236e342f34&contract.read=0x0000000000000000000000000000000000000001"
+
+	assert.Equal(t, multitenancy.ErrNotAuthorized, CheckContractReadAccess(arbitraryCtx, b, contract))
+	assert.Equal(t, multitenancy.ErrNotAuthorized, CheckCallAccess(arbitraryCtx, b, nil))
+}
+
+func TestCallForPSIs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPSMR := mps.NewMockPrivateStateMetadataResolver(ctrl)
+	mockPSMR.EXPECT().ResolveForUserContext(gomock.Any()).DoAndReturn(func(ctx context.Context) (*mps.PrivateStateMetadata, error) {
+		psi, _ := rpc.PrivateStateIdentifierFromContext(ctx)
+		return mps.NewPrivateStateMetadata(psi, "", "", mps.Resident, nil), nil
+	}).AnyTimes()
+	contract := common.HexToAddress("0x1932c48b2bf8102ba33b4a6b545c32236e342f34")
+	b := &multitenantStubBackend{
+		MPSStubBackend: MPSStubBackend{psmr: mockPSMR},
+		token: &proto.PreAuthenticatedAuthenticationToken{Authorities: []*proto.GrantedAuthority{
+			{Raw: "psi://PS1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab"},
+			{Raw: "psi://PS2?node.eoa=0x0&contract.read=0x9d13c6d3afe1721beef56b55d303b09e021e27ab"},
+		}},
+	}
+	api := NewPublicBlockChainAPI(b)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	results, err := api.CallForPSIs(arbitraryCtx, nil, CallArgs{To: &contract}, latest, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*multitenancy.PrivateStateResult{
+		{PSI: "PS1", Error: multitenancy.ErrNotAuthorized.Error()},
+		{PSI: "PS2", Error: multitenancy.ErrNotAuthorized.Error()},
+	}, results)
+
+	_, err = api.CallForPSIs(arbitraryCtx, []types.PrivateStateIdentifier{"PS1", "PS3"}, CallArgs{To: &contract}, latest, nil)
+
+	assert.EqualError(t, err, "not authorized: PS3")
+}
+
 func createKeystore(t *testing.T) (*keystore.KeyStore, accounts.Account, accounts.Account) {
 	assert := assert.New(t)
 
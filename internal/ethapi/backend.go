@@ -95,12 +95,18 @@ type Backend interface {
 	CallTimeOut() time.Duration
 	// AccountExtraDataStateGetterByNumber returns state getter at a given block height
 	AccountExtraDataStateGetterByNumber(ctx context.Context, number rpc.BlockNumber) (vm.AccountExtraDataStateGetter, error)
-	PSMR() mps.PrivateStateMetadataResolver
-	SupportsMultitenancy(rpcCtx context.Context) (*proto.PreAuthenticatedAuthenticationToken, bool)
+	MultitenancyBackend
 	// IsPrivacyMarkerTransactionCreationEnabled returns true if privacy marker transactions are enabled and should be created
 	IsPrivacyMarkerTransactionCreationEnabled() bool
 }
 
+// Quorum
+// MultitenancyBackend is the part of Backend resolving the private state of the caller and its access token
+type MultitenancyBackend interface {
+	PSMR() mps.PrivateStateMetadataResolver
+	SupportsMultitenancy(rpcCtx context.Context) (*proto.PreAuthenticatedAuthenticationToken, bool)
+}
+
 func GetAPIs(apiBackend Backend) []rpc.API {
 	nonceLock := new(AddrLocker)
 	return []rpc.API{
@@ -143,6 +149,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateAccountProxyAPI(apiBackend, nonceLock),
 			Public:    false,
+		}, {
+			Namespace: "priv",
+			Version:   "1.0",
+			Service:   NewPrivatePrivacyGroupAPI(apiBackend),
+			Public:    false,
 		},
 	}
 }
@@ -0,0 +1,164 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/private/engine"
+)
+
+// Quorum
+// PrivatePrivacyGroupAPI manages the privacy groups of the private transaction manager, so that
+// GoQuorum nodes are able to use the same groups as Besu nodes sharing the Tessera network.
+// It is not exposed unless enabled explicitly, as without multiple private states any caller
+// could create or delete the groups of the node.
+type PrivatePrivacyGroupAPI struct {
+	b Backend
+}
+
+func NewPrivatePrivacyGroupAPI(b Backend) *PrivatePrivacyGroupAPI {
+	return &PrivatePrivacyGroupAPI{b}
+}
+
+// CreatePrivacyGroupArgs represents the arguments to create a new privacy group
+type CreatePrivacyGroupArgs struct {
+	// Addresses is the list of public keys of the members of the group
+	Addresses []string `json:"addresses"`
+	// From is the public key of the creator of the group, it is always a member.
+	// Empty value means the default public key of the private state of the caller.
+	From        string `json:"from"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CreatePrivacyGroup creates a new privacy group with the given members
+func (s *PrivatePrivacyGroupAPI) CreatePrivacyGroup(ctx context.Context, args CreatePrivacyGroupArgs) (*engine.PrivacyGroup, error) {
+	if len(args.Addresses) == 0 {
+		return nil, errors.New("a privacy group must have at least one member")
+	}
+	from, err := s.resolveFrom(ctx, args.From)
+	if err != nil {
+		return nil, err
+	}
+	return private.P.CreatePrivacyGroup(from, args.Name, args.Description, args.Addresses)
+}
+
+// FindPrivacyGroup returns the privacy groups made of exactly the given members
+func (s *PrivatePrivacyGroupAPI) FindPrivacyGroup(ctx context.Context, addresses []string) ([]engine.PrivacyGroup, error) {
+	groups, err := private.P.FindPrivacyGroup(addresses)
+	if err != nil {
+		return nil, err
+	}
+	if !s.b.ChainConfig().IsMPS {
+		return groups, nil
+	}
+	// only disclose the groups the private state of the caller is a member of
+	psm, err := s.b.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]engine.PrivacyGroup, 0, len(groups))
+	for _, group := range groups {
+		if !s.b.PSMR().NotIncludeAny(psm, group.Members...) {
+			result = append(result, group)
+		}
+	}
+	return result, nil
+}
+
+// DeletePrivacyGroup deletes the given privacy group and returns its id. The optional from is the public key
+// of the member deleting the group, it defaults to the default public key of the private state of the caller.
+func (s *PrivatePrivacyGroupAPI) DeletePrivacyGroup(ctx context.Context, privacyGroupId string, from *string) (string, error) {
+	if privacyGroupId == "" {
+		return "", errors.New("missing privacy group id")
+	}
+	var requestedFrom string
+	if from != nil {
+		requestedFrom = *from
+	}
+	resolvedFrom, err := s.resolveFrom(ctx, requestedFrom)
+	if err != nil {
+		return "", err
+	}
+	return private.P.DeletePrivacyGroup(resolvedFrom, privacyGroupId)
+}
+
+// resolveFrom validates that from belongs to the private state of the caller, and defaults it to
+// the first public key of that private state when empty
+func (s *PrivatePrivacyGroupAPI) resolveFrom(ctx context.Context, from string) (string, error) {
+	if !s.b.ChainConfig().IsMPS {
+		return from, nil
+	}
+	psm, err := s.b.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return resolvePrivateFrom(psm, from)
+}
+
+func resolvePrivateFrom(psm *mps.PrivateStateMetadata, from string) (string, error) {
+	if from == "" {
+		return psm.Addresses[0], nil
+	}
+	if psm.NotIncludeAny(from) {
+		return "", fmt.Errorf("the from (%s) address does not match the specified private state (%s)", from, psm.ID)
+	}
+	return from, nil
+}
+
+// resolvePrivacyGroup sets the recipients of the transaction to the members of the privacy group given
+// by PrivacyGroupId. The payload is distributed to the members of the group at the time it is sent.
+// rawTx is the signed transaction of the raw send paths, its payload has already been stored by privateFrom.
+//
+// privateFrom must be a member of the group. When it is not given it is resolved first: from the stored
+// payload of rawTx, or else the default public key of the private state of the caller. Without multiple
+// private states the default key of the private transaction manager is unknown, so it must be given.
+func (args *PrivateTxArgs) resolvePrivacyGroup(ctx context.Context, b Backend, rawTx *types.Transaction) error {
+	if args.PrivacyGroupId == "" {
+		return nil
+	}
+	if args.PrivateFor != nil {
+		return errors.New("privateFor and privacyGroupId cannot be used together")
+	}
+	if args.PrivateFrom == "" {
+		switch {
+		case rawTx != nil:
+			_, storedFrom, _, err := private.P.ReceiveRaw(common.BytesToEncryptedPayloadHash(rawTx.Data()))
+			if err != nil {
+				return err
+			}
+			args.PrivateFrom = storedFrom
+		case b.ChainConfig().IsMPS:
+			psm, err := b.PSMR().ResolveForUserContext(ctx)
+			if err != nil {
+				return err
+			}
+			args.PrivateFrom = psm.Addresses[0]
+		default:
+			return errors.New("privateFrom must be given with privacyGroupId")
+		}
+	}
+	group, err := private.P.RetrievePrivacyGroup(args.PrivacyGroupId)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve privacy group %s: %v", args.PrivacyGroupId, err)
+	}
+	isMember := false
+	privateFor := make([]string, 0, len(group.Members))
+	for _, member := range group.Members {
+		if member == args.PrivateFrom {
+			isMember = true
+			continue
+		}
+		privateFor = append(privateFor, member)
+	}
+	if !isMember {
+		return fmt.Errorf("the privateFrom (%s) address is not a member of the privacy group %s", args.PrivateFrom, args.PrivacyGroupId)
+	}
+	args.PrivateFor = privateFor
+	return nil
+}
@@ -0,0 +1,176 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/mps"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Quorum
+// MaxPrivateTransactionAuditBlockRange is the maximum number of blocks covered by a single audit trail request,
+// larger ranges are retrieved using several requests
+const MaxPrivateTransactionAuditBlockRange = 10000
+
+// PrivateTransactionAuditEntry describes a private transaction, as seen by the private state of the caller
+type PrivateTransactionAuditEntry struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	// PrivateTransactionHash is the hash of the private transaction when TransactionHash is a privacy marker transaction
+	PrivateTransactionHash *common.Hash    `json:"privateTransactionHash,omitempty"`
+	From                   common.Address  `json:"from"`
+	To                     *common.Address `json:"to"`
+	ContractAddress        *common.Address `json:"contractAddress"`
+	Status                 hexutil.Uint64  `json:"status"`
+	// extra metadata from the private transaction manager
+	SenderKey           string                 `json:"senderKey"`
+	PrivacyFlag         engine.PrivacyFlagType `json:"privacyFlag"`
+	ManagedParties      []string               `json:"managedParties"`
+	MandatoryRecipients []string               `json:"mandatoryRecipients"`
+	ACHashes            []string               `json:"affectedContractTransactions"`
+}
+
+// GetPrivateTransactionAuditTrail returns the private transactions and privacy marker transactions between fromBlock
+// and toBlock (inclusive) the private state of the caller is party to. If address is given, only the transactions
+// which called, created or emitted logs from that contract are returned.
+func (s *PublicBlockChainAPI) GetPrivateTransactionAuditTrail(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, address *common.Address) ([]*PrivateTransactionAuditEntry, error) {
+	if !private.IsQuorumPrivacyEnabled() {
+		return nil, fmt.Errorf("PrivateTransactionManager is not enabled")
+	}
+	return s.privateTransactionAuditTrail(ctx, fromBlock, toBlock, address)
+}
+
+func (s *PublicBlockChainAPI) privateTransactionAuditTrail(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, address *common.Address) ([]*PrivateTransactionAuditEntry, error) {
+	psm, err := s.b.PSMR().ResolveForUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	first, last := s.resolveAuditBlockNumber(fromBlock), s.resolveAuditBlockNumber(toBlock)
+	if first > last {
+		return nil, fmt.Errorf("invalid block range %d-%d", first, last)
+	}
+	if last-first >= MaxPrivateTransactionAuditBlockRange {
+		return nil, fmt.Errorf("block range exceeds the maximum of %d blocks", MaxPrivateTransactionAuditBlockRange)
+	}
+	entries := make([]*PrivateTransactionAuditEntry, 0)
+	for number := first; number <= last; number++ {
+		block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		blockEntries, err := s.privateTransactionAuditEntries(ctx, psm, block, address)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, blockEntries...)
+	}
+	return entries, nil
+}
+
+func (s *PublicBlockChainAPI) resolveAuditBlockNumber(number rpc.BlockNumber) uint64 {
+	if number < 0 {
+		return s.b.CurrentBlock().NumberU64()
+	}
+	return uint64(number)
+}
+
+func (s *PublicBlockChainAPI) privateTransactionAuditEntries(ctx context.Context, psm *mps.PrivateStateMetadata, block *types.Block, address *common.Address) ([]*PrivateTransactionAuditEntry, error) {
+	var (
+		entries  []*PrivateTransactionAuditEntry
+		receipts types.Receipts
+	)
+	for index, tx := range block.Transactions() {
+		if !tx.IsPrivate() && !tx.IsPrivacyMarker() {
+			continue
+		}
+		privateTx := tx
+		if tx.IsPrivacyMarker() {
+			innerTx, managedParties, _, err := private.FetchPrivateTransaction(tx.Data())
+			if err != nil {
+				return nil, err
+			}
+			if innerTx == nil || s.b.PSMR().NotIncludeAny(psm, managedParties...) {
+				continue
+			}
+			privateTx = innerTx
+		}
+		senderKey, managedParties, payload, extra, err := private.P.Receive(common.BytesToEncryptedPayloadHash(privateTx.Data()))
+		if err != nil {
+			return nil, err
+		}
+		// the private state of the caller is not a party to the transaction
+		if payload == nil || s.b.PSMR().NotIncludeAny(psm, managedParties...) {
+			continue
+		}
+		if receipts == nil {
+			if receipts, err = s.b.GetReceipts(ctx, block.Hash()); err != nil {
+				return nil, err
+			}
+		}
+		if index >= len(receipts) {
+			return nil, fmt.Errorf("receipt of transaction %s not found", tx.Hash().Hex())
+		}
+		receipt := receipts[index]
+		if tx.IsPrivacyMarker() {
+			if receipt = receipt.PSReceipts[psm.ID]; receipt == nil {
+				return nil, fmt.Errorf("receipt of private transaction %s not found", privateTx.Hash().Hex())
+			}
+		}
+		if address != nil && !touchesContract(privateTx, receipt, *address) {
+			continue
+		}
+		entries = append(entries, newPrivateTransactionAuditEntry(block, uint64(index), tx, privateTx, receipt, senderKey, extra))
+	}
+	return entries, nil
+}
+
+func touchesContract(tx *types.Transaction, receipt *types.Receipt, address common.Address) bool {
+	if (tx.To() != nil && *tx.To() == address) || receipt.ContractAddress == address {
+		return true
+	}
+	for _, l := range receipt.Logs {
+		if l.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+func newPrivateTransactionAuditEntry(block *types.Block, index uint64, tx, privateTx *types.Transaction, receipt *types.Receipt, senderKey string, extra *engine.ExtraMetadata) *PrivateTransactionAuditEntry {
+	entry := &PrivateTransactionAuditEntry{
+		BlockNumber:      hexutil.Uint64(block.NumberU64()),
+		BlockHash:        block.Hash(),
+		TransactionHash:  tx.Hash(),
+		TransactionIndex: hexutil.Uint64(index),
+		From:             privateTx.From(),
+		To:               privateTx.To(),
+		Status:           hexutil.Uint64(receipt.Status),
+		SenderKey:        senderKey,
+	}
+	if tx != privateTx {
+		hash := privateTx.Hash()
+		entry.PrivateTransactionHash = &hash
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		contractAddress := receipt.ContractAddress
+		entry.ContractAddress = &contractAddress
+	}
+	if extra != nil {
+		entry.PrivacyFlag = extra.PrivacyFlag
+		entry.ManagedParties = extra.ManagedParties
+		entry.MandatoryRecipients = extra.MandatoryRecipients
+		entry.ACHashes = extra.ACHashes.ToBase64s()
+	}
+	return entry
+}
@@ -41,6 +41,7 @@ var Modules = map[string]string{
 	"quorumExtension":  Extension_JS,
 	"plugin_account":   Account_Plugin_Js,
 	"qlight":           QLight_JS,
+	"priv":             Priv_JS,
 }
 
 const ChequebookJs = `
@@ -231,6 +232,30 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'purgePrivatePayloadCache',
+			call: 'admin_purgePrivatePayloadCache'
+		}),
+		new web3._extend.Method({
+			name: 'refreshPrivateTransactionManager',
+			call: 'admin_refreshPrivateTransactionManager',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'refreshResidentGroups',
+			call: 'admin_refreshResidentGroups'
+		}),
+		new web3._extend.Method({
+			name: 'reloadPrivateTransactionManagerTLS',
+			call: 'admin_reloadPrivateTransactionManagerTLS'
+		}),
+		new web3._extend.Method({
+			name: 'checkPrivatePayloads',
+			call: 'admin_checkPrivatePayloads',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -312,6 +337,12 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'privateStateDiff',
+			call: 'debug_privateStateDiff',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'dumpAddress',
 			call: 'debug_dumpAddress',
@@ -661,6 +692,12 @@ web3._extend({
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'getPrivateTransactionAuditTrail',
+			call: 'eth_getPrivateTransactionAuditTrail',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'decryptQuorumPayload',
 			call: 'eth_decryptQuorumPayload',
@@ -684,6 +721,22 @@ web3._extend({
             params: 1,
             outputFormatter: web3._extend.formatters.outputTransactionReceiptFormatter
         }),
+		new web3._extend.Method({
+			name: 'getPrivateTransactionReceiptForPSIs',
+			call: 'eth_getPrivateTransactionReceiptForPSIs',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'callForPSIs',
+			call: 'eth_callForPSIs',
+			params: 4,
+			inputFormatter: [null, web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getLogsForPSIs',
+			call: 'eth_getLogsForPSIs',
+			params: 2
+		}),
 		// END-QUORUM
 	],
 	properties: [
@@ -1016,6 +1069,18 @@ web3._extend({
                        params: 3,
                        inputFormatter: [null,null,web3._extend.formatters.inputTransactionFormatter]
                }),
+               new web3._extend.Method({
+                       name: 'addCallPermission',
+                       call: 'quorumPermission_addCallPermission',
+                       params: 5,
+                       inputFormatter: [null,null,web3._extend.formatters.inputAddressFormatter,null,web3._extend.formatters.inputTransactionFormatter]
+               }),
+               new web3._extend.Method({
+                       name: 'removeCallPermission',
+                       call: 'quorumPermission_removeCallPermission',
+                       params: 5,
+                       inputFormatter: [null,null,web3._extend.formatters.inputAddressFormatter,null,web3._extend.formatters.inputTransactionFormatter]
+               }),
                new web3._extend.Method({
                        name: 'addAccountToOrg',
                        call: 'quorumPermission_addAccountToOrg',
@@ -1076,6 +1141,46 @@ web3._extend({
                        params: 4,
                        inputFormatter: [null, null, null, null]
                }),
+               new web3._extend.Method({
+                       name: 'explainTransactionAllowed',
+                       call: 'quorumPermission_explainTransactionAllowed',
+                       params: 1,
+                       inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
+               }),
+               new web3._extend.Method({
+                       name: 'explainConnectionAllowed',
+                       call: 'quorumPermission_explainConnectionAllowed',
+                       params: 4,
+                       inputFormatter: [null, null, null, null]
+               }),
+               new web3._extend.Method({
+                       name: 'planPermissions',
+                       call: 'quorumPermission_planPermissions',
+                       params: 2,
+                       inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+               }),
+               new web3._extend.Method({
+                       name: 'applyPermissions',
+                       call: 'quorumPermission_applyPermissions',
+                       params: 2,
+                       inputFormatter: [null, web3._extend.formatters.inputTransactionFormatter]
+               }),
+               new web3._extend.Method({
+                       name: 'history',
+                       call: 'quorumPermission_history',
+                       params: 1
+               }),
+               new web3._extend.Method({
+                       name: 'migrationPlan',
+                       call: 'quorumPermission_migrationPlan',
+                       params: 1
+               }),
+               new web3._extend.Method({
+                       name: 'verifyMigration',
+                       call: 'quorumPermission_verifyMigration',
+                       params: 1,
+                       inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+               }),
 
        ],
        properties:
@@ -1096,6 +1201,10 @@ web3._extend({
 					   name: 'acctList',
 				       getter: 'quorumPermission_acctList'
 			  }),
+              new web3._extend.Property({
+					   name: 'callPermissionList',
+				       getter: 'quorumPermission_callPermissionList'
+			  }),
        ]
 })
 `
@@ -1325,6 +1434,31 @@ web3._extend({
 });
 `
 
+const Priv_JS = `
+web3._extend({
+	property: 'priv',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'createPrivacyGroup',
+			call: 'priv_createPrivacyGroup',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'findPrivacyGroup',
+			call: 'priv_findPrivacyGroup',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'deletePrivacyGroup',
+			call: 'priv_deletePrivacyGroup',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+	]
+});
+`
+
 const Account_Plugin_Js = `
 web3._extend({
 	property: 'plugin_account',
@@ -16,6 +16,7 @@ var (
 	ErrNotAuthorized    = errors.New("not authorized")
 	ErrPSIFoundMultiple = errors.New("found multiple authorized private state identifiers")
 	ErrPSINotFound      = errors.New("no private state identifiers found")
+	ErrNoPSIsRequested  = errors.New("no private state identifiers requested")
 )
 
 // IsAuthorized performs authorization check for security attributes against
@@ -43,6 +44,13 @@ func isAuthorized(authToken *proto.PreAuthenticatedAuthenticationToken, attr *Pr
 	if attr.selfEOA != nil {
 		query.Set(QuerySelfEOA, toHexAddress(attr.selfEOA))
 	}
+	if attr.contract != nil {
+		if attr.contractReadOnly {
+			query.Set(QueryContractRead, toHexAddress(attr.contract))
+		} else {
+			query.Set(QueryContract, toHexAddress(attr.contract))
+		}
+	}
 	// construct the request
 	askValue, err := url.Parse(fmt.Sprintf("%s://%s?%s", SchemePSI, attr.psi, query.Encode()))
 	if err != nil {
@@ -110,6 +118,56 @@ func ExtractPSI(authToken *proto.PreAuthenticatedAuthenticationToken) (types.Pri
 	return authorizedPSI, nil
 }
 
+// ExtractPSIs returns all the distinct PSIs found in the granted scope.
+// If there is none, return error
+func ExtractPSIs(authToken *proto.PreAuthenticatedAuthenticationToken) ([]types.PrivateStateIdentifier, error) {
+	var psis []types.PrivateStateIdentifier
+	seen := make(map[types.PrivateStateIdentifier]bool)
+	for _, granted := range authToken.GetAuthorities() {
+		grantedValue, err := url.Parse(granted.GetRaw())
+		if err != nil || grantedValue.Scheme != SchemePSI {
+			continue
+		}
+		grantedPSI := types.PrivateStateIdentifier(grantedValue.Host)
+		if seen[grantedPSI] {
+			continue
+		}
+		seen[grantedPSI] = true
+		psis = append(psis, grantedPSI)
+	}
+	if len(psis) == 0 {
+		return nil, ErrPSINotFound
+	}
+	return psis, nil
+}
+
+// AuthorizePSIs returns the PSIs a multi-PSI read fans out over. When no PSI is requested,
+// it fans out over all the PSIs granted in the access token. It fails if any requested PSI
+// is not granted, so a read never silently skips a private state.
+//
+// authToken is nil when multitenancy is disabled, in which case the PSIs must be requested.
+func AuthorizePSIs(authToken *proto.PreAuthenticatedAuthenticationToken, psis []types.PrivateStateIdentifier) ([]types.PrivateStateIdentifier, error) {
+	if len(psis) == 0 {
+		if authToken == nil {
+			return nil, ErrNoPSIsRequested
+		}
+		return ExtractPSIs(authToken)
+	}
+	if authToken == nil {
+		return psis, nil
+	}
+	for _, psi := range psis {
+		isAuthorized, err := IsPSIAuthorized(authToken, psi)
+		if err != nil {
+			return nil, err
+		}
+		if !isAuthorized {
+			return nil, fmt.Errorf("%w: %s", ErrNotAuthorized, psi)
+		}
+	}
+	return psis, nil
+}
+
 func toHexAddress(a *common.Address) string {
 	if a == nil {
 		return ""
@@ -127,9 +185,34 @@ func match(ask, granted *url.URL) bool {
 }
 
 func matchQuery(ask, granted url.Values) bool {
+	return matchEOAs(ask, granted) && matchContract(ask, granted)
+}
+
+func matchEOAs(ask, granted url.Values) bool {
+	// reading a contract is not done on behalf of an EOA, a scope only restricts it to the contracts
+	// it lists, see matchContract
+	if len(ask[QueryNodeEOA]) == 0 && len(ask[QuerySelfEOA]) == 0 && len(ask[QueryContractRead]) > 0 {
+		return true
+	}
 	return matchEOA(granted[QueryNodeEOA], ask[QueryNodeEOA]) || matchEOA(granted[QuerySelfEOA], ask[QuerySelfEOA])
 }
 
+// matchContract checks the contract being accessed against the contracts of the granted
+// scope. A scope without any contract is not restricted to particular contracts.
+func matchContract(ask, granted url.Values) bool {
+	if len(granted[QueryContract]) == 0 && len(granted[QueryContractRead]) == 0 {
+		return true
+	}
+	if askContracts := ask[QueryContract]; len(askContracts) > 0 {
+		return common.ContainsAll(granted[QueryContract], []string{AnyContractAddress}, askContracts)
+	}
+	if askContracts := ask[QueryContractRead]; len(askContracts) > 0 {
+		readable := append(append([]string{}, granted[QueryContract]...), granted[QueryContractRead]...)
+		return common.ContainsAll(readable, []string{AnyContractAddress}, askContracts)
+	}
+	return true
+}
+
 func matchEOA(grantedEOAs []string, askEOAs []string) bool {
 	if len(grantedEOAs) == 0 || len(askEOAs) == 0 {
 		return false
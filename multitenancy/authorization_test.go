@@ -1,6 +1,7 @@
 package multitenancy
 
 import (
+	"errors"
 	"net/url"
 	"os"
 	"testing"
@@ -211,6 +212,143 @@ func TestAuthorize(t *testing.T) {
 	}
 }
 
+func TestAuthorize_whenContractScoped(t *testing.T) {
+	var (
+		contractA = common.HexToAddress("0x9d13c6d3afe1721beef56b55d303b09e021e27ab")
+		contractB = common.HexToAddress("0x1932c48b2bf8102ba33b4a6b545c32236e342f34")
+		eoa       = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+	)
+	testCases := []testCase{
+		{
+			msg: "Not restricted to contracts, write",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithNodeEOA(eoa).WithContract(&contractA),
+			isAuthorized: true,
+		},
+		{
+			msg: "Not restricted to contracts, read",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(contractA),
+			isAuthorized: true,
+		},
+		{
+			msg: "Granted contract, write",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithNodeEOA(eoa).WithContract(&contractA),
+			isAuthorized: true,
+		},
+		{
+			msg: "Granted contract, read",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(contractA),
+			isAuthorized: true,
+		},
+		{
+			msg: "Granted contract, write to another contract",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithNodeEOA(eoa).WithContract(&contractB),
+			isAuthorized: false,
+		},
+		{
+			msg: "Granted contract, read another contract",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(contractB),
+			isAuthorized: false,
+		},
+		{
+			msg: "Granted contract, create a contract",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithNodeEOA(eoa).WithContract(nil),
+			isAuthorized: false,
+		},
+		{
+			msg: "Granted any contract, create a contract",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract=0x0",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithNodeEOA(eoa).WithContract(nil),
+			isAuthorized: true,
+		},
+		{
+			msg: "Granted read only contract, write",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract.read=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithNodeEOA(eoa).WithContract(&contractA),
+			isAuthorized: false,
+		},
+		{
+			msg: "Granted read any contract and write one contract, read another contract",
+			granted: []string{
+				"psi://arbitrary.ps1?self.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab&contract.read=0x0",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(contractB),
+			isAuthorized: true,
+		},
+		{
+			msg: "Granted contract with a different EOA, write",
+			granted: []string{
+				"psi://arbitrary.ps1?self.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithNodeEOA(eoa).WithContract(&contractA),
+			isAuthorized: false,
+		},
+		{
+			msg: "Granted specific EOA not restricted to contracts, read",
+			granted: []string{
+				"psi://arbitrary.ps1?self.eoa=0x000000000000000000000000000000000000aaaa",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(contractA),
+			isAuthorized: true,
+		},
+		{
+			msg: "Granted specific EOA and read only contract, read",
+			granted: []string{
+				"psi://arbitrary.ps1?self.eoa=0x000000000000000000000000000000000000aaaa&contract.read=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(contractA),
+			isAuthorized: true,
+		},
+		{
+			msg: "Granted contract, read any contract",
+			granted: []string{
+				"psi://arbitrary.ps1?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(common.Address{}),
+			isAuthorized: false,
+		},
+		{
+			msg: "Granted contract in another PSI, read",
+			granted: []string{
+				"psi://arbitrary.ps2?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab",
+			},
+			ask:          (&PrivateStateSecurityAttribute{}).WithPSI("arbitrary.ps1").WithContractRead(contractA),
+			isAuthorized: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		log.Debug("Test case :: " + tc.msg)
+		actual, err := IsAuthorized(toToken(tc.granted), tc.ask)
+		assert.NoError(t, err, tc.msg)
+		assert.Equal(t, tc.isAuthorized, actual, tc.msg)
+	}
+}
+
 func toToken(granted []string) *proto.PreAuthenticatedAuthenticationToken {
 	values := make([]*proto.GrantedAuthority, len(granted))
 	for i, g := range granted {
@@ -251,3 +389,36 @@ func TestExtractPSI_whenFoundMultiple(t *testing.T) {
 
 	assert.EqualError(t, err, ErrPSIFoundMultiple.Error())
 }
+
+func TestAuthorizePSIs_whenTypical(t *testing.T) {
+	token := toToken([]string{
+		"psi://arbitrary.psi1?contract.read=0x0",
+		"psi://arbitrary.psi2",
+		"psi://arbitrary.psi1",
+		"rpc://eth_call",
+	})
+
+	psis, err := AuthorizePSIs(token, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PrivateStateIdentifier{"arbitrary.psi1", "arbitrary.psi2"}, psis)
+
+	psis, err = AuthorizePSIs(token, []types.PrivateStateIdentifier{"arbitrary.psi2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PrivateStateIdentifier{"arbitrary.psi2"}, psis)
+}
+
+func TestAuthorizePSIs_whenNotAuthorized(t *testing.T) {
+	_, err := AuthorizePSIs(toToken([]string{"psi://arbitrary.psi1"}), []types.PrivateStateIdentifier{"arbitrary.psi1", "arbitrary.psi2"})
+
+	assert.True(t, errors.Is(err, ErrNotAuthorized))
+	assert.EqualError(t, err, "not authorized: arbitrary.psi2")
+}
+
+func TestAuthorizePSIs_whenMultitenancyDisabled(t *testing.T) {
+	_, err := AuthorizePSIs(nil, nil)
+	assert.Equal(t, ErrNoPSIsRequested, err)
+
+	psis, err := AuthorizePSIs(nil, []types.PrivateStateIdentifier{"arbitrary.psi1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PrivateStateIdentifier{"arbitrary.psi1"}, psis)
+}
@@ -12,4 +12,20 @@
 //   - Specific:
 //     `psi://MY_PSI?node.eoa=0xdf08aad9d60f2227fdaed44dffd22753faf3d676`
 //     `psi://MY_PSI?self.eoa=0x1234aad9d60f2227fdaed44dffd22753faf3d676`
+//
+// # Query param `contract` and `contract.read` restrict the scope to contracts, can be multiple
+//
+// A scope without any of them grants access to every contract of the private state.
+// `contract` grants read and write access, `contract.read` grants read access only.
+// Contract creation requires write access to any contract (`contract=0x0`).
+// Reading a contract (`eth_call`, `eth_estimateGas`, `debug_traceCall`, `eth_getCode`, `eth_getStorageAt`
+// and logs, including the logs of receipts) does not require an EOA. A scope listing contracts only grants
+// reading these contracts, a scope without any contract grants reading every contract of the private state.
+// A call without a recipient requires read access to any contract (`contract.read=0x0`).
+//
+// Scope examples:
+//   - `psi://MY_PSI?node.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab`:
+//     any node-managed EOA can send transactions to and read the given contract only
+//   - `psi://MY_PSI?self.eoa=0x0&contract=0x9d13c6d3afe1721beef56b55d303b09e021e27ab&contract.read=0x0`:
+//     any self-managed EOA can send transactions to the given contract and read any contract
 package multitenancy
@@ -14,10 +14,23 @@ const (
 	QueryNodeEOA = "node.eoa"
 	// QuerySelfEOA query parameter captures the self-manged EOA address in the URL-based access scope
 	QuerySelfEOA = "self.eoa"
+	// QueryContract query parameter captures a contract address which can be read and written in the URL-based access scope
+	QueryContract = "contract"
+	// QueryContractRead query parameter captures a contract address which can only be read in the URL-based access scope
+	QueryContractRead = "contract.read"
 	// AnyEOAAddress represents wild card for EOA address
 	AnyEOAAddress = "0x0"
+	// AnyContractAddress represents wild card for contract address
+	AnyContractAddress = "0x0"
 )
 
+// PrivateStateResult is the outcome of a multi-PSI read for one of the private states
+type PrivateStateResult struct {
+	PSI    types.PrivateStateIdentifier `json:"psi"`
+	Result interface{}                  `json:"result,omitempty"`
+	Error  string                       `json:"error,omitempty"`
+}
+
 // PrivateStateSecurityAttribute contains security configuration ask
 // which are defined for a secure private state
 type PrivateStateSecurityAttribute struct {
@@ -28,10 +41,15 @@ type PrivateStateSecurityAttribute struct {
 	// the self-managed Externally Owned Account being used to sign transactions
 	// impacting the private state
 	selfEOA *common.Address
+	// the contract being accessed in the private state, nil if the access
+	// is not restricted to a contract
+	contract *common.Address
+	// true if the contract is only being read
+	contractReadOnly bool
 }
 
 func (pssa *PrivateStateSecurityAttribute) String() string {
-	return fmt.Sprintf("psi=%s node.eoa=%s self.eoa=%s", pssa.psi, toHexAddress(pssa.nodeEOA), toHexAddress(pssa.selfEOA))
+	return fmt.Sprintf("psi=%s node.eoa=%s self.eoa=%s contract=%s read-only=%v", pssa.psi, toHexAddress(pssa.nodeEOA), toHexAddress(pssa.selfEOA), toHexAddress(pssa.contract), pssa.contractReadOnly)
 }
 
 func (pssa *PrivateStateSecurityAttribute) WithPSI(psi types.PrivateStateIdentifier) *PrivateStateSecurityAttribute {
@@ -59,3 +77,19 @@ func (pssa *PrivateStateSecurityAttribute) WithSelfEOA(eoa common.Address) *Priv
 	pssa.selfEOA, pssa.nodeEOA = &eoa, nil
 	return pssa
 }
+
+// WithContract set the contract being written, nil being a contract creation which
+// requires access to any contract
+func (pssa *PrivateStateSecurityAttribute) WithContract(contract *common.Address) *PrivateStateSecurityAttribute {
+	if contract == nil {
+		contract = &common.Address{}
+	}
+	pssa.contract, pssa.contractReadOnly = contract, false
+	return pssa
+}
+
+// WithContractRead set the contract being read. Reading a contract does not require an EOA
+func (pssa *PrivateStateSecurityAttribute) WithContractRead(contract common.Address) *PrivateStateSecurityAttribute {
+	pssa.contract, pssa.contractReadOnly = &contract, true
+	return pssa
+}
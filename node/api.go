@@ -185,6 +185,8 @@ func (api *privateAdminAPI) StartHTTP(host *string, port *int, cors *string, api
 		CorsAllowedOrigins: api.node.config.HTTPCors,
 		Vhosts:             api.node.config.HTTPVirtualHosts,
 		Modules:            api.node.config.HTTPModules,
+		auditSink:          api.node.auditSinkFor(api.node.config.HTTPAudit),
+		rateLimiter:        api.node.rpcRateLimiter,
 	}
 	if cors != nil {
 		config.CorsAllowedOrigins = nil
@@ -264,6 +266,8 @@ func (api *privateAdminAPI) StartWS(host *string, port *int, allowedOrigins *str
 		Modules: api.node.config.WSModules,
 		Origins: api.node.config.WSOrigins,
 		// ExposeAll: api.node.config.WSExposeAll,
+		auditSink:   api.node.auditSinkFor(api.node.config.WSAudit),
+		rateLimiter: api.node.rpcRateLimiter,
 	}
 	if apis != nil {
 		config.Modules = nil
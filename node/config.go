@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -48,6 +49,7 @@ const (
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	datadirRPCAuditLog     = "rpc-audit.log"      // Path within the datadir to the RPC audit log
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -209,6 +211,36 @@ type Config struct {
 	Plugins              *plugin.Settings `toml:",omitempty"`
 	EnableNodePermission bool             `toml:",omitempty"` // comes from EnableNodePermissionFlag --permissioned.
 	EnableMultitenancy   bool             `toml:",omitempty"` // comes from MultitenancyFlag flag
+
+	// HTTPAudit, WSAudit and IPCAudit record the authentication and authorization decisions
+	// made by the respective RPC endpoint to the RPC audit log
+	HTTPAudit bool `toml:",omitempty"`
+	WSAudit   bool `toml:",omitempty"`
+	IPCAudit  bool `toml:",omitempty"`
+	// RPCAuditLog is the path of the RPC audit log, defaults to rpc-audit.log in the instance directory
+	RPCAuditLog string `toml:",omitempty"`
+	// RPCAuditLogMaxSize is the size in megabytes after which the RPC audit log is rotated, 0 disables the rotation
+	RPCAuditLogMaxSize int `toml:",omitempty"`
+
+	// JWTAuthentication enables the built-in authentication of the RPC requests with JWT access tokens,
+	// used when the security plugin is not configured
+	JWTAuthentication *security.JWTAuthenticationConfig `toml:",omitempty"`
+
+	// RPCRateLimit throttles the calls each principal makes over HTTP and WS
+	RPCRateLimit *rpc.RateLimitConfig `toml:",omitempty"`
+}
+
+// Quorum
+// RPCAuditLogPath resolves the path of the RPC audit log, returns an empty string if none of
+// the RPC endpoints is audited.
+func (c *Config) RPCAuditLogPath() string {
+	if !c.HTTPAudit && !c.WSAudit && !c.IPCAudit {
+		return ""
+	}
+	if c.RPCAuditLog != "" {
+		return c.RPCAuditLog
+	}
+	return c.ResolvePath(datadirRPCAuditLog)
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
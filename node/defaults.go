@@ -51,6 +51,7 @@ var DefaultConfig = Config{
 		MaxPeers:   50,
 		NAT:        nat.Any(),
 	},
+	RPCAuditLogMaxSize: 100,
 }
 
 // DefaultDataDir is the default data directory to use for the databases and other
@@ -65,7 +65,11 @@ type Node struct {
 	databases map[*closeTrackingDB]struct{} // All open databases
 
 	// Quorum
-	pluginManager *plugin.PluginManager // Manage all plugins for this node. If plugin is not enabled, an EmptyPluginManager is set.
+	pluginManager  *plugin.PluginManager              // Manage all plugins for this node. If plugin is not enabled, an EmptyPluginManager is set.
+	rpcAuditSink   rpc.AuditSink                      // Records the decisions of the audited RPC endpoints, nil if none is audited
+	rpcAuditLog    *rpc.FileAuditSink                 // The RPC audit log opened by the node, closed with the node
+	jwtAuthManager *security.JWTAuthenticationManager // Built-in authentication manager, nil if not configured
+	rpcRateLimiter *rpc.RateLimiter                   // Throttles the calls of each principal over HTTP and WS, nil if not configured
 	// End Quorum
 }
 
@@ -176,10 +180,31 @@ func New(conf *Config) (*Node, error) {
 		return nil, err
 	}
 
+	// Quorum
+	if conf.JWTAuthentication != nil {
+		if node.jwtAuthManager, err = security.NewJWTAuthenticationManager(conf.JWTAuthentication); err != nil {
+			return nil, err
+		}
+	}
+	if conf.RPCRateLimit != nil && len(conf.RPCRateLimit.Rules) > 0 {
+		if node.rpcRateLimiter, err = rpc.NewRateLimiter(conf.RPCRateLimit); err != nil {
+			return nil, err
+		}
+	}
+	if path := conf.RPCAuditLogPath(); path != "" {
+		if node.rpcAuditLog, err = rpc.NewFileAuditSink(path, int64(conf.RPCAuditLogMaxSize)*1024*1024); err != nil {
+			return nil, err
+		}
+		node.rpcAuditSink = node.rpcAuditLog
+	} else if conf.HTTPAudit || conf.WSAudit || conf.IPCAudit {
+		return nil, errors.New("RPC audit log requires a data directory or an explicit path")
+	}
+	// End Quorum
+
 	// Configure RPC servers.
 	node.http = newHTTPServer(node.log, conf.HTTPTimeouts).withMultitenancy(node.config.EnableMultitenancy)
 	node.ws = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts).withMultitenancy(node.config.EnableMultitenancy)
-	node.ipc = newIPCServer(node.log, conf.IPCEndpoint()).withMultitenancy(node.config.EnableMultitenancy)
+	node.ipc = newIPCServer(node.log, conf.IPCEndpoint()).withMultitenancy(node.config.EnableMultitenancy).withAudit(node.auditSinkFor(conf.IPCAudit))
 
 	return node, nil
 }
@@ -281,6 +306,14 @@ func (n *Node) doClose(errs []error) error {
 		}
 	}
 
+	// Quorum
+	n.closeRPCAuditSink()
+	if n.rpcAuditLog != nil {
+		if err := n.rpcAuditLog.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// Release instance directory lock.
 	n.closeDataDir()
 
@@ -335,6 +368,10 @@ func containsLifecycle(lfs []Lifecycle, l Lifecycle) bool {
 // It is the inverse of Start.
 func (n *Node) stopServices(running []Lifecycle) error {
 	n.stopRPC()
+	// Quorum
+	// pass the pending RPC audit records on before the audit plugin is stopped
+	n.closeRPCAuditSink()
+	// End Quorum
 
 	// Stop running lifecycles in reverse order.
 	failure := &StopError{Services: make(map[reflect.Type]error)}
@@ -419,6 +456,8 @@ func (n *Node) startRPC() error {
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
+			auditSink:          n.auditSinkFor(n.config.HTTPAudit),
+			rateLimiter:        n.rpcRateLimiter,
 		}
 		server := n.http
 		if err := server.setListenAddr(n.config.HTTPHost, n.config.HTTPPort); err != nil {
@@ -433,9 +472,11 @@ func (n *Node) startRPC() error {
 	if n.config.WSHost != "" {
 		server := n.wsServerForPort(n.config.WSPort)
 		config := wsConfig{
-			Modules: n.config.WSModules,
-			Origins: n.config.WSOrigins,
-			prefix:  n.config.WSPathPrefix,
+			Modules:     n.config.WSModules,
+			Origins:     n.config.WSOrigins,
+			prefix:      n.config.WSPathPrefix,
+			auditSink:   n.auditSinkFor(n.config.WSAudit),
+			rateLimiter: n.rpcRateLimiter,
 		}
 		if err := server.setListenAddr(n.config.WSHost, n.config.WSPort); err != nil {
 			return err
@@ -458,6 +499,22 @@ func (n *Node) wsServerForPort(port int) *httpServer {
 	return n.ws
 }
 
+// Quorum
+// auditSinkFor returns the sink recording the decisions of an RPC endpoint, nil if the endpoint is not audited
+func (n *Node) auditSinkFor(audited bool) rpc.AuditSink {
+	if !audited {
+		return nil
+	}
+	return n.rpcAuditSink
+}
+
+// closeRPCAuditSink stops the asynchronous sink set with SetRPCAuditSink, closing it more than once is harmless
+func (n *Node) closeRPCAuditSink() {
+	if sink, ok := n.rpcAuditSink.(interface{ Close() }); ok {
+		sink.Close()
+	}
+}
+
 func (n *Node) stopRPC() {
 	n.http.stop()
 	n.ws.stop()
@@ -533,6 +590,27 @@ func (n *Node) RegisterAPIs(apis []rpc.API) {
 	n.rpcAPIs = append(n.rpcAPIs, apis...)
 }
 
+// Quorum
+// SetRPCAuditSink replaces the RPC audit log as the sink recording the decisions of the audited
+// RPC endpoints, e.g. with a sink forwarding the records to the audit plugin. A sink with a
+// Close() method, such as rpc.AsyncAuditSink, is closed once the RPC endpoints are stopped.
+func (n *Node) SetRPCAuditSink(sink rpc.AuditSink) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != initializingState {
+		panic("can't set RPC audit sink on running/stopped node")
+	}
+	if n.rpcAuditLog != nil {
+		if err := n.rpcAuditLog.Close(); err != nil {
+			n.log.Warn("Failed to close RPC audit log", "err", err)
+		}
+		n.rpcAuditLog = nil
+	}
+	n.rpcAuditSink = sink
+	n.ipc.withAudit(n.auditSinkFor(n.config.IPCAudit))
+}
+
 // RegisterHandler mounts a handler on the given path on the canonical HTTP server.
 //
 // The name of the handler is shown in a log message when the HTTP server starts
@@ -745,12 +823,26 @@ func (n *Node) GetSecuritySupports() (tlsConfigSource security.TLSConfigurationS
 		if authManager, err = sp.AuthenticationManager(); err != nil {
 			return
 		}
+		if n.jwtAuthManager != nil {
+			err = errors.New("the built-in JWT authentication cannot be used together with the security plugin")
+		}
+	} else if n.jwtAuthManager != nil {
+		log.Info("Security Plugin is not enabled, using the built-in JWT authentication")
+		authManager = n.jwtAuthManager
 	} else {
 		log.Info("Security Plugin is not enabled")
 	}
 	return
 }
 
+// Quorum
+//
+// IsAuthenticationEnabled returns true if the RPC requests are authenticated, either by the security
+// plugin or by the built-in JWT authentication
+func (n *Node) IsAuthenticationEnabled() bool {
+	return n.pluginManager.IsEnabled(plugin.SecurityPluginInterfaceName) || n.jwtAuthManager != nil
+}
+
 // Quorum
 //
 // delegate call to node.Config
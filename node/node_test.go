@@ -17,6 +17,7 @@
 package node
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -32,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/plugin"
+	"github.com/ethereum/go-ethereum/plugin/security"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
 )
@@ -590,6 +593,108 @@ func (test rpcPrefixTest) check(t *testing.T, node *Node) {
 	}
 }
 
+// Quorum
+// Tests that only the decisions of the audited RPC endpoints are recorded to the RPC audit log.
+func TestNodeRPCAudit(t *testing.T) {
+	conf := &Config{
+		DataDir:   t.TempDir(),
+		HTTPHost:  "127.0.0.1",
+		WSHost:    "127.0.0.1",
+		WSPort:    0,
+		HTTPAudit: true,
+		P2P:       p2p.Config{PrivateKey: testNodeKey},
+	}
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	defer node.Close()
+
+	resp := rpcRequest(t, node.HTTPEndpoint())
+	resp.Body.Close()
+	ws, err := rpc.Dial(node.WSEndpoint())
+	if err != nil {
+		t.Fatalf("could not dial websocket endpoint: %v", err)
+	}
+	assert.NoError(t, ws.Call(nil, "rpc_modules"))
+	ws.Close()
+	assert.NoError(t, node.Close())
+
+	blob, err := ioutil.ReadFile(conf.RPCAuditLogPath())
+	assert.NoError(t, err)
+	var records []rpc.AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(blob)), "\n") {
+		var record rpc.AuditRecord
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, rpc.AuditTransportHTTP, records[0].Transport)
+		assert.Equal(t, "rpc_modules", records[0].Method)
+		assert.Equal(t, rpc.AuditDecisionAllowed, records[0].Decision)
+	}
+}
+
+// Quorum
+// Tests that the sink set with SetRPCAuditSink, e.g. the audit plugin, replaces the RPC audit log
+// and receives the pending records before the node stops.
+func TestNodeSetRPCAuditSink(t *testing.T) {
+	conf := &Config{
+		DataDir:   t.TempDir(),
+		HTTPHost:  "127.0.0.1",
+		HTTPAudit: true,
+		P2P:       p2p.Config{PrivateKey: testNodeKey},
+	}
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	var records []*rpc.AuditRecord
+	node.SetRPCAuditSink(rpc.NewAsyncAuditSink(func(record *rpc.AuditRecord) {
+		records = append(records, record)
+	}, rpc.DefaultAuditBufferSize))
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+
+	resp := rpcRequest(t, node.HTTPEndpoint())
+	resp.Body.Close()
+	assert.NoError(t, node.Close())
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "rpc_modules", records[0].Method)
+	}
+	blob, _ := ioutil.ReadFile(conf.RPCAuditLogPath())
+	assert.Empty(t, blob)
+}
+
+// Quorum
+// Tests that the built-in JWT authentication manager protects the RPC servers when the security plugin is not enabled.
+func TestNodeJWTAuthentication(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, ioutil.WriteFile(secretFile, []byte("arbitrary secret"), 0600))
+	conf := testNodeConfig()
+	conf.JWTAuthentication = &security.JWTAuthenticationConfig{HMACSecretFile: secretFile}
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	defer node.Close()
+
+	_, authManager, err := node.GetSecuritySupports()
+
+	assert.NoError(t, err)
+	assert.IsType(t, &security.JWTAuthenticationManager{}, authManager)
+	assert.True(t, node.IsAuthenticationEnabled())
+
+	conf.JWTAuthentication = &security.JWTAuthenticationConfig{}
+	_, err = New(conf)
+	assert.Error(t, err)
+}
+
 func createNode(t *testing.T, httpPort, wsPort int) *Node {
 	conf := &Config{
 		HTTPHost: "127.0.0.1",
@@ -41,6 +41,10 @@ type httpConfig struct {
 	CorsAllowedOrigins []string
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
+
+	// Quorum
+	auditSink   rpc.AuditSink    // records the authentication and authorization decisions, nil if not audited
+	rateLimiter *rpc.RateLimiter // throttles the calls of each principal, nil if not rate limited
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
@@ -48,6 +52,10 @@ type wsConfig struct {
 	Origins []string
 	Modules []string
 	prefix  string // path prefix on which to mount ws handler
+
+	// Quorum
+	auditSink   rpc.AuditSink    // records the authentication and authorization decisions, nil if not audited
+	rateLimiter *rpc.RateLimiter // throttles the calls of each principal, nil if not rate limited
 }
 
 type rpcHandler struct {
@@ -295,6 +303,12 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig, authManager se
 
 	// Create RPC server and handler.
 	srv := rpc.NewProtectedServer(authManager, h.isMultitenant)
+	if config.auditSink != nil {
+		srv.EnableAudit(config.auditSink)
+	}
+	if config.rateLimiter != nil {
+		srv.EnableRateLimit(config.rateLimiter)
+	}
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
@@ -328,6 +342,12 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig, authManager secur
 
 	// Create RPC server and handler.
 	srv := rpc.NewProtectedServer(authManager, h.isMultitenant)
+	if config.auditSink != nil {
+		srv.EnableAudit(config.auditSink)
+	}
+	if config.rateLimiter != nil {
+		srv.EnableRateLimit(config.rateLimiter)
+	}
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
@@ -496,6 +516,8 @@ type ipcServer struct {
 	// Quorum
 	// isMultitenant determines if the server supports mutlitenancy
 	isMultitenant bool
+	// auditSink records the decisions made for the IPC calls, nil if not audited
+	auditSink rpc.AuditSink
 }
 
 func newIPCServer(log log.Logger, endpoint string) *ipcServer {
@@ -509,6 +531,13 @@ func (is *ipcServer) withMultitenancy(b bool) *ipcServer {
 	return is
 }
 
+// Quorum
+// withAudit records the decisions made for the IPC calls to sink, if not nil
+func (is *ipcServer) withAudit(sink rpc.AuditSink) *ipcServer {
+	is.auditSink = sink
+	return is
+}
+
 // Start starts the httpServer's http.Server
 func (is *ipcServer) start(apis []rpc.API) error {
 	is.mu.Lock()
@@ -523,6 +552,9 @@ func (is *ipcServer) start(apis []rpc.API) error {
 		return err
 	}
 	srv.EnableMultitenancy(is.isMultitenant)
+	if is.auditSink != nil {
+		srv.EnableAudit(is.auditSink)
+	}
 	is.log.Info("IPC endpoint opened", "url", is.endpoint, "isMultitenant", is.isMultitenant)
 	is.listener, is.srv = listener, srv
 	return nil
@@ -479,6 +479,8 @@ type Transition struct {
 	BeneficiaryMode              *string               `json:"beneficiaryMode,omitempty"`              // Mode for setting the beneficiary, either: list, besu, validators (beneficiary list is the list of validators)
 	MiningBeneficiary            *common.Address       `json:"miningBeneficiary,omitempty"`            // Wallet address that benefits at every new block (besu mode)
 	MaxRequestTimeoutSeconds     *uint64               `json:"maxRequestTimeoutSeconds,omitempty"`     // The max a timeout should be for a round change
+	CallPermissionsEnabled       *bool                 `json:"callPermissionsEnabled,omitempty"`       // enforce the contract call permissions of the roles (v2 permissions model)
+	V2PermissionMigrated         *bool                 `json:"v2PermissionMigrated,omitempty"`         // validate the transactions against the v2 permissions model a v1 network is migrated to
 }
 
 // String implements the fmt.Stringer interface.
@@ -755,6 +757,8 @@ func (c *ChainConfig) CheckTransitionsData() error {
 		isQBFT = true
 	}
 	prevBlock := big.NewInt(0)
+	callPermissionsEnabled := false
+	v2PermissionMigrated := false
 	for _, transition := range c.Transitions {
 		if transition.Algorithm != "" && !strings.EqualFold(transition.Algorithm, IBFT) && !strings.EqualFold(transition.Algorithm, QBFT) {
 			return ErrTransitionAlgorithm
@@ -792,6 +796,18 @@ func (c *ChainConfig) CheckTransitionsData() error {
 		if transition.BeneficiaryMode != nil && *transition.BeneficiaryMode != "fixed" && *transition.BeneficiaryMode != "validators" && *transition.BeneficiaryMode != "" && *transition.BeneficiaryMode != "list" {
 			return ErrBeneficiaryMode
 		}
+		if transition.CallPermissionsEnabled != nil {
+			if callPermissionsEnabled && !*transition.CallPermissionsEnabled {
+				return ErrCallPermissionsDisabled
+			}
+			callPermissionsEnabled = *transition.CallPermissionsEnabled
+		}
+		if transition.V2PermissionMigrated != nil {
+			if v2PermissionMigrated && !*transition.V2PermissionMigrated {
+				return ErrV2PermissionReverted
+			}
+			v2PermissionMigrated = *transition.V2PermissionMigrated
+		}
 		prevBlock = transition.Block
 	}
 	return nil
@@ -1005,6 +1021,50 @@ func (c *ChainConfig) IsECPrecompileEnabled(num *big.Int) bool {
 	return isForked(c.EnableECPrecompileBlock, num) || isECEnabled
 }
 
+// Quorum
+//
+// IsCallPermissionsEnabled returns whether num represents a block number where the contract call
+// permissions of the roles are enforced. Once enabled by a transition they stay enforced.
+func (c *ChainConfig) IsCallPermissionsEnabled(num *big.Int) bool {
+	isCallPermissionsEnabled := false
+	c.GetTransitionValue(num, func(transition Transition) {
+		if transition.CallPermissionsEnabled != nil {
+			isCallPermissionsEnabled = isCallPermissionsEnabled || *transition.CallPermissionsEnabled
+		}
+	})
+
+	return isCallPermissionsEnabled
+}
+
+// Quorum
+//
+// IsV2PermissionMigrated returns whether num represents a block number where the transactions of a
+// v1 permissioned network are validated against the v2 permissions model it was migrated to. Once
+// migrated by a transition the network stays on the v2 model.
+func (c *ChainConfig) IsV2PermissionMigrated(num *big.Int) bool {
+	isV2PermissionMigrated := false
+	c.GetTransitionValue(num, func(transition Transition) {
+		if transition.V2PermissionMigrated != nil {
+			isV2PermissionMigrated = isV2PermissionMigrated || *transition.V2PermissionMigrated
+		}
+	})
+
+	return isV2PermissionMigrated
+}
+
+// Quorum
+//
+// V2PermissionMigrationBlock returns the first block validated against the v2 permissions model a
+// v1 network is migrated to, nil if no transition migrates the network.
+func (c *ChainConfig) V2PermissionMigrationBlock() *big.Int {
+	for _, transition := range c.Transitions {
+		if transition.V2PermissionMigrated != nil && *transition.V2PermissionMigrated {
+			return transition.Block
+		}
+	}
+	return nil
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64, isQuorumEIP155Activated bool) *ConfigCompatError {
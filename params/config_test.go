@@ -332,10 +332,10 @@ func TestCheckTransitionsData(t *testing.T) {
 	var ibftTransitionsConfig, qbftTransitionsConfig, invalidTransition, invalidBlockOrder []Transition
 	var emptyBlockPeriodSeconds uint64 = 10
 
-	tranI0 := Transition{big.NewInt(0), IBFT, 30000, 5, nil, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil}
-	tranQ5 := Transition{big.NewInt(5), QBFT, 30000, 5, &emptyBlockPeriodSeconds, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil}
-	tranI10 := Transition{big.NewInt(10), IBFT, 30000, 5, nil, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil}
-	tranQ8 := Transition{big.NewInt(8), QBFT, 30000, 5, &emptyBlockPeriodSeconds, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil}
+	tranI0 := Transition{big.NewInt(0), IBFT, 30000, 5, nil, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil, nil, nil}
+	tranQ5 := Transition{big.NewInt(5), QBFT, 30000, 5, &emptyBlockPeriodSeconds, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil, nil, nil}
+	tranI10 := Transition{big.NewInt(10), IBFT, 30000, 5, nil, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil, nil, nil}
+	tranQ8 := Transition{big.NewInt(8), QBFT, 30000, 5, &emptyBlockPeriodSeconds, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil, nil, nil}
 
 	ibftTransitionsConfig = append(ibftTransitionsConfig, tranI0, tranI10)
 	qbftTransitionsConfig = append(qbftTransitionsConfig, tranQ5, tranQ8)
@@ -395,7 +395,7 @@ func TestCheckTransitionsData(t *testing.T) {
 			wantErr: ErrBlockOrder,
 		},
 		{
-			stored:  &ChainConfig{Transitions: []Transition{{nil, IBFT, 30000, 5, &emptyBlockPeriodSeconds, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil}}},
+			stored:  &ChainConfig{Transitions: []Transition{{nil, IBFT, 30000, 5, &emptyBlockPeriodSeconds, 10, 50, common.Address{}, nil, "", nil, nil, nil, nil, nil, 0, nil, 0, nil, nil, nil, nil, nil, nil}}},
 			wantErr: ErrBlockNumberMissing,
 		},
 		{
@@ -426,6 +426,22 @@ func TestCheckTransitionsData(t *testing.T) {
 			stored:  &ChainConfig{Transitions: []Transition{{Block: big.NewInt(0)}}},
 			wantErr: nil,
 		},
+		{
+			stored:  &ChainConfig{Transitions: []Transition{{Block: big.NewInt(0), CallPermissionsEnabled: newPBool(false)}, {Block: big.NewInt(10), CallPermissionsEnabled: newPBool(true)}}},
+			wantErr: nil,
+		},
+		{
+			stored:  &ChainConfig{Transitions: []Transition{{Block: big.NewInt(10), CallPermissionsEnabled: newPBool(true)}, {Block: big.NewInt(20), CallPermissionsEnabled: newPBool(false)}}},
+			wantErr: ErrCallPermissionsDisabled,
+		},
+		{
+			stored:  &ChainConfig{Transitions: []Transition{{Block: big.NewInt(0), V2PermissionMigrated: newPBool(false)}, {Block: big.NewInt(10), V2PermissionMigrated: newPBool(true)}}},
+			wantErr: nil,
+		},
+		{
+			stored:  &ChainConfig{Transitions: []Transition{{Block: big.NewInt(10), V2PermissionMigrated: newPBool(true)}, {Block: big.NewInt(20), V2PermissionMigrated: newPBool(false)}}},
+			wantErr: ErrV2PermissionReverted,
+		},
 	}
 
 	for _, test := range tests {
@@ -619,6 +635,67 @@ func TestIsGasPriceEnabled(t *testing.T) {
 	}
 }
 
+func TestIsCallPermissionsEnabled(t *testing.T) {
+	config := *TestChainConfig
+	config.Transitions = []Transition{
+		{Block: big.NewInt(11), CallPermissionsEnabled: newPBool(true)},
+		{Block: big.NewInt(21), CallPermissionsEnabled: newPBool(false)},
+	}
+
+	tests := []struct {
+		config                 *ChainConfig
+		blockNumber            int64
+		CallPermissionsEnabled bool
+	}{
+		{MainnetChainConfig, 0, false},
+		{&config, 10, false},
+		{&config, 11, true},
+		{&config, 20, true},
+		{&config, 21, true},
+	}
+
+	for _, test := range tests {
+		isCallPermissionsEnabled := test.config.IsCallPermissionsEnabled(big.NewInt(test.blockNumber))
+		if isCallPermissionsEnabled != test.CallPermissionsEnabled {
+			t.Errorf("error mismatch on %v:\nexpected: %v\nreceived: %v\n", test.blockNumber, test.CallPermissionsEnabled, isCallPermissionsEnabled)
+		}
+	}
+}
+
+func TestIsV2PermissionMigrated(t *testing.T) {
+	config := *TestChainConfig
+	config.Transitions = []Transition{
+		{Block: big.NewInt(5), V2PermissionMigrated: newPBool(false)},
+		{Block: big.NewInt(11), V2PermissionMigrated: newPBool(true)},
+		{Block: big.NewInt(21), V2PermissionMigrated: newPBool(false)},
+	}
+
+	tests := []struct {
+		config               *ChainConfig
+		blockNumber          int64
+		V2PermissionMigrated bool
+	}{
+		{MainnetChainConfig, 0, false},
+		{&config, 10, false},
+		{&config, 11, true},
+		{&config, 20, true},
+		{&config, 21, true},
+	}
+
+	for _, test := range tests {
+		isV2PermissionMigrated := test.config.IsV2PermissionMigrated(big.NewInt(test.blockNumber))
+		if isV2PermissionMigrated != test.V2PermissionMigrated {
+			t.Errorf("error mismatch on %v:\nexpected: %v\nreceived: %v\n", test.blockNumber, test.V2PermissionMigrated, isV2PermissionMigrated)
+		}
+	}
+	if block := config.V2PermissionMigrationBlock(); block == nil || block.Int64() != 11 {
+		t.Errorf("migration block mismatch: expected 11, received %v", block)
+	}
+	if block := MainnetChainConfig.V2PermissionMigrationBlock(); block != nil {
+		t.Errorf("migration block mismatch: expected nil, received %v", block)
+	}
+}
+
 func newPBool(b bool) *bool {
 	return &b
 }
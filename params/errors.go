@@ -17,6 +17,8 @@ var (
 	ErrMissingValidatorSelectionMode   = errors.New("validator selection mode is missing, should specify `contract` when using validatorcontractaddress")
 	ErrTransactionSizeLimit            = errors.New("genesis transaction size limit must be between 32 and 128")
 	ErrBeneficiaryMode                 = errors.New("beneficiary mode is not valid")
+	ErrCallPermissionsDisabled         = errors.New("call permissions can't be disabled once enabled by a transition")
+	ErrV2PermissionReverted            = errors.New("v2 permissions model can't be reverted to v1 once migrated by a transition")
 )
 
 func ErrTransitionIncompatible(field string) error {
@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/permission/core"
 	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var isStringAlphaNumeric = regexp.MustCompile(`^[a-zA-Z0-9_-]*$`).MatchString
@@ -44,6 +45,8 @@ const (
 	InitiateAccountRecovery
 	ApproveNodeRecovery
 	ApproveAccountRecovery
+	AddCallPermission
+	RemoveCallPermission
 )
 
 type AccountUpdateAction int
@@ -106,6 +109,10 @@ func (q *QuorumControlsAPI) AcctList() []core.AccountInfo {
 	return core.AcctInfoMap.GetAcctList()
 }
 
+func (q *QuorumControlsAPI) CallPermissionList() []core.CallPermission {
+	return core.CallPermissionMap.GetCallPermissionList()
+}
+
 func (q *QuorumControlsAPI) GetOrgDetails(orgId string) (core.OrgDetailInfo, error) {
 	o, err := core.OrgInfoMap.GetOrg(orgId)
 	if err != nil {
@@ -348,6 +355,43 @@ func (q *QuorumControlsAPI) RemoveRole(orgId string, roleId string, txa ethapi.S
 	return actionSuccess, nil
 }
 
+// AddCallPermission permits the accounts of a role to call a function of a contract, or all
+// its functions for the zero selector. Once a role has call permissions its accounts can only
+// call the permitted contract functions.
+func (q *QuorumControlsAPI) AddCallPermission(orgId string, roleId string, contract common.Address, selector core.FunctionSelector, txa ethapi.SendTxArgs) (string, error) {
+	callPermService, err := q.permCtrl.NewPermissionCallPermissionService(txa)
+	if err != nil {
+		return "", err
+	}
+	args := ptype.TxArgs{OrgId: orgId, RoleId: roleId, Contract: contract, Selector: selector, Txa: txa}
+	if err := q.valCallPermission(args, AddCallPermission); err != nil {
+		return "", err
+	}
+	tx, err := callPermService.AddCallPermission(args)
+	if err != nil {
+		return reportExecError(AddCallPermission, err)
+	}
+	log.Debug("executed permission action", "action", AddCallPermission, "tx", tx)
+	return actionSuccess, nil
+}
+
+func (q *QuorumControlsAPI) RemoveCallPermission(orgId string, roleId string, contract common.Address, selector core.FunctionSelector, txa ethapi.SendTxArgs) (string, error) {
+	callPermService, err := q.permCtrl.NewPermissionCallPermissionService(txa)
+	if err != nil {
+		return "", err
+	}
+	args := ptype.TxArgs{OrgId: orgId, RoleId: roleId, Contract: contract, Selector: selector, Txa: txa}
+	if err := q.valCallPermission(args, RemoveCallPermission); err != nil {
+		return "", err
+	}
+	tx, err := callPermService.RemoveCallPermission(args)
+	if err != nil {
+		return reportExecError(RemoveCallPermission, err)
+	}
+	log.Debug("executed permission action", "action", RemoveCallPermission, "tx", tx)
+	return actionSuccess, nil
+}
+
 func (q *QuorumControlsAPI) AddAccountToOrg(acct common.Address, orgId string, roleId string, txa ethapi.SendTxArgs) (string, error) {
 	accountService, err := q.permCtrl.NewPermissionAccountService(txa)
 	if err != nil {
@@ -473,7 +517,9 @@ func (q *QuorumControlsAPI) ApproveBlackListedAccountRecovery(orgId string, acct
 	return actionSuccess, nil
 }
 
-func (q *QuorumControlsAPI) TransactionAllowed(txa ethapi.SendTxArgs) bool {
+// transactionFromArgs returns the sender, target, value, gas price, gas limit, payload and type of
+// the transaction described by txa
+func transactionFromArgs(txa ethapi.SendTxArgs) (common.Address, common.Address, *big.Int, *big.Int, *big.Int, []byte, core.TransactionType) {
 	var value, gasPrice, gasLimit *big.Int
 	var payload []byte
 	var to, from common.Address
@@ -512,12 +558,32 @@ func (q *QuorumControlsAPI) TransactionAllowed(txa ethapi.SendTxArgs) bool {
 	} else if txa.Data != nil {
 		transactionType = core.ContractCallTxn
 	}
+	return from, to, value, gasPrice, gasLimit, payload, transactionType
+}
 
+func (q *QuorumControlsAPI) TransactionAllowed(txa ethapi.SendTxArgs) bool {
+	from, to, value, gasPrice, gasLimit, payload, transactionType := transactionFromArgs(txa)
 	if err := core.IsTransactionAllowed(from, to, value, gasPrice, gasLimit, payload, transactionType); err != nil {
 		return false
-	} else {
-		return true
 	}
+	if transactionType == core.ContractCallTxn && q.permCtrl.isCallPermissionsEnabled() {
+		return core.CheckCallPermission(from, to, payload, txa.IsPrivate()) == nil
+	}
+	return true
+}
+
+// ExplainTransactionAllowed returns the trace of the TransactionAllowed decision: the account
+// lookup, the org and ultimate parent status, the resolved role and access and the rule which
+// denied the transaction
+func (q *QuorumControlsAPI) ExplainTransactionAllowed(txa ethapi.SendTxArgs) *core.PermissionDecision {
+	from, to, value, gasPrice, gasLimit, payload, transactionType := transactionFromArgs(txa)
+	decision := core.ExplainTransaction(from, to, payload, transactionType)
+	decision.Conclude(core.IsTransactionAllowed(from, to, value, gasPrice, gasLimit, payload, transactionType) == nil)
+	if decision.Allowed && transactionType == core.ContractCallTxn && q.permCtrl.isCallPermissionsEnabled() {
+		// the role access allows contract calls, trace the call permissions of the role
+		decision.ExplainCallPermission(from, to, payload, txa.IsPrivate())
+	}
+	return decision
 }
 
 func (q *QuorumControlsAPI) ConnectionAllowed(enodeId, ip string, port, raftPort uint16) bool {
@@ -533,10 +599,101 @@ func (q *QuorumControlsAPI) ConnectionAllowed(enodeId, ip string, port, raftPort
 	}
 }
 
+// ExplainConnectionAllowed returns the trace of the ConnectionAllowed decision: the node lookup,
+// the org and ultimate parent status and the rule which denied the connection
+func (q *QuorumControlsAPI) ExplainConnectionAllowed(enodeId, ip string, port, raftPort uint16) (*core.PermissionDecision, error) {
+	url := enodeId
+	if q.permCtrl.IsV2Permission() {
+		url = core.GetNodeUrl(enodeId, ip, port, raftPort, q.permCtrl.isRaft)
+	}
+	node, err := enode.ParseV4(url)
+	if err != nil {
+		return nil, err
+	}
+	decision := core.ExplainConnection(node, ip)
+	decision.Conclude(q.ConnectionAllowed(enodeId, ip, port, raftPort))
+	return decision, nil
+}
+
+// PlanPermissions returns the ordered permission transactions reconciling the permission model with
+// spec. The model is read from the contracts at blockNumber if given, from the caches otherwise.
+func (q *QuorumControlsAPI) PlanPermissions(spec PermissionSpec, blockNumber *rpc.BlockNumber) (*PermissionPlan, error) {
+	snapshot, err := q.permCtrl.permissionSnapshot(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return planPermissions(snapshot, q.permCtrl.config(), &spec)
+}
+
+// MigrationPlan returns the transactions deploying the v2 permission contracts and populating them
+// with the v1 permissions at args.Block
+func (q *QuorumControlsAPI) MigrationPlan(args MigrationArgs) (*MigrationPlan, error) {
+	return q.permCtrl.migrationPlan(args)
+}
+
+// VerifyMigration compares the v1 permissions at blockNumber with the v2 permissions they were migrated
+// to, read from the caches once the node switched to the v2 model and from the v2 contracts before
+func (q *QuorumControlsAPI) VerifyMigration(blockNumber *rpc.BlockNumber) (*MigrationVerification, error) {
+	return q.permCtrl.verifyMigration(blockNumber)
+}
+
+// History returns the permission events matching args in block order, with the transaction hash,
+// the admin account which sent it and the status before and after the event
+func (q *QuorumControlsAPI) History(args PermissionHistoryArgs) ([]*PermissionHistoryEntry, error) {
+	return q.permCtrl.permissionHistory(args)
+}
+
+// ApplyPermissions plans the permission transactions reconciling the permission model with spec and
+// submits from txa.From the ones which do not depend on other steps
+func (q *QuorumControlsAPI) ApplyPermissions(spec PermissionSpec, txa ethapi.SendTxArgs) (*PermissionApplyResult, error) {
+	plan, err := q.PlanPermissions(spec, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &PermissionApplyResult{Plan: plan, Results: make([]*PermissionStepResult, len(plan.Steps))}
+	for i, step := range plan.Steps {
+		result.Results[i] = &PermissionStepResult{Step: i, Status: StepWaiting}
+		if len(step.DependsOn) > 0 {
+			continue
+		}
+		if _, err := q.submitPermissionStep(step, txa); err != nil {
+			result.Results[i].Status, result.Results[i].Error = StepFailed, err.Error()
+			continue
+		}
+		result.Results[i].Status = StepSubmitted
+	}
+	return result, nil
+}
+
+// submitPermissionStep calls the QuorumControlsAPI method of step
+func (q *QuorumControlsAPI) submitPermissionStep(step *PermissionStep, txa ethapi.SendTxArgs) (string, error) {
+	switch step.Method {
+	case addOrgMethod:
+		return q.AddOrg(step.OrgId, step.Url, *step.Account, txa)
+	case approveOrgMethod:
+		return q.ApproveOrg(step.OrgId, step.Url, *step.Account, txa)
+	case addSubOrgMethod:
+		return q.AddSubOrg(step.ParentOrgId, step.OrgId, step.Url, txa)
+	case addNodeMethod:
+		return q.AddNode(step.OrgId, step.Url, txa)
+	case assignAdminRoleMethod:
+		return q.AssignAdminRole(step.OrgId, *step.Account, step.RoleId, txa)
+	case approveAdminRoleMethod:
+		return q.ApproveAdminRole(step.OrgId, *step.Account, txa)
+	case addNewRoleMethod:
+		return q.AddNewRole(step.OrgId, step.RoleId, uint8(*step.Access), step.IsVoter, step.IsAdmin, txa)
+	case addAccountToOrgMethod:
+		return q.AddAccountToOrg(*step.Account, step.OrgId, step.RoleId, txa)
+	case changeAccountRoleMethod:
+		return q.ChangeAccountRole(*step.Account, step.OrgId, step.RoleId, txa)
+	}
+	return "", fmt.Errorf("unknown permission action %s", step.Method)
+}
+
 // check if the account is network admin
 func (q *QuorumControlsAPI) isNetworkAdmin(account common.Address) bool {
 	ac, _ := core.AcctInfoMap.GetAccount(account)
-	return ac != nil && ac.RoleId == q.permCtrl.permConfig.NwAdminRole
+	return ac != nil && ac.RoleId == q.permCtrl.config().NwAdminRole
 }
 
 func (q *QuorumControlsAPI) isOrgAdmin(account common.Address, orgId string) error {
@@ -674,7 +831,7 @@ func (q *QuorumControlsAPI) valAccountStatusChange(orgId string, account common.
 		return err
 	}
 
-	if ac.IsOrgAdmin && (ac.RoleId == q.permCtrl.permConfig.NwAdminRole || ac.RoleId == q.permCtrl.permConfig.OrgAdminRole) && (op == 1 || op == 3) {
+	if ac.IsOrgAdmin && (ac.RoleId == q.permCtrl.config().NwAdminRole || ac.RoleId == q.permCtrl.config().OrgAdminRole) && (op == 1 || op == 3) {
 		return ptype.ErrOpNotAllowed
 	}
 
@@ -706,7 +863,7 @@ func (q *QuorumControlsAPI) checkOrgAdminExists(orgId, roleId string, account co
 		if ac.OrgId != orgId {
 			return ptype.ErrAccountInUse
 		}
-		if roleId != "" && roleId == q.permCtrl.permConfig.OrgAdminRole && ac.IsOrgAdmin {
+		if roleId != "" && roleId == q.permCtrl.config().OrgAdminRole && ac.IsOrgAdmin {
 			return ptype.ErrAccountOrgAdmin
 		}
 	}
@@ -719,11 +876,11 @@ func (q *QuorumControlsAPI) valSubOrgBreadthDepth(porgId string) error {
 		return ptype.ErrOpNotAllowed
 	}
 
-	if q.permCtrl.permConfig.SubOrgDepth.Cmp(org.Level) == 0 {
+	if q.permCtrl.config().SubOrgDepth.Cmp(org.Level) == 0 {
 		return ptype.ErrMaxDepth
 	}
 
-	if q.permCtrl.permConfig.SubOrgBreadth.Cmp(big.NewInt(int64(len(org.SubOrgList)))) == 0 {
+	if q.permCtrl.config().SubOrgBreadth.Cmp(big.NewInt(int64(len(org.SubOrgList)))) == 0 {
 		return ptype.ErrMaxBreadth
 	}
 
@@ -781,7 +938,7 @@ func (q *QuorumControlsAPI) valAddOrg(args ptype.TxArgs) error {
 	}
 
 	// check if any previous op is pending approval for network admin
-	if q.checkPendingOp(q.permCtrl.permConfig.NwAdminOrg) {
+	if q.checkPendingOp(q.permCtrl.config().NwAdminOrg) {
 		return ptype.ErrPendingApprovals
 	}
 	// check if org already exists
@@ -807,7 +964,7 @@ func (q *QuorumControlsAPI) valApproveOrg(args ptype.TxArgs) error {
 		return ptype.ErrNotNetworkAdmin
 	}
 	// check if anything pending approval
-	if !q.validatePendingOp(q.permCtrl.permConfig.NwAdminOrg, args.OrgId, args.Url, args.AcctId, 1) {
+	if !q.validatePendingOp(q.permCtrl.config().NwAdminOrg, args.OrgId, args.Url, args.AcctId, 1) {
 		return ptype.ErrNothingToApprove
 	}
 	return nil
@@ -853,7 +1010,7 @@ func (q *QuorumControlsAPI) valUpdateOrgStatus(args ptype.TxArgs) error {
 	}
 
 	//check if passed org id is network admin org. update should not be allowed
-	if args.OrgId == q.permCtrl.permConfig.NwAdminOrg {
+	if args.OrgId == q.permCtrl.config().NwAdminOrg {
 		return ptype.ErrOpNotAllowed
 	}
 	// check if status update can be performed. Org should be approved for suspension
@@ -877,7 +1034,7 @@ func (q *QuorumControlsAPI) valApproveOrgStatus(args ptype.TxArgs) error {
 	} else {
 		return ptype.ErrOpNotAllowed
 	}
-	if !q.validatePendingOp(q.permCtrl.permConfig.NwAdminOrg, args.OrgId, "", common.Address{}, pendingOp) {
+	if !q.validatePendingOp(q.permCtrl.config().NwAdminOrg, args.OrgId, "", common.Address{}, pendingOp) {
 		return ptype.ErrNothingToApprove
 	}
 	return nil
@@ -917,7 +1074,7 @@ func (q *QuorumControlsAPI) valAssignAdminRole(args ptype.TxArgs) error {
 		return ptype.ErrInvalidInput
 	}
 	// check if caller is network admin
-	if args.RoleId != q.permCtrl.permConfig.OrgAdminRole && args.RoleId != q.permCtrl.permConfig.NwAdminRole {
+	if args.RoleId != q.permCtrl.config().OrgAdminRole && args.RoleId != q.permCtrl.config().NwAdminRole {
 		return ptype.ErrOpNotAllowed
 	}
 
@@ -949,7 +1106,7 @@ func (q *QuorumControlsAPI) valApproveAdminRole(args ptype.TxArgs) error {
 		return ptype.ErrInvalidAccount
 	}
 	// validate pending op
-	if !q.validatePendingOp(q.permCtrl.permConfig.NwAdminOrg, ac.OrgId, "", args.AcctId, 4) {
+	if !q.validatePendingOp(q.permCtrl.config().NwAdminOrg, ac.OrgId, "", args.AcctId, 4) {
 		return ptype.ErrNothingToApprove
 	}
 	return nil
@@ -977,7 +1134,7 @@ func (q *QuorumControlsAPI) valRemoveRole(args ptype.TxArgs) error {
 	}
 
 	// admin roles cannot be removed
-	if args.RoleId == q.permCtrl.permConfig.OrgAdminRole || args.RoleId == q.permCtrl.permConfig.NwAdminRole {
+	if args.RoleId == q.permCtrl.config().OrgAdminRole || args.RoleId == q.permCtrl.config().NwAdminRole {
 		return ptype.ErrAdminRoles
 	}
 
@@ -996,11 +1153,33 @@ func (q *QuorumControlsAPI) valRemoveRole(args ptype.TxArgs) error {
 	return nil
 }
 
+func (q *QuorumControlsAPI) valCallPermission(args ptype.TxArgs, permAction PermAction) error {
+	if args.RoleId == "" || args.Contract == (common.Address{}) {
+		return ptype.ErrInvalidInput
+	}
+	// check if caller is org admin
+	if er := q.isOrgAdmin(args.Txa.From, args.OrgId); er != nil {
+		return er
+	}
+	// the role must be defined in the org
+	if r, _ := core.RoleInfoMap.GetRole(args.OrgId, args.RoleId); r == nil || !r.Active {
+		return ptype.ErrInvalidRole
+	}
+	active := core.CallPermissionMap.IsActive(args.OrgId, args.RoleId, args.Contract, args.Selector)
+	if permAction == AddCallPermission && active {
+		return ptype.ErrCallPermExists
+	}
+	if permAction == RemoveCallPermission && !active {
+		return ptype.ErrCallPermNotThere
+	}
+	return nil
+}
+
 func (q *QuorumControlsAPI) valAssignRole(args ptype.TxArgs) error {
 	if args.AcctId == (common.Address{0}) {
 		return ptype.ErrInvalidInput
 	}
-	if args.RoleId == q.permCtrl.permConfig.OrgAdminRole || args.RoleId == q.permCtrl.permConfig.NwAdminRole {
+	if args.RoleId == q.permCtrl.config().OrgAdminRole || args.RoleId == q.permCtrl.config().NwAdminRole {
 		return ptype.ErrInvalidRole
 	}
 	// check if caller is network admin
@@ -1049,7 +1228,7 @@ func (q *QuorumControlsAPI) valRecoverNode(args ptype.TxArgs, action PermAction)
 			return err
 		}
 		// check no pending approval items
-		if q.checkPendingOp(q.permCtrl.permConfig.NwAdminOrg) {
+		if q.checkPendingOp(q.permCtrl.config().NwAdminOrg) {
 			return ptype.ErrPendingApprovals
 		}
 	} else {
@@ -1057,7 +1236,7 @@ func (q *QuorumControlsAPI) valRecoverNode(args ptype.TxArgs, action PermAction)
 		if err := q.valNodeStatusChange(args.OrgId, args.Url, 5, ApproveNodeRecovery); err != nil {
 			return err
 		}
-		if !q.validatePendingOp(q.permCtrl.permConfig.NwAdminOrg, args.OrgId, args.Url, common.Address{}, 5) {
+		if !q.validatePendingOp(q.permCtrl.config().NwAdminOrg, args.OrgId, args.Url, common.Address{}, 5) {
 			return ptype.ErrNothingToApprove
 		}
 	}
@@ -1084,11 +1263,11 @@ func (q *QuorumControlsAPI) valRecoverAccount(args ptype.TxArgs, action PermActi
 		return err
 	}
 
-	if action == InitiateAccountRecovery && q.checkPendingOp(q.permCtrl.permConfig.NwAdminOrg) {
+	if action == InitiateAccountRecovery && q.checkPendingOp(q.permCtrl.config().NwAdminOrg) {
 		return ptype.ErrPendingApprovals
 	}
 
-	if action == ApproveAccountRecovery && !q.validatePendingOp(q.permCtrl.permConfig.NwAdminOrg, args.OrgId, "", args.AcctId, 6) {
+	if action == ApproveAccountRecovery && !q.validatePendingOp(q.permCtrl.config().NwAdminOrg, args.OrgId, "", args.AcctId, 6) {
 		return ptype.ErrNothingToApprove
 	}
 	return nil
@@ -29,12 +29,16 @@ type PermissionCtrl struct {
 	chainID        *big.Int
 	dataDir        string
 	permConfig     *ptype.PermissionConfig
+	v1Config       *ptype.PermissionConfig // config of the v1 model once the node switched to the v2 model it was migrated to
 	contract       ptype.InitService
 	backend        ptype.Backend
 	useDns         bool
 	isRaft         bool
 	startWaitGroup *sync.WaitGroup // waitgroup to make sure all dependencies are ready before we start the service
 	errorChan      chan error      // channel to capture error when starting aysnc
+	history        *permissionHistory
+	historyMu      sync.Mutex   // serializes the indexing and queries of the permission history
+	modelMu        sync.RWMutex // protects the backend and contract service switched by the v2 migration
 }
 
 var permissionService *PermissionCtrl
@@ -122,8 +126,30 @@ func (p *PermissionCtrl) Stop() error {
 	return nil
 }
 
+// activeModel returns the backend of the permissions model in use with the contract backend of
+// its config, both read at once so that they belong to the same model
+func (p *PermissionCtrl) activeModel() (ptype.Backend, ptype.ContractBackend) {
+	p.modelMu.RLock()
+	defer p.modelMu.RUnlock()
+	return p.backend, ptype.ContractBackend{EthClnt: p.ethClnt, Key: p.key, PermConfig: p.permConfig, IsRaft: p.isRaft, UseDns: p.isRaft, ChainID: p.chainID}
+}
+
+// config returns the config of the permissions model in use
+func (p *PermissionCtrl) config() *ptype.PermissionConfig {
+	p.modelMu.RLock()
+	defer p.modelMu.RUnlock()
+	return p.permConfig
+}
+
+// contractService returns the contract service of the permissions model in use
+func (p *PermissionCtrl) contractService() ptype.InitService {
+	p.modelMu.RLock()
+	defer p.modelMu.RUnlock()
+	return p.contract
+}
+
 func (p *PermissionCtrl) IsV2Permission() bool {
-	return p.permConfig.PermissionsModel == ptype.PERMISSION_V2
+	return p.config().PermissionsModel == ptype.PERMISSION_V2
 }
 
 func NewPermissionContractService(ethClnt bind.ContractBackend, permissionV2 bool, key *ecdsa.PrivateKey,
@@ -152,7 +178,8 @@ func (p *PermissionCtrl) NewPermissionRoleService(txa ethapi.SendTxArgs) (ptype.
 	if err != nil {
 		return nil, err
 	}
-	return p.backend.GetRoleService(transactOpts, p.getContractBackend())
+	backend, contractBackend := p.activeModel()
+	return backend.GetRoleService(transactOpts, contractBackend)
 }
 
 func (p *PermissionCtrl) NewPermissionOrgService(txa ethapi.SendTxArgs) (ptype.OrgService, error) {
@@ -160,7 +187,8 @@ func (p *PermissionCtrl) NewPermissionOrgService(txa ethapi.SendTxArgs) (ptype.O
 	if err != nil {
 		return nil, err
 	}
-	return p.backend.GetOrgService(transactOpts, p.getContractBackend())
+	backend, contractBackend := p.activeModel()
+	return backend.GetOrgService(transactOpts, contractBackend)
 }
 
 func (p *PermissionCtrl) NewPermissionNodeService(txa ethapi.SendTxArgs) (ptype.NodeService, error) {
@@ -168,7 +196,8 @@ func (p *PermissionCtrl) NewPermissionNodeService(txa ethapi.SendTxArgs) (ptype.
 	if err != nil {
 		return nil, err
 	}
-	return p.backend.GetNodeService(transactOpts, p.getContractBackend())
+	backend, contractBackend := p.activeModel()
+	return backend.GetNodeService(transactOpts, contractBackend)
 }
 
 func (p *PermissionCtrl) NewPermissionAccountService(txa ethapi.SendTxArgs) (ptype.AccountService, error) {
@@ -176,23 +205,32 @@ func (p *PermissionCtrl) NewPermissionAccountService(txa ethapi.SendTxArgs) (pty
 	if err != nil {
 		return nil, err
 	}
-	return p.backend.GetAccountService(transactOpts, p.getContractBackend())
+	backend, contractBackend := p.activeModel()
+	return backend.GetAccountService(transactOpts, contractBackend)
 }
 
-func (p *PermissionCtrl) NewPermissionAuditService() (ptype.AuditService, error) {
-	return p.backend.GetAuditService(p.getContractBackend())
+func (p *PermissionCtrl) NewPermissionCallPermissionService(txa ethapi.SendTxArgs) (ptype.CallPermissionService, error) {
+	transactOpts, err := p.getTxParams(txa)
+	if err != nil {
+		return nil, err
+	}
+	backend, contractBackend := p.activeModel()
+	return backend.GetCallPermissionService(transactOpts, contractBackend)
 }
 
-func (p *PermissionCtrl) NewPermissionControlService() (ptype.ControlService, error) {
-	return p.backend.GetControlService(p.getContractBackend())
+func (p *PermissionCtrl) NewPermissionAuditService() (ptype.AuditService, error) {
+	backend, contractBackend := p.activeModel()
+	return backend.GetAuditService(contractBackend)
 }
 
-func (p *PermissionCtrl) getContractBackend() ptype.ContractBackend {
-	return ptype.ContractBackend{EthClnt: p.ethClnt, Key: p.key, PermConfig: p.permConfig, IsRaft: p.isRaft, UseDns: p.isRaft, ChainID: p.chainID}
+func (p *PermissionCtrl) NewPermissionControlService() (ptype.ControlService, error) {
+	backend, contractBackend := p.activeModel()
+	return backend.GetControlService(contractBackend)
 }
 
 func (p *PermissionCtrl) ConnectionAllowed(_enodeId, _ip string, _port, _raftPort uint16) (bool, error) {
-	cs, err := p.backend.GetControlService(p.getContractBackend())
+	backend, contractBackend := p.activeModel()
+	cs, err := backend.GetControlService(contractBackend)
 	if err != nil {
 		return false, err
 	}
@@ -205,12 +243,17 @@ func (p *PermissionCtrl) IsTransactionAllowed(_sender common.Address, _target co
 		return nil
 	}
 
-	cs, err := p.backend.GetControlService(p.getContractBackend())
+	backend, contractBackend := p.activeModel()
+	cs, err := backend.GetControlService(contractBackend)
 	if err != nil {
 		return err
 	}
 
-	return cs.TransactionAllowed(_sender, _target, _value, _gasPrice, _gasLimit, _payload, transactionType)
+	err = cs.TransactionAllowed(_sender, _target, _value, _gasPrice, _gasLimit, _payload, transactionType)
+	if errors.Is(err, ptype.ErrNoPermissionForTxn) {
+		log.Info("Transaction not permitted", "from", _sender, "to", _target, "reason", err)
+	}
+	return err
 }
 
 func (p *PermissionCtrl) populateBackEnd() error {
@@ -226,7 +269,8 @@ func (p *PermissionCtrl) populateBackEnd() error {
 
 	case ptype.PERMISSION_V1:
 		p.backend = &v1.Backend{
-			Ib: *backend,
+			Ib:   *backend,
+			Quit: make(chan struct{}),
 		}
 		log.Info("permission service: using v1 permissions model")
 		return nil
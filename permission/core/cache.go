@@ -146,28 +146,81 @@ var (
 	AcctInfoMap *AcctCache
 )
 
+// PermissionCaches are the caches of a permissions model, populated before they are set as the
+// caches in use
+type PermissionCaches struct {
+	Orgs            *OrgCache
+	Roles           *RoleCache
+	Nodes           *NodeCache
+	Accounts        *AcctCache
+	CallPermissions *CallPermissionCache
+}
+
+var setCachesMu sync.Mutex
+
+// SetCaches sets the caches in use when the permission service starts, the caches already in use
+// get the records of the given caches and their functions populating them. Each cache is replaced
+// in one step, its readers see either the previous or the new records but never an empty cache.
+func SetCaches(c *PermissionCaches) {
+	setCachesMu.Lock()
+	defer setCachesMu.Unlock()
+	if OrgInfoMap == nil || RoleInfoMap == nil || NodeInfoMap == nil || AcctInfoMap == nil {
+		OrgInfoMap, RoleInfoMap, NodeInfoMap, AcctInfoMap = c.Orgs, c.Roles, c.Nodes, c.Accounts
+	} else {
+		OrgInfoMap.Replace(c.Orgs)
+		RoleInfoMap.Replace(c.Roles)
+		NodeInfoMap.Replace(c.Nodes)
+		AcctInfoMap.Replace(c.Accounts)
+	}
+	CallPermissionMap.Replace(c.CallPermissions)
+}
+
+// cacheEntries holds the records of a permission cache and whether records were evicted from it
+type cacheEntries struct {
+	c       *lru.Cache
+	evicted bool
+}
+
+func newCacheEntries(cacheSize int) *cacheEntries {
+	e := &cacheEntries{evicted: false}
+	onEvictedFunc := func(k interface{}, v interface{}) {
+		e.evicted = true
+	}
+	e.c, _ = lru.NewWithEvict(cacheSize, onEvictedFunc)
+	return e
+}
+
 type OrgKey struct {
 	OrgId string
 }
 
-type OrgCache struct {
-	c                 *lru.Cache
-	mux               sync.Mutex
-	evicted           bool
+type orgEntries struct {
+	*cacheEntries
 	populateCacheFunc func(orgId string) (*OrgInfo, error)
 }
 
+type OrgCache struct {
+	entries atomic.Value // *orgEntries, replaced in one step
+	mux     sync.Mutex
+}
+
 func (o *OrgCache) PopulateCacheFunc(cf func(string) (*OrgInfo, error)) {
-	o.populateCacheFunc = cf
+	o.lru().populateCacheFunc = cf
 }
 
 func NewOrgCache(cacheSize int) *OrgCache {
-	orgCache := OrgCache{evicted: false}
-	onEvictedFunc := func(k interface{}, v interface{}) {
-		orgCache.evicted = true
-	}
-	orgCache.c, _ = lru.NewWithEvict(cacheSize, onEvictedFunc)
-	return &orgCache
+	orgCache := new(OrgCache)
+	orgCache.entries.Store(&orgEntries{cacheEntries: newCacheEntries(cacheSize)})
+	return orgCache
+}
+
+// Replace replaces the records of the cache with the records of from in one step
+func (o *OrgCache) Replace(from *OrgCache) {
+	o.entries.Store(from.lru())
+}
+
+func (o *OrgCache) lru() *orgEntries {
+	return o.entries.Load().(*orgEntries)
 }
 
 type RoleKey struct {
@@ -175,23 +228,32 @@ type RoleKey struct {
 	RoleId string
 }
 
-type RoleCache struct {
-	c                 *lru.Cache
-	evicted           bool
+type roleEntries struct {
+	*cacheEntries
 	populateCacheFunc func(*RoleKey) (*RoleInfo, error)
 }
 
+type RoleCache struct {
+	entries atomic.Value // *roleEntries, replaced in one step
+}
+
 func (r *RoleCache) PopulateCacheFunc(cf func(*RoleKey) (*RoleInfo, error)) {
-	r.populateCacheFunc = cf
+	r.lru().populateCacheFunc = cf
 }
 
 func NewRoleCache(cacheSize int) *RoleCache {
-	roleCache := RoleCache{evicted: false}
-	onEvictedFunc := func(k interface{}, v interface{}) {
-		roleCache.evicted = true
-	}
-	roleCache.c, _ = lru.NewWithEvict(cacheSize, onEvictedFunc)
-	return &roleCache
+	roleCache := new(RoleCache)
+	roleCache.entries.Store(&roleEntries{cacheEntries: newCacheEntries(cacheSize)})
+	return roleCache
+}
+
+// Replace replaces the records of the cache with the records of from in one step
+func (r *RoleCache) Replace(from *RoleCache) {
+	r.entries.Store(from.lru())
+}
+
+func (r *RoleCache) lru() *roleEntries {
+	return r.entries.Load().(*roleEntries)
 }
 
 type NodeKey struct {
@@ -199,52 +261,69 @@ type NodeKey struct {
 	Url   string
 }
 
-type NodeCache struct {
-	c                       *lru.Cache
-	evicted                 bool
+type nodeEntries struct {
+	*cacheEntries
 	populateCacheFunc       func(string) (*NodeInfo, error)
 	populateAndValidateFunc func(string, string) bool
 }
 
+type NodeCache struct {
+	entries atomic.Value // *nodeEntries, replaced in one step
+}
+
 func (n *NodeCache) PopulateValidateFunc(cf func(string, string) bool) {
-	n.populateAndValidateFunc = cf
+	n.lru().populateAndValidateFunc = cf
 }
 
 func (n *NodeCache) PopulateCacheFunc(cf func(string) (*NodeInfo, error)) {
-	n.populateCacheFunc = cf
+	n.lru().populateCacheFunc = cf
 }
 
 func NewNodeCache(cacheSize int) *NodeCache {
-	nodeCache := NodeCache{evicted: false}
-	onEvictedFunc := func(k interface{}, v interface{}) {
-		nodeCache.evicted = true
-	}
-	nodeCache.c, _ = lru.NewWithEvict(cacheSize, onEvictedFunc)
-	return &nodeCache
+	nodeCache := new(NodeCache)
+	nodeCache.entries.Store(&nodeEntries{cacheEntries: newCacheEntries(cacheSize)})
+	return nodeCache
+}
+
+// Replace replaces the records of the cache with the records of from in one step
+func (n *NodeCache) Replace(from *NodeCache) {
+	n.entries.Store(from.lru())
+}
+
+func (n *NodeCache) lru() *nodeEntries {
+	return n.entries.Load().(*nodeEntries)
 }
 
 type AccountKey struct {
 	AcctId common.Address
 }
 
-type AcctCache struct {
-	c                 *lru.Cache
-	evicted           bool
+type acctEntries struct {
+	*cacheEntries
 	populateCacheFunc func(account common.Address) (*AccountInfo, error)
 }
 
+type AcctCache struct {
+	entries atomic.Value // *acctEntries, replaced in one step
+}
+
 func (a *AcctCache) PopulateCacheFunc(cf func(common.Address) (*AccountInfo, error)) {
-	a.populateCacheFunc = cf
+	a.lru().populateCacheFunc = cf
 }
 
 func NewAcctCache(cacheSize int) *AcctCache {
-	acctCache := AcctCache{evicted: false}
-	onEvictedFunc := func(k interface{}, v interface{}) {
-		acctCache.evicted = true
-	}
+	acctCache := new(AcctCache)
+	acctCache.entries.Store(&acctEntries{cacheEntries: newCacheEntries(cacheSize)})
+	return acctCache
+}
+
+// Replace replaces the records of the cache with the records of from in one step
+func (a *AcctCache) Replace(from *AcctCache) {
+	a.entries.Store(from.lru())
+}
 
-	acctCache.c, _ = lru.NewWithEvict(cacheSize, onEvictedFunc)
-	return &acctCache
+func (a *AcctCache) lru() *acctEntries {
+	return a.entries.Load().(*acctEntries)
 }
 
 func SetSyncStatus() {
@@ -309,23 +388,24 @@ func GetNodeUrl(enodeId string, ip string, port uint16, raftport uint16, isRaft
 func (o *OrgCache) UpsertOrg(orgId, parentOrg, ultimateParent string, level *big.Int, status OrgStatus) {
 	defer o.mux.Unlock()
 	o.mux.Lock()
+	c := o.lru().c
 	var key OrgKey
 	if parentOrg == "" {
 		key = OrgKey{OrgId: orgId}
 	} else {
 		key = OrgKey{OrgId: parentOrg + "." + orgId}
 		pkey := OrgKey{OrgId: parentOrg}
-		if ent, ok := o.c.Get(pkey); ok {
+		if ent, ok := c.Get(pkey); ok {
 			porg := ent.(*OrgInfo)
 			if !containsKey(porg.SubOrgList, key.OrgId) {
 				porg.SubOrgList = append(porg.SubOrgList, key.OrgId)
-				o.c.Add(pkey, porg)
+				c.Add(pkey, porg)
 			}
 		}
 	}
 
 	norg := &OrgInfo{orgId, key.OrgId, parentOrg, ultimateParent, level, nil, status}
-	o.c.Add(key, norg)
+	c.Add(key, norg)
 }
 
 func (o *OrgCache) UpsertOrgWithSubOrgList(orgRec *OrgInfo) {
@@ -336,7 +416,7 @@ func (o *OrgCache) UpsertOrgWithSubOrgList(orgRec *OrgInfo) {
 		key = OrgKey{OrgId: orgRec.ParentOrgId + "." + orgRec.OrgId}
 	}
 	orgRec.FullOrgId = key.OrgId
-	o.c.Add(key, orgRec)
+	o.lru().c.Add(key, orgRec)
 }
 
 func containsKey(s []string, e string) bool {
@@ -350,14 +430,15 @@ func containsKey(s []string, e string) bool {
 
 func (o *OrgCache) GetOrg(orgId string) (*OrgInfo, error) {
 	key := OrgKey{OrgId: orgId}
-	if ent, ok := o.c.Get(key); ok {
+	e := o.lru()
+	if ent, ok := e.c.Get(key); ok {
 		return ent.(*OrgInfo), nil
 	}
 	// check if the org cache is evicted. if yes we need
 	// fetch the record from the contract
-	if o.evicted {
+	if e.evicted {
 		// call cache population function to populate from contract
-		orgRec, err := o.populateCacheFunc(orgId)
+		orgRec, err := e.populateCacheFunc(orgId)
 		if err != nil {
 			return nil, err
 		}
@@ -370,33 +451,40 @@ func (o *OrgCache) GetOrg(orgId string) (*OrgInfo, error) {
 }
 
 func (o *OrgCache) GetOrgList() []OrgInfo {
-	olist := make([]OrgInfo, len(o.c.Keys()))
-	for i, k := range o.c.Keys() {
-		v, _ := o.c.Get(k)
+	c := o.lru().c
+	keys := c.Keys()
+	olist := make([]OrgInfo, 0, len(keys))
+	for _, k := range keys {
+		v, ok := c.Get(k)
+		if !ok {
+			continue
+		}
 		vp := v.(*OrgInfo)
-		olist[i] = *vp
+		olist = append(olist, *vp)
 	}
 	return olist
 }
 
 func (n *NodeCache) UpsertNode(orgId string, url string, status NodeStatus) {
 	key := NodeKey{OrgId: orgId, Url: url}
-	n.c.Add(key, &NodeInfo{OrgId: orgId, Url: url, Status: status})
+	n.lru().c.Add(key, &NodeInfo{OrgId: orgId, Url: url, Status: status})
 }
 
 func (n *NodeCache) GetNodeByUrl(url string) (*NodeInfo, error) {
-	for _, k := range n.c.Keys() {
+	e := n.lru()
+	for _, k := range e.c.Keys() {
 		ent := k.(NodeKey)
 		if ent.Url == url {
-			v, _ := n.c.Get(ent)
-			return v.(*NodeInfo), nil
+			if v, ok := e.c.Get(ent); ok {
+				return v.(*NodeInfo), nil
+			}
 		}
 	}
 	// check if the node cache is evicted. if yes we need
 	// fetch the record from the contract
-	if n.evicted {
+	if e.evicted {
 		// call cache population function to populate from contract
-		nodeRec, err := n.populateCacheFunc(url)
+		nodeRec, err := e.populateCacheFunc(url)
 		if err != nil {
 			return nil, err
 		}
@@ -410,17 +498,21 @@ func (n *NodeCache) GetNodeByUrl(url string) (*NodeInfo, error) {
 }
 
 func (n *NodeCache) getSourceList() []*NodeInfo {
-	olist := make([]*NodeInfo, len(n.c.Keys()))
-	for i, k := range n.c.Keys() {
-		v, _ := n.c.Get(k)
-		olist[i] = v.(*NodeInfo)
+	c := n.lru().c
+	keys := c.Keys()
+	olist := make([]*NodeInfo, 0, len(keys))
+	for _, k := range keys {
+		if v, ok := c.Get(k); ok {
+			olist = append(olist, v.(*NodeInfo))
+		}
 	}
 	return olist
 }
 
 func (n *NodeCache) GetNodeList() []NodeInfo {
-	olist := make([]NodeInfo, len(n.c.Keys()))
-	for i, v := range n.getSourceList() {
+	sources := n.getSourceList()
+	olist := make([]NodeInfo, len(sources))
+	for i, v := range sources {
 		olist[i] = *v
 	}
 	return olist
@@ -428,19 +520,20 @@ func (n *NodeCache) GetNodeList() []NodeInfo {
 
 func (a *AcctCache) UpsertAccount(orgId string, role string, acct common.Address, orgAdmin bool, status AcctStatus) {
 	key := AccountKey{acct}
-	a.c.Add(key, &AccountInfo{orgId, role, acct, orgAdmin, status})
+	a.lru().c.Add(key, &AccountInfo{orgId, role, acct, orgAdmin, status})
 }
 
 func (a *AcctCache) GetAccount(acct common.Address) (*AccountInfo, error) {
-	if v, ok := a.c.Get(AccountKey{acct}); ok {
+	e := a.lru()
+	if v, ok := e.c.Get(AccountKey{acct}); ok {
 		return v.(*AccountInfo), nil
 	}
 
 	// check if the account cache is evicted. if yes we need
 	// fetch the record from the contract
-	if a.evicted {
+	if e.evicted {
 		// call function to populate cache with the record
-		acctRec, err := a.populateCacheFunc(acct)
+		acctRec, err := e.populateCacheFunc(acct)
 		// insert the received record into cache
 		if err != nil {
 			return nil, err
@@ -453,19 +546,28 @@ func (a *AcctCache) GetAccount(acct common.Address) (*AccountInfo, error) {
 }
 
 func (a *AcctCache) GetAcctList() []AccountInfo {
-	alist := make([]AccountInfo, len(a.c.Keys()))
-	for i, k := range a.c.Keys() {
-		v, _ := a.c.Get(k)
+	c := a.lru().c
+	keys := c.Keys()
+	alist := make([]AccountInfo, 0, len(keys))
+	for _, k := range keys {
+		v, ok := c.Get(k)
+		if !ok {
+			continue
+		}
 		vp := v.(*AccountInfo)
-		alist[i] = *vp
+		alist = append(alist, *vp)
 	}
 	return alist
 }
 
 func (a *AcctCache) GetAcctListOrg(orgId string) []AccountInfo {
 	var alist []AccountInfo
-	for _, k := range a.c.Keys() {
-		v, _ := a.c.Get(k)
+	c := a.lru().c
+	for _, k := range c.Keys() {
+		v, ok := c.Get(k)
+		if !ok {
+			continue
+		}
 		vp := v.(*AccountInfo)
 		if vp.OrgId == orgId {
 			alist = append(alist, *vp)
@@ -476,8 +578,12 @@ func (a *AcctCache) GetAcctListOrg(orgId string) []AccountInfo {
 
 func (a *AcctCache) GetAcctListRole(orgId, roleId string) []AccountInfo {
 	var alist []AccountInfo
-	for _, k := range a.c.Keys() {
-		v, _ := a.c.Get(k)
+	c := a.lru().c
+	for _, k := range c.Keys() {
+		v, ok := c.Get(k)
+		if !ok {
+			continue
+		}
 		vp := v.(*AccountInfo)
 
 		orgRec, err := OrgInfoMap.GetOrg(vp.OrgId)
@@ -494,19 +600,20 @@ func (a *AcctCache) GetAcctListRole(orgId, roleId string) []AccountInfo {
 
 func (r *RoleCache) UpsertRole(orgId string, role string, voter bool, admin bool, access AccessType, active bool) {
 	key := RoleKey{orgId, role}
-	r.c.Add(key, &RoleInfo{orgId, role, voter, admin, access, active})
+	r.lru().c.Add(key, &RoleInfo{orgId, role, voter, admin, access, active})
 }
 
 func (r *RoleCache) GetRole(orgId string, roleId string) (*RoleInfo, error) {
 	key := RoleKey{OrgId: orgId, RoleId: roleId}
-	if ent, ok := r.c.Get(key); ok {
+	e := r.lru()
+	if ent, ok := e.c.Get(key); ok {
 		return ent.(*RoleInfo), nil
 	}
 	// check if the role cache is evicted. if yes we need
 	// fetch the record from the contract
-	if r.evicted {
+	if e.evicted {
 		// call cache population function to populate from contract
-		roleRec, err := r.populateCacheFunc(&RoleKey{RoleId: roleId, OrgId: orgId})
+		roleRec, err := e.populateCacheFunc(&RoleKey{RoleId: roleId, OrgId: orgId})
 		if err != nil {
 			return nil, err
 		}
@@ -520,11 +627,16 @@ func (r *RoleCache) GetRole(orgId string, roleId string) (*RoleInfo, error) {
 }
 
 func (r *RoleCache) GetRoleList() []RoleInfo {
-	rlist := make([]RoleInfo, len(r.c.Keys()))
-	for i, k := range r.c.Keys() {
-		v, _ := r.c.Get(k)
+	c := r.lru().c
+	keys := c.Keys()
+	rlist := make([]RoleInfo, 0, len(keys))
+	for _, k := range keys {
+		v, ok := c.Get(k)
+		if !ok {
+			continue
+		}
 		vp := v.(*RoleInfo)
-		rlist[i] = *vp
+		rlist = append(rlist, *vp)
 	}
 	return rlist
 }
@@ -532,26 +644,7 @@ func (r *RoleCache) GetRoleList() []RoleInfo {
 // Returns the access type for an account. If not found returns
 // default access
 func GetAcctAccess(acctId common.Address) AccessType {
-	// check if the org status is fine to do the transaction
-	a, _ := AcctInfoMap.GetAccount(acctId)
-	if a != nil && a.Status == AcctActive {
-		// get the org details and ultimate org details. check org status
-		// if the org is not approved or pending suspension
-		if checkIfOrgActive(a.OrgId) {
-			if a.RoleId == networkAdminRole || a.RoleId == orgAdminRole {
-				return FullAccess
-			}
-			if r, _ := RoleInfoMap.GetRole(a.OrgId, a.RoleId); r != nil && r.Active {
-				return r.Access
-			}
-			if o, _ := OrgInfoMap.GetOrg(a.OrgId); o != nil {
-				if r, _ := RoleInfoMap.GetRole(o.UltimateParent, a.RoleId); r != nil && r.Active {
-					return r.Access
-				}
-			}
-		}
-	}
-	return defaultAccess
+	return new(PermissionDecision).explainAcctAccess(acctId)
 }
 
 // checks if the given org is active in the network
@@ -622,13 +715,19 @@ func ValidateNodeForTxn(nodeId enode.ID, from common.Address) bool {
 			}
 		}
 	}
-	if NodeInfoMap.evicted {
-		return NodeInfoMap.populateAndValidateFunc(nodeId.String(), acOrgRec.UltimateParent)
+	if e := NodeInfoMap.lru(); e.evicted {
+		return e.populateAndValidateFunc(nodeId.String(), acOrgRec.UltimateParent)
 	}
 
 	return false
 }
 
+// returns true if none of the caches has evicted records, i.e. they hold the
+// complete permission model
+func CachesComplete() bool {
+	return !OrgInfoMap.lru().evicted && !RoleInfoMap.lru().evicted && !NodeInfoMap.lru().evicted && !AcctInfoMap.lru().evicted
+}
+
 func IsV2Permission() bool {
 	return PermissionModel == V2
 }
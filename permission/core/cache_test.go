@@ -457,3 +457,47 @@ func Test_checkIfOrgActive(t *testing.T) {
 		})
 	}
 }
+
+func TestSetCaches(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	OrgInfoMap = NewOrgCache(params.DEFAULT_ORGCACHE_SIZE)
+	RoleInfoMap = NewRoleCache(params.DEFAULT_ROLECACHE_SIZE)
+	NodeInfoMap = NewNodeCache(params.DEFAULT_NODECACHE_SIZE)
+	AcctInfoMap = NewAcctCache(params.DEFAULT_ACCOUNTCACHE_SIZE)
+	orgCache, callPermissions := OrgInfoMap, CallPermissionMap
+	OrgInfoMap.UpsertOrg(NETWORKADMIN, "", NETWORKADMIN, big.NewInt(1), OrgApproved)
+
+	caches := &PermissionCaches{
+		Orgs:            NewOrgCache(params.DEFAULT_ORGCACHE_SIZE),
+		Roles:           NewRoleCache(params.DEFAULT_ROLECACHE_SIZE),
+		Nodes:           NewNodeCache(params.DEFAULT_NODECACHE_SIZE),
+		Accounts:        NewAcctCache(params.DEFAULT_ACCOUNTCACHE_SIZE),
+		CallPermissions: NewCallPermissionCache(),
+	}
+	caches.Orgs.UpsertOrg(ORGADMIN, "", ORGADMIN, big.NewInt(1), OrgApproved)
+	caches.Roles.UpsertRole(ORGADMIN, ORGADMIN, true, true, FullAccess, true)
+	caches.Nodes.UpsertNode(ORGADMIN, NODE1, NodeApproved)
+	caches.Accounts.UpsertAccount(ORGADMIN, ORGADMIN, Acct1, true, AcctActive)
+	caches.CallPermissions.UpsertCallPermission(ORGADMIN, ORGADMIN, Acct2, FunctionSelector{}, true)
+
+	SetCaches(caches)
+
+	// the caches in use are kept, their records are replaced
+	assert.True(OrgInfoMap == orgCache)
+	assert.True(CallPermissionMap == callPermissions)
+	_, err := OrgInfoMap.GetOrg(NETWORKADMIN)
+	assert.Error(err)
+	orgInfo, err := OrgInfoMap.GetOrg(ORGADMIN)
+	assert.NoError(err)
+	assert.Equal(ORGADMIN, orgInfo.OrgId)
+	roleInfo, _ := RoleInfoMap.GetRole(ORGADMIN, ORGADMIN)
+	assert.NotNil(roleInfo)
+	nodeInfo, _ := NodeInfoMap.GetNodeByUrl(NODE1)
+	assert.NotNil(nodeInfo)
+	acctInfo, _ := AcctInfoMap.GetAccount(Acct1)
+	assert.NotNil(acctInfo)
+	assert.True(CallPermissionMap.IsRestricted(ORGADMIN, ORGADMIN))
+
+	CallPermissionMap = NewCallPermissionCache()
+}
@@ -0,0 +1,207 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// FunctionSelector is the 4-byte selector of a contract function. The zero selector stands for
+// every function of the contract.
+type FunctionSelector [4]byte
+
+func (s FunctionSelector) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(s[:]).MarshalText()
+}
+
+func (s *FunctionSelector) UnmarshalText(input []byte) error {
+	return hexutil.UnmarshalFixedText("FunctionSelector", input, s[:])
+}
+
+func (s FunctionSelector) String() string {
+	return hexutil.Encode(s[:])
+}
+
+// CallPermission permits the accounts of a role to call a contract, or a single function of it
+type CallPermission struct {
+	OrgId    string           `json:"orgId"`
+	RoleId   string           `json:"roleId"`
+	Contract common.Address   `json:"contract"`
+	Selector FunctionSelector `json:"selector"`
+	Active   bool             `json:"active"`
+}
+
+type callTarget struct {
+	Contract common.Address
+	Selector FunctionSelector
+}
+
+// CallPermissionCache holds the call permissions of the roles. Unlike the other caches it is not
+// an LRU cache, an evicted call permission would silently lift the restrictions of its role.
+type CallPermissionCache struct {
+	mux         sync.RWMutex
+	permissions map[RoleKey]map[callTarget]bool
+}
+
+func NewCallPermissionCache() *CallPermissionCache {
+	return &CallPermissionCache{permissions: make(map[RoleKey]map[callTarget]bool)}
+}
+
+var CallPermissionMap = NewCallPermissionCache()
+
+// Replace replaces the call permissions of the cache with the call permissions of from in one step
+func (c *CallPermissionCache) Replace(from *CallPermissionCache) {
+	from.mux.RLock()
+	permissions := from.permissions
+	from.mux.RUnlock()
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.permissions = permissions
+}
+
+var ErrNoCallPermission = errors.New("role does not have permission to call the contract function")
+
+func (c *CallPermissionCache) UpsertCallPermission(orgId, roleId string, contract common.Address, selector FunctionSelector, active bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	key := RoleKey{OrgId: orgId, RoleId: roleId}
+	if c.permissions[key] == nil {
+		c.permissions[key] = make(map[callTarget]bool)
+	}
+	c.permissions[key][callTarget{contract, selector}] = active
+}
+
+// GetCallPermissionList returns the call permissions of all the roles, including the revoked ones
+func (c *CallPermissionCache) GetCallPermissionList() []CallPermission {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	list := make([]CallPermission, 0)
+	for key, targets := range c.permissions {
+		for t, active := range targets {
+			list = append(list, CallPermission{key.OrgId, key.RoleId, t.Contract, t.Selector, active})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.OrgId != b.OrgId {
+			return a.OrgId < b.OrgId
+		}
+		if a.RoleId != b.RoleId {
+			return a.RoleId < b.RoleId
+		}
+		if a.Contract != b.Contract {
+			return a.Contract.Hex() < b.Contract.Hex()
+		}
+		return a.Selector.String() < b.Selector.String()
+	})
+	return list
+}
+
+// IsRestricted returns true if the role has active call permissions, i.e. its accounts can only
+// call the permitted contract functions
+func (c *CallPermissionCache) IsRestricted(orgId, roleId string) bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	for _, active := range c.permissions[RoleKey{OrgId: orgId, RoleId: roleId}] {
+		if active {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive returns true if the call permission of the role for the function of contract is active
+func (c *CallPermissionCache) IsActive(orgId, roleId string, contract common.Address, selector FunctionSelector) bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.permissions[RoleKey{OrgId: orgId, RoleId: roleId}][callTarget{contract, selector}]
+}
+
+// IsPermitted returns true if the role can call the function of contract given by selector,
+// either by a call permission for the function or for the whole contract
+func (c *CallPermissionCache) IsPermitted(orgId, roleId string, contract common.Address, selector FunctionSelector) bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	targets := c.permissions[RoleKey{OrgId: orgId, RoleId: roleId}]
+	return targets[callTarget{contract, FunctionSelector{}}] || (selector != FunctionSelector{} && targets[callTarget{contract, selector}])
+}
+
+// selectorOf returns the function selector of the call payload. Payloads shorter than a selector
+// can only be permitted for the whole contract.
+func selectorOf(payload []byte) FunctionSelector {
+	var selector FunctionSelector
+	if len(payload) >= len(selector) {
+		copy(selector[:], payload)
+	}
+	return selector
+}
+
+// ExplainCallPermission traces the call permissions of the role of from for a contract call to the
+// target. Admin accounts are not restricted by call permissions. The payload of a private transaction
+// is the hash of its encrypted payload, so only the call permissions for the whole contract apply to
+// private transactions.
+func (d *PermissionDecision) ExplainCallPermission(from common.Address, to common.Address, payload []byte, isPrivate bool) *PermissionDecision {
+	a, _ := AcctInfoMap.GetAccount(from)
+	if a == nil || a.IsOrgAdmin || CheckIfAdminAccount(from) {
+		return d.allow("account %s is not restricted by call permissions", from.Hex())
+	}
+	if d.Account == nil {
+		d.Account, d.OrgId, d.RoleId = &from, a.OrgId, a.RoleId
+	}
+	// the role is looked up in the org of the account and then in its ultimate parent, as for the access
+	orgId := a.OrgId
+	if r, _ := RoleInfoMap.GetRole(a.OrgId, a.RoleId); r == nil {
+		if o, _ := OrgInfoMap.GetOrg(a.OrgId); o != nil {
+			orgId = o.UltimateParent
+		}
+	}
+	if !CallPermissionMap.IsRestricted(orgId, a.RoleId) {
+		return d.allow("role %s has no call permissions, all contracts can be called", a.RoleId)
+	}
+	if isPrivate {
+		if CallPermissionMap.IsPermitted(orgId, a.RoleId, to, FunctionSelector{}) {
+			return d.allow("role %s of org %s is permitted to call contract %s", a.RoleId, orgId, to.Hex())
+		}
+		return d.deny("role %s of org %s is not permitted to call contract %s, private transactions require a call permission for the whole contract", a.RoleId, orgId, to.Hex())
+	}
+	selector := selectorOf(payload)
+	if CallPermissionMap.IsPermitted(orgId, a.RoleId, to, selector) {
+		return d.allow("role %s of org %s is permitted to call function %s of contract %s", a.RoleId, orgId, selector, to.Hex())
+	}
+	return d.deny("role %s of org %s is not permitted to call function %s of contract %s", a.RoleId, orgId, selector, to.Hex())
+}
+
+// CheckCallPermission checks the call permissions of the role of from for a contract call to the
+// target, see ExplainCallPermission
+func CheckCallPermission(from common.Address, to common.Address, payload []byte, isPrivate bool) error {
+	if !PermissionsEnabled() || len(payload) == 0 {
+		return nil
+	}
+	if d := new(PermissionDecision).ExplainCallPermission(from, to, payload, isPrivate); !d.Allowed {
+		return fmt.Errorf("%w: %s", ErrNoCallPermission, d.Reason())
+	}
+	return nil
+}
+
+// CheckTransactionPermission checks the account permission of the sender of tx and, if the call
+// permissions are enforced at the block tx is validated for, the call permissions of its role.
+// The private transaction of a privacy marker transaction is checked on its own.
+func CheckTransactionPermission(tx *types.Transaction, callPermissionsEnabled bool) error {
+	if err := CheckAccountPermission(tx.From(), tx.To(), tx.Value(), tx.Data(), tx.Gas(), tx.GasPrice()); err != nil {
+		return err
+	}
+	if !callPermissionsEnabled || tx.To() == nil || tx.IsPrivacyMarker() {
+		return nil
+	}
+	if err := CheckCallPermission(tx.From(), *tx.To(), tx.Data(), tx.IsPrivate()); err != nil {
+		log.Info("Transaction not permitted", "from", tx.From(), "to", tx.To(), "reason", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func TestFunctionSelector_JSON(t *testing.T) {
+	assert := testifyassert.New(t)
+	var selector FunctionSelector
+
+	assert.NoError(json.Unmarshal([]byte(`"0x8456cb59"`), &selector))
+	assert.Equal(FunctionSelector{0x84, 0x56, 0xcb, 0x59}, selector)
+	blob, err := json.Marshal(selector)
+	assert.NoError(err)
+	assert.Equal(`"0x8456cb59"`, string(blob))
+	assert.Error(json.Unmarshal([]byte(`"0x8456cb"`), &selector))
+}
+
+func TestCallPermissionCache(t *testing.T) {
+	assert := testifyassert.New(t)
+	token, other := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	pause := FunctionSelector{0x84, 0x56, 0xcb, 0x59}
+	c := NewCallPermissionCache()
+
+	assert.False(c.IsRestricted("ORG1", "OPS"))
+
+	c.UpsertCallPermission("ORG1", "OPS", token, pause, true)
+
+	assert.True(c.IsRestricted("ORG1", "OPS"))
+	assert.True(c.IsActive("ORG1", "OPS", token, pause))
+	assert.True(c.IsPermitted("ORG1", "OPS", token, pause))
+	assert.False(c.IsPermitted("ORG1", "OPS", token, FunctionSelector{1, 2, 3, 4}))
+	assert.False(c.IsPermitted("ORG1", "OPS", token, FunctionSelector{}))
+	assert.False(c.IsPermitted("ORG1", "OPS", other, pause))
+
+	c.UpsertCallPermission("ORG1", "OPS", other, FunctionSelector{}, true)
+
+	assert.True(c.IsPermitted("ORG1", "OPS", other, FunctionSelector{1, 2, 3, 4}))
+	assert.True(c.IsPermitted("ORG1", "OPS", other, FunctionSelector{}))
+
+	c.UpsertCallPermission("ORG1", "OPS", token, pause, false)
+	c.UpsertCallPermission("ORG1", "OPS", other, FunctionSelector{}, false)
+
+	assert.False(c.IsRestricted("ORG1", "OPS"))
+	assert.False(c.IsPermitted("ORG1", "OPS", token, pause))
+	assert.Equal([]CallPermission{
+		{OrgId: "ORG1", RoleId: "OPS", Contract: token, Selector: pause},
+		{OrgId: "ORG1", RoleId: "OPS", Contract: other},
+	}, c.GetCallPermissionList())
+}
+
+func TestCheckCallPermission(t *testing.T) {
+	assert := testifyassert.New(t)
+	SetDefaults(NETWORKADMIN, ORGADMIN, true)
+	defer SetDefaults(NETWORKADMIN, ORGADMIN, false)
+	setupExplainCaches()
+	RoleInfoMap.UpsertRole("ORG1", "TRADER", false, false, ContractCall, true)
+	CallPermissionMap = NewCallPermissionCache()
+	token := common.HexToAddress("0x1")
+	pause := []byte{0x84, 0x56, 0xcb, 0x59}
+	transfer := []byte{0xa9, 0x05, 0x9c, 0xbb, 0x01}
+	CallPermissionMap.UpsertCallPermission("ORG1", "TRADER", token, FunctionSelector{0x84, 0x56, 0xcb, 0x59}, true)
+
+	assert.NoError(CheckCallPermission(Acct1, token, pause, false))
+	err := CheckCallPermission(Acct1, token, transfer, false)
+	assert.True(errors.Is(err, ErrNoCallPermission))
+	assert.EqualError(err, ErrNoCallPermission.Error()+": role TRADER of org ORG1 is not permitted to call function 0xa9059cbb of contract "+token.Hex()+" (account "+Acct1.Hex()+", org ORG1.SUB1, role TRADER)")
+	assert.Error(CheckCallPermission(Acct1, common.HexToAddress("0x2"), pause, false))
+	assert.Error(CheckCallPermission(Acct1, token, []byte{0x84}, false))
+
+	// admin accounts and unrestricted roles are not affected
+	orgAdmin, other := common.HexToAddress("0x3"), common.HexToAddress("0x4")
+	AcctInfoMap.UpsertAccount("ORG1", ORGADMIN, orgAdmin, true, AcctActive)
+	assert.NoError(CheckCallPermission(orgAdmin, token, transfer, false))
+	AcctInfoMap.UpsertAccount("ORG1", "OTHER", other, false, AcctActive)
+	assert.NoError(CheckCallPermission(other, token, transfer, false))
+
+	decision := ExplainTransaction(Acct1, token, transfer, ContractCallTxn)
+	assert.True(decision.Allowed, "the access of the role allows contract calls")
+	decision.ExplainCallPermission(Acct1, token, transfer, false)
+
+	assert.False(decision.Allowed)
+	assert.Equal("role TRADER of org ORG1 is not permitted to call function 0xa9059cbb of contract "+token.Hex(), decision.DeniedBy)
+	assert.Contains(decision.Trace, "ContractCall access allows contract call transactions")
+}
+
+func TestCheckCallPermission_whenPrivate(t *testing.T) {
+	assert := testifyassert.New(t)
+	SetDefaults(NETWORKADMIN, ORGADMIN, true)
+	defer SetDefaults(NETWORKADMIN, ORGADMIN, false)
+	setupExplainCaches()
+	CallPermissionMap = NewCallPermissionCache()
+	token, other := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	// the payload of a private transaction is the hash of the encrypted payload
+	payloadHash := common.BytesToEncryptedPayloadHash([]byte{0x84, 0x56, 0xcb, 0x59, 0x01}).Bytes()
+	CallPermissionMap.UpsertCallPermission("ORG1", "TRADER", token, FunctionSelector{0x84, 0x56, 0xcb, 0x59}, true)
+	CallPermissionMap.UpsertCallPermission("ORG1", "TRADER", other, FunctionSelector{}, true)
+
+	err := CheckCallPermission(Acct1, token, payloadHash, true)
+
+	assert.True(errors.Is(err, ErrNoCallPermission), "function call permissions must not match private transactions")
+	assert.Contains(err.Error(), "private transactions require a call permission for the whole contract")
+	assert.NoError(CheckCallPermission(Acct1, other, payloadHash, true))
+}
+
+func TestCheckTransactionPermission(t *testing.T) {
+	assert := testifyassert.New(t)
+	SetDefaults(NETWORKADMIN, ORGADMIN, true)
+	defer SetDefaults(NETWORKADMIN, ORGADMIN, false)
+	setupExplainCaches()
+	saved := PermissionTransactionAllowedFunc
+	defer func() { PermissionTransactionAllowedFunc = saved }()
+	PermissionTransactionAllowedFunc = func(common.Address, common.Address, *big.Int, *big.Int, *big.Int, []byte, TransactionType) error {
+		return nil
+	}
+	key, _ := crypto.GenerateKey()
+	AcctInfoMap.UpsertAccount("ORG1.SUB1", "TRADER", crypto.PubkeyToAddress(key.PublicKey), false, AcctActive)
+	CallPermissionMap = NewCallPermissionCache()
+	token := common.HexToAddress("0x1")
+	CallPermissionMap.UpsertCallPermission("ORG1", "TRADER", token, FunctionSelector{0x84, 0x56, 0xcb, 0x59}, true)
+	signer := types.HomesteadSigner{}
+	transfer, err := types.SignTx(types.NewTransaction(0, token, big.NewInt(0), 0, big.NewInt(0), []byte{0xa9, 0x05, 0x9c, 0xbb}), signer, key)
+	assert.NoError(err)
+	pmt, err := types.SignTx(types.NewTransaction(1, common.QuorumPrivacyPrecompileContractAddress(), big.NewInt(0), 0, big.NewInt(0), []byte{0xa9, 0x05, 0x9c, 0xbb}), signer, key)
+	assert.NoError(err)
+
+	assert.NoError(CheckTransactionPermission(transfer, false), "call permissions are not enforced before the transition")
+	assert.True(errors.Is(CheckTransactionPermission(transfer, true), ErrNoCallPermission))
+	assert.NoError(CheckTransactionPermission(pmt, true), "the private transaction of a marker transaction is checked on its own")
+}
@@ -0,0 +1,311 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+var accessTypeNames = map[AccessType]string{
+	ReadOnly:                  "ReadOnly",
+	Transact:                  "Transact",
+	ContractDeploy:            "ContractDeploy",
+	FullAccess:                "FullAccess",
+	ContractCall:              "ContractCall",
+	TransactAndContractCall:   "TransactAndContractCall",
+	TransactAndContractDeploy: "TransactAndContractDeploy",
+	ContractCallAndDeploy:     "ContractCallAndDeploy",
+}
+
+var transactionTypeNames = map[TransactionType]string{
+	ValueTransferTxn:  "value transfer",
+	ContractCallTxn:   "contract call",
+	ContractDeployTxn: "contract deploy",
+}
+
+var orgStatusNames = map[OrgStatus]string{
+	OrgPendingApproval:   "PendingApproval",
+	OrgApproved:          "Approved",
+	OrgPendingSuspension: "PendingSuspension",
+	OrgSuspended:         "Suspended",
+}
+
+var acctStatusNames = map[AcctStatus]string{
+	AcctPendingApproval:   "PendingApproval",
+	AcctActive:            "Active",
+	AcctInactive:          "Inactive",
+	AcctSuspended:         "Suspended",
+	AcctBlacklisted:       "Blacklisted",
+	AcctRecoveryInitiated: "RecoveryInitiated",
+	AcctRecoveryCompleted: "RecoveryCompleted",
+}
+
+var nodeStatusNames = map[NodeStatus]string{
+	NodePendingApproval:   "PendingApproval",
+	NodeApproved:          "Approved",
+	NodeDeactivated:       "Deactivated",
+	NodeBlackListed:       "Blacklisted",
+	NodeRecoveryInitiated: "RecoveryInitiated",
+}
+
+// PermissionDecision is the trace of a transaction or connection permission check made from the
+// permission caches. Trace lists the steps of the check in order and DeniedBy the rule which denied
+// it, if any.
+type PermissionDecision struct {
+	Allowed  bool     `json:"allowed"`
+	DeniedBy string   `json:"deniedBy,omitempty"`
+	Trace    []string `json:"trace"`
+
+	Account              *common.Address  `json:"account,omitempty"`
+	AccountStatus        AcctStatus       `json:"accountStatus,omitempty"`
+	Node                 string           `json:"node,omitempty"`
+	NodeStatus           NodeStatus       `json:"nodeStatus,omitempty"`
+	OrgId                string           `json:"orgId,omitempty"`
+	OrgStatus            OrgStatus        `json:"orgStatus,omitempty"`
+	UltimateParent       string           `json:"ultimateParent,omitempty"`
+	UltimateParentStatus OrgStatus        `json:"ultimateParentStatus,omitempty"`
+	RoleId               string           `json:"roleId,omitempty"`
+	RoleOrgId            string           `json:"roleOrgId,omitempty"`
+	Access               *AccessType      `json:"access,omitempty"`
+	TransactionType      *TransactionType `json:"transactionType,omitempty"`
+}
+
+func (d *PermissionDecision) trace(format string, args ...interface{}) {
+	d.Trace = append(d.Trace, fmt.Sprintf(format, args...))
+}
+
+func (d *PermissionDecision) allow(format string, args ...interface{}) *PermissionDecision {
+	d.trace(format, args...)
+	d.Allowed, d.DeniedBy = true, ""
+	return d
+}
+
+func (d *PermissionDecision) deny(format string, args ...interface{}) *PermissionDecision {
+	d.trace(format, args...)
+	d.Allowed, d.DeniedBy = false, d.Trace[len(d.Trace)-1]
+	return d
+}
+
+func (d *PermissionDecision) setAccess(access AccessType, format string, args ...interface{}) AccessType {
+	d.Access = &access
+	d.trace(format+", access is %s", append(args, accessTypeNames[access])...)
+	return access
+}
+
+// Conclude records the outcome of the check made by the permission contracts when it differs from
+// the decision traced from the caches
+func (d *PermissionDecision) Conclude(allowed bool) {
+	switch {
+	case allowed && !d.Allowed:
+		d.allow("allowed by the permission contracts, the permission caches are not up to date")
+	case !allowed && d.Allowed:
+		d.deny("denied by the permission contracts, the permission caches are not up to date")
+	}
+}
+
+// Reason returns the rule which denied the check followed by the account, org and role it applied to
+func (d *PermissionDecision) Reason() string {
+	if d.Allowed {
+		return "allowed"
+	}
+	var context []string
+	if d.Account != nil {
+		context = append(context, "account "+d.Account.Hex())
+	}
+	if d.Node != "" {
+		context = append(context, "node "+d.Node)
+	}
+	if d.OrgId != "" {
+		context = append(context, "org "+d.OrgId)
+	}
+	if d.RoleId != "" {
+		context = append(context, "role "+d.RoleId)
+	}
+	if len(context) == 0 {
+		return d.DeniedBy
+	}
+	return fmt.Sprintf("%s (%s)", d.DeniedBy, strings.Join(context, ", "))
+}
+
+// explainOrg traces the status of orgId and of its ultimate parent. It returns the org status and
+// the ultimate parent status, zero if the ultimate parent is not found.
+func (d *PermissionDecision) explainOrg(orgId string) (*OrgInfo, OrgStatus, OrgStatus) {
+	d.OrgId = orgId
+	o, _ := OrgInfoMap.GetOrg(orgId)
+	if o == nil {
+		d.trace("org %s not found", orgId)
+		return nil, 0, 0
+	}
+	d.OrgStatus, d.UltimateParent = o.Status, o.UltimateParent
+	d.trace("org %s is %s", orgId, orgStatusNames[o.Status])
+	if o.UltimateParent == orgId {
+		d.UltimateParentStatus = o.Status
+		return o, o.Status, o.Status
+	}
+	u, _ := OrgInfoMap.GetOrg(o.UltimateParent)
+	if u == nil {
+		d.trace("ultimate parent org %s not found", o.UltimateParent)
+		return o, o.Status, 0
+	}
+	d.UltimateParentStatus = u.Status
+	d.trace("ultimate parent org %s is %s", u.OrgId, orgStatusNames[u.Status])
+	return o, o.Status, u.Status
+}
+
+// explainRole resolves the role of a in its org and then in the ultimate parent org
+func (d *PermissionDecision) explainRole(a *AccountInfo, o *OrgInfo, requireActive bool) *RoleInfo {
+	orgIds := []string{a.OrgId}
+	if o != nil && o.UltimateParent != a.OrgId {
+		orgIds = append(orgIds, o.UltimateParent)
+	}
+	for i, orgId := range orgIds {
+		r, _ := RoleInfoMap.GetRole(orgId, a.RoleId)
+		switch {
+		case r == nil:
+			d.trace("role %s not found in org %s", a.RoleId, orgId)
+		case requireActive && !r.Active:
+			d.trace("role %s of org %s is not active", a.RoleId, orgId)
+		default:
+			d.RoleOrgId = orgId
+			if i > 0 {
+				d.trace("role %s resolved from the ultimate parent org %s", a.RoleId, orgId)
+			} else {
+				d.trace("role %s resolved from org %s", a.RoleId, orgId)
+			}
+			return r
+		}
+	}
+	return nil
+}
+
+// explainAcctAccess traces how the access of acctId is resolved by GetAcctAccess
+func (d *PermissionDecision) explainAcctAccess(acctId common.Address) AccessType {
+	d.Account = &acctId
+	a, _ := AcctInfoMap.GetAccount(acctId)
+	if a == nil {
+		return d.setAccess(defaultAccess, "account %s not found, default access applies", acctId.Hex())
+	}
+	d.AccountStatus, d.RoleId = a.Status, a.RoleId
+	d.trace("account %s belongs to org %s with role %s", acctId.Hex(), a.OrgId, a.RoleId)
+	if a.Status != AcctActive {
+		return d.setAccess(defaultAccess, "account is %s, default access applies", acctStatusNames[a.Status])
+	}
+	o, orgStatus, ultStatus := d.explainOrg(a.OrgId)
+	if o == nil || orgStatus == OrgSuspended || ultStatus == OrgSuspended {
+		return d.setAccess(defaultAccess, "org or ultimate parent org is not active, default access applies")
+	}
+	if a.RoleId == networkAdminRole || a.RoleId == orgAdminRole {
+		return d.setAccess(FullAccess, "role %s is an admin role", a.RoleId)
+	}
+	if r := d.explainRole(a, o, true); r != nil {
+		return d.setAccess(r.Access, "role %s of org %s", r.RoleId, r.OrgId)
+	}
+	return d.setAccess(defaultAccess, "no active role %s, default access applies", a.RoleId)
+}
+
+// ExplainTransaction traces the permission check of a transaction sent by from. The access rules
+// of the permission model in use are applied to the permission caches.
+func ExplainTransaction(from common.Address, to common.Address, payload []byte, transactionType TransactionType) *PermissionDecision {
+	d := &PermissionDecision{}
+	if !PermissionsEnabled() {
+		return d.allow("permissions are not enabled yet, all transactions are allowed")
+	}
+	if PermissionModel == V2 {
+		return explainTransactionV2(d, from, to, payload)
+	}
+	access := d.explainAcctAccess(from)
+	d.TransactionType = &transactionType
+	switch {
+	case access == FullAccess || access == ContractDeploy:
+		return d.allow("%s access allows all transactions", accessTypeNames[access])
+	case access == Transact && transactionType != ContractDeployTxn:
+		return d.allow("Transact access allows %s transactions", transactionTypeNames[transactionType])
+	case access == Transact:
+		return d.deny("Transact access does not allow contract deploy transactions")
+	case access == ReadOnly:
+		return d.deny("ReadOnly access does not allow transactions")
+	}
+	return d.deny("%s access is not supported by the permission model", accessTypeNames[access])
+}
+
+// explainTransactionV2 follows the transactionAllowed checks of the v2 permission contracts
+func explainTransactionV2(d *PermissionDecision, from common.Address, to common.Address, payload []byte) *PermissionDecision {
+	d.Account = &from
+	a, _ := AcctInfoMap.GetAccount(from)
+	if a == nil {
+		return d.deny("account %s is not in the permission model", from.Hex())
+	}
+	d.AccountStatus, d.RoleId = a.Status, a.RoleId
+	d.trace("account %s belongs to org %s with role %s", from.Hex(), a.OrgId, a.RoleId)
+	if a.Status != AcctActive {
+		return d.deny("account is %s, only active accounts can transact", acctStatusNames[a.Status])
+	}
+	o, orgStatus, ultStatus := d.explainOrg(a.OrgId)
+	active := func(s OrgStatus) bool { return s == OrgApproved || s == OrgPendingSuspension }
+	if o == nil || !active(orgStatus) || !active(ultStatus) {
+		return d.deny("org and ultimate parent org must be approved for their accounts to transact")
+	}
+	if a.RoleId == networkAdminRole || a.IsOrgAdmin {
+		d.setAccess(FullAccess, "account is an admin account")
+		return d.allow("admin accounts can send all transactions")
+	}
+	access := ReadOnly
+	if r := d.explainRole(a, o, false); r != nil {
+		if r.IsAdmin {
+			d.setAccess(FullAccess, "role %s of org %s is an admin role", r.RoleId, r.OrgId)
+			return d.allow("admin accounts can send all transactions")
+		}
+		access = d.setAccess(r.Access, "role %s of org %s", r.RoleId, r.OrgId)
+	} else {
+		d.setAccess(access, "no role %s", a.RoleId)
+	}
+	transactionType := ValueTransferTxn
+	if to == (common.Address{}) {
+		transactionType = ContractDeployTxn
+	} else if len(payload) > 0 {
+		transactionType = ContractCallTxn
+	}
+	d.TransactionType = &transactionType
+	allowed := map[TransactionType][]AccessType{
+		ValueTransferTxn:  {FullAccess, Transact, TransactAndContractCall, TransactAndContractDeploy},
+		ContractDeployTxn: {FullAccess, ContractDeploy, TransactAndContractDeploy, ContractCallAndDeploy},
+		ContractCallTxn:   {FullAccess, ContractCall, TransactAndContractCall, ContractCallAndDeploy},
+	}[transactionType]
+	for _, t := range allowed {
+		if t == access {
+			return d.allow("%s access allows %s transactions", accessTypeNames[access], transactionTypeNames[transactionType])
+		}
+	}
+	return d.deny("%s access does not allow %s transactions", accessTypeNames[access], transactionTypeNames[transactionType])
+}
+
+// ExplainConnection traces the permission check of a connection from node. The v2 permission model
+// also requires the connection ip to be the registered one.
+func ExplainConnection(node *enode.Node, ip string) *PermissionDecision {
+	d := &PermissionDecision{Node: node.URLv4()}
+	var n *NodeInfo
+	for _, rec := range NodeInfoMap.GetNodeList() {
+		if rec.ID() == node.ID() {
+			rec := rec
+			n = &rec
+			break
+		}
+	}
+	if n == nil {
+		return d.deny("node %s is not in the permission model", node.ID().TerminalString())
+	}
+	d.NodeStatus = n.Status
+	d.trace("node %s belongs to org %s", n.Url, n.OrgId)
+	d.explainOrg(n.OrgId)
+	if n.Status != NodeApproved {
+		return d.deny("node is %s, only approved nodes can connect", nodeStatusNames[n.Status])
+	}
+	if PermissionModel == V2 {
+		if rec, err := enode.ParseV4(n.Url); err == nil && rec.IP().String() != ip {
+			return d.deny("connection ip %s is not the registered ip %s", ip, rec.IP())
+		}
+	}
+	return d.allow("approved nodes can connect")
+}
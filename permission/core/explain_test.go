@@ -0,0 +1,120 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func setupExplainCaches() {
+	OrgInfoMap = NewOrgCache(params.DEFAULT_ORGCACHE_SIZE)
+	RoleInfoMap = NewRoleCache(params.DEFAULT_ROLECACHE_SIZE)
+	NodeInfoMap = NewNodeCache(params.DEFAULT_NODECACHE_SIZE)
+	AcctInfoMap = NewAcctCache(params.DEFAULT_ACCOUNTCACHE_SIZE)
+	SetQIP714BlockReached()
+	SetNetworkBootUpCompleted()
+
+	OrgInfoMap.UpsertOrg("ORG1", "", "ORG1", big.NewInt(1), OrgApproved)
+	OrgInfoMap.UpsertOrg("SUB1", "ORG1", "ORG1", big.NewInt(2), OrgApproved)
+	RoleInfoMap.UpsertRole("ORG1", "TRADER", false, false, Transact, true)
+	AcctInfoMap.UpsertAccount("ORG1.SUB1", "TRADER", Acct1, false, AcctActive)
+	AcctInfoMap.UpsertAccount("ORG1.SUB1", "TRADER", Acct2, false, AcctSuspended)
+}
+
+func TestExplainTransaction(t *testing.T) {
+	assert := testifyassert.New(t)
+	SetDefaults(NETWORKADMIN, ORGADMIN, false)
+	setupExplainCaches()
+	target := common.HexToAddress("0x1")
+
+	decision := ExplainTransaction(Acct1, target, nil, ValueTransferTxn)
+
+	assert.True(decision.Allowed)
+	assert.Equal("ORG1.SUB1", decision.OrgId)
+	assert.Equal("ORG1", decision.UltimateParent)
+	assert.Equal("ORG1", decision.RoleOrgId)
+	assert.Equal(Transact, *decision.Access)
+	assert.Equal([]string{
+		"account " + Acct1.Hex() + " belongs to org ORG1.SUB1 with role TRADER",
+		"org ORG1.SUB1 is Approved",
+		"ultimate parent org ORG1 is Approved",
+		"role TRADER not found in org ORG1.SUB1",
+		"role TRADER resolved from the ultimate parent org ORG1",
+		"role TRADER of org ORG1, access is Transact",
+		"Transact access allows value transfer transactions",
+	}, decision.Trace)
+
+	decision = ExplainTransaction(Acct1, common.Address{}, []byte{1}, ContractDeployTxn)
+
+	assert.False(decision.Allowed)
+	assert.Equal("Transact access does not allow contract deploy transactions", decision.DeniedBy)
+	assert.Equal("Transact access does not allow contract deploy transactions (account "+Acct1.Hex()+", org ORG1.SUB1, role TRADER)", decision.Reason())
+
+	decision = ExplainTransaction(Acct2, target, nil, ValueTransferTxn)
+
+	assert.False(decision.Allowed)
+	assert.Equal(AcctSuspended, decision.AccountStatus)
+	assert.Equal("ReadOnly access does not allow transactions", decision.DeniedBy)
+	assert.Contains(decision.Trace, "account is Suspended, default access applies, access is ReadOnly")
+
+	OrgInfoMap.UpsertOrg("ORG1", "", "ORG1", big.NewInt(1), OrgSuspended)
+	decision = ExplainTransaction(Acct1, target, nil, ValueTransferTxn)
+
+	assert.False(decision.Allowed)
+	assert.Equal(OrgSuspended, decision.UltimateParentStatus)
+	assert.Contains(decision.Trace, "org or ultimate parent org is not active, default access applies, access is ReadOnly")
+
+	decision.Conclude(true)
+
+	assert.True(decision.Allowed)
+	assert.Equal("allowed", decision.Reason())
+}
+
+func TestExplainTransaction_whenV2(t *testing.T) {
+	assert := testifyassert.New(t)
+	SetDefaults(NETWORKADMIN, ORGADMIN, true)
+	defer SetDefaults(NETWORKADMIN, ORGADMIN, false)
+	setupExplainCaches()
+	RoleInfoMap.UpsertRole("ORG1", "TRADER", false, false, ContractCall, false)
+
+	decision := ExplainTransaction(Acct1, common.HexToAddress("0x1"), []byte{1}, ContractCallTxn)
+
+	assert.True(decision.Allowed)
+	assert.Equal(ContractCall, *decision.Access)
+
+	decision = ExplainTransaction(Acct1, common.HexToAddress("0x1"), nil, ContractCallTxn)
+
+	assert.False(decision.Allowed)
+	assert.Equal(ValueTransferTxn, *decision.TransactionType)
+	assert.Equal("ContractCall access does not allow value transfer transactions", decision.DeniedBy)
+
+	decision = ExplainTransaction(common.HexToAddress("0x2"), common.HexToAddress("0x1"), nil, ValueTransferTxn)
+
+	assert.False(decision.Allowed)
+	assert.Equal("account 0x0000000000000000000000000000000000000002 is not in the permission model", decision.DeniedBy)
+}
+
+func TestExplainConnection(t *testing.T) {
+	assert := testifyassert.New(t)
+	SetDefaults(NETWORKADMIN, ORGADMIN, false)
+	setupExplainCaches()
+	NodeInfoMap.UpsertNode("ORG1.SUB1", NODE1, NodeApproved)
+	NodeInfoMap.UpsertNode("ORG1.SUB1", NODE2, NodeBlackListed)
+	node1, _ := enode.ParseV4(NODE1)
+	node2, _ := enode.ParseV4(NODE2)
+
+	decision := ExplainConnection(node1, "127.0.0.1")
+
+	assert.True(decision.Allowed)
+	assert.Equal("ORG1.SUB1", decision.OrgId)
+
+	decision = ExplainConnection(node2, "127.0.0.1")
+
+	assert.False(decision.Allowed)
+	assert.Equal(NodeBlackListed, decision.NodeStatus)
+	assert.Equal("node is Blacklisted, only approved nodes can connect", decision.DeniedBy)
+}
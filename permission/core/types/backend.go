@@ -39,6 +39,7 @@ type PermissionConfig struct {
 	RoleAddress      common.Address `json:"roleMgrAddress"`
 	VoterAddress     common.Address `json:"voterMgrAddress"`
 	OrgAddress       common.Address `json:"orgMgrAddress"`
+	CallPermAddress  common.Address `json:"callPermMgrAddress"` // optional, v2 only
 	NwAdminOrg       string         `json:"nwAdminOrg"`
 	NwAdminRole      string         `json:"nwAdminRole"`
 	OrgAdminRole     string         `json:"orgAdminRole"`
@@ -46,6 +47,23 @@ type PermissionConfig struct {
 	Accounts      []common.Address `json:"accounts"` //initial list of account that need full access
 	SubOrgDepth   *big.Int         `json:"subOrgDepth"`
 	SubOrgBreadth *big.Int         `json:"subOrgBreadth"`
+
+	Migration *PermissionMigration `json:"migration,omitempty"` // optional, v1 only
+}
+
+// PermissionMigration holds the v2 contracts a v1 network is migrated to. The switch block is set by
+// the v2PermissionMigrated transition of the chain config, the v2 contracts must be deployed, booted
+// and populated with the v1 permissions before the block is reached.
+type PermissionMigration struct {
+	UpgrdAddress    common.Address `json:"upgrdableAddress"`
+	InterfAddress   common.Address `json:"interfaceAddress"`
+	ImplAddress     common.Address `json:"implAddress"`
+	NodeAddress     common.Address `json:"nodeMgrAddress"`
+	AccountAddress  common.Address `json:"accountMgrAddress"`
+	RoleAddress     common.Address `json:"roleMgrAddress"`
+	VoterAddress    common.Address `json:"voterMgrAddress"`
+	OrgAddress      common.Address `json:"orgMgrAddress"`
+	CallPermAddress common.Address `json:"callPermMgrAddress"`
 }
 
 var (
@@ -78,6 +96,11 @@ var (
 	ErrNodeDoesNotExists  = errors.New("Node does not exist")
 	ErrOrgDoesNotExists   = errors.New("Org does not exist")
 	ErrInactiveRole       = errors.New("Role is already inactive")
+	ErrNoCallPermMgr      = errors.New("Call permissions are not configured in the permissions model")
+	ErrCallPermExists     = errors.New("Call permission exists for the role")
+	ErrCallPermNotThere   = errors.New("Call permission does not exist for the role")
+	ErrNoMigration        = errors.New("No migration to the v2 permissions model is configured")
+	ErrNotV1Model         = errors.New("The node does not use the v1 permissions model")
 
 	ErrNotMasterOrg         = errors.New("Org is not a master org")
 	ErrHostNameNotSupported = errors.New("Hostname not supported in the network")
@@ -139,6 +162,8 @@ type Backend interface {
 	GetAuditService(auditBackend ContractBackend) (AuditService, error)
 	// control service for account management service
 	GetControlService(controlBackend ContractBackend) (ControlService, error)
+	// call permission service for role call permissions management service
+	GetCallPermissionService(transactOpts *bind.TransactOpts, callPermBackend ContractBackend) (CallPermissionService, error)
 	// Monitors account access related events and updates the cache accordingly
 	ManageAccountPermissions() error
 	// Monitors Node management events and updates cache accordingly
@@ -148,6 +173,8 @@ type Backend interface {
 	ManageOrgPermissions() error
 	// monitors role management related events and updated cache
 	ManageRolePermissions() error
+	// monitors role call permission events and updates cache
+	ManageCallPermissions() error
 
 	// monitors for network boot up complete event
 	MonitorNetworkBootUp() error
@@ -319,6 +346,14 @@ func ParsePermissionConfig(dir string) (PermissionConfig, error) {
 	if permConfig.IsEmpty() {
 		return PermissionConfig{}, fmt.Errorf("missing contract addresses in %s", params.PERMISSION_MODEL_CONFIG)
 	}
+	if m := permConfig.Migration; m != nil {
+		switch {
+		case permConfig.PermissionsModel != PERMISSION_V1:
+			return PermissionConfig{}, fmt.Errorf("only the v1 permissions model can be migrated in %s", params.PERMISSION_MODEL_CONFIG)
+		case m.InterfAddress == common.HexToAddress("0x0"):
+			return PermissionConfig{}, fmt.Errorf("missing migration contract addresses in %s", params.PERMISSION_MODEL_CONFIG)
+		}
+	}
 
 	return permConfig, nil
 }
@@ -347,3 +382,14 @@ func GetNodeDetails(url string, isRaft, useDns bool) (string, string, uint16, ui
 func (pc *PermissionConfig) IsEmpty() bool {
 	return pc.InterfAddress == common.HexToAddress("0x0")
 }
+
+// V2Config returns the config of the v2 permissions model the v1 model is migrated to
+func (pc *PermissionConfig) V2Config() *PermissionConfig {
+	v2 := *pc
+	m := pc.Migration
+	v2.PermissionsModel, v2.Migration = PERMISSION_V2, nil
+	v2.UpgrdAddress, v2.InterfAddress, v2.ImplAddress = m.UpgrdAddress, m.InterfAddress, m.ImplAddress
+	v2.NodeAddress, v2.AccountAddress, v2.RoleAddress = m.NodeAddress, m.AccountAddress, m.RoleAddress
+	v2.VoterAddress, v2.OrgAddress, v2.CallPermAddress = m.VoterAddress, m.OrgAddress, m.CallPermAddress
+	return &v2
+}
@@ -23,6 +23,8 @@ type TxArgs struct {
 	AcctId     common.Address
 	AccessType uint8
 	Action     uint8
+	Contract   common.Address
+	Selector   core.FunctionSelector
 	Txa        ethapi.SendTxArgs
 }
 
@@ -40,6 +42,12 @@ type RoleService interface {
 	RemoveRole(_args TxArgs) (*types.Transaction, error)
 }
 
+// Call permission services
+type CallPermissionService interface {
+	AddCallPermission(_args TxArgs) (*types.Transaction, error)
+	RemoveCallPermission(_args TxArgs) (*types.Transaction, error)
+}
+
 // Org services
 type OrgService interface {
 	AddOrg(_args TxArgs) (*types.Transaction, error)
@@ -118,6 +126,16 @@ type InitService interface {
 	GetNodeDetailsFromIndex(_nodeIndex *big.Int) (string, string, *big.Int, error)
 	GetNumberOfNodes() (*big.Int, error)
 	GetNodeDetails(enodeId string) (string, string, *big.Int, error)
+
+	SetCallBlock(blockNumber *big.Int)
+	EventContracts() []EventContract
+}
+
+// EventContract is a permission contract whose events make up the permission history
+type EventContract struct {
+	Name    string
+	Address common.Address
+	ABI     string
 }
 
 func BindContract(contractInstance interface{}, bindFunc func() (interface{}, error)) error {
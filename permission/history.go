@@ -0,0 +1,381 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// number of blocks scanned by each log query while indexing the permission history
+const historyBlockRange = 2048
+
+// PermissionHistoryArgs filters the permission history. Empty fields match all the entries.
+// Node is an enode id or an enode url.
+type PermissionHistoryArgs struct {
+	OrgId     string           `json:"orgId"`
+	Account   *common.Address  `json:"account"`
+	Node      string           `json:"node"`
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+}
+
+// PermissionHistoryEntry is a permission event with the transaction which raised it. Admin is the
+// sender of the transaction. StatusBefore and StatusAfter hold the org, account or node status
+// before and after the event, they are zero for role and voter events.
+type PermissionHistoryEntry struct {
+	BlockNumber  uint64          `json:"blockNumber"`
+	TxHash       common.Hash     `json:"txHash"`
+	LogIndex     uint            `json:"logIndex"`
+	Admin        common.Address  `json:"admin"`
+	Contract     string          `json:"contract"`
+	Event        string          `json:"event"`
+	OrgId        string          `json:"orgId,omitempty"`
+	RoleId       string          `json:"roleId,omitempty"`
+	Account      *common.Address `json:"account,omitempty"`
+	EnodeId      string          `json:"enodeId,omitempty"`
+	Url          string          `json:"url,omitempty"`
+	StatusBefore uint8           `json:"statusBefore"`
+	StatusAfter  uint8           `json:"statusAfter"`
+}
+
+type historyContract struct {
+	name  string
+	abi   abi.ABI
+	from  uint64 // first block the events of the contract are indexed at
+	until uint64 // block the events of the contract are no longer indexed at, 0 for none
+}
+
+// permissionHistory indexes the events of the permission contracts by org, account and node.
+// It tracks the statuses while indexing to report the status before each event.
+type permissionHistory struct {
+	contracts map[common.Address]*historyContract
+	isRaft    bool
+
+	next     uint64      // next block to index
+	lastHash common.Hash // hash of the last indexed block, used to detect reorgs
+
+	entries    []*PermissionHistoryEntry
+	byOrg      map[string][]int
+	byAccount  map[common.Address][]int
+	byNode     map[string][]int
+	orgStatus  map[string]pcore.OrgStatus
+	acctStatus map[common.Address]pcore.AcctStatus
+	nodeStatus map[string]pcore.NodeStatus
+}
+
+func newPermissionHistory(contracts []ptype.EventContract, isRaft bool) (*permissionHistory, error) {
+	h := &permissionHistory{contracts: make(map[common.Address]*historyContract), isRaft: isRaft}
+	if err := h.addContracts(contracts, 0, 0); err != nil {
+		return nil, err
+	}
+	h.reset()
+	return h, nil
+}
+
+// addContracts indexes the events of contracts emitted from block from until block until, 0 for
+// all the blocks after from
+func (h *permissionHistory) addContracts(contracts []ptype.EventContract, from, until uint64) error {
+	for _, c := range contracts {
+		parsed, err := abi.JSON(strings.NewReader(c.ABI))
+		if err != nil {
+			return err
+		}
+		h.contracts[c.Address] = &historyContract{name: c.Name, abi: parsed, from: from, until: until}
+	}
+	return nil
+}
+
+// reset drops the index so that it is built again from the genesis block
+func (h *permissionHistory) reset() {
+	h.next, h.lastHash = 0, common.Hash{}
+	h.entries = nil
+	h.byOrg = make(map[string][]int)
+	h.byAccount = make(map[common.Address][]int)
+	h.byNode = make(map[string][]int)
+	h.orgStatus = make(map[string]pcore.OrgStatus)
+	h.acctStatus = make(map[common.Address]pcore.AcctStatus)
+	h.nodeStatus = make(map[string]pcore.NodeStatus)
+}
+
+func (h *permissionHistory) addresses() []common.Address {
+	addresses := make([]common.Address, 0, len(h.contracts))
+	for address := range h.contracts {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// addLog indexes the permission event of l. Logs of other contracts or events are ignored.
+func (h *permissionHistory) addLog(l types.Log, admin common.Address) error {
+	contract, ok := h.contracts[l.Address]
+	if !ok || len(l.Topics) == 0 || l.BlockNumber < contract.from || (contract.until != 0 && l.BlockNumber >= contract.until) {
+		return nil
+	}
+	event, err := contract.abi.EventByID(l.Topics[0])
+	if err != nil {
+		return nil
+	}
+	fields := make(map[string]interface{})
+	if err := contract.abi.UnpackIntoMap(fields, event.Name, l.Data); err != nil {
+		return err
+	}
+	str := func(name string) string {
+		s, _ := fields[name].(string)
+		return s
+	}
+	entry := &PermissionHistoryEntry{
+		BlockNumber: l.BlockNumber,
+		TxHash:      l.TxHash,
+		LogIndex:    l.Index,
+		Admin:       admin,
+		Contract:    contract.name,
+		Event:       event.Name,
+		OrgId:       str("_orgId"),
+		RoleId:      str("_roleId"),
+	}
+	if account, ok := fields["_account"].(common.Address); ok {
+		entry.Account = &account
+	} else if account, ok := fields["_vAccount"].(common.Address); ok {
+		entry.Account = &account
+	}
+	status, _ := fields["_status"].(*big.Int)
+
+	switch contract.name {
+	case "OrgManager":
+		// org events carry the org id and its parent, the index uses the full org id
+		if porgId := str("_porgId"); porgId != "" {
+			entry.OrgId = porgId + "." + entry.OrgId
+		}
+		before, after := h.orgStatus[entry.OrgId], h.orgStatus[entry.OrgId]
+		switch {
+		case status != nil:
+			after = pcore.OrgStatus(status.Uint64())
+		case event.Name == "OrgSuspended":
+			after = pcore.OrgSuspended
+		case event.Name == "OrgSuspensionRevoked":
+			after = pcore.OrgApproved
+		}
+		h.orgStatus[entry.OrgId] = after
+		entry.StatusBefore, entry.StatusAfter = uint8(before), uint8(after)
+	case "AcctManager":
+		before, after := h.acctStatus[*entry.Account], h.acctStatus[*entry.Account]
+		if status != nil {
+			after = pcore.AcctStatus(status.Uint64())
+		}
+		h.acctStatus[*entry.Account] = after
+		entry.StatusBefore, entry.StatusAfter = uint8(before), uint8(after)
+	case "NodeManager":
+		// v1 events carry the enode url while v2 events carry the enode id and address
+		if ip, ok := fields["_ip"].(string); ok {
+			port, _ := fields["_port"].(uint16)
+			raftport, _ := fields["_raftport"].(uint16)
+			entry.EnodeId = str("_enodeId")
+			entry.Url = pcore.GetNodeUrl(entry.EnodeId, ip, port, raftport, h.isRaft)
+		} else {
+			entry.Url = str("_enodeId")
+			entry.EnodeId = historyEnodeId(entry.Url)
+		}
+		before := h.nodeStatus[entry.EnodeId]
+		after := map[string]pcore.NodeStatus{
+			"NodeProposed":          pcore.NodePendingApproval,
+			"NodeApproved":          pcore.NodeApproved,
+			"NodeDeactivated":       pcore.NodeDeactivated,
+			"NodeActivated":         pcore.NodeApproved,
+			"NodeBlacklisted":       pcore.NodeBlackListed,
+			"NodeRecoveryInitiated": pcore.NodeRecoveryInitiated,
+			"NodeRecoveryCompleted": pcore.NodeApproved,
+		}[event.Name]
+		h.nodeStatus[entry.EnodeId] = after
+		entry.StatusBefore, entry.StatusAfter = uint8(before), uint8(after)
+	}
+
+	position := len(h.entries)
+	h.entries = append(h.entries, entry)
+	if entry.OrgId != "" {
+		h.byOrg[entry.OrgId] = append(h.byOrg[entry.OrgId], position)
+	}
+	if entry.Account != nil {
+		h.byAccount[*entry.Account] = append(h.byAccount[*entry.Account], position)
+	}
+	if entry.EnodeId != "" {
+		h.byNode[entry.EnodeId] = append(h.byNode[entry.EnodeId], position)
+	}
+	return nil
+}
+
+// filter returns the indexed entries matching args between the blocks from and to
+func (h *permissionHistory) filter(args PermissionHistoryArgs, from, to uint64) []*PermissionHistoryEntry {
+	enodeId := historyEnodeId(args.Node)
+	var positions []int
+	switch {
+	case args.OrgId != "":
+		positions = h.byOrg[args.OrgId]
+	case args.Account != nil:
+		positions = h.byAccount[*args.Account]
+	case enodeId != "":
+		positions = h.byNode[enodeId]
+	default:
+		positions = make([]int, len(h.entries))
+		for i := range positions {
+			positions[i] = i
+		}
+	}
+	result := make([]*PermissionHistoryEntry, 0)
+	for _, i := range positions {
+		e := h.entries[i]
+		if e.BlockNumber < from || e.BlockNumber > to {
+			continue
+		}
+		if (args.OrgId != "" && e.OrgId != args.OrgId) ||
+			(args.Account != nil && (e.Account == nil || *e.Account != *args.Account)) ||
+			(enodeId != "" && e.EnodeId != enodeId) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// historyEnodeId returns the enode id of an enode url, or node itself if it is not a url
+func historyEnodeId(node string) string {
+	if !strings.HasPrefix(node, "enode://") {
+		return node
+	}
+	n, err := enode.ParseV4(node)
+	if err != nil {
+		return node
+	}
+	return n.EnodeID()
+}
+
+// permissionHistory brings the history index up to the current block and returns its entries
+// matching args
+func (p *PermissionCtrl) permissionHistory(args PermissionHistoryArgs) ([]*PermissionHistoryEntry, error) {
+	p.historyMu.Lock()
+	defer p.historyMu.Unlock()
+
+	if p.history == nil {
+		h, err := p.newHistory()
+		if err != nil {
+			return nil, err
+		}
+		p.history = h
+	}
+	head, err := p.syncHistory(p.history)
+	if err != nil {
+		return nil, err
+	}
+	blockNumber := func(n *rpc.BlockNumber, def uint64) uint64 {
+		if n == nil || *n < 0 {
+			return def
+		}
+		return uint64(*n)
+	}
+	from, to := blockNumber(args.FromBlock, 0), blockNumber(args.ToBlock, head)
+	if args.FromBlock != nil && *args.FromBlock < 0 {
+		from = head
+	}
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+	return p.history.filter(args, from, to), nil
+}
+
+// newHistory returns an empty index of the permission events. A v1 model migrated to v2 is indexed
+// from its v1 contracts before the migration block and from the v2 contracts from that block on,
+// whether or not the node already switched to the v2 model.
+func (p *PermissionCtrl) newHistory() (*permissionHistory, error) {
+	config, err := p.migrationConfig()
+	migrationBlock := p.migrationBlock()
+	if err != nil || config.Migration == nil || migrationBlock == nil {
+		return newPermissionHistory(p.contractService().EventContracts(), p.isRaft)
+	}
+	block := migrationBlock.Uint64()
+	h, err := newPermissionHistory(nil, p.isRaft)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.addContracts(NewPermissionContractService(p.ethClnt, false, p.key, config, p.isRaft, p.useDns, p.chainID).EventContracts(), 0, block); err != nil {
+		return nil, err
+	}
+	v2Config := config.V2Config()
+	if err := h.addContracts(NewPermissionContractService(p.ethClnt, true, p.key, v2Config, p.isRaft, p.useDns, p.chainID).EventContracts(), block, 0); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// syncHistory indexes the permission events up to the current block and returns its number.
+// The index is built again when the last indexed block is no longer canonical.
+func (p *PermissionCtrl) syncHistory(h *permissionHistory) (uint64, error) {
+	chain := p.eth.BlockChain()
+	head := chain.CurrentBlock().NumberU64()
+	if h.next > 0 {
+		if header := chain.GetHeaderByNumber(h.next - 1); header == nil || header.Hash() != h.lastHash {
+			h.reset()
+		}
+	}
+	var block *types.Block
+	for from := h.next; from <= head; from += historyBlockRange {
+		to := from + historyBlockRange - 1
+		if to > head {
+			to = head
+		}
+		logs, err := p.ethClnt.FilterLogs(context.Background(), goethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: h.addresses(),
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, l := range logs {
+			if l.Removed {
+				continue
+			}
+			if block == nil || block.Hash() != l.BlockHash {
+				if block = chain.GetBlockByHash(l.BlockHash); block == nil {
+					return 0, errors.New("block of permission event not found")
+				}
+			}
+			admin, err := transactionSender(chain.Config(), block, l.TxIndex)
+			if err != nil {
+				return 0, err
+			}
+			if err := h.addLog(l, admin); err != nil {
+				return 0, err
+			}
+		}
+		header := chain.GetHeaderByNumber(to)
+		if header == nil {
+			return 0, errors.New("permission history indexing interrupted by a reorg")
+		}
+		h.next, h.lastHash = to+1, header.Hash()
+	}
+	return head, nil
+}
+
+// transactionSender returns the sender of the transaction at index in block
+func transactionSender(config *params.ChainConfig, block *types.Block, index uint) (common.Address, error) {
+	txs := block.Transactions()
+	if index >= uint(len(txs)) {
+		return common.Address{}, errors.New("transaction of permission event not found")
+	}
+	tx := txs[index]
+	signer := types.MakeSigner(config, block.Number())
+	if tx.IsPrivate() {
+		signer = types.QuorumPrivateTxSigner{}
+	}
+	return types.Sender(signer, tx)
+}
@@ -0,0 +1,119 @@
+package permission
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	v2bind "github.com/ethereum/go-ethereum/permission/v2/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuorumControlsAPI_History(t *testing.T) {
+	testObject := typicalQuorumControlsAPI(t)
+	simulated := contrBackend.(*backends.SimulatedBackend)
+	orgAdmin := getArbitraryAccount()
+	txa := ethapi.SendTxArgs{From: guardianAddress}
+
+	_, err := testObject.AddOrg(arbitraryOrgToAdd, arbitraryNode1, orgAdmin, txa)
+	assert.NoError(t, err)
+	simulated.Commit()
+	_, err = testObject.ApproveOrg(arbitraryOrgToAdd, arbitraryNode1, orgAdmin, txa)
+	assert.NoError(t, err)
+	simulated.Commit()
+
+	history, err := testObject.History(PermissionHistoryArgs{OrgId: arbitraryOrgToAdd})
+
+	assert.NoError(t, err)
+	var events []string
+	for _, e := range history {
+		events = append(events, e.Event)
+		assert.Equal(t, guardianAddress, e.Admin)
+		assert.NotEqual(t, common.Hash{}, e.TxHash)
+	}
+	assert.Equal(t, []string{"OrgPendingApproval", "NodeProposed", "AccountAccessModified", "OrgApproved", "RoleCreated", "NodeApproved", "AccountAccessModified"}, events)
+	assert.Equal(t, []uint8{0, uint8(pcore.OrgPendingApproval)}, []uint8{history[0].StatusBefore, history[0].StatusAfter})
+	assert.Equal(t, []uint8{uint8(pcore.OrgPendingApproval), uint8(pcore.OrgApproved)}, []uint8{history[3].StatusBefore, history[3].StatusAfter})
+
+	history, err = testObject.History(PermissionHistoryArgs{Node: arbitraryNode1})
+
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, arbitraryNode1, history[1].Url)
+		assert.Equal(t, []uint8{uint8(pcore.NodePendingApproval), uint8(pcore.NodeApproved)}, []uint8{history[1].StatusBefore, history[1].StatusAfter})
+	}
+
+	fromBlock := rpc.BlockNumber(2)
+	history, err = testObject.History(PermissionHistoryArgs{Account: &orgAdmin, FromBlock: &fromBlock})
+
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, uint64(2), history[0].BlockNumber)
+		assert.Equal(t, []uint8{uint8(pcore.AcctPendingApproval), uint8(pcore.AcctActive)}, []uint8{history[0].StatusBefore, history[0].StatusAfter})
+	}
+
+	toBlock := rpc.BlockNumber(1)
+	_, err = testObject.History(PermissionHistoryArgs{FromBlock: &fromBlock, ToBlock: &toBlock})
+
+	assert.EqualError(t, err, "invalid block range")
+}
+
+func TestPermissionHistory_whenV2NodeEvents(t *testing.T) {
+	nodeManager := common.HexToAddress("0x1")
+	h, err := newPermissionHistory([]ptype.EventContract{{Name: "NodeManager", Address: nodeManager, ABI: v2bind.NodeManagerABI}}, true)
+	assert.NoError(t, err)
+	enodeId := historyEnodeId(arbitraryNode1)
+	event := h.contracts[nodeManager].abi.Events["NodeBlacklisted"]
+	data, err := event.Inputs.Pack(enodeId, "127.0.0.1", uint16(21000), uint16(50401), arbitraryOrgToAdd)
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.addLog(types.Log{Address: nodeManager, Topics: []common.Hash{event.ID}, Data: data, BlockNumber: 5}, guardianAddress))
+
+	history := h.filter(PermissionHistoryArgs{Node: arbitraryNode1}, 0, 10)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, &PermissionHistoryEntry{
+			BlockNumber: 5,
+			Admin:       guardianAddress,
+			Contract:    "NodeManager",
+			Event:       "NodeBlacklisted",
+			OrgId:       arbitraryOrgToAdd,
+			EnodeId:     enodeId,
+			Url:         arbitraryNode1,
+			StatusAfter: uint8(pcore.NodeBlackListed),
+		}, history[0])
+	}
+	assert.Empty(t, h.filter(PermissionHistoryArgs{OrgId: arbitraryOrgToAdd}, 6, 10))
+	assert.Empty(t, h.filter(PermissionHistoryArgs{Account: &guardianAddress}, 0, 10))
+}
+
+func TestPermissionHistory_whenMigrated(t *testing.T) {
+	v1NodeManager, v2NodeManager := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	h, err := newPermissionHistory(nil, true)
+	assert.NoError(t, err)
+	assert.NoError(t, h.addContracts([]ptype.EventContract{{Name: "NodeManager", Address: v1NodeManager, ABI: v2bind.NodeManagerABI}}, 0, 10))
+	assert.NoError(t, h.addContracts([]ptype.EventContract{{Name: "NodeManager", Address: v2NodeManager, ABI: v2bind.NodeManagerABI}}, 10, 0))
+	event := h.contracts[v2NodeManager].abi.Events["NodeBlacklisted"]
+	data, err := event.Inputs.Pack(historyEnodeId(arbitraryNode1), "127.0.0.1", uint16(21000), uint16(50401), arbitraryOrgToAdd)
+	assert.NoError(t, err)
+
+	for _, l := range []types.Log{
+		{Address: v1NodeManager, BlockNumber: 9},
+		{Address: v2NodeManager, BlockNumber: 9}, // population of the v2 contracts before the migration block
+		{Address: v1NodeManager, BlockNumber: 10},
+		{Address: v2NodeManager, BlockNumber: 10},
+	} {
+		l.Topics, l.Data = []common.Hash{event.ID}, data
+		assert.NoError(t, h.addLog(l, guardianAddress))
+	}
+
+	history := h.filter(PermissionHistoryArgs{Node: arbitraryNode1}, 0, 20)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, uint64(9), history[0].BlockNumber)
+		assert.Equal(t, uint64(10), history[1].BlockNumber)
+	}
+}
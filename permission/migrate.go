@@ -0,0 +1,784 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/permission/core"
+	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	v1 "github.com/ethereum/go-ethereum/permission/v1"
+	v2 "github.com/ethereum/go-ethereum/permission/v2"
+	binding "github.com/ethereum/go-ethereum/permission/v2/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const networkAdminVoters = "network admin voters"
+
+// actions of the v2 status update functions
+const (
+	suspendAction   = 1
+	blacklistAction = 3
+)
+
+// MigrationArgs are the accounts deploying the v2 permission contracts and the block the v1
+// permissions are read at
+type MigrationArgs struct {
+	Block    *rpc.BlockNumber `json:"block"`    // latest block if nil
+	Deployer common.Address   `json:"deployer"` // account deploying and booting the v2 contracts
+	Nonce    *hexutil.Uint64  `json:"nonce"`    // pending nonce of the deployer if nil
+	Guardian *common.Address  `json:"guardian"` // guardian of the upgradable contract, the deployer if nil
+}
+
+// MigrationStep is a transaction of a migration plan, a contract creation if To is nil. From is
+// nil for the votes, which are sent by the network admin voters until a majority is reached.
+type MigrationStep struct {
+	Description string          `json:"description"`
+	From        *common.Address `json:"from,omitempty"`
+	Signer      string          `json:"signer"`
+	To          *common.Address `json:"to,omitempty"`
+	Data        hexutil.Bytes   `json:"data"`
+	NeedsVotes  bool            `json:"needsVotes"`
+}
+
+func (s *MigrationStep) String() string {
+	to := "create"
+	if s.To != nil {
+		to = "to " + s.To.Hex()
+	}
+	signer := s.Signer
+	if s.From != nil {
+		signer = s.From.Hex()
+	}
+	if s.NeedsVotes {
+		signer += ", needs network admin votes"
+	}
+	return fmt.Sprintf("%s (%s, signed by %s)", s.Description, to, signer)
+}
+
+// MigrationPlan lists in order the transactions deploying the v2 permission contracts and populating
+// them with the v1 permissions. Each transaction must be mined, and each vote approved, before the
+// next one is sent. Contracts holds the addresses to set as migration in permission-config.json, the
+// switch block is set by a v2PermissionMigrated transition of the chain config.
+type MigrationPlan struct {
+	Block     uint64                     `json:"block"`
+	Contracts *ptype.PermissionMigration `json:"contracts"`
+	Steps     []*MigrationStep           `json:"steps"`
+	Warnings  []string                   `json:"warnings,omitempty"`
+}
+
+// MigrationVerification reports the differences between the v1 permissions and the v2 permissions
+// they were migrated to
+type MigrationVerification struct {
+	Block       uint64   `json:"block"`
+	Source      string   `json:"source"` // v2 caches or v2 contracts
+	Matches     bool     `json:"matches"`
+	Differences []string `json:"differences"`
+}
+
+// migratedAccess returns the v2 access type granting the transactions allowed by a v1 access type.
+// v1 Transact also allows contract calls and v1 ContractDeploy allows all transactions.
+func migratedAccess(access core.AccessType) core.AccessType {
+	switch access {
+	case core.Transact:
+		return core.TransactAndContractCall
+	case core.ContractDeploy:
+		return core.FullAccess
+	}
+	return access
+}
+
+// migratedAcctStatus returns the v2 status of an account with the given v1 status
+func migratedAcctStatus(status core.AcctStatus) core.AcctStatus {
+	if status == core.AcctRecoveryCompleted {
+		return core.AcctActive
+	}
+	return status
+}
+
+type migrationPlanner struct {
+	snapshot *permissionSnapshot
+	config   *ptype.PermissionConfig
+	isRaft   bool
+	useDns   bool
+	plan     *MigrationPlan
+
+	interf   common.Address
+	nwAdmin  *common.Address
+	skipped  map[string]bool // orgs which cannot be migrated
+	accounts map[common.Address]bool
+	nodes    map[string]bool
+}
+
+// planMigration computes the transactions deploying the v2 contracts from deployer, starting at
+// nonce, and populating them with the v1 permissions of s
+func planMigration(s *permissionSnapshot, config *ptype.PermissionConfig, deployer, guardian common.Address, nonce uint64, isRaft, useDns bool) (*MigrationPlan, error) {
+	pl := &migrationPlanner{
+		snapshot: s,
+		config:   config,
+		isRaft:   isRaft,
+		useDns:   useDns,
+		plan:     &MigrationPlan{Steps: []*MigrationStep{}},
+		skipped:  make(map[string]bool),
+		accounts: make(map[common.Address]bool),
+		nodes:    make(map[string]bool),
+	}
+	if err := pl.deploy(deployer, guardian, nonce); err != nil {
+		return nil, err
+	}
+	if err := pl.boot(deployer); err != nil {
+		return nil, err
+	}
+	orgs := pl.sortedOrgs()
+	for _, o := range orgs {
+		if err := pl.migrateOrg(o); err != nil {
+			return nil, err
+		}
+	}
+	for _, step := range []func() error{pl.migrateRoles, pl.migrateAccounts, pl.migrateNodes, pl.migrateRemovedRoles} {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range orgs {
+		if err := pl.migrateOrgStatus(o); err != nil {
+			return nil, err
+		}
+	}
+	return pl.plan, nil
+}
+
+func (pl *migrationPlanner) warn(format string, args ...interface{}) {
+	pl.plan.Warnings = append(pl.plan.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (pl *migrationPlanner) add(step *MigrationStep) {
+	pl.plan.Steps = append(pl.plan.Steps, step)
+}
+
+// call adds a call of a PermissionsInterface function
+func (pl *migrationPlanner) call(from *common.Address, signer, description, method string, args ...interface{}) error {
+	data, err := binding.PermInterfaceParsedABI.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %v", method, err)
+	}
+	to := pl.interf
+	pl.add(&MigrationStep{Description: description, From: from, Signer: signer, To: &to, Data: data})
+	return nil
+}
+
+// vote adds a call of a PermissionsInterface function approving a pending operation
+func (pl *migrationPlanner) vote(description, method string, args ...interface{}) error {
+	if err := pl.call(nil, networkAdminVoters, description, method, args...); err != nil {
+		return err
+	}
+	pl.plan.Steps[len(pl.plan.Steps)-1].NeedsVotes = true
+	return nil
+}
+
+// deploy adds the contract creations of the v2 contracts, whose addresses follow from the deployer
+// account and nonce, and the guardian transaction linking them
+func (pl *migrationPlanner) deploy(deployer, guardian common.Address, nonce uint64) error {
+	create := func(name string, parsed abi.ABI, bin string, args ...interface{}) (common.Address, error) {
+		input, err := parsed.Pack("", args...)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("%s: %v", name, err)
+		}
+		from := deployer
+		pl.add(&MigrationStep{Description: "deploy " + name, From: &from, Data: append(common.FromHex(bin), input...)})
+		address := crypto.CreateAddress(deployer, nonce)
+		nonce++
+		return address, nil
+	}
+	c := new(ptype.PermissionMigration)
+	var err error
+	if c.UpgrdAddress, err = create("PermissionsUpgradable", binding.PermUpgrParsedABI, binding.PermUpgrBin, guardian); err != nil {
+		return err
+	}
+	for _, m := range []struct {
+		name    string
+		parsed  abi.ABI
+		bin     string
+		address *common.Address
+	}{
+		{"OrgManager", binding.OrgManagerParsedABI, binding.OrgManagerBin, &c.OrgAddress},
+		{"RoleManager", binding.RoleManagerParsedABI, binding.RoleManagerBin, &c.RoleAddress},
+		{"AccountManager", binding.AcctManagerParsedABI, binding.AcctManagerBin, &c.AccountAddress},
+		{"VoterManager", binding.VoterManagerParsedABI, binding.VoterManagerBin, &c.VoterAddress},
+		{"NodeManager", binding.NodeManagerParsedABI, binding.NodeManagerBin, &c.NodeAddress},
+		{"PermissionsInterface", binding.PermInterfaceParsedABI, binding.PermInterfaceBin, &c.InterfAddress},
+	} {
+		if *m.address, err = create(m.name, m.parsed, m.bin, c.UpgrdAddress); err != nil {
+			return err
+		}
+	}
+	if c.ImplAddress, err = create("PermissionsImplementation", binding.PermImplParsedABI, binding.PermImplBin,
+		c.UpgrdAddress, c.OrgAddress, c.RoleAddress, c.AccountAddress, c.VoterAddress, c.NodeAddress); err != nil {
+		return err
+	}
+	data, err := binding.PermUpgrParsedABI.Pack("init", c.InterfAddress, c.ImplAddress)
+	if err != nil {
+		return err
+	}
+	pl.add(&MigrationStep{Description: "link the interface and implementation contracts", From: &guardian, To: &c.UpgrdAddress, Data: data})
+	pl.plan.Contracts, pl.interf = c, c.InterfAddress
+	return nil
+}
+
+// boot adds the transactions booting the v2 network with the nodes and network admin accounts of the
+// network admin org
+func (pl *migrationPlanner) boot(deployer common.Address) error {
+	cfg := pl.config
+	if err := pl.call(&deployer, "", "set the network admin org and roles", "setPolicy", cfg.NwAdminOrg, cfg.NwAdminRole, cfg.OrgAdminRole); err != nil {
+		return err
+	}
+	if cfg.SubOrgBreadth == nil || cfg.SubOrgDepth == nil {
+		return errors.New("sub org breadth and depth are not configured")
+	}
+	if err := pl.call(&deployer, "", "set the sub org breadth and depth", "init", cfg.SubOrgBreadth, cfg.SubOrgDepth); err != nil {
+		return err
+	}
+	for _, n := range pl.sortedNodes() {
+		if n.OrgId != cfg.NwAdminOrg {
+			continue
+		}
+		enodeId, ip, port, raftPort, err := ptype.GetNodeDetails(n.Url, pl.isRaft, pl.useDns)
+		if err != nil {
+			return err
+		}
+		pl.nodes[n.Url] = true
+		if err := pl.call(&deployer, "", "add admin node "+n.Url, "addAdminNode", enodeId, ip, port, raftPort); err != nil {
+			return err
+		}
+	}
+	for _, a := range pl.sortedAccounts() {
+		if a.OrgId != cfg.NwAdminOrg || a.RoleId != cfg.NwAdminRole || a.Status == core.AcctPendingApproval || a.Status == core.AdminRevoked {
+			continue
+		}
+		account := a.AcctId
+		if pl.nwAdmin == nil && migratedAcctStatus(a.Status) == core.AcctActive {
+			pl.nwAdmin = &account
+		}
+		pl.accounts[account] = true
+		if err := pl.call(&deployer, "", "add network admin account "+account.Hex(), "addAdminAccount", account); err != nil {
+			return err
+		}
+	}
+	if pl.nwAdmin == nil {
+		return fmt.Errorf("org %s has no active network admin account", cfg.NwAdminOrg)
+	}
+	return pl.call(&deployer, "", "complete the network boot", "updateNetworkBootStatus")
+}
+
+func (pl *migrationPlanner) sortedOrgs() []core.OrgInfo {
+	orgs := make([]core.OrgInfo, 0, len(pl.snapshot.orgs))
+	for _, o := range pl.snapshot.orgs {
+		orgs = append(orgs, o)
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		if c := orgs[i].Level.Cmp(orgs[j].Level); c != 0 {
+			return c < 0
+		}
+		return orgs[i].FullOrgId < orgs[j].FullOrgId
+	})
+	return orgs
+}
+
+func (pl *migrationPlanner) sortedAccounts() []core.AccountInfo {
+	accounts := make([]core.AccountInfo, 0, len(pl.snapshot.accounts))
+	for _, a := range pl.snapshot.accounts {
+		accounts = append(accounts, a)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].OrgId != accounts[j].OrgId {
+			return accounts[i].OrgId < accounts[j].OrgId
+		}
+		return accounts[i].AcctId.Hex() < accounts[j].AcctId.Hex()
+	})
+	return accounts
+}
+
+func (pl *migrationPlanner) sortedNodes() []*core.NodeInfo {
+	nodes := append([]*core.NodeInfo(nil), pl.snapshot.nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].OrgId < nodes[j].OrgId })
+	return nodes
+}
+
+func (pl *migrationPlanner) sortedRoles() []core.RoleInfo {
+	roles := make([]core.RoleInfo, 0, len(pl.snapshot.roles))
+	for _, r := range pl.snapshot.roles {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].OrgId != roles[j].OrgId {
+			return roles[i].OrgId < roles[j].OrgId
+		}
+		return roles[i].RoleId < roles[j].RoleId
+	})
+	return roles
+}
+
+// orgAdmin returns the first active org admin account of the ultimate parent of orgId, the v2
+// transactions of the org are signed by it
+func (pl *migrationPlanner) orgAdmin(orgId string) (*common.Address, string) {
+	ultimateParent := orgId
+	if o, ok := pl.snapshot.orgs[orgId]; ok {
+		ultimateParent = o.UltimateParent
+	}
+	for _, a := range pl.sortedAccounts() {
+		if a.OrgId == ultimateParent && a.IsOrgAdmin && migratedAcctStatus(a.Status) == core.AcctActive {
+			account := a.AcctId
+			return &account, ""
+		}
+	}
+	return nil, "admin of org " + ultimateParent
+}
+
+// isSkipped returns true if the org or one of its parents cannot be migrated
+func (pl *migrationPlanner) isSkipped(orgId string) bool {
+	for id := orgId; id != ""; {
+		if pl.skipped[id] {
+			return true
+		}
+		o, ok := pl.snapshot.orgs[id]
+		if !ok {
+			return false
+		}
+		id = o.ParentOrgId
+	}
+	return false
+}
+
+// orgNode returns the first node of the org, the org is created in v2 with it
+func (pl *migrationPlanner) orgNode(orgId string) *core.NodeInfo {
+	for _, n := range pl.sortedNodes() {
+		if n.OrgId == orgId {
+			return n
+		}
+	}
+	return nil
+}
+
+// migrateOrg adds the transactions creating the org with its first node and, for a top level org,
+// its first org admin account
+func (pl *migrationPlanner) migrateOrg(o core.OrgInfo) error {
+	if o.FullOrgId == pl.config.NwAdminOrg {
+		return nil
+	}
+	if pl.isSkipped(o.ParentOrgId) {
+		pl.skipped[o.FullOrgId] = true
+		pl.warn("sub org %s is not migrated as its parent org is not", o.FullOrgId)
+		return nil
+	}
+	enodeId, ip, port, raftPort := "", "", uint16(0), uint16(0)
+	n := pl.orgNode(o.FullOrgId)
+	if n != nil {
+		var err error
+		if enodeId, ip, port, raftPort, err = ptype.GetNodeDetails(n.Url, pl.isRaft, pl.useDns); err != nil {
+			return err
+		}
+		pl.nodes[n.Url] = true
+	}
+	if o.ParentOrgId != "" {
+		from, signer := pl.orgAdmin(o.FullOrgId)
+		return pl.call(from, signer, "add sub org "+o.FullOrgId, "addSubOrg", o.ParentOrgId, o.OrgId, enodeId, ip, port, raftPort)
+	}
+	var admin *core.AccountInfo
+	for _, a := range pl.sortedAccounts() {
+		if a.OrgId == o.FullOrgId && a.RoleId == pl.config.OrgAdminRole && a.Status != core.AdminRevoked {
+			a := a
+			admin = &a
+			break
+		}
+	}
+	if n == nil || admin == nil {
+		pl.skipped[o.FullOrgId] = true
+		pl.warn("org %s is not migrated, it has no node or no %s account to be proposed with", o.FullOrgId, pl.config.OrgAdminRole)
+		return nil
+	}
+	pl.accounts[admin.AcctId] = true
+	if err := pl.call(pl.nwAdmin, "", "propose org "+o.FullOrgId, "addOrg", o.OrgId, enodeId, ip, port, raftPort, admin.AcctId); err != nil {
+		return err
+	}
+	if o.Status == core.OrgPendingApproval {
+		return nil
+	}
+	return pl.vote("approve org "+o.FullOrgId, "approveOrg", o.OrgId, enodeId, ip, port, raftPort, admin.AcctId)
+}
+
+// migrateRoles adds the roles other than the admin roles, which are created with their org
+func (pl *migrationPlanner) migrateRoles() error {
+	for _, r := range pl.sortedRoles() {
+		if r.RoleId == pl.config.NwAdminRole || r.RoleId == pl.config.OrgAdminRole || pl.isSkipped(r.OrgId) {
+			continue
+		}
+		from, signer := pl.orgAdmin(r.OrgId)
+		access := migratedAccess(r.Access)
+		if access != r.Access {
+			pl.warn("role %s of org %s is migrated with access %d allowing the same transactions as v1 access %d", r.RoleId, r.OrgId, access, r.Access)
+		}
+		if err := pl.call(from, signer, fmt.Sprintf("add role %s to org %s", r.RoleId, r.OrgId), "addNewRole",
+			r.RoleId, r.OrgId, big.NewInt(int64(access)), r.IsVoter, r.IsAdmin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateAccounts assigns the roles of the accounts and restores their statuses
+func (pl *migrationPlanner) migrateAccounts() error {
+	var statuses []core.AccountInfo
+	for _, a := range pl.sortedAccounts() {
+		if pl.isSkipped(a.OrgId) {
+			continue
+		}
+		account := a.AcctId
+		switch {
+		case a.Status == core.AdminRevoked:
+			pl.warn("account %s of org %s has its admin role revoked and is not migrated", account.Hex(), a.OrgId)
+			continue
+		case pl.accounts[account]:
+		case a.RoleId == pl.config.NwAdminRole || a.RoleId == pl.config.OrgAdminRole:
+			if err := pl.call(pl.nwAdmin, "", fmt.Sprintf("assign role %s of org %s to %s", a.RoleId, a.OrgId, account.Hex()), "assignAdminRole", a.OrgId, account, a.RoleId); err != nil {
+				return err
+			}
+			if a.Status == core.AcctPendingApproval {
+				continue
+			}
+			if err := pl.vote("approve admin account "+account.Hex(), "approveAdminRole", a.OrgId, account); err != nil {
+				return err
+			}
+		default:
+			from, signer := pl.orgAdmin(a.OrgId)
+			if err := pl.call(from, signer, fmt.Sprintf("assign role %s of org %s to %s", a.RoleId, a.OrgId, account.Hex()), "assignAccountRole", account, a.OrgId, a.RoleId); err != nil {
+				return err
+			}
+		}
+		statuses = append(statuses, a)
+	}
+	// the statuses are updated once the accounts of the org admins are in place
+	for _, a := range statuses {
+		var action int64
+		switch a.Status {
+		case core.AcctSuspended:
+			action = suspendAction
+		case core.AcctBlacklisted, core.AcctRecoveryInitiated:
+			if a.Status == core.AcctRecoveryInitiated {
+				pl.warn("recovery of account %s of org %s must be initiated again", a.AcctId.Hex(), a.OrgId)
+			}
+			action = blacklistAction
+		case core.AcctInactive:
+			pl.warn("account %s of org %s is inactive, it is migrated as active", a.AcctId.Hex(), a.OrgId)
+			continue
+		default:
+			continue
+		}
+		from, signer := pl.orgAdmin(a.OrgId)
+		if err := pl.call(from, signer, fmt.Sprintf("update status of account %s to %d", a.AcctId.Hex(), a.Status), "updateAccountStatus", a.OrgId, a.AcctId, big.NewInt(action)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateNodes adds the nodes not added with their org and restores the statuses of all nodes
+func (pl *migrationPlanner) migrateNodes() error {
+	nodes := pl.sortedNodes()
+	for _, n := range nodes {
+		if pl.nodes[n.Url] || pl.isSkipped(n.OrgId) {
+			continue
+		}
+		enodeId, ip, port, raftPort, err := ptype.GetNodeDetails(n.Url, pl.isRaft, pl.useDns)
+		if err != nil {
+			return err
+		}
+		from, signer := pl.orgAdmin(n.OrgId)
+		if err := pl.call(from, signer, fmt.Sprintf("add node %s to org %s", n.Url, n.OrgId), "addNode", n.OrgId, enodeId, ip, port, raftPort); err != nil {
+			return err
+		}
+	}
+	for _, n := range nodes {
+		var action int64
+		switch n.Status {
+		case core.NodeDeactivated:
+			action = suspendAction
+		case core.NodeBlackListed, core.NodeRecoveryInitiated:
+			if n.Status == core.NodeRecoveryInitiated {
+				pl.warn("recovery of node %s of org %s must be initiated again", n.Url, n.OrgId)
+			}
+			action = blacklistAction
+		default:
+			continue
+		}
+		if pl.isSkipped(n.OrgId) {
+			continue
+		}
+		enodeId, ip, port, raftPort, err := ptype.GetNodeDetails(n.Url, pl.isRaft, pl.useDns)
+		if err != nil {
+			return err
+		}
+		from, signer := pl.orgAdmin(n.OrgId)
+		if err := pl.call(from, signer, fmt.Sprintf("update status of node %s to %d", n.Url, n.Status), "updateNodeStatus", n.OrgId, enodeId, ip, port, raftPort, big.NewInt(action)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateRemovedRoles removes the roles removed in v1, once no account is assigned them
+func (pl *migrationPlanner) migrateRemovedRoles() error {
+	for _, r := range pl.sortedRoles() {
+		if r.Active || r.RoleId == pl.config.NwAdminRole || r.RoleId == pl.config.OrgAdminRole || pl.isSkipped(r.OrgId) {
+			continue
+		}
+		from, signer := pl.orgAdmin(r.OrgId)
+		if err := pl.call(from, signer, fmt.Sprintf("remove role %s of org %s", r.RoleId, r.OrgId), "removeRole", r.RoleId, r.OrgId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateOrgStatus suspends the suspended orgs, last as their changes are rejected once suspended
+func (pl *migrationPlanner) migrateOrgStatus(o core.OrgInfo) error {
+	if (o.Status != core.OrgPendingSuspension && o.Status != core.OrgSuspended) || pl.isSkipped(o.FullOrgId) {
+		return nil
+	}
+	if err := pl.call(pl.nwAdmin, "", "propose the suspension of org "+o.FullOrgId, "updateOrgStatus", o.FullOrgId, big.NewInt(suspendAction)); err != nil {
+		return err
+	}
+	if o.Status == core.OrgPendingSuspension {
+		return nil
+	}
+	return pl.vote("approve the suspension of org "+o.FullOrgId, "approveOrgStatus", o.FullOrgId, big.NewInt(suspendAction))
+}
+
+// verifyMigration returns the differences between the v1 permissions and the v2 permissions they were
+// migrated to, taking into account the mapping of the access types and statuses
+func verifyMigration(from, to *permissionSnapshot) []string {
+	diffs := make([]string, 0)
+	diff := func(format string, args ...interface{}) {
+		diffs = append(diffs, fmt.Sprintf(format, args...))
+	}
+	for id, o := range from.orgs {
+		m, ok := to.orgs[id]
+		switch {
+		case !ok:
+			diff("org %s is missing", id)
+		case m.ParentOrgId != o.ParentOrgId || m.UltimateParent != o.UltimateParent || m.Level.Cmp(o.Level) != 0:
+			diff("org %s has parent %s instead of %s", id, m.ParentOrgId, o.ParentOrgId)
+		case m.Status != o.Status:
+			diff("org %s has status %d instead of %d", id, m.Status, o.Status)
+		}
+	}
+	for id := range to.orgs {
+		if _, ok := from.orgs[id]; !ok {
+			diff("org %s is not in v1", id)
+		}
+	}
+	for key, r := range from.roles {
+		m, ok := to.roles[key]
+		switch {
+		case !ok:
+			diff("role %s of org %s is missing", key.RoleId, key.OrgId)
+		case m.Access != migratedAccess(r.Access):
+			diff("role %s of org %s has access %d instead of %d", key.RoleId, key.OrgId, m.Access, migratedAccess(r.Access))
+		case m.IsVoter != r.IsVoter || m.IsAdmin != r.IsAdmin:
+			diff("role %s of org %s has voter %t and admin %t instead of %t and %t", key.RoleId, key.OrgId, m.IsVoter, m.IsAdmin, r.IsVoter, r.IsAdmin)
+		case m.Active != r.Active:
+			diff("role %s of org %s has active %t instead of %t", key.RoleId, key.OrgId, m.Active, r.Active)
+		}
+	}
+	for key := range to.roles {
+		if _, ok := from.roles[key]; !ok {
+			diff("role %s of org %s is not in v1", key.RoleId, key.OrgId)
+		}
+	}
+	for addr, a := range from.accounts {
+		m, ok := to.accounts[addr]
+		switch {
+		case !ok:
+			diff("account %s is missing", addr.Hex())
+		case m.OrgId != a.OrgId || m.RoleId != a.RoleId:
+			diff("account %s has role %s of org %s instead of %s of org %s", addr.Hex(), m.RoleId, m.OrgId, a.RoleId, a.OrgId)
+		case m.IsOrgAdmin != a.IsOrgAdmin:
+			diff("account %s has org admin %t instead of %t", addr.Hex(), m.IsOrgAdmin, a.IsOrgAdmin)
+		case m.Status != migratedAcctStatus(a.Status):
+			diff("account %s has status %d instead of %d", addr.Hex(), m.Status, migratedAcctStatus(a.Status))
+		}
+	}
+	for addr := range to.accounts {
+		if _, ok := from.accounts[addr]; !ok {
+			diff("account %s is not in v1", addr.Hex())
+		}
+	}
+	// the v2 node urls are rebuilt from the enode id, ip and ports, nodes are matched by id
+	for _, n := range from.nodes {
+		m := to.node(n.Url)
+		switch {
+		case m == nil:
+			diff("node %s is missing", n.Url)
+		case m.OrgId != n.OrgId:
+			diff("node %s belongs to org %s instead of %s", n.Url, m.OrgId, n.OrgId)
+		case m.Status != n.Status:
+			diff("node %s has status %d instead of %d", n.Url, m.Status, n.Status)
+		}
+	}
+	for _, n := range to.nodes {
+		if from.node(n.Url) == nil {
+			diff("node %s is not in v1", n.Url)
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// migrationConfig returns the config of the v1 permissions model, which is kept once the node
+// switched to the v2 model it was migrated to
+func (p *PermissionCtrl) migrationConfig() (*ptype.PermissionConfig, error) {
+	p.modelMu.RLock()
+	defer p.modelMu.RUnlock()
+	if p.v1Config != nil {
+		return p.v1Config, nil
+	}
+	if p.permConfig.PermissionsModel == ptype.PERMISSION_V2 {
+		return nil, ptype.ErrNotV1Model
+	}
+	return p.permConfig, nil
+}
+
+// callBlock returns the block number the contracts are read at, nil for their pending state
+func (p *PermissionCtrl) callBlock(blockNumber rpc.BlockNumber) *big.Int {
+	switch blockNumber {
+	case rpc.PendingBlockNumber:
+		return nil
+	case rpc.LatestBlockNumber:
+		return p.eth.BlockChain().CurrentBlock().Number()
+	}
+	return big.NewInt(blockNumber.Int64())
+}
+
+// migrationPlan returns the transactions deploying the v2 contracts and populating them with the v1
+// permissions at the block of args
+func (p *PermissionCtrl) migrationPlan(args MigrationArgs) (*MigrationPlan, error) {
+	config, err := p.migrationConfig()
+	if err != nil {
+		return nil, err
+	}
+	blockNumber := rpc.LatestBlockNumber
+	if args.Block != nil && *args.Block != rpc.PendingBlockNumber {
+		blockNumber = *args.Block
+	}
+	block := p.callBlock(blockNumber)
+	s, err := readContractSnapshot(NewPermissionContractService(p.ethClnt, false, p.key, config, p.isRaft, p.useDns, p.chainID), block)
+	if err != nil {
+		return nil, err
+	}
+	guardian := args.Deployer
+	if args.Guardian != nil {
+		guardian = *args.Guardian
+	}
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	} else if nonce, err = p.ethClnt.PendingNonceAt(context.Background(), args.Deployer); err != nil {
+		return nil, err
+	}
+	plan, err := planMigration(s, config, args.Deployer, guardian, nonce, p.isRaft, p.useDns)
+	if err != nil {
+		return nil, err
+	}
+	plan.Block = block.Uint64()
+	return plan, nil
+}
+
+// verifyMigration compares the v1 permissions at blockNumber with the v2 caches if the node switched
+// to the v2 model, with the v2 contracts at the same block otherwise
+func (p *PermissionCtrl) verifyMigration(blockNumber *rpc.BlockNumber) (*MigrationVerification, error) {
+	config, err := p.migrationConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.Migration == nil {
+		return nil, ptype.ErrNoMigration
+	}
+	number := rpc.LatestBlockNumber
+	if blockNumber != nil && *blockNumber != rpc.PendingBlockNumber {
+		number = *blockNumber
+	}
+	block := p.callBlock(number)
+	s1, err := readContractSnapshot(NewPermissionContractService(p.ethClnt, false, p.key, config, p.isRaft, p.useDns, p.chainID), block)
+	if err != nil {
+		return nil, err
+	}
+	result := &MigrationVerification{Block: block.Uint64(), Source: "v2 caches"}
+	var s2 *permissionSnapshot
+	if p.IsV2Permission() && core.CachesComplete() {
+		s2 = cacheSnapshot()
+	} else {
+		result.Source = "v2 contracts"
+		if s2, err = readContractSnapshot(NewPermissionContractService(p.ethClnt, true, p.key, config.V2Config(), p.isRaft, p.useDns, p.chainID), block); err != nil {
+			return nil, err
+		}
+	}
+	result.Differences = verifyMigration(s1, s2)
+	result.Matches = len(result.Differences) == 0
+	return result, nil
+}
+
+// switchToV2 switches the node from the v1 permissions model to the v2 contracts it was migrated to.
+// The v2 contracts must be booted. The caches are populated from the v2 contracts before the v1 event
+// watchers are stopped, then the caches and the model in use are replaced together.
+func (p *PermissionCtrl) switchToV2() error {
+	v1Config := p.config()
+	config := v1Config.V2Config()
+	contract := NewPermissionContractService(p.ethClnt, true, p.key, config, p.isRaft, p.useDns, p.chainID)
+	if err := contract.BindContracts(); err != nil {
+		return err
+	}
+	booted, err := contract.GetNetworkBootStatus()
+	if err != nil {
+		return err
+	}
+	if !booted {
+		return errors.New("the v2 permission contracts are not booted")
+	}
+	caches := p.instantiateCache(params.DEFAULT_ORGCACHE_SIZE, params.DEFAULT_ROLECACHE_SIZE,
+		params.DEFAULT_NODECACHE_SIZE, params.DEFAULT_ACCOUNTCACHE_SIZE)
+	if err := p.populateFromContract(contract, caches); err != nil {
+		return fmt.Errorf("failed to populate the v2 permissions: %v", err)
+	}
+	v1Backend := p.backend.(*v1.Backend)
+	backend := &v2.Backend{Ib: v1Backend.Ib, Contr: contract.(*v2.Init)}
+	v1Backend.Stop()
+
+	p.modelMu.Lock()
+	p.v1Config = v1Config
+	p.permConfig, p.contract, p.backend = config, contract, backend
+	core.SetCaches(caches)
+	core.SetDefaults(config.NwAdminRole, config.OrgAdminRole, true)
+	p.modelMu.Unlock()
+
+	for _, f := range []func() error{
+		p.checkCallPermissionsTransition,
+		backend.ManageOrgPermissions,
+		backend.ManageNodePermissions,
+		backend.ManageRolePermissions,
+		backend.ManageAccountPermissions,
+		backend.ManageCallPermissions,
+	} {
+		if err := f(); err != nil {
+			log.Error("Failed to monitor the v2 permission contracts", "err", err)
+		}
+	}
+	log.Info("permission service: switched to the v2 permissions model", "block", p.migrationBlock())
+	return nil
+}
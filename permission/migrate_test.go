@@ -0,0 +1,229 @@
+package permission
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func typicalMigrationSnapshot() *permissionSnapshot {
+	s := typicalPermissionSnapshot()
+	s.roles[pcore.RoleKey{OrgId: arbitraryNetworkAdminOrg, RoleId: arbitraryNetworkAdminRole}] = pcore.RoleInfo{OrgId: arbitraryNetworkAdminOrg, RoleId: arbitraryNetworkAdminRole, Access: pcore.FullAccess, IsVoter: true, IsAdmin: true, Active: true}
+	s.accounts[common.HexToAddress("0x9")] = pcore.AccountInfo{OrgId: arbitraryNetworkAdminOrg, RoleId: arbitraryNetworkAdminRole, AcctId: common.HexToAddress("0x9"), IsOrgAdmin: true, Status: pcore.AcctActive}
+	s.nodes = append(s.nodes, &pcore.NodeInfo{OrgId: arbitraryNetworkAdminOrg, Url: arbitraryNode2, Status: pcore.NodeApproved})
+	return s
+}
+
+func TestPlanMigration(t *testing.T) {
+	config := &ptype.PermissionConfig{NwAdminOrg: arbitraryNetworkAdminOrg, NwAdminRole: arbitraryNetworkAdminRole, OrgAdminRole: arbitraryOrgAdminRole, SubOrgBreadth: big.NewInt(4), SubOrgDepth: big.NewInt(4)}
+	s := typicalMigrationSnapshot()
+	s.orgs["ORG2"] = pcore.OrgInfo{OrgId: "ORG2", FullOrgId: "ORG2", UltimateParent: "ORG2", Level: big.NewInt(1), Status: pcore.OrgApproved}
+	s.orgs["ORG2."+arbitrarySubOrg] = pcore.OrgInfo{OrgId: arbitrarySubOrg, FullOrgId: "ORG2." + arbitrarySubOrg, ParentOrgId: "ORG2", UltimateParent: "ORG2", Level: big.NewInt(2), Status: pcore.OrgApproved}
+	s.accounts[common.HexToAddress("0x2")] = pcore.AccountInfo{OrgId: arbitraryOrgToAdd, RoleId: arbitrartNewRole1, AcctId: common.HexToAddress("0x2"), Status: pcore.AcctSuspended}
+	deployer, guardian := common.HexToAddress("0xd"), common.HexToAddress("0xe")
+
+	plan, err := planMigration(s, config, deployer, guardian, 5, false, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.CreateAddress(deployer, 5), plan.Contracts.UpgrdAddress)
+	assert.Equal(t, crypto.CreateAddress(deployer, 11), plan.Contracts.InterfAddress)
+	assert.Equal(t, crypto.CreateAddress(deployer, 12), plan.Contracts.ImplAddress)
+	var descriptions []string
+	for _, step := range plan.Steps {
+		descriptions = append(descriptions, step.Description)
+	}
+	assert.Equal(t, []string{
+		"deploy PermissionsUpgradable",
+		"deploy OrgManager",
+		"deploy RoleManager",
+		"deploy AccountManager",
+		"deploy VoterManager",
+		"deploy NodeManager",
+		"deploy PermissionsInterface",
+		"deploy PermissionsImplementation",
+		"link the interface and implementation contracts",
+		"set the network admin org and roles",
+		"set the sub org breadth and depth",
+		"add admin node " + arbitraryNode2,
+		"add network admin account 0x0000000000000000000000000000000000000009",
+		"complete the network boot",
+		"propose org " + arbitraryOrgToAdd,
+		"approve org " + arbitraryOrgToAdd,
+		"add role " + arbitrartNewRole1 + " to org " + arbitraryOrgToAdd,
+		"assign role " + arbitrartNewRole1 + " of org " + arbitraryOrgToAdd + " to 0x0000000000000000000000000000000000000002",
+		"update status of account 0x0000000000000000000000000000000000000002 to 4",
+	}, descriptions)
+	assert.Nil(t, plan.Steps[0].To)
+	assert.Equal(t, &guardian, plan.Steps[8].From)
+	assert.Equal(t, &plan.Contracts.InterfAddress, plan.Steps[9].To)
+	assert.Equal(t, common.HexToAddress("0x9"), *plan.Steps[14].From)
+	assert.Nil(t, plan.Steps[15].From)
+	assert.True(t, plan.Steps[15].NeedsVotes)
+	assert.Equal(t, common.HexToAddress("0x1"), *plan.Steps[16].From)
+	assert.Equal(t, []string{
+		"org ORG2 is not migrated, it has no node or no ORG_ADMIN_ROLE account to be proposed with",
+		"sub org ORG2.SUB1 is not migrated as its parent org is not",
+		"role NEW_ROLE_1 of org ORG1 is migrated with access 5 allowing the same transactions as v1 access 1",
+	}, plan.Warnings)
+
+	delete(s.accounts, common.HexToAddress("0x9"))
+	_, err = planMigration(s, config, deployer, guardian, 5, false, false)
+
+	assert.EqualError(t, err, "org NETWORK_ADMIN has no active network admin account")
+}
+
+func TestVerifyMigration(t *testing.T) {
+	from, to := typicalMigrationSnapshot(), typicalMigrationSnapshot()
+	for key, r := range to.roles {
+		r.Access = migratedAccess(r.Access)
+		to.roles[key] = r
+	}
+	to.nodes[0] = &pcore.NodeInfo{OrgId: arbitraryOrgToAdd, Url: "enode://ac6b1096ca56b9f6d004b779ae3728bf83f8e22453404cc3cef16a3d9b96608bc67c4b30db88e0a5a6c6390213f7acbe1153ff6d23ce57380104288ae19373ef@127.0.0.1:21000?discport=0&raftport=50401", Status: pcore.NodeApproved}
+
+	assert.Empty(t, verifyMigration(from, to))
+
+	delete(to.orgs, arbitraryOrgToAdd)
+	to.accounts[common.HexToAddress("0x2")] = pcore.AccountInfo{OrgId: arbitraryOrgToAdd, RoleId: arbitrartNewRole1, AcctId: common.HexToAddress("0x2"), Status: pcore.AcctSuspended}
+	to.nodes[1].Status = pcore.NodeDeactivated
+
+	assert.Equal(t, []string{
+		"account 0x0000000000000000000000000000000000000002 has status 4 instead of 2",
+		"node " + arbitraryNode2 + " has status 3 instead of 2",
+		"org ORG1 is missing",
+	}, verifyMigration(from, to))
+}
+
+// sendMigrationSteps sends the steps of plan from the guardian account, the only network admin voter
+func sendMigrationSteps(t *testing.T, plan *MigrationPlan) {
+	signer := types.NewEIP155Signer(ethereum.BlockChain().Config().ChainID)
+	for i, step := range plan.Steps {
+		nonce, err := contrBackend.PendingNonceAt(context.Background(), guardianAddress)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var tx *types.Transaction
+		if step.To == nil {
+			tx = types.NewContractCreation(nonce, big.NewInt(0), 10000000, big.NewInt(0), step.Data)
+		} else {
+			tx = types.NewTransaction(nonce, *step.To, big.NewInt(0), 10000000, big.NewInt(0), step.Data)
+		}
+		tx, _ = types.SignTx(tx, signer, guardianKey)
+		if !assert.NoError(t, contrBackend.SendTransaction(context.Background(), tx, bind.PrivateTxArgs{}), step.Description) {
+			return
+		}
+		contrBackend.(*backends.SimulatedBackend).Commit()
+		receipt, err := contrBackend.(*backends.SimulatedBackend).TransactionReceipt(context.Background(), tx.Hash())
+		if !assert.NoError(t, err) || !assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "step %d: %s", i, step.Description) {
+			return
+		}
+	}
+}
+
+func TestQuorumControlsAPI_MigrationPlan(t *testing.T) {
+	testObject := typicalQuorumControlsAPI(t)
+	txa := ethapi.SendTxArgs{From: guardianAddress}
+	orgAdmin := getArbitraryAccount()
+	_, err := testObject.AddOrg(arbitraryOrgToAdd, arbitraryNode1, orgAdmin, txa)
+	assert.NoError(t, err)
+	contrBackend.(*backends.SimulatedBackend).Commit()
+	_, err = testObject.ApproveOrg(arbitraryOrgToAdd, arbitraryNode1, orgAdmin, txa)
+	assert.NoError(t, err)
+	contrBackend.(*backends.SimulatedBackend).Commit()
+
+	_, err = testObject.VerifyMigration(nil)
+
+	assert.Equal(t, ptype.ErrNoMigration, err)
+
+	plan, err := testObject.MigrationPlan(MigrationArgs{Deployer: guardianAddress})
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, ethereum.BlockChain().CurrentBlock().NumberU64(), plan.Block)
+	// the org admin of the new org is not an account of the node, it does not sign any step
+	for _, step := range plan.Steps {
+		assert.NotEqual(t, &orgAdmin, step.From)
+	}
+
+	sendMigrationSteps(t, plan)
+	migration := *plan.Contracts
+	testObject.permCtrl.permConfig.Migration = &migration
+	latest := rpc.LatestBlockNumber
+
+	result, err := testObject.VerifyMigration(&latest)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "v2 contracts", result.Source)
+		assert.Empty(t, result.Differences)
+		assert.True(t, result.Matches)
+	}
+
+	nonce := hexutil.Uint64(0)
+	again, err := testObject.MigrationPlan(MigrationArgs{Deployer: guardianAddress, Nonce: &nonce})
+
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.CreateAddress(guardianAddress, 0), again.Contracts.UpgrdAddress)
+}
+
+func TestPermissionCtrl_SwitchToV2(t *testing.T) {
+	testObject := typicalQuorumControlsAPI(t)
+	pc := testObject.permCtrl
+	defer pcore.SetDefaults(arbitraryNetworkAdminRole, arbitraryOrgAdminRole, false)
+	// mine the v1 contract deployments so they are part of the latest block
+	contrBackend.(*backends.SimulatedBackend).Commit()
+	plan, err := testObject.MigrationPlan(MigrationArgs{Deployer: guardianAddress})
+	if !assert.NoError(t, err) {
+		return
+	}
+	migration := *plan.Contracts
+	pc.permConfig.Migration = &migration
+	chainConfig := ethereum.BlockChain().Config()
+	defer func(transitions []params.Transition) {
+		chainConfig.Transitions = transitions
+	}(chainConfig.Transitions)
+	migrationBlock := big.NewInt(int64(plan.Block) + 1)
+	migrated := true
+	chainConfig.Transitions = append(chainConfig.Transitions, params.Transition{Block: migrationBlock, V2PermissionMigrated: &migrated})
+
+	// the v2 contracts are not deployed yet
+	assert.Error(t, pc.switchToV2())
+	assert.False(t, pc.IsV2Permission())
+
+	sendMigrationSteps(t, plan)
+
+	assert.True(t, pc.isMigratedAfter(ethereum.BlockChain().CurrentBlock().Number()))
+	assert.NoError(t, pc.switchToV2())
+	assert.True(t, pc.IsV2Permission())
+	assert.True(t, pcore.IsV2Permission())
+	assert.Equal(t, plan.Contracts.InterfAddress, pc.permConfig.InterfAddress)
+	assert.Nil(t, pc.permConfig.Migration)
+
+	result, err := testObject.VerifyMigration(nil)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "v2 caches", result.Source)
+		assert.Empty(t, result.Differences)
+	}
+	orgDetails, err := testObject.GetOrgDetails(arbitraryNetworkAdminOrg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, guardianAddress, orgDetails.AcctList[0].AcctId)
+	}
+	// the events of the v1 contracts before the migration block remain in the history
+	history, err := testObject.History(PermissionHistoryArgs{OrgId: arbitraryNetworkAdminOrg})
+	if assert.NoError(t, err) && assert.NotEmpty(t, history) {
+		assert.True(t, history[0].BlockNumber < migrationBlock.Uint64())
+	}
+}
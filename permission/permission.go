@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/eth"
@@ -15,6 +16,8 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	pcore "github.com/ethereum/go-ethereum/permission/core"
 	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	v1 "github.com/ethereum/go-ethereum/permission/v1"
+	v2 "github.com/ethereum/go-ethereum/permission/v2"
 )
 
 // This is to make sure all contract instances are ready and initialized
@@ -26,7 +29,7 @@ func (p *PermissionCtrl) AfterStart() error {
 	if err != nil {
 		return err
 	}
-	if err = p.contract.BindContracts(); err != nil {
+	if err = p.contractService().BindContracts(); err != nil {
 		return fmt.Errorf("populateInitPermissions failed to bind contracts: %v", err)
 	}
 
@@ -41,13 +44,17 @@ func (p *PermissionCtrl) AfterStart() error {
 	setPermissionService(p)
 
 	// set the default access to ReadOnly
-	pcore.SetDefaults(p.permConfig.NwAdminRole, p.permConfig.OrgAdminRole, p.IsV2Permission())
+	config := p.config()
+	pcore.SetDefaults(config.NwAdminRole, config.OrgAdminRole, config.PermissionsModel == ptype.PERMISSION_V2)
 	for _, f := range []func() error{
 		p.monitorQIP714Block,               // monitor block number to activate new permissions controls
+		p.checkCallPermissionsTransition,   // check a transition enforces the role call permissions
+		p.monitorPermissionMigration,       // monitor block number to switch to the migrated v2 permissions model
 		p.backend.ManageOrgPermissions,     // monitor org management related events
 		p.backend.ManageNodePermissions,    // monitor org  level Node management events
 		p.backend.ManageRolePermissions,    // monitor org level role management events
 		p.backend.ManageAccountPermissions, // monitor org level account management events
+		p.backend.ManageCallPermissions,    // monitor role call permission events
 	} {
 		if err := f(); err != nil {
 			return err
@@ -106,6 +113,14 @@ func (p *PermissionCtrl) asyncStart() {
 	p.ethClnt = ethclient.NewClient(client)
 	p.eth = ethereum
 	p.isRaft = p.eth.BlockChain().Config().Istanbul == nil && p.eth.BlockChain().Config().IBFT == nil && p.eth.BlockChain().Config().QBFT == nil && p.eth.BlockChain().Config().Clique == nil
+	if p.permConfig.Migration != nil && p.isMigratedAfter(p.eth.BlockChain().CurrentBlock().Number()) {
+		// the migration block is reached, start with the v2 contracts the v1 model was migrated to
+		log.Info("permission service: v1 permissions model migrated to v2", "block", p.migrationBlock())
+		p.modelMu.Lock()
+		p.v1Config, p.permConfig = p.permConfig, p.permConfig.V2Config()
+		p.backend = &v2.Backend{Ib: p.backend.(*v1.Backend).Ib}
+		p.modelMu.Unlock()
+	}
 	p.updateBackEnd()
 }
 
@@ -139,27 +154,106 @@ func (p *PermissionCtrl) monitorQIP714Block() error {
 	return nil
 }
 
-func (p *PermissionCtrl) instantiateCache(orgCacheSize, roleCacheSize, nodeCacheSize, accountCacheSize int) {
-	// instantiate the cache objects for permissions
-	pcore.OrgInfoMap = pcore.NewOrgCache(orgCacheSize)
-	pcore.OrgInfoMap.PopulateCacheFunc(p.populateOrgToCache)
+// warns when a call permission manager is configured but no transition enforces the call
+// permissions. Call permissions are only supported by the v2 permissions model, they are enforced
+// for the blocks the chain config enables them at (see params.ChainConfig.IsCallPermissionsEnabled).
+func (p *PermissionCtrl) checkCallPermissionsTransition() error {
+	if config := p.config(); config.PermissionsModel != ptype.PERMISSION_V2 || config.CallPermAddress == (common.Address{}) {
+		return nil
+	}
+	for _, transition := range p.eth.BlockChain().Config().Transitions {
+		if transition.CallPermissionsEnabled != nil {
+			return nil
+		}
+	}
+	log.Warn("Call permission manager is configured but no transition enables the call permissions")
+	return nil
+}
 
-	pcore.RoleInfoMap = pcore.NewRoleCache(roleCacheSize)
-	pcore.RoleInfoMap.PopulateCacheFunc(p.populateRoleToCache)
+// isCallPermissionsEnabled returns true if the call permissions are enforced for the transactions
+// included in the next block
+func (p *PermissionCtrl) isCallPermissionsEnabled() bool {
+	next := new(big.Int).Add(p.eth.BlockChain().CurrentBlock().Number(), common.Big1)
+	return p.eth.BlockChain().Config().IsCallPermissionsEnabled(next)
+}
+
+// migrationBlock returns the block the chain config migrates the v1 permissions model to v2 at, nil
+// if no transition migrates it
+func (p *PermissionCtrl) migrationBlock() *big.Int {
+	return p.eth.BlockChain().Config().V2PermissionMigrationBlock()
+}
 
-	pcore.NodeInfoMap = pcore.NewNodeCache(nodeCacheSize)
-	pcore.NodeInfoMap.PopulateCacheFunc(p.populateNodeCache)
-	pcore.NodeInfoMap.PopulateValidateFunc(p.populateNodeCacheAndValidate)
+// isMigratedAfter returns true if the block after num is validated against the v2 permissions model
+// the v1 model is migrated to
+func (p *PermissionCtrl) isMigratedAfter(num *big.Int) bool {
+	next := new(big.Int).Add(num, common.Big1)
+	return p.eth.BlockChain().Config().IsV2PermissionMigrated(next)
+}
+
+// monitors the migration block of the v1 permissions model and switches the node to the v2 model
+// before the block is validated. The switch is retried on the next blocks if the v2 contracts are
+// not ready.
+func (p *PermissionCtrl) monitorPermissionMigration() error {
+	config := p.config()
+	if config.PermissionsModel == ptype.PERMISSION_V2 {
+		return nil
+	}
+	switch block := p.migrationBlock(); {
+	case block == nil && config.Migration != nil:
+		log.Warn("Migration contracts are configured but no transition migrates the permissions model to v2")
+		return nil
+	case block == nil:
+		return nil
+	case config.Migration == nil:
+		return fmt.Errorf("the chain config migrates the permissions model to v2 at block %v but %s has no migration contracts", block, params.PERMISSION_MODEL_CONFIG)
+	}
+	go func() {
+		chainHeadCh := make(chan core.ChainHeadEvent, 1)
+		headSub := p.eth.BlockChain().SubscribeChainHeadEvent(chainHeadCh)
+		defer headSub.Unsubscribe()
+		stopChan, stopSubscription := ptype.SubscribeStopEvent()
+		defer stopSubscription.Unsubscribe()
+		for {
+			select {
+			case head := <-chainHeadCh:
+				if !p.isMigratedAfter(head.Block.Number()) {
+					continue
+				}
+				if err := p.switchToV2(); err != nil {
+					log.Error("Failed to switch to the v2 permissions model", "block", head.Block.Number(), "err", err)
+					continue
+				}
+				return
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+	return nil
+}
 
-	pcore.AcctInfoMap = pcore.NewAcctCache(accountCacheSize)
-	pcore.AcctInfoMap.PopulateCacheFunc(p.populateAccountToCache)
+func (p *PermissionCtrl) instantiateCache(orgCacheSize, roleCacheSize, nodeCacheSize, accountCacheSize int) *pcore.PermissionCaches {
+	// instantiate the cache objects for permissions
+	caches := &pcore.PermissionCaches{
+		Orgs:            pcore.NewOrgCache(orgCacheSize),
+		Roles:           pcore.NewRoleCache(roleCacheSize),
+		Nodes:           pcore.NewNodeCache(nodeCacheSize),
+		Accounts:        pcore.NewAcctCache(accountCacheSize),
+		CallPermissions: pcore.NewCallPermissionCache(),
+	}
+	caches.Orgs.PopulateCacheFunc(p.populateOrgToCache)
+	caches.Roles.PopulateCacheFunc(p.populateRoleToCache)
+	caches.Nodes.PopulateCacheFunc(p.populateNodeCache)
+	caches.Nodes.PopulateValidateFunc(p.populateNodeCacheAndValidate)
+	caches.Accounts.PopulateCacheFunc(p.populateAccountToCache)
+	return caches
 }
 
 // Thus function checks if the initial network boot up status and if no
 // populates permissions model with details from permission-config.json
 func (p *PermissionCtrl) populateInitPermissions(orgCacheSize, roleCacheSize, nodeCacheSize, accountCacheSize int) error {
-	p.instantiateCache(orgCacheSize, roleCacheSize, nodeCacheSize, accountCacheSize)
-	networkInitialized, err := p.contract.GetNetworkBootStatus()
+	caches := p.instantiateCache(orgCacheSize, roleCacheSize, nodeCacheSize, accountCacheSize)
+	networkInitialized, err := p.contractService().GetNetworkBootStatus()
 	if err != nil {
 		// handle the scenario of no contract code.
 		log.Warn("Failed to retrieve network boot status ", "err", err)
@@ -168,45 +262,57 @@ func (p *PermissionCtrl) populateInitPermissions(orgCacheSize, roleCacheSize, no
 
 	if !networkInitialized {
 		p.backend.MonitorNetworkBootUp()
-		if err := p.bootupNetwork(); err != nil {
+		if err := p.bootupNetwork(caches); err != nil {
 			return err
 		}
+		pcore.SetCaches(caches)
 	} else {
-		//populate orgs, nodes, roles and accounts from contract
-		for _, f := range []func() error{
-			p.populateOrgsFromContract,
-			p.populateNodesFromContract,
-			p.populateRolesFromContract,
-			p.populateAccountsFromContract,
-		} {
-			if err := f(); err != nil {
-				return err
-			}
+		if err := p.populateFromContract(p.contractService(), caches); err != nil {
+			return err
 		}
+		pcore.SetCaches(caches)
 		pcore.SetNetworkBootUpCompleted()
 	}
 	return nil
 }
 
+// populates the caches with the orgs, nodes, roles, accounts and call permissions of the contracts
+func (p *PermissionCtrl) populateFromContract(contract ptype.InitService, caches *pcore.PermissionCaches) error {
+	if err := p.populateOrgsFromContract(contract, caches.Orgs); err != nil {
+		return err
+	}
+	if err := p.populateNodesFromContract(contract, caches.Nodes); err != nil {
+		return err
+	}
+	if err := p.populateRolesFromContract(contract, caches.Roles); err != nil {
+		return err
+	}
+	if err := p.populateAccountsFromContract(contract, caches.Accounts); err != nil {
+		return err
+	}
+	return p.populateCallPermissionsFromContract(contract, caches.CallPermissions)
+}
+
 // initialize the permissions model and populate initial values
-func (p *PermissionCtrl) bootupNetwork() error {
-	if _, err := p.contract.SetPolicy(p.permConfig.NwAdminOrg, p.permConfig.NwAdminRole, p.permConfig.OrgAdminRole); err != nil {
+func (p *PermissionCtrl) bootupNetwork(caches *pcore.PermissionCaches) error {
+	config := p.config()
+	if _, err := p.contractService().SetPolicy(config.NwAdminOrg, config.NwAdminRole, config.OrgAdminRole); err != nil {
 		log.Error("bootupNetwork SetPolicy failed", "err", err)
 		return err
 	}
-	if _, err := p.contract.Init(p.permConfig.SubOrgBreadth, p.permConfig.SubOrgDepth); err != nil {
+	if _, err := p.contractService().Init(config.SubOrgBreadth, config.SubOrgDepth); err != nil {
 		log.Error("bootupNetwork init failed", "err", err)
 		return err
 	}
 
-	pcore.OrgInfoMap.UpsertOrg(p.permConfig.NwAdminOrg, "", p.permConfig.NwAdminOrg, big.NewInt(1), pcore.OrgApproved)
-	pcore.RoleInfoMap.UpsertRole(p.permConfig.NwAdminOrg, p.permConfig.NwAdminRole, true, true, pcore.FullAccess, true)
+	caches.Orgs.UpsertOrg(config.NwAdminOrg, "", config.NwAdminOrg, big.NewInt(1), pcore.OrgApproved)
+	caches.Roles.UpsertRole(config.NwAdminOrg, config.NwAdminRole, true, true, pcore.FullAccess, true)
 	// populate the initial Node list from static-nodes.json
-	if err := p.populateStaticNodesToContract(); err != nil {
+	if err := p.populateStaticNodesToContract(caches.Nodes); err != nil {
 		return err
 	}
 	// populate initial account access to full access
-	if err := p.populateInitAccountAccess(); err != nil {
+	if err := p.populateInitAccountAccess(caches.Accounts); err != nil {
 		return err
 	}
 
@@ -219,12 +325,12 @@ func (p *PermissionCtrl) bootupNetwork() error {
 }
 
 // populates the account access details from contract into cache
-func (p *PermissionCtrl) populateAccountsFromContract() error {
-	if numberOfRoles, err := p.contract.GetNumberOfAccounts(); err == nil {
+func (p *PermissionCtrl) populateAccountsFromContract(contract ptype.InitService, cache *pcore.AcctCache) error {
+	if numberOfRoles, err := contract.GetNumberOfAccounts(); err == nil {
 		iOrgNum := numberOfRoles.Uint64()
 		for k := uint64(0); k < iOrgNum; k++ {
-			if addr, org, role, status, orgAdmin, err := p.contract.GetAccountDetailsFromIndex(big.NewInt(int64(k))); err == nil {
-				pcore.AcctInfoMap.UpsertAccount(org, role, addr, orgAdmin, pcore.AcctStatus(int(status.Int64())))
+			if addr, org, role, status, orgAdmin, err := contract.GetAccountDetailsFromIndex(big.NewInt(int64(k))); err == nil {
+				cache.UpsertAccount(org, role, addr, orgAdmin, pcore.AcctStatus(int(status.Int64())))
 			}
 		}
 	} else {
@@ -234,12 +340,12 @@ func (p *PermissionCtrl) populateAccountsFromContract() error {
 }
 
 // populates the role details from contract into cache
-func (p *PermissionCtrl) populateRolesFromContract() error {
-	if numberOfRoles, err := p.contract.GetNumberOfRoles(); err == nil {
+func (p *PermissionCtrl) populateRolesFromContract(contract ptype.InitService, cache *pcore.RoleCache) error {
+	if numberOfRoles, err := contract.GetNumberOfRoles(); err == nil {
 		iOrgNum := numberOfRoles.Uint64()
 		for k := uint64(0); k < iOrgNum; k++ {
-			if roleStruct, err := p.contract.GetRoleDetailsFromIndex(big.NewInt(int64(k))); err == nil {
-				pcore.RoleInfoMap.UpsertRole(roleStruct.OrgId, roleStruct.RoleId, roleStruct.Voter, roleStruct.Admin, pcore.AccessType(int(roleStruct.AccessType.Int64())), roleStruct.Active)
+			if roleStruct, err := contract.GetRoleDetailsFromIndex(big.NewInt(int64(k))); err == nil {
+				cache.UpsertRole(roleStruct.OrgId, roleStruct.RoleId, roleStruct.Voter, roleStruct.Admin, pcore.AccessType(int(roleStruct.AccessType.Int64())), roleStruct.Active)
 			}
 		}
 	} else {
@@ -249,12 +355,12 @@ func (p *PermissionCtrl) populateRolesFromContract() error {
 }
 
 // populates the Node details from contract into cache
-func (p *PermissionCtrl) populateNodesFromContract() error {
-	if numberOfNodes, err := p.contract.GetNumberOfNodes(); err == nil {
+func (p *PermissionCtrl) populateNodesFromContract(contract ptype.InitService, cache *pcore.NodeCache) error {
+	if numberOfNodes, err := contract.GetNumberOfNodes(); err == nil {
 		iOrgNum := numberOfNodes.Uint64()
 		for k := uint64(0); k < iOrgNum; k++ {
-			if orgId, url, status, err := p.contract.GetNodeDetailsFromIndex(big.NewInt(int64(k))); err == nil {
-				pcore.NodeInfoMap.UpsertNode(orgId, url, pcore.NodeStatus(int(status.Int64())))
+			if orgId, url, status, err := contract.GetNodeDetailsFromIndex(big.NewInt(int64(k))); err == nil {
+				cache.UpsertNode(orgId, url, pcore.NodeStatus(int(status.Int64())))
 			}
 		}
 	} else {
@@ -264,12 +370,32 @@ func (p *PermissionCtrl) populateNodesFromContract() error {
 }
 
 // populates the org details from contract into cache
-func (p *PermissionCtrl) populateOrgsFromContract() error {
-	if numberOfOrgs, err := p.contract.GetNumberOfOrgs(); err == nil {
+func (p *PermissionCtrl) populateOrgsFromContract(contract ptype.InitService, cache *pcore.OrgCache) error {
+	if numberOfOrgs, err := contract.GetNumberOfOrgs(); err == nil {
 		iOrgNum := numberOfOrgs.Uint64()
 		for k := uint64(0); k < iOrgNum; k++ {
-			if orgId, porgId, ultParent, level, status, err := p.contract.GetOrgInfo(big.NewInt(int64(k))); err == nil {
-				pcore.OrgInfoMap.UpsertOrg(orgId, porgId, ultParent, level, pcore.OrgStatus(int(status.Int64())))
+			if orgId, porgId, ultParent, level, status, err := contract.GetOrgInfo(big.NewInt(int64(k))); err == nil {
+				cache.UpsertOrg(orgId, porgId, ultParent, level, pcore.OrgStatus(int(status.Int64())))
+			}
+		}
+	} else {
+		return err
+	}
+	return nil
+}
+
+// populates the call permission details from the v2 contracts into cache
+func (p *PermissionCtrl) populateCallPermissionsFromContract(contract ptype.InitService, cache *pcore.CallPermissionCache) error {
+	c, ok := contract.(*v2.Init)
+	if !ok || c.PermCall == nil {
+		return nil
+	}
+	opts := &bind.CallOpts{Pending: true}
+	if numberOfCallPermissions, err := c.PermCall.GetNumberOfCallPermissions(opts); err == nil {
+		iCallPermNum := numberOfCallPermissions.Uint64()
+		for k := uint64(0); k < iCallPermNum; k++ {
+			if callPerm, err := c.PermCall.GetCallPermissionFromIndex(opts, big.NewInt(int64(k))); err == nil {
+				cache.UpsertCallPermission(callPerm.OrgId, callPerm.RoleId, callPerm.ContractAddress, callPerm.Selector, callPerm.Active)
 			}
 		}
 	} else {
@@ -279,37 +405,39 @@ func (p *PermissionCtrl) populateOrgsFromContract() error {
 }
 
 // Reads the node list from static-nodes.json and populates into the contract
-func (p *PermissionCtrl) populateStaticNodesToContract() error {
+func (p *PermissionCtrl) populateStaticNodesToContract(cache *pcore.NodeCache) error {
+	config := p.config()
 	nodes := p.node.Server().Config.StaticNodes
 	for _, node := range nodes {
 		url := pcore.GetNodeUrl(node.EnodeID(), node.IP().String(), uint16(node.TCP()), uint16(node.RaftPort()), p.isRaft)
-		_, err := p.contract.AddAdminNode(url)
+		_, err := p.contractService().AddAdminNode(url)
 		if err != nil {
 			log.Warn("Failed to propose node", "err", err, "enode", node.EnodeID())
 			return err
 		}
-		pcore.NodeInfoMap.UpsertNode(p.permConfig.NwAdminOrg, url, 2)
+		cache.UpsertNode(config.NwAdminOrg, url, 2)
 	}
 	return nil
 }
 
 // Invokes the initAccounts function of smart contract to set the initial
 // set of accounts access to full access
-func (p *PermissionCtrl) populateInitAccountAccess() error {
-	for _, a := range p.permConfig.Accounts {
-		_, er := p.contract.AddAdminAccount(a)
+func (p *PermissionCtrl) populateInitAccountAccess(cache *pcore.AcctCache) error {
+	config := p.config()
+	for _, a := range config.Accounts {
+		_, er := p.contractService().AddAdminAccount(a)
 		if er != nil {
 			log.Warn("Error adding permission initial account list", "err", er, "account", a)
 			return er
 		}
-		pcore.AcctInfoMap.UpsertAccount(p.permConfig.NwAdminOrg, p.permConfig.NwAdminRole, a, true, 2)
+		cache.UpsertAccount(config.NwAdminOrg, config.NwAdminRole, a, true, 2)
 	}
 	return nil
 }
 
 // updates network boot status to true
 func (p *PermissionCtrl) updateNetworkStatus() error {
-	_, err := p.contract.UpdateNetworkBootStatus()
+	_, err := p.contractService().UpdateNetworkBootStatus()
 	if err != nil {
 		log.Warn("Failed to udpate network boot status ", "err", err)
 		return err
@@ -319,7 +447,7 @@ func (p *PermissionCtrl) updateNetworkStatus() error {
 
 // getter to get an account record from the contract
 func (p *PermissionCtrl) populateAccountToCache(acctId common.Address) (*pcore.AccountInfo, error) {
-	account, orgId, roleId, status, isAdmin, err := p.contract.GetAccountDetails(acctId)
+	account, orgId, roleId, status, isAdmin, err := p.contractService().GetAccountDetails(acctId)
 	if err != nil {
 		return nil, err
 	}
@@ -332,7 +460,7 @@ func (p *PermissionCtrl) populateAccountToCache(acctId common.Address) (*pcore.A
 
 // getter to get a org record from the contract
 func (p *PermissionCtrl) populateOrgToCache(orgId string) (*pcore.OrgInfo, error) {
-	org, parentOrgId, ultimateParentId, orgLevel, orgStatus, err := p.contract.GetOrgDetails(orgId)
+	org, parentOrgId, ultimateParentId, orgLevel, orgStatus, err := p.contractService().GetOrgDetails(orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +469,7 @@ func (p *PermissionCtrl) populateOrgToCache(orgId string) (*pcore.OrgInfo, error
 	}
 	orgInfo := pcore.OrgInfo{OrgId: org, ParentOrgId: parentOrgId, UltimateParent: ultimateParentId, Status: pcore.OrgStatus(orgStatus.Int64()), Level: orgLevel}
 	// now need to build the list of sub orgs for this org
-	subOrgIndexes, err := p.contract.GetSubOrgIndexes(orgId)
+	subOrgIndexes, err := p.contractService().GetSubOrgIndexes(orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -352,7 +480,7 @@ func (p *PermissionCtrl) populateOrgToCache(orgId string) (*pcore.OrgInfo, error
 
 	// range through the sub org indexes and get the org ids to populate the suborg list
 	for _, s := range subOrgIndexes {
-		subOrgId, _, _, _, _, err := p.contract.GetOrgInfo(s)
+		subOrgId, _, _, _, _, err := p.contractService().GetOrgInfo(s)
 
 		if err != nil {
 			return nil, err
@@ -364,7 +492,7 @@ func (p *PermissionCtrl) populateOrgToCache(orgId string) (*pcore.OrgInfo, error
 
 // getter to get a role record from the contract
 func (p *PermissionCtrl) populateRoleToCache(roleKey *pcore.RoleKey) (*pcore.RoleInfo, error) {
-	roleDetails, err := p.contract.GetRoleDetails(roleKey.RoleId, roleKey.OrgId)
+	roleDetails, err := p.contractService().GetRoleDetails(roleKey.RoleId, roleKey.OrgId)
 
 	if err != nil {
 		return nil, err
@@ -378,7 +506,7 @@ func (p *PermissionCtrl) populateRoleToCache(roleKey *pcore.RoleKey) (*pcore.Rol
 
 // getter to get a role record from the contract
 func (p *PermissionCtrl) populateNodeCache(url string) (*pcore.NodeInfo, error) {
-	orgId, url, status, err := p.contract.GetNodeDetails(url)
+	orgId, url, status, err := p.contractService().GetNodeDetails(url)
 	if err != nil {
 		return nil, err
 	}
@@ -393,10 +521,10 @@ func (p *PermissionCtrl) populateNodeCache(url string) (*pcore.NodeInfo, error)
 func (p *PermissionCtrl) populateNodeCacheAndValidate(hexNodeId, ultimateParentId string) bool {
 	txnAllowed := false
 	passedEnode, _ := enode.ParseV4(hexNodeId)
-	if numberOfNodes, err := p.contract.GetNumberOfNodes(); err == nil {
+	if numberOfNodes, err := p.contractService().GetNumberOfNodes(); err == nil {
 		numNodes := numberOfNodes.Uint64()
 		for k := uint64(0); k < numNodes; k++ {
-			if orgId, url, status, err := p.contract.GetNodeDetailsFromIndex(big.NewInt(int64(k))); err == nil {
+			if orgId, url, status, err := p.contractService().GetNodeDetailsFromIndex(big.NewInt(int64(k))); err == nil {
 				if orgRec, err := pcore.OrgInfoMap.GetOrg(orgId); err != nil {
 					if orgRec.UltimateParent == ultimateParentId {
 						recEnode, _ := enode.ParseV4(url)
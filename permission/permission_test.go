@@ -892,6 +892,35 @@ func TestParsePermissionConfig(t *testing.T) {
 	}
 	permConfig, _ := ptype.ParsePermissionConfig(d)
 	assert.False(t, permConfig.IsEmpty(), "expected non empty object")
+
+	_ = os.Remove(fileName)
+	tmpPermCofig.Migration = &ptype.PermissionMigration{}
+	blob, _ = json.Marshal(tmpPermCofig)
+	if err := ioutil.WriteFile(fileName, blob, 0644); err != nil {
+		t.Fatal("Error writing new Node info to file", "fileName", fileName, "err", err)
+	}
+	_, err = ptype.ParsePermissionConfig(d)
+	assert.True(t, err != nil, "expected migration contract address error")
+
+	_ = os.Remove(fileName)
+	tmpPermCofig.Migration.InterfAddress = common.HexToAddress("0x1")
+	blob, _ = json.Marshal(tmpPermCofig)
+	if err := ioutil.WriteFile(fileName, blob, 0644); err != nil {
+		t.Fatal("Error writing new Node info to file", "fileName", fileName, "err", err)
+	}
+	permConfig, err = ptype.ParsePermissionConfig(d)
+	assert.NoError(t, err)
+	assert.Equal(t, ptype.PERMISSION_V2, permConfig.V2Config().PermissionsModel)
+	assert.Equal(t, common.HexToAddress("0x1"), permConfig.V2Config().InterfAddress)
+
+	_ = os.Remove(fileName)
+	tmpPermCofig.PermissionsModel = "v2"
+	blob, _ = json.Marshal(tmpPermCofig)
+	if err := ioutil.WriteFile(fileName, blob, 0644); err != nil {
+		t.Fatal("Error writing new Node info to file", "fileName", fileName, "err", err)
+	}
+	_, err = ptype.ParsePermissionConfig(d)
+	assert.True(t, err != nil, "expected migration of v2 model error")
 }
 
 func TestIsTransactionAllowed_V1(t *testing.T) {
@@ -995,3 +1024,73 @@ func TestIsTransactionAllowed_V1(t *testing.T) {
 		})
 	}
 }
+
+func TestQuorumControlsAPI_ExplainTransactionAllowed(t *testing.T) {
+	testObject := typicalQuorumControlsAPI(t)
+	pcore.PermissionTransactionAllowedFunc = testObject.permCtrl.IsTransactionAllowed
+	pcore.SetQIP714BlockReached()
+	pcore.SetNetworkBootUpCompleted()
+	acct := getArbitraryAccount()
+
+	decision := testObject.ExplainTransactionAllowed(ethapi.SendTxArgs{From: guardianAddress, To: &acct})
+
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, arbitraryNetworkAdminOrg, decision.OrgId)
+	assert.Equal(t, arbitraryNetworkAdminRole, decision.RoleId)
+
+	decision = testObject.ExplainTransactionAllowed(ethapi.SendTxArgs{From: acct, To: &acct})
+
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, pcore.ReadOnly, *decision.Access)
+	assert.Equal(t, "ReadOnly access does not allow transactions", decision.DeniedBy)
+
+	err := pcore.CheckAccountPermission(acct, &acct, big.NewInt(0), nil, 0, big.NewInt(0))
+
+	assert.True(t, errors.Is(err, ptype.ErrNoPermissionForTxn))
+	assert.EqualError(t, err, ptype.ErrNoPermissionForTxn.Error()+": "+decision.Reason())
+}
+
+func TestQuorumControlsAPI_ExplainConnectionAllowed(t *testing.T) {
+	testObject := typicalQuorumControlsAPI(t)
+	pcore.NodeInfoMap.UpsertNode(arbitraryNetworkAdminOrg, arbitraryNode1, pcore.NodeApproved)
+
+	decision, err := testObject.ExplainConnectionAllowed(arbitraryNode1, "127.0.0.1", 21000, 50401)
+
+	assert.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, arbitraryNetworkAdminOrg, decision.OrgId)
+
+	decision, err = testObject.ExplainConnectionAllowed(arbitraryNode2, "127.0.0.1", 21001, 50402)
+
+	assert.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.DeniedBy, "is not in the permission model")
+}
+
+func TestQuorumControlsAPI_CallPermissions(t *testing.T) {
+	testObject := typicalQuorumControlsAPI(t)
+	pcore.PermissionTransactionAllowedFunc = testObject.permCtrl.IsTransactionAllowed
+	pcore.SetQIP714BlockReached()
+	pcore.SetNetworkBootUpCompleted()
+	acct := getArbitraryAccount()
+	token := common.HexToAddress("0x1")
+	pause := pcore.FunctionSelector{0x84, 0x56, 0xcb, 0x59}
+	pcore.RoleInfoMap.UpsertRole(arbitraryNetworkAdminOrg, "OPS", false, false, pcore.Transact, true)
+	pcore.AcctInfoMap.UpsertAccount(arbitraryNetworkAdminOrg, "OPS", acct, false, pcore.AcctActive)
+
+	_, err := testObject.AddCallPermission(arbitraryNetworkAdminOrg, "OPS", token, pause, ethapi.SendTxArgs{From: guardianAddress})
+
+	assert.Equal(t, ptype.ErrNoCallPermMgr, err)
+
+	pcore.CallPermissionMap.UpsertCallPermission(arbitraryNetworkAdminOrg, "OPS", token, pause, true)
+	data := hexutil.Bytes{0xa9, 0x05, 0x9c, 0xbb}
+
+	assert.Len(t, testObject.CallPermissionList(), 1)
+	// no transition enforces the call permissions of the test chain
+	assert.True(t, testObject.TransactionAllowed(ethapi.SendTxArgs{From: acct, To: &token, Data: &data}))
+	assert.NoError(t, pcore.CheckCallPermission(acct, token, pause[:], false))
+	err = pcore.CheckCallPermission(acct, token, data, false)
+	assert.True(t, errors.Is(err, pcore.ErrNoCallPermission))
+	assert.NoError(t, pcore.CheckCallPermission(guardianAddress, token, data, false))
+	assert.NoError(t, pcore.CheckCallPermission(acct, token, nil, false))
+}
@@ -0,0 +1,519 @@
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/permission/core"
+	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// names of the QuorumControlsAPI methods submitting the steps of a plan
+const (
+	addOrgMethod            = "addOrg"
+	approveOrgMethod        = "approveOrg"
+	addSubOrgMethod         = "addSubOrg"
+	addNodeMethod           = "addNode"
+	assignAdminRoleMethod   = "assignAdminRole"
+	approveAdminRoleMethod  = "approveAdminRole"
+	addNewRoleMethod        = "addNewRole"
+	addAccountToOrgMethod   = "addAccountToOrg"
+	changeAccountRoleMethod = "changeAccountRole"
+)
+
+// outcomes of applying a step of a plan
+const (
+	StepSubmitted = "submitted"
+	StepFailed    = "failed"
+	StepWaiting   = "waiting"
+)
+
+const networkAdminSigner = "network admin"
+
+// PermissionSpec declares the desired state of the permission model, so that it can be kept in
+// version control. Only the declared orgs are reconciled, the other orgs are left untouched.
+type PermissionSpec struct {
+	Orgs []OrgSpec `json:"orgs"`
+}
+
+// OrgSpec declares an org with its roles, accounts, nodes and sub orgs. A new top level org is
+// proposed with its first node and its first account having the org admin role.
+type OrgSpec struct {
+	OrgId    string        `json:"orgId"`
+	Roles    []RoleSpec    `json:"roles,omitempty"`
+	Accounts []AccountSpec `json:"accounts,omitempty"`
+	Nodes    []string      `json:"nodes,omitempty"`
+	SubOrgs  []OrgSpec     `json:"subOrgs,omitempty"`
+}
+
+type RoleSpec struct {
+	RoleId  string          `json:"roleId"`
+	Access  core.AccessType `json:"access"`
+	IsVoter bool            `json:"isVoter"`
+	IsAdmin bool            `json:"isAdmin"`
+}
+
+type AccountSpec struct {
+	Account common.Address `json:"account"`
+	RoleId  string         `json:"roleId"`
+}
+
+// PermissionStep is a permission transaction of a plan, Method is the QuorumControlsAPI
+// method submitting it
+type PermissionStep struct {
+	Method      string           `json:"method"`
+	OrgId       string           `json:"orgId"`
+	ParentOrgId string           `json:"parentOrgId,omitempty"`
+	Url         string           `json:"url,omitempty"`
+	RoleId      string           `json:"roleId,omitempty"`
+	Account     *common.Address  `json:"account,omitempty"`
+	Access      *core.AccessType `json:"access,omitempty"`
+	IsVoter     bool             `json:"isVoter,omitempty"`
+	IsAdmin     bool             `json:"isAdmin,omitempty"`
+	Signer      string           `json:"signer"`              // network admin or admin of the given org
+	NeedsVotes  bool             `json:"needsVotes"`          // the step is a vote of the network admin voters
+	DependsOn   []int            `json:"dependsOn,omitempty"` // steps to be mined, and approved, first
+}
+
+func (s *PermissionStep) String() string {
+	var b strings.Builder
+	b.WriteString(s.Method)
+	if s.ParentOrgId != "" {
+		fmt.Fprintf(&b, " parentOrgId=%s", s.ParentOrgId)
+	}
+	fmt.Fprintf(&b, " orgId=%s", s.OrgId)
+	if s.RoleId != "" {
+		fmt.Fprintf(&b, " roleId=%s", s.RoleId)
+	}
+	if s.Access != nil {
+		fmt.Fprintf(&b, " access=%d isVoter=%t isAdmin=%t", *s.Access, s.IsVoter, s.IsAdmin)
+	}
+	if s.Account != nil {
+		fmt.Fprintf(&b, " account=%s", s.Account.Hex())
+	}
+	if s.Url != "" {
+		fmt.Fprintf(&b, " url=%s", s.Url)
+	}
+	fmt.Fprintf(&b, " (signed by %s", s.Signer)
+	if s.NeedsVotes {
+		b.WriteString(", needs network admin votes")
+	}
+	if len(s.DependsOn) > 0 {
+		deps := make([]string, len(s.DependsOn))
+		for i, d := range s.DependsOn {
+			deps[i] = fmt.Sprint(d)
+		}
+		fmt.Fprintf(&b, ", after %s", strings.Join(deps, ","))
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// PermissionPlan lists in order the permission transactions reconciling the permission model with a
+// PermissionSpec, and the differences which cannot be reconciled with a transaction
+type PermissionPlan struct {
+	Steps    []*PermissionStep `json:"steps"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+type PermissionStepResult struct {
+	Step   int    `json:"step"`
+	Status string `json:"status"` // StepSubmitted, StepFailed or StepWaiting for the steps it depends on
+	Error  string `json:"error,omitempty"`
+}
+
+// PermissionApplyResult reports the steps of a plan submitted by ApplyPermissions. The steps waiting for
+// others are submitted by applying the spec again once the steps they depend on are mined and approved.
+type PermissionApplyResult struct {
+	Plan    *PermissionPlan         `json:"plan"`
+	Results []*PermissionStepResult `json:"results"`
+}
+
+// permissionSnapshot is the state of the permission model a plan is computed against
+type permissionSnapshot struct {
+	orgs     map[string]core.OrgInfo // by full org id
+	roles    map[core.RoleKey]core.RoleInfo
+	accounts map[common.Address]core.AccountInfo
+	nodes    []*core.NodeInfo
+}
+
+func newPermissionSnapshot() *permissionSnapshot {
+	return &permissionSnapshot{
+		orgs:     make(map[string]core.OrgInfo),
+		roles:    make(map[core.RoleKey]core.RoleInfo),
+		accounts: make(map[common.Address]core.AccountInfo),
+	}
+}
+
+// cacheSnapshot reads the permission model from the caches
+func cacheSnapshot() *permissionSnapshot {
+	s := newPermissionSnapshot()
+	for _, o := range core.OrgInfoMap.GetOrgList() {
+		s.orgs[o.FullOrgId] = o
+	}
+	for _, r := range core.RoleInfoMap.GetRoleList() {
+		s.roles[core.RoleKey{OrgId: r.OrgId, RoleId: r.RoleId}] = r
+	}
+	for _, a := range core.AcctInfoMap.GetAcctList() {
+		s.accounts[a.AcctId] = a
+	}
+	for _, n := range core.NodeInfoMap.GetNodeList() {
+		s.nodes = append(s.nodes, &core.NodeInfo{OrgId: n.OrgId, Url: n.Url, Status: n.Status})
+	}
+	return s
+}
+
+// contractSnapshot reads the permission model from the contracts at the given block, or from their
+// pending state if nil
+func (p *PermissionCtrl) contractSnapshot(blockNumber *big.Int) (*permissionSnapshot, error) {
+	config := p.config()
+	return readContractSnapshot(NewPermissionContractService(p.ethClnt, config.PermissionsModel == ptype.PERMISSION_V2, p.key, config, p.isRaft, p.useDns, p.chainID), blockNumber)
+}
+
+// readContractSnapshot reads the permission model from the contracts of the given contract service
+func readContractSnapshot(contract ptype.InitService, blockNumber *big.Int) (*permissionSnapshot, error) {
+	if err := contract.BindContracts(); err != nil {
+		return nil, err
+	}
+	contract.SetCallBlock(blockNumber)
+
+	s := newPermissionSnapshot()
+	numberOfOrgs, err := contract.GetNumberOfOrgs()
+	if err != nil {
+		return nil, err
+	}
+	for k := int64(0); k < numberOfOrgs.Int64(); k++ {
+		orgId, porgId, ultParent, level, status, err := contract.GetOrgInfo(big.NewInt(k))
+		if err != nil {
+			return nil, err
+		}
+		o := core.OrgInfo{OrgId: orgId, FullOrgId: orgId, ParentOrgId: porgId, UltimateParent: ultParent, Level: level, Status: core.OrgStatus(status.Int64())}
+		if porgId != "" {
+			o.FullOrgId = porgId + "." + orgId
+		}
+		s.orgs[o.FullOrgId] = o
+	}
+	numberOfRoles, err := contract.GetNumberOfRoles()
+	if err != nil {
+		return nil, err
+	}
+	for k := int64(0); k < numberOfRoles.Int64(); k++ {
+		r, err := contract.GetRoleDetailsFromIndex(big.NewInt(k))
+		if err != nil {
+			return nil, err
+		}
+		s.roles[core.RoleKey{OrgId: r.OrgId, RoleId: r.RoleId}] = core.RoleInfo{OrgId: r.OrgId, RoleId: r.RoleId, IsVoter: r.Voter, IsAdmin: r.Admin, Access: core.AccessType(r.AccessType.Int64()), Active: r.Active}
+	}
+	numberOfAccounts, err := contract.GetNumberOfAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for k := int64(0); k < numberOfAccounts.Int64(); k++ {
+		addr, orgId, roleId, status, orgAdmin, err := contract.GetAccountDetailsFromIndex(big.NewInt(k))
+		if err != nil {
+			return nil, err
+		}
+		s.accounts[addr] = core.AccountInfo{OrgId: orgId, RoleId: roleId, AcctId: addr, IsOrgAdmin: orgAdmin, Status: core.AcctStatus(status.Int64())}
+	}
+	numberOfNodes, err := contract.GetNumberOfNodes()
+	if err != nil {
+		return nil, err
+	}
+	for k := int64(0); k < numberOfNodes.Int64(); k++ {
+		orgId, url, status, err := contract.GetNodeDetailsFromIndex(big.NewInt(k))
+		if err != nil {
+			return nil, err
+		}
+		s.nodes = append(s.nodes, &core.NodeInfo{OrgId: orgId, Url: url, Status: core.NodeStatus(status.Int64())})
+	}
+	return s, nil
+}
+
+// permissionSnapshot reads the permission model from the caches if blockNumber is nil and none of them
+// has evicted records, from the contracts otherwise
+func (p *PermissionCtrl) permissionSnapshot(blockNumber *rpc.BlockNumber) (*permissionSnapshot, error) {
+	if core.OrgInfoMap == nil {
+		return nil, errors.New("permission service is not started")
+	}
+	if blockNumber == nil {
+		if core.CachesComplete() {
+			return cacheSnapshot(), nil
+		}
+		return p.contractSnapshot(nil)
+	}
+	switch *blockNumber {
+	case rpc.PendingBlockNumber:
+		return p.contractSnapshot(nil)
+	case rpc.LatestBlockNumber:
+		return p.contractSnapshot(p.eth.BlockChain().CurrentBlock().Number())
+	}
+	return p.contractSnapshot(big.NewInt(blockNumber.Int64()))
+}
+
+// node returns the node with the same enode id as url
+func (s *permissionSnapshot) node(url string) *core.NodeInfo {
+	id := enode.ID{}
+	if n, err := enode.ParseV4(url); err == nil {
+		id = n.ID()
+	}
+	for _, n := range s.nodes {
+		if n.Url == url || (id != enode.ID{} && n.ID() == id) {
+			return n
+		}
+	}
+	return nil
+}
+
+type permissionPlanner struct {
+	snapshot *permissionSnapshot
+	config   *ptype.PermissionConfig
+	plan     *PermissionPlan
+
+	orgs      map[string]bool
+	accounts  map[common.Address]bool
+	roleSteps map[core.RoleKey]int // steps adding the declared roles
+	lastVote  int                  // last step voted by the network admins, -1 if none
+}
+
+// planPermissions computes the permission transactions reconciling s with spec
+func planPermissions(s *permissionSnapshot, config *ptype.PermissionConfig, spec *PermissionSpec) (*PermissionPlan, error) {
+	pl := &permissionPlanner{
+		snapshot:  s,
+		config:    config,
+		plan:      &PermissionPlan{Steps: []*PermissionStep{}},
+		orgs:      make(map[string]bool),
+		accounts:  make(map[common.Address]bool),
+		roleSteps: make(map[core.RoleKey]int),
+		lastVote:  -1,
+	}
+	for i := range spec.Orgs {
+		if err := pl.planOrg(&spec.Orgs[i], "", "", nil); err != nil {
+			return nil, err
+		}
+	}
+	return pl.plan, nil
+}
+
+func (pl *permissionPlanner) add(step *PermissionStep) int {
+	pl.plan.Steps = append(pl.plan.Steps, step)
+	return len(pl.plan.Steps) - 1
+}
+
+func (pl *permissionPlanner) warn(format string, args ...interface{}) {
+	pl.plan.Warnings = append(pl.plan.Warnings, fmt.Sprintf(format, args...))
+}
+
+// addVote adds a step proposing an operation to the network admin voters followed by the step approving it.
+// As the network admins have a single pending operation at a time, the proposal waits for the previous vote.
+func (pl *permissionPlanner) addVote(propose *PermissionStep, approve *PermissionStep) int {
+	if propose != nil {
+		if pl.lastVote >= 0 {
+			propose.DependsOn = append(propose.DependsOn, pl.lastVote)
+		}
+		approve.DependsOn = append(approve.DependsOn, pl.add(propose))
+	} else if pl.lastVote >= 0 {
+		approve.DependsOn = append(approve.DependsOn, pl.lastVote)
+	}
+	approve.Signer, approve.NeedsVotes = networkAdminSigner, true
+	pl.lastVote = pl.add(approve)
+	return pl.lastVote
+}
+
+// orgProposal returns the node and the org admin account a top level org is proposed with
+func (pl *permissionPlanner) orgProposal(org *OrgSpec) (string, *common.Address, error) {
+	for i := range org.Accounts {
+		if org.Accounts[i].RoleId == pl.config.OrgAdminRole && len(org.Nodes) > 0 {
+			return org.Nodes[0], &org.Accounts[i].Account, nil
+		}
+	}
+	return "", nil, fmt.Errorf("org %s must declare a node and an account with role %s to be proposed", org.OrgId, pl.config.OrgAdminRole)
+}
+
+// planOrg adds the steps reconciling org, whose steps depend on after
+func (pl *permissionPlanner) planOrg(org *OrgSpec, parentId, ultimateParent string, after []int) error {
+	if org.OrgId == "" || !isStringAlphaNumeric(org.OrgId) {
+		return fmt.Errorf("invalid org id %q", org.OrgId)
+	}
+	orgId := org.OrgId
+	if parentId != "" {
+		orgId = parentId + "." + org.OrgId
+	} else {
+		ultimateParent = org.OrgId
+	}
+	if pl.orgs[orgId] {
+		return fmt.Errorf("org %s is declared twice", orgId)
+	}
+	pl.orgs[orgId] = true
+	orgAdminSigner := "admin of org " + ultimateParent
+
+	var (
+		proposedUrl   string
+		proposedAdmin *common.Address
+	)
+	existing, exists := pl.snapshot.orgs[orgId]
+	switch {
+	case !exists && parentId == "":
+		url, admin, err := pl.orgProposal(org)
+		if err != nil {
+			return err
+		}
+		proposedUrl, proposedAdmin = url, admin
+		after = []int{pl.addVote(
+			&PermissionStep{Method: addOrgMethod, OrgId: orgId, Url: url, Account: admin, Signer: networkAdminSigner},
+			&PermissionStep{Method: approveOrgMethod, OrgId: orgId, Url: url, Account: admin})}
+	case !exists:
+		if len(org.Nodes) > 0 {
+			proposedUrl = org.Nodes[0]
+		}
+		after = []int{pl.add(&PermissionStep{Method: addSubOrgMethod, ParentOrgId: parentId, OrgId: org.OrgId, Url: proposedUrl, Signer: orgAdminSigner, DependsOn: after})}
+	case existing.Status == core.OrgPendingApproval:
+		url, admin, err := pl.orgProposal(org)
+		if err != nil {
+			return err
+		}
+		proposedUrl, proposedAdmin = url, admin
+		after = []int{pl.addVote(nil, &PermissionStep{Method: approveOrgMethod, OrgId: orgId, Url: url, Account: admin})}
+	case existing.Status != core.OrgApproved:
+		pl.warn("org %s is not approved (status %d), its changes may be rejected", orgId, existing.Status)
+	}
+
+	roles := make(map[string]bool)
+	for _, role := range org.Roles {
+		roles[role.RoleId] = true
+		key := core.RoleKey{OrgId: orgId, RoleId: role.RoleId}
+		if r, ok := pl.snapshot.roles[key]; ok {
+			if !r.Active {
+				pl.warn("role %s of org %s has been removed and cannot be added back", role.RoleId, orgId)
+			} else if r.Access != role.Access || r.IsVoter != role.IsVoter || r.IsAdmin != role.IsAdmin {
+				pl.warn("role %s of org %s differs from its declaration, roles cannot be changed", role.RoleId, orgId)
+			}
+			continue
+		}
+		access := role.Access
+		pl.roleSteps[key] = pl.add(&PermissionStep{Method: addNewRoleMethod, OrgId: orgId, RoleId: role.RoleId, Access: &access, IsVoter: role.IsVoter, IsAdmin: role.IsAdmin, Signer: orgAdminSigner, DependsOn: after})
+	}
+
+	accounts := make(map[common.Address]bool)
+	for _, acct := range org.Accounts {
+		if pl.accounts[acct.Account] {
+			return fmt.Errorf("account %s is declared twice", acct.Account.Hex())
+		}
+		pl.accounts[acct.Account], accounts[acct.Account] = true, true
+		if proposedAdmin != nil && acct.Account == *proposedAdmin {
+			continue
+		}
+		pl.planAccount(acct, orgId, ultimateParent, orgAdminSigner, after)
+	}
+
+	nodes := make(map[string]bool)
+	for _, url := range org.Nodes {
+		nodes[url] = true
+		if url == proposedUrl {
+			continue
+		}
+		if n := pl.snapshot.node(url); n != nil {
+			nodes[n.Url] = true
+			if n.OrgId != orgId {
+				pl.warn("node %s belongs to org %s instead of %s", url, n.OrgId, orgId)
+			} else if n.Status != core.NodeApproved {
+				pl.warn("node %s of org %s is not approved (status %d)", url, orgId, n.Status)
+			}
+			continue
+		}
+		pl.add(&PermissionStep{Method: addNodeMethod, OrgId: orgId, Url: url, Signer: orgAdminSigner, DependsOn: after})
+	}
+
+	subOrgs := make(map[string]bool)
+	for i := range org.SubOrgs {
+		subOrgs[orgId+"."+org.SubOrgs[i].OrgId] = true
+		if err := pl.planOrg(&org.SubOrgs[i], orgId, ultimateParent, after); err != nil {
+			return err
+		}
+	}
+
+	if exists {
+		pl.warnUndeclared(orgId, roles, accounts, nodes, subOrgs)
+	}
+	return nil
+}
+
+// planAccount adds the steps giving acct its declared role in orgId
+func (pl *permissionPlanner) planAccount(acct AccountSpec, orgId, ultimateParent, orgAdminSigner string, after []int) {
+	isAdminRole := acct.RoleId == pl.config.OrgAdminRole || acct.RoleId == pl.config.NwAdminRole
+	account := acct.Account
+	method := addAccountToOrgMethod
+	if a, ok := pl.snapshot.accounts[account]; ok {
+		switch {
+		case a.OrgId != orgId:
+			pl.warn("account %s belongs to org %s instead of %s", account.Hex(), a.OrgId, orgId)
+			return
+		case a.RoleId == acct.RoleId && a.Status == core.AcctPendingApproval && isAdminRole:
+			pl.addVote(nil, &PermissionStep{Method: approveAdminRoleMethod, OrgId: orgId, Account: &account})
+			return
+		case a.RoleId == acct.RoleId:
+			if a.Status != core.AcctActive {
+				pl.warn("account %s of org %s is not active (status %d)", account.Hex(), orgId, a.Status)
+			}
+			return
+		}
+		method = changeAccountRoleMethod
+	}
+	if isAdminRole {
+		pl.addVote(
+			&PermissionStep{Method: assignAdminRoleMethod, OrgId: orgId, RoleId: acct.RoleId, Account: &account, Signer: networkAdminSigner, DependsOn: after},
+			&PermissionStep{Method: approveAdminRoleMethod, OrgId: orgId, Account: &account})
+		return
+	}
+	dependsOn := append([]int(nil), after...)
+	roleExists := false
+	for _, key := range []core.RoleKey{{OrgId: orgId, RoleId: acct.RoleId}, {OrgId: ultimateParent, RoleId: acct.RoleId}} {
+		if step, ok := pl.roleSteps[key]; ok {
+			dependsOn = append(dependsOn, step)
+			roleExists = true
+			break
+		}
+		if r, ok := pl.snapshot.roles[key]; ok && r.Active {
+			roleExists = true
+			break
+		}
+	}
+	if !roleExists {
+		pl.warn("role %s of account %s is neither declared nor active in org %s or %s", acct.RoleId, account.Hex(), orgId, ultimateParent)
+		return
+	}
+	pl.add(&PermissionStep{Method: method, OrgId: orgId, RoleId: acct.RoleId, Account: &account, Signer: orgAdminSigner, DependsOn: dependsOn})
+}
+
+// warnUndeclared reports the records of an existing org missing from its declaration, they are
+// left untouched
+func (pl *permissionPlanner) warnUndeclared(orgId string, roles map[string]bool, accounts map[common.Address]bool, nodes, subOrgs map[string]bool) {
+	var undeclared []string
+	for _, r := range pl.snapshot.roles {
+		if r.OrgId == orgId && r.Active && !roles[r.RoleId] && r.RoleId != pl.config.OrgAdminRole && r.RoleId != pl.config.NwAdminRole {
+			undeclared = append(undeclared, fmt.Sprintf("role %s of org %s is not declared", r.RoleId, orgId))
+		}
+	}
+	for _, a := range pl.snapshot.accounts {
+		if a.OrgId == orgId && !accounts[a.AcctId] {
+			undeclared = append(undeclared, fmt.Sprintf("account %s of org %s is not declared", a.AcctId.Hex(), orgId))
+		}
+	}
+	for _, n := range pl.snapshot.nodes {
+		if n.OrgId == orgId && !nodes[n.Url] {
+			undeclared = append(undeclared, fmt.Sprintf("node %s of org %s is not declared", n.Url, orgId))
+		}
+	}
+	for _, o := range pl.snapshot.orgs {
+		if o.ParentOrgId == orgId && !subOrgs[o.FullOrgId] {
+			undeclared = append(undeclared, fmt.Sprintf("sub org %s is not declared", o.FullOrgId))
+		}
+	}
+	sort.Strings(undeclared)
+	pl.plan.Warnings = append(pl.plan.Warnings, undeclared...)
+}
@@ -0,0 +1,125 @@
+package permission
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	pcore "github.com/ethereum/go-ethereum/permission/core"
+	ptype "github.com/ethereum/go-ethereum/permission/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func typicalPermissionSnapshot() *permissionSnapshot {
+	s := newPermissionSnapshot()
+	s.orgs[arbitraryNetworkAdminOrg] = pcore.OrgInfo{OrgId: arbitraryNetworkAdminOrg, FullOrgId: arbitraryNetworkAdminOrg, UltimateParent: arbitraryNetworkAdminOrg, Level: big.NewInt(1), Status: pcore.OrgApproved}
+	s.orgs[arbitraryOrgToAdd] = pcore.OrgInfo{OrgId: arbitraryOrgToAdd, FullOrgId: arbitraryOrgToAdd, UltimateParent: arbitraryOrgToAdd, Level: big.NewInt(1), Status: pcore.OrgApproved}
+	s.roles[pcore.RoleKey{OrgId: arbitraryOrgToAdd, RoleId: arbitrartNewRole1}] = pcore.RoleInfo{OrgId: arbitraryOrgToAdd, RoleId: arbitrartNewRole1, Access: pcore.Transact, Active: true}
+	s.accounts[common.HexToAddress("0x1")] = pcore.AccountInfo{OrgId: arbitraryOrgToAdd, RoleId: arbitraryOrgAdminRole, AcctId: common.HexToAddress("0x1"), IsOrgAdmin: true, Status: pcore.AcctActive}
+	s.accounts[common.HexToAddress("0x2")] = pcore.AccountInfo{OrgId: arbitraryOrgToAdd, RoleId: arbitrartNewRole1, AcctId: common.HexToAddress("0x2"), Status: pcore.AcctActive}
+	s.nodes = append(s.nodes, &pcore.NodeInfo{OrgId: arbitraryOrgToAdd, Url: arbitraryNode1, Status: pcore.NodeApproved})
+	return s
+}
+
+func TestPlanPermissions(t *testing.T) {
+	config := &ptype.PermissionConfig{NwAdminOrg: arbitraryNetworkAdminOrg, NwAdminRole: arbitraryNetworkAdminRole, OrgAdminRole: arbitraryOrgAdminRole}
+	spec := &PermissionSpec{Orgs: []OrgSpec{
+		{
+			OrgId: arbitraryOrgToAdd,
+			Roles: []RoleSpec{{RoleId: arbitrartNewRole1, Access: pcore.FullAccess}, {RoleId: arbitrartNewRole2, Access: pcore.Transact}},
+			Accounts: []AccountSpec{
+				{Account: common.HexToAddress("0x1"), RoleId: arbitraryOrgAdminRole},
+				{Account: common.HexToAddress("0x2"), RoleId: arbitrartNewRole2},
+				{Account: common.HexToAddress("0x3"), RoleId: arbitraryOrgAdminRole},
+			},
+			Nodes: []string{arbitraryNode1, arbitraryNode2},
+		},
+		{
+			OrgId:    "ORG2",
+			Accounts: []AccountSpec{{Account: common.HexToAddress("0x4"), RoleId: arbitraryOrgAdminRole}},
+			Nodes:    []string{arbitraryNode3},
+			SubOrgs: []OrgSpec{{
+				OrgId:    arbitrarySubOrg,
+				Roles:    []RoleSpec{{RoleId: arbitrartNewRole1, Access: pcore.ReadOnly, IsVoter: true}},
+				Accounts: []AccountSpec{{Account: common.HexToAddress("0x5"), RoleId: arbitrartNewRole1}},
+				Nodes:    []string{arbitraryNode4},
+			}},
+		},
+	}}
+
+	plan, err := planPermissions(typicalPermissionSnapshot(), config, spec)
+
+	assert.NoError(t, err)
+	readOnly, transact := pcore.ReadOnly, pcore.Transact
+	acct := func(hex string) *common.Address {
+		a := common.HexToAddress(hex)
+		return &a
+	}
+	assert.Equal(t, []*PermissionStep{
+		{Method: addNewRoleMethod, OrgId: arbitraryOrgToAdd, RoleId: arbitrartNewRole2, Access: &transact, Signer: "admin of org ORG1"},
+		{Method: changeAccountRoleMethod, OrgId: arbitraryOrgToAdd, RoleId: arbitrartNewRole2, Account: acct("0x2"), Signer: "admin of org ORG1", DependsOn: []int{0}},
+		{Method: assignAdminRoleMethod, OrgId: arbitraryOrgToAdd, RoleId: arbitraryOrgAdminRole, Account: acct("0x3"), Signer: networkAdminSigner},
+		{Method: approveAdminRoleMethod, OrgId: arbitraryOrgToAdd, Account: acct("0x3"), Signer: networkAdminSigner, NeedsVotes: true, DependsOn: []int{2}},
+		{Method: addNodeMethod, OrgId: arbitraryOrgToAdd, Url: arbitraryNode2, Signer: "admin of org ORG1"},
+		{Method: addOrgMethod, OrgId: "ORG2", Url: arbitraryNode3, Account: acct("0x4"), Signer: networkAdminSigner, DependsOn: []int{3}},
+		{Method: approveOrgMethod, OrgId: "ORG2", Url: arbitraryNode3, Account: acct("0x4"), Signer: networkAdminSigner, NeedsVotes: true, DependsOn: []int{5}},
+		{Method: addSubOrgMethod, ParentOrgId: "ORG2", OrgId: arbitrarySubOrg, Url: arbitraryNode4, Signer: "admin of org ORG2", DependsOn: []int{6}},
+		{Method: addNewRoleMethod, OrgId: "ORG2.SUB1", RoleId: arbitrartNewRole1, Access: &readOnly, IsVoter: true, Signer: "admin of org ORG2", DependsOn: []int{7}},
+		{Method: addAccountToOrgMethod, OrgId: "ORG2.SUB1", RoleId: arbitrartNewRole1, Account: acct("0x5"), Signer: "admin of org ORG2", DependsOn: []int{7, 8}},
+	}, plan.Steps)
+	assert.Equal(t, []string{"role NEW_ROLE_1 of org ORG1 differs from its declaration, roles cannot be changed"}, plan.Warnings)
+	assert.Equal(t, "approveOrg orgId=ORG2 account=0x0000000000000000000000000000000000000004 url="+arbitraryNode3+" (signed by network admin, needs network admin votes, after 5)", plan.Steps[6].String())
+}
+
+func TestPlanPermissions_whenUndeclaredOrInvalid(t *testing.T) {
+	config := &ptype.PermissionConfig{NwAdminOrg: arbitraryNetworkAdminOrg, NwAdminRole: arbitraryNetworkAdminRole, OrgAdminRole: arbitraryOrgAdminRole}
+
+	plan, err := planPermissions(typicalPermissionSnapshot(), config, &PermissionSpec{Orgs: []OrgSpec{{OrgId: arbitraryOrgToAdd}}})
+
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Steps)
+	assert.Equal(t, []string{
+		"account 0x0000000000000000000000000000000000000001 of org ORG1 is not declared",
+		"account 0x0000000000000000000000000000000000000002 of org ORG1 is not declared",
+		"node " + arbitraryNode1 + " of org ORG1 is not declared",
+		"role NEW_ROLE_1 of org ORG1 is not declared",
+	}, plan.Warnings)
+
+	_, err = planPermissions(typicalPermissionSnapshot(), config, &PermissionSpec{Orgs: []OrgSpec{{OrgId: "ORG2"}}})
+	assert.EqualError(t, err, "org ORG2 must declare a node and an account with role ORG_ADMIN_ROLE to be proposed")
+
+	_, err = planPermissions(typicalPermissionSnapshot(), config, &PermissionSpec{Orgs: []OrgSpec{{OrgId: "ORG.2"}}})
+	assert.EqualError(t, err, "invalid org id \"ORG.2\"")
+}
+
+func TestQuorumControlsAPI_PlanAndApplyPermissions(t *testing.T) {
+	testObject := typicalQuorumControlsAPI(t)
+	orgAdmin := getArbitraryAccount()
+	spec := PermissionSpec{Orgs: []OrgSpec{{
+		OrgId:    arbitraryOrgToAdd,
+		Accounts: []AccountSpec{{Account: orgAdmin, RoleId: arbitraryOrgAdminRole}},
+		Nodes:    []string{arbitraryNode1},
+	}}}
+	pending := rpc.PendingBlockNumber
+
+	plan, err := testObject.PlanPermissions(spec, &pending)
+
+	assert.NoError(t, err)
+	if assert.Len(t, plan.Steps, 2) {
+		assert.Equal(t, addOrgMethod, plan.Steps[0].Method)
+		assert.Equal(t, approveOrgMethod, plan.Steps[1].Method)
+	}
+
+	result, err := testObject.ApplyPermissions(spec, ethapi.SendTxArgs{From: guardianAddress})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*PermissionStepResult{{Step: 0, Status: StepSubmitted}, {Step: 1, Status: StepWaiting}}, result.Results)
+
+	result, err = testObject.ApplyPermissions(spec, ethapi.SendTxArgs{From: getArbitraryAccount()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, StepFailed, result.Results[0].Status)
+	assert.Equal(t, ptype.ErrInvalidAccount.Error(), result.Results[0].Error)
+}
@@ -14,6 +14,14 @@ import (
 type Backend struct {
 	Ib    ptype.InterfaceBackend
 	Contr *Init
+	Quit  chan struct{} // closed to stop the event watchers when the node migrates to the v2 model
+}
+
+// Stop stops the event watchers, the node no longer uses the v1 permissions model
+func (b *Backend) Stop() {
+	if b.Quit != nil {
+		close(b.Quit)
+	}
 }
 
 func (b *Backend) ManageAccountPermissions() error {
@@ -57,6 +65,8 @@ func (b *Backend) ManageAccountPermissions() error {
 			case <-stopChan:
 				log.Info("quit account Contr watch")
 				return
+			case <-b.Quit:
+				return
 			}
 		}
 	}()
@@ -97,6 +107,8 @@ func (b *Backend) ManageRolePermissions() error {
 			case <-stopChan:
 				log.Info("quit role Contr watch")
 				return
+			case <-b.Quit:
+				return
 			}
 		}
 	}()
@@ -149,6 +161,8 @@ func (b *Backend) ManageOrgPermissions() error {
 			case <-stopChan:
 				log.Info("quit org Contr watch")
 				return
+			case <-b.Quit:
+				return
 			}
 		}
 	}()
@@ -249,12 +263,19 @@ func (b *Backend) ManageNodePermissions() error {
 			case <-stopChan:
 				log.Info("quit Node Contr watch")
 				return
+			case <-b.Quit:
+				return
 			}
 		}
 	}()
 	return nil
 }
 
+// call permissions are only supported by the v2 permissions model
+func (b *Backend) ManageCallPermissions() error {
+	return nil
+}
+
 func (b *Backend) MonitorNetworkBootUp() error {
 	netWorkBootCh := make(chan *pb.PermImplPermissionsInitialized, 1)
 
@@ -279,6 +300,8 @@ func (b *Backend) MonitorNetworkBootUp() error {
 			case <-stopChan:
 				log.Info("quit implementation contract network boot watch")
 				return
+			case <-b.Quit:
+				return
 			}
 		}
 	}()
@@ -361,3 +384,7 @@ func (b *Backend) GetAuditService(auditBackend ptype.ContractBackend) (ptype.Aud
 func (b *Backend) GetControlService(controlBackend ptype.ContractBackend) (ptype.ControlService, error) {
 	return &Control{}, nil
 }
+
+func (b *Backend) GetCallPermissionService(transactOpts *bind.TransactOpts, callPermBackend ptype.ContractBackend) (ptype.CallPermissionService, error) {
+	return nil, ptype.ErrNoCallPermMgr
+}
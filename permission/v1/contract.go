@@ -155,6 +155,17 @@ func (i *Init) GetOrgDetails(_orgId string) (string, string, string, *big.Int, *
 	return i.permOrgSession.GetOrgDetails(_orgId)
 }
 
+// SetCallBlock makes the getters read the state of the contracts at the given block, or their
+// pending state if nil. It must be called after BindContracts.
+func (i *Init) SetCallBlock(blockNumber *big.Int) {
+	opts := bind.CallOpts{Pending: blockNumber == nil, BlockNumber: blockNumber}
+	i.PermInterfSession.CallOpts = opts
+	i.permOrgSession.CallOpts = opts
+	i.permNodeSession.CallOpts = opts
+	i.permRoleSession.CallOpts = opts
+	i.permAcctSession.CallOpts = opts
+}
+
 func (a *Audit) ValidatePendingOp(_authOrg, _orgId, _url string, _account common.Address, _pendingOp int64) bool {
 	pOrg, pUrl, pAcct, op, err := a.Backend.PermInterfSession.GetPendingOp(_authOrg)
 	return err == nil && (op.Int64() == _pendingOp && pOrg == _orgId && pUrl == _url && pAcct == _account)
@@ -181,23 +192,10 @@ func (c *Control) ConnectionAllowed(_enodeId, _ip string, _port, _raftPort uint1
 }
 
 func (c *Control) TransactionAllowed(_sender common.Address, _target common.Address, _value *big.Int, _gasPrice *big.Int, _gasLimit *big.Int, _payload []byte, transactionType core.TransactionType) error {
-	accessType := core.GetAcctAccess(_sender)
-	switch accessType {
-	case core.ReadOnly:
-		return ptype.ErrNoPermissionForTxn
-
-	case core.Transact:
-		if transactionType == core.ContractDeployTxn {
-			return ptype.ErrNoPermissionForTxn
-		}
-		return nil
-
-	case core.FullAccess, core.ContractDeploy:
-		return nil
-
-	default:
-		return ptype.ErrNoPermissionForTxn
+	if decision := core.ExplainTransaction(_sender, _target, _payload, transactionType); !decision.Allowed {
+		return fmt.Errorf("%w: %s", ptype.ErrNoPermissionForTxn, decision.Reason())
 	}
+	return nil
 }
 
 func (r *Role) RemoveRole(_args ptype.TxArgs) (*types.Transaction, error) {
@@ -274,6 +272,17 @@ func (a *Account) AssignAdminRole(_args ptype.TxArgs) (*types.Transaction, error
 // This is to make sure all Contr instances are ready and initialized
 //
 // Required to be call after standard service start lifecycle
+// EventContracts returns the contracts emitting the permission events with their ABI
+func (i *Init) EventContracts() []ptype.EventContract {
+	return []ptype.EventContract{
+		{Name: "OrgManager", Address: i.Backend.PermConfig.OrgAddress, ABI: pb.OrgManagerABI},
+		{Name: "AcctManager", Address: i.Backend.PermConfig.AccountAddress, ABI: pb.AcctManagerABI},
+		{Name: "NodeManager", Address: i.Backend.PermConfig.NodeAddress, ABI: pb.NodeManagerABI},
+		{Name: "RoleManager", Address: i.Backend.PermConfig.RoleAddress, ABI: pb.RoleManagerABI},
+		{Name: "VoterManager", Address: i.Backend.PermConfig.VoterAddress, ABI: pb.VoterManagerABI},
+	}
+}
+
 func (i *Init) BindContracts() error {
 	log.Debug("permission service: binding contracts")
 	err := i.bindContract()
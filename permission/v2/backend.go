@@ -102,6 +102,44 @@ func (b *Backend) ManageRolePermissions() error {
 	return nil
 }
 
+func (b *Backend) ManageCallPermissions() error {
+	if b.Contr.PermCall == nil {
+		return nil
+	}
+	chCallPermAdded := make(chan *eb.CallPermManagerCallPermissionAdded, 1)
+	chCallPermRevoked := make(chan *eb.CallPermManagerCallPermissionRevoked, 1)
+
+	opts := &bind.WatchOpts{}
+	var blockNumber uint64 = 1
+	opts.Start = &blockNumber
+
+	if _, err := b.Contr.PermCall.CallPermManagerFilterer.WatchCallPermissionAdded(opts, chCallPermAdded); err != nil {
+		return fmt.Errorf("failed WatchCallPermissionAdded: %v", err)
+	}
+
+	if _, err := b.Contr.PermCall.CallPermManagerFilterer.WatchCallPermissionRevoked(opts, chCallPermRevoked); err != nil {
+		return fmt.Errorf("failed WatchCallPermissionRevoked: %v", err)
+	}
+
+	go func() {
+		stopChan, stopSubscription := ptype.SubscribeStopEvent()
+		defer stopSubscription.Unsubscribe()
+		for {
+			select {
+			case evtCallPermAdded := <-chCallPermAdded:
+				core.CallPermissionMap.UpsertCallPermission(evtCallPermAdded.OrgId, evtCallPermAdded.RoleId, evtCallPermAdded.Contract, evtCallPermAdded.Selector, true)
+
+			case evtCallPermRevoked := <-chCallPermRevoked:
+				core.CallPermissionMap.UpsertCallPermission(evtCallPermRevoked.OrgId, evtCallPermRevoked.RoleId, evtCallPermRevoked.Contract, evtCallPermRevoked.Selector, false)
+			case <-stopChan:
+				log.Info("quit call permission contract watch")
+				return
+			}
+		}
+	}()
+	return nil
+}
+
 func (b *Backend) ManageOrgPermissions() error {
 	chPendingApproval := make(chan *eb.OrgManagerOrgPendingApproval, 1)
 	chOrgApproved := make(chan *eb.OrgManagerOrgApproved, 1)
@@ -341,3 +379,22 @@ func (b *Backend) GetControlService(controlBackend ptype.ContractBackend) (ptype
 	}
 	return &Control{Backend: backEnd}, nil
 }
+
+func (b *Backend) GetCallPermissionService(transactOpts *bind.TransactOpts, callPermBackend ptype.ContractBackend) (ptype.CallPermissionService, error) {
+	if callPermBackend.PermConfig.CallPermAddress == (common.Address{}) {
+		return nil, ptype.ErrNoCallPermMgr
+	}
+	var permCall *eb.CallPermManager
+	if err := ptype.BindContract(&permCall, func() (interface{}, error) {
+		return eb.NewCallPermManager(callPermBackend.PermConfig.CallPermAddress, callPermBackend.EthClnt)
+	}); err != nil {
+		return nil, err
+	}
+	return &CallPermission{Session: &eb.CallPermManagerSession{
+		Contract: permCall,
+		CallOpts: bind.CallOpts{
+			Pending: true,
+		},
+		TransactOpts: *transactOpts,
+	}}, nil
+}
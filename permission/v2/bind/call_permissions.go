@@ -0,0 +1,585 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bind
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// CallPermManagerABI is the input ABI used to generate the binding from.
+const CallPermManagerABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"getNumberOfCallPermissions\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_orgId\",\"type\":\"string\"},{\"name\":\"_roleId\",\"type\":\"string\"},{\"name\":\"_contract\",\"type\":\"address\"},{\"name\":\"_selector\",\"type\":\"bytes4\"}],\"name\":\"removeCallPermission\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_cIndex\",\"type\":\"uint256\"}],\"name\":\"getCallPermissionFromIndex\",\"outputs\":[{\"name\":\"orgId\",\"type\":\"string\"},{\"name\":\"roleId\",\"type\":\"string\"},{\"name\":\"contractAddress\",\"type\":\"address\"},{\"name\":\"selector\",\"type\":\"bytes4\"},{\"name\":\"active\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_orgId\",\"type\":\"string\"},{\"name\":\"_roleId\",\"type\":\"string\"},{\"name\":\"_contract\",\"type\":\"address\"},{\"name\":\"_selector\",\"type\":\"bytes4\"}],\"name\":\"addCallPermission\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_permUpgradable\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_orgId\",\"type\":\"string\"},{\"indexed\":false,\"name\":\"_roleId\",\"type\":\"string\"},{\"indexed\":false,\"name\":\"_contract\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_selector\",\"type\":\"bytes4\"}],\"name\":\"CallPermissionAdded\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_orgId\",\"type\":\"string\"},{\"indexed\":false,\"name\":\"_roleId\",\"type\":\"string\"},{\"indexed\":false,\"name\":\"_contract\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_selector\",\"type\":\"bytes4\"}],\"name\":\"CallPermissionRevoked\",\"type\":\"event\"}]"
+
+var CallPermManagerParsedABI, _ = abi.JSON(strings.NewReader(CallPermManagerABI))
+
+// CallPermManager is an auto generated Go binding around an Ethereum contract.
+type CallPermManager struct {
+	CallPermManagerCaller     // Read-only binding to the contract
+	CallPermManagerTransactor // Write-only binding to the contract
+	CallPermManagerFilterer   // Log filterer for contract events
+}
+
+// CallPermManagerCaller is an auto generated read-only Go binding around an Ethereum contract.
+type CallPermManagerCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CallPermManagerTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type CallPermManagerTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CallPermManagerFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type CallPermManagerFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CallPermManagerSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type CallPermManagerSession struct {
+	Contract     *CallPermManager  // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// CallPermManagerCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type CallPermManagerCallerSession struct {
+	Contract *CallPermManagerCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts          // Call options to use throughout this session
+}
+
+// CallPermManagerTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type CallPermManagerTransactorSession struct {
+	Contract     *CallPermManagerTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts          // Transaction auth options to use throughout this session
+}
+
+// CallPermManagerRaw is an auto generated low-level Go binding around an Ethereum contract.
+type CallPermManagerRaw struct {
+	Contract *CallPermManager // Generic contract binding to access the raw methods on
+}
+
+// CallPermManagerCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type CallPermManagerCallerRaw struct {
+	Contract *CallPermManagerCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// CallPermManagerTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type CallPermManagerTransactorRaw struct {
+	Contract *CallPermManagerTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewCallPermManager creates a new instance of CallPermManager, bound to a specific deployed contract.
+func NewCallPermManager(address common.Address, backend bind.ContractBackend) (*CallPermManager, error) {
+	contract, err := bindCallPermManager(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &CallPermManager{CallPermManagerCaller: CallPermManagerCaller{contract: contract}, CallPermManagerTransactor: CallPermManagerTransactor{contract: contract}, CallPermManagerFilterer: CallPermManagerFilterer{contract: contract}}, nil
+}
+
+// NewCallPermManagerCaller creates a new read-only instance of CallPermManager, bound to a specific deployed contract.
+func NewCallPermManagerCaller(address common.Address, caller bind.ContractCaller) (*CallPermManagerCaller, error) {
+	contract, err := bindCallPermManager(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CallPermManagerCaller{contract: contract}, nil
+}
+
+// NewCallPermManagerTransactor creates a new write-only instance of CallPermManager, bound to a specific deployed contract.
+func NewCallPermManagerTransactor(address common.Address, transactor bind.ContractTransactor) (*CallPermManagerTransactor, error) {
+	contract, err := bindCallPermManager(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CallPermManagerTransactor{contract: contract}, nil
+}
+
+// NewCallPermManagerFilterer creates a new log filterer instance of CallPermManager, bound to a specific deployed contract.
+func NewCallPermManagerFilterer(address common.Address, filterer bind.ContractFilterer) (*CallPermManagerFilterer, error) {
+	contract, err := bindCallPermManager(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &CallPermManagerFilterer{contract: contract}, nil
+}
+
+// bindCallPermManager binds a generic wrapper to an already deployed contract.
+func bindCallPermManager(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(CallPermManagerABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CallPermManager *CallPermManagerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _CallPermManager.Contract.CallPermManagerCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CallPermManager *CallPermManagerRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CallPermManager.Contract.CallPermManagerTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CallPermManager *CallPermManagerRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CallPermManager.Contract.CallPermManagerTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CallPermManager *CallPermManagerCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _CallPermManager.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CallPermManager *CallPermManagerTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CallPermManager.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CallPermManager *CallPermManagerTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CallPermManager.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetCallPermissionFromIndex is a free data retrieval call binding the contract method 0xbbb725aa.
+//
+// Solidity: function getCallPermissionFromIndex(uint256 _cIndex) view returns(string orgId, string roleId, address contractAddress, bytes4 selector, bool active)
+func (_CallPermManager *CallPermManagerCaller) GetCallPermissionFromIndex(opts *bind.CallOpts, _cIndex *big.Int) (struct {
+	OrgId           string
+	RoleId          string
+	ContractAddress common.Address
+	Selector        [4]byte
+	Active          bool
+}, error) {
+	var out []interface{}
+	err := _CallPermManager.contract.Call(opts, &out, "getCallPermissionFromIndex", _cIndex)
+
+	outstruct := new(struct {
+		OrgId           string
+		RoleId          string
+		ContractAddress common.Address
+		Selector        [4]byte
+		Active          bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.OrgId = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.RoleId = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.ContractAddress = *abi.ConvertType(out[2], new(common.Address)).(*common.Address)
+	outstruct.Selector = *abi.ConvertType(out[3], new([4]byte)).(*[4]byte)
+	outstruct.Active = *abi.ConvertType(out[4], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// GetCallPermissionFromIndex is a free data retrieval call binding the contract method 0xbbb725aa.
+//
+// Solidity: function getCallPermissionFromIndex(uint256 _cIndex) view returns(string orgId, string roleId, address contractAddress, bytes4 selector, bool active)
+func (_CallPermManager *CallPermManagerSession) GetCallPermissionFromIndex(_cIndex *big.Int) (struct {
+	OrgId           string
+	RoleId          string
+	ContractAddress common.Address
+	Selector        [4]byte
+	Active          bool
+}, error) {
+	return _CallPermManager.Contract.GetCallPermissionFromIndex(&_CallPermManager.CallOpts, _cIndex)
+}
+
+// GetCallPermissionFromIndex is a free data retrieval call binding the contract method 0xbbb725aa.
+//
+// Solidity: function getCallPermissionFromIndex(uint256 _cIndex) view returns(string orgId, string roleId, address contractAddress, bytes4 selector, bool active)
+func (_CallPermManager *CallPermManagerCallerSession) GetCallPermissionFromIndex(_cIndex *big.Int) (struct {
+	OrgId           string
+	RoleId          string
+	ContractAddress common.Address
+	Selector        [4]byte
+	Active          bool
+}, error) {
+	return _CallPermManager.Contract.GetCallPermissionFromIndex(&_CallPermManager.CallOpts, _cIndex)
+}
+
+// GetNumberOfCallPermissions is a free data retrieval call binding the contract method 0xbeda3c24.
+//
+// Solidity: function getNumberOfCallPermissions() view returns(uint256)
+func (_CallPermManager *CallPermManagerCaller) GetNumberOfCallPermissions(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _CallPermManager.contract.Call(opts, &out, "getNumberOfCallPermissions")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetNumberOfCallPermissions is a free data retrieval call binding the contract method 0xbeda3c24.
+//
+// Solidity: function getNumberOfCallPermissions() view returns(uint256)
+func (_CallPermManager *CallPermManagerSession) GetNumberOfCallPermissions() (*big.Int, error) {
+	return _CallPermManager.Contract.GetNumberOfCallPermissions(&_CallPermManager.CallOpts)
+}
+
+// GetNumberOfCallPermissions is a free data retrieval call binding the contract method 0xbeda3c24.
+//
+// Solidity: function getNumberOfCallPermissions() view returns(uint256)
+func (_CallPermManager *CallPermManagerCallerSession) GetNumberOfCallPermissions() (*big.Int, error) {
+	return _CallPermManager.Contract.GetNumberOfCallPermissions(&_CallPermManager.CallOpts)
+}
+
+// AddCallPermission is a paid mutator transaction binding the contract method 0x96f87bc7.
+//
+// Solidity: function addCallPermission(string _orgId, string _roleId, address _contract, bytes4 _selector) returns()
+func (_CallPermManager *CallPermManagerTransactor) AddCallPermission(opts *bind.TransactOpts, _orgId string, _roleId string, _contract common.Address, _selector [4]byte) (*types.Transaction, error) {
+	return _CallPermManager.contract.Transact(opts, "addCallPermission", _orgId, _roleId, _contract, _selector)
+}
+
+// AddCallPermission is a paid mutator transaction binding the contract method 0x96f87bc7.
+//
+// Solidity: function addCallPermission(string _orgId, string _roleId, address _contract, bytes4 _selector) returns()
+func (_CallPermManager *CallPermManagerSession) AddCallPermission(_orgId string, _roleId string, _contract common.Address, _selector [4]byte) (*types.Transaction, error) {
+	return _CallPermManager.Contract.AddCallPermission(&_CallPermManager.TransactOpts, _orgId, _roleId, _contract, _selector)
+}
+
+// AddCallPermission is a paid mutator transaction binding the contract method 0x96f87bc7.
+//
+// Solidity: function addCallPermission(string _orgId, string _roleId, address _contract, bytes4 _selector) returns()
+func (_CallPermManager *CallPermManagerTransactorSession) AddCallPermission(_orgId string, _roleId string, _contract common.Address, _selector [4]byte) (*types.Transaction, error) {
+	return _CallPermManager.Contract.AddCallPermission(&_CallPermManager.TransactOpts, _orgId, _roleId, _contract, _selector)
+}
+
+// RemoveCallPermission is a paid mutator transaction binding the contract method 0xa01f3a55.
+//
+// Solidity: function removeCallPermission(string _orgId, string _roleId, address _contract, bytes4 _selector) returns()
+func (_CallPermManager *CallPermManagerTransactor) RemoveCallPermission(opts *bind.TransactOpts, _orgId string, _roleId string, _contract common.Address, _selector [4]byte) (*types.Transaction, error) {
+	return _CallPermManager.contract.Transact(opts, "removeCallPermission", _orgId, _roleId, _contract, _selector)
+}
+
+// RemoveCallPermission is a paid mutator transaction binding the contract method 0xa01f3a55.
+//
+// Solidity: function removeCallPermission(string _orgId, string _roleId, address _contract, bytes4 _selector) returns()
+func (_CallPermManager *CallPermManagerSession) RemoveCallPermission(_orgId string, _roleId string, _contract common.Address, _selector [4]byte) (*types.Transaction, error) {
+	return _CallPermManager.Contract.RemoveCallPermission(&_CallPermManager.TransactOpts, _orgId, _roleId, _contract, _selector)
+}
+
+// RemoveCallPermission is a paid mutator transaction binding the contract method 0xa01f3a55.
+//
+// Solidity: function removeCallPermission(string _orgId, string _roleId, address _contract, bytes4 _selector) returns()
+func (_CallPermManager *CallPermManagerTransactorSession) RemoveCallPermission(_orgId string, _roleId string, _contract common.Address, _selector [4]byte) (*types.Transaction, error) {
+	return _CallPermManager.Contract.RemoveCallPermission(&_CallPermManager.TransactOpts, _orgId, _roleId, _contract, _selector)
+}
+
+// CallPermManagerCallPermissionAddedIterator is returned from FilterCallPermissionAdded and is used to iterate over the raw logs and unpacked data for CallPermissionAdded events raised by the CallPermManager contract.
+type CallPermManagerCallPermissionAddedIterator struct {
+	Event *CallPermManagerCallPermissionAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CallPermManagerCallPermissionAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CallPermManagerCallPermissionAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CallPermManagerCallPermissionAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CallPermManagerCallPermissionAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CallPermManagerCallPermissionAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CallPermManagerCallPermissionAdded represents a CallPermissionAdded event raised by the CallPermManager contract.
+type CallPermManagerCallPermissionAdded struct {
+	OrgId    string
+	RoleId   string
+	Contract common.Address
+	Selector [4]byte
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterCallPermissionAdded is a free log retrieval operation binding the contract event 0xbeeaa5e3ae472e83d3c7d0bf8ad59120455c73b6cb581e56f487e982b48401c0.
+//
+// Solidity: event CallPermissionAdded(string _orgId, string _roleId, address _contract, bytes4 _selector)
+func (_CallPermManager *CallPermManagerFilterer) FilterCallPermissionAdded(opts *bind.FilterOpts) (*CallPermManagerCallPermissionAddedIterator, error) {
+
+	logs, sub, err := _CallPermManager.contract.FilterLogs(opts, "CallPermissionAdded")
+	if err != nil {
+		return nil, err
+	}
+	return &CallPermManagerCallPermissionAddedIterator{contract: _CallPermManager.contract, event: "CallPermissionAdded", logs: logs, sub: sub}, nil
+}
+
+var CallPermissionAddedTopicHash = "0xbeeaa5e3ae472e83d3c7d0bf8ad59120455c73b6cb581e56f487e982b48401c0"
+
+// WatchCallPermissionAdded is a free log subscription operation binding the contract event 0xbeeaa5e3ae472e83d3c7d0bf8ad59120455c73b6cb581e56f487e982b48401c0.
+//
+// Solidity: event CallPermissionAdded(string _orgId, string _roleId, address _contract, bytes4 _selector)
+func (_CallPermManager *CallPermManagerFilterer) WatchCallPermissionAdded(opts *bind.WatchOpts, sink chan<- *CallPermManagerCallPermissionAdded) (event.Subscription, error) {
+
+	logs, sub, err := _CallPermManager.contract.WatchLogs(opts, "CallPermissionAdded")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CallPermManagerCallPermissionAdded)
+				if err := _CallPermManager.contract.UnpackLog(event, "CallPermissionAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCallPermissionAdded is a log parse operation binding the contract event 0xbeeaa5e3ae472e83d3c7d0bf8ad59120455c73b6cb581e56f487e982b48401c0.
+//
+// Solidity: event CallPermissionAdded(string _orgId, string _roleId, address _contract, bytes4 _selector)
+func (_CallPermManager *CallPermManagerFilterer) ParseCallPermissionAdded(log types.Log) (*CallPermManagerCallPermissionAdded, error) {
+	event := new(CallPermManagerCallPermissionAdded)
+	if err := _CallPermManager.contract.UnpackLog(event, "CallPermissionAdded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// CallPermManagerCallPermissionRevokedIterator is returned from FilterCallPermissionRevoked and is used to iterate over the raw logs and unpacked data for CallPermissionRevoked events raised by the CallPermManager contract.
+type CallPermManagerCallPermissionRevokedIterator struct {
+	Event *CallPermManagerCallPermissionRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CallPermManagerCallPermissionRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CallPermManagerCallPermissionRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CallPermManagerCallPermissionRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CallPermManagerCallPermissionRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CallPermManagerCallPermissionRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CallPermManagerCallPermissionRevoked represents a CallPermissionRevoked event raised by the CallPermManager contract.
+type CallPermManagerCallPermissionRevoked struct {
+	OrgId    string
+	RoleId   string
+	Contract common.Address
+	Selector [4]byte
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterCallPermissionRevoked is a free log retrieval operation binding the contract event 0xd283a5f0deb304da05d3252d8e96ce913416c55228516be6eb45b94dea3574d9.
+//
+// Solidity: event CallPermissionRevoked(string _orgId, string _roleId, address _contract, bytes4 _selector)
+func (_CallPermManager *CallPermManagerFilterer) FilterCallPermissionRevoked(opts *bind.FilterOpts) (*CallPermManagerCallPermissionRevokedIterator, error) {
+
+	logs, sub, err := _CallPermManager.contract.FilterLogs(opts, "CallPermissionRevoked")
+	if err != nil {
+		return nil, err
+	}
+	return &CallPermManagerCallPermissionRevokedIterator{contract: _CallPermManager.contract, event: "CallPermissionRevoked", logs: logs, sub: sub}, nil
+}
+
+var CallPermissionRevokedTopicHash = "0xd283a5f0deb304da05d3252d8e96ce913416c55228516be6eb45b94dea3574d9"
+
+// WatchCallPermissionRevoked is a free log subscription operation binding the contract event 0xd283a5f0deb304da05d3252d8e96ce913416c55228516be6eb45b94dea3574d9.
+//
+// Solidity: event CallPermissionRevoked(string _orgId, string _roleId, address _contract, bytes4 _selector)
+func (_CallPermManager *CallPermManagerFilterer) WatchCallPermissionRevoked(opts *bind.WatchOpts, sink chan<- *CallPermManagerCallPermissionRevoked) (event.Subscription, error) {
+
+	logs, sub, err := _CallPermManager.contract.WatchLogs(opts, "CallPermissionRevoked")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CallPermManagerCallPermissionRevoked)
+				if err := _CallPermManager.contract.UnpackLog(event, "CallPermissionRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCallPermissionRevoked is a log parse operation binding the contract event 0xd283a5f0deb304da05d3252d8e96ce913416c55228516be6eb45b94dea3574d9.
+//
+// Solidity: event CallPermissionRevoked(string _orgId, string _roleId, address _contract, bytes4 _selector)
+func (_CallPermManager *CallPermManagerFilterer) ParseCallPermissionRevoked(log types.Log) (*CallPermManagerCallPermissionRevoked, error) {
+	event := new(CallPermManagerCallPermissionRevoked)
+	if err := _CallPermManager.contract.UnpackLog(event, "CallPermissionRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -45,6 +45,10 @@ type Node struct {
 	Backend *PermissionModelV2
 }
 
+type CallPermission struct {
+	Session *binding.CallPermManagerSession
+}
+
 type Init struct {
 	Backend ptype.ContractBackend
 	//binding contracts
@@ -54,6 +58,7 @@ type Init struct {
 	PermAcct   *binding.AcctManager
 	PermRole   *binding.RoleManager
 	PermOrg    *binding.OrgManager
+	PermCall   *binding.CallPermManager // nil if call permissions are not configured
 	//sessions
 	PermInterfSession *binding.PermInterfaceSession
 	permOrgSession    *binding.OrgManagerSession
@@ -188,6 +193,32 @@ func (i *Init) GetOrgDetails(_orgId string) (string, string, string, *big.Int, *
 	return i.permOrgSession.GetOrgDetails(_orgId)
 }
 
+// SetCallBlock makes the getters read the state of the contracts at the given block, or their
+// pending state if nil. It must be called after BindContracts.
+func (i *Init) SetCallBlock(blockNumber *big.Int) {
+	opts := bind.CallOpts{Pending: blockNumber == nil, BlockNumber: blockNumber}
+	i.PermInterfSession.CallOpts = opts
+	i.permOrgSession.CallOpts = opts
+	i.permNodeSession.CallOpts = opts
+	i.permRoleSession.CallOpts = opts
+	i.permAcctSession.CallOpts = opts
+}
+
+// EventContracts returns the contracts emitting the permission events with their ABI
+func (i *Init) EventContracts() []ptype.EventContract {
+	contracts := []ptype.EventContract{
+		{Name: "OrgManager", Address: i.Backend.PermConfig.OrgAddress, ABI: binding.OrgManagerABI},
+		{Name: "AcctManager", Address: i.Backend.PermConfig.AccountAddress, ABI: binding.AcctManagerABI},
+		{Name: "NodeManager", Address: i.Backend.PermConfig.NodeAddress, ABI: binding.NodeManagerABI},
+		{Name: "RoleManager", Address: i.Backend.PermConfig.RoleAddress, ABI: binding.RoleManagerABI},
+		{Name: "VoterManager", Address: i.Backend.PermConfig.VoterAddress, ABI: binding.VoterManagerABI},
+	}
+	if i.Backend.PermConfig.CallPermAddress != (common.Address{}) {
+		contracts = append(contracts, ptype.EventContract{Name: "CallPermManager", Address: i.Backend.PermConfig.CallPermAddress, ABI: binding.CallPermManagerABI})
+	}
+	return contracts
+}
+
 // This is to make sure all contract instances are ready and initialized
 //
 // Required to be call after standard service start lifecycle
@@ -236,7 +267,9 @@ func (c *Control) TransactionAllowed(_sender common.Address, _target common.Addr
 	if allowed, err := c.Backend.PermInterfSession.TransactionAllowed(_sender, _target, _value, _gasPrice, _gasLimit, _payload); err != nil {
 		return err
 	} else if !allowed {
-		return ptype.ErrNoPermissionForTxn
+		decision := core.ExplainTransaction(_sender, _target, _payload, _transactionType)
+		decision.Conclude(false)
+		return fmt.Errorf("%w: %s", ptype.ErrNoPermissionForTxn, decision.Reason())
 	}
 	return nil
 }
@@ -252,6 +285,14 @@ func (r *Role) AddNewRole(_args ptype.TxArgs) (*types.Transaction, error) {
 	return r.Backend.PermInterfSession.AddNewRole(_args.RoleId, _args.OrgId, big.NewInt(int64(_args.AccessType)), _args.IsVoter, _args.IsAdmin)
 }
 
+func (c *CallPermission) AddCallPermission(_args ptype.TxArgs) (*types.Transaction, error) {
+	return c.Session.AddCallPermission(_args.OrgId, _args.RoleId, _args.Contract, _args.Selector)
+}
+
+func (c *CallPermission) RemoveCallPermission(_args ptype.TxArgs) (*types.Transaction, error) {
+	return c.Session.RemoveCallPermission(_args.OrgId, _args.RoleId, _args.Contract, _args.Selector)
+}
+
 func (o *Org) ApproveOrgStatus(_args ptype.TxArgs) (*types.Transaction, error) {
 	return o.Backend.PermInterfSession.ApproveOrgStatus(_args.OrgId, big.NewInt(int64(_args.Action)))
 }
@@ -348,6 +389,13 @@ func (i *Init) bindContract() error {
 	}); err != nil {
 		return err
 	}
+	if i.Backend.PermConfig.CallPermAddress != (common.Address{}) {
+		if err := ptype.BindContract(&i.PermCall, func() (interface{}, error) {
+			return binding.NewCallPermManager(i.Backend.PermConfig.CallPermAddress, i.Backend.EthClnt)
+		}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,110 @@
+pragma solidity ^0.5.3;
+
+import "./PermissionsUpgradable.sol";
+import "./PermissionsImplementation.sol";
+/** @title Call permission manager contract
+  * @notice This contract holds the contract call allowlists of the roles.
+    A role with active call permissions can only call the listed contracts,
+    or the listed functions of them. A call permission with a zero selector
+    permits every function of the contract. Roles without call permissions
+    are only limited by their base access. call permissions can be managed
+    by the org admins of the org the role belongs to. there are few view
+    functions exposed as public and can be called directly. these are
+    invoked by quorum for populating permissions data in cache
+  */
+contract CallPermissionManager {
+    PermissionsUpgradable private permUpgradable;
+
+    struct CallPermissionDetails {
+        string orgId;
+        string roleId;
+        address contractAddress;
+        bytes4 selector;
+        bool active;
+    }
+
+    CallPermissionDetails[] private callPermissionList;
+    mapping(bytes32 => uint256) private callPermissionIndex;
+
+    event CallPermissionAdded(string _orgId, string _roleId, address _contract,
+        bytes4 _selector);
+    event CallPermissionRevoked(string _orgId, string _roleId, address _contract,
+        bytes4 _selector);
+
+    /** @notice confirms that the caller is an org admin of the org
+      * @param _orgId - org id
+     */
+    modifier orgAdmin(string memory _orgId) {
+        require(PermissionsImplementation(permUpgradable.getPermImpl()).isOrgAdmin(msg.sender, _orgId),
+            "account is not a org admin account");
+        _;
+    }
+
+    /** @notice constructor. sets the permissions upgradable address
+      */
+    constructor (address _permUpgradable) public {
+        permUpgradable = PermissionsUpgradable(_permUpgradable);
+    }
+
+    /** @notice function to permit a role to call a contract function
+      * @param _orgId - org id to which the role belongs
+      * @param _roleId - role id
+      * @param _contract - address of the contract
+      * @param _selector - 4-byte selector of the function, zero for every
+               function of the contract
+      */
+    function addCallPermission(string calldata _orgId, string calldata _roleId,
+        address _contract, bytes4 _selector) external orgAdmin(_orgId) {
+        require(_contract != address(0), "invalid contract address");
+        bytes32 key = keccak256(abi.encode(_orgId, _roleId, _contract, _selector));
+        if (callPermissionIndex[key] == 0) {
+            callPermissionList.push(CallPermissionDetails(_orgId, _roleId, _contract,
+                _selector, true));
+            callPermissionIndex[key] = callPermissionList.length;
+        } else {
+            uint256 cIndex = callPermissionIndex[key] - 1;
+            require(!callPermissionList[cIndex].active, "call permission exists");
+            callPermissionList[cIndex].active = true;
+        }
+        emit CallPermissionAdded(_orgId, _roleId, _contract, _selector);
+    }
+
+    /** @notice function to revoke a call permission of a role
+      * @param _orgId - org id to which the role belongs
+      * @param _roleId - role id
+      * @param _contract - address of the contract
+      * @param _selector - 4-byte selector of the function
+      */
+    function removeCallPermission(string calldata _orgId, string calldata _roleId,
+        address _contract, bytes4 _selector) external orgAdmin(_orgId) {
+        bytes32 key = keccak256(abi.encode(_orgId, _roleId, _contract, _selector));
+        require(callPermissionIndex[key] != 0, "call permission does not exist");
+        uint256 cIndex = callPermissionIndex[key] - 1;
+        require(callPermissionList[cIndex].active, "call permission is not active");
+        callPermissionList[cIndex].active = false;
+        emit CallPermissionRevoked(_orgId, _roleId, _contract, _selector);
+    }
+
+    /** @notice returns the call permission details for a passed index
+      * @param _cIndex - call permission index
+      * @return org id
+      * @return role id
+      * @return contract address
+      * @return function selector
+      * @return bool to indicate if the call permission is active
+      */
+    function getCallPermissionFromIndex(uint256 _cIndex) external view returns
+    (string memory orgId, string memory roleId, address contractAddress,
+        bytes4 selector, bool active) {
+        return (callPermissionList[_cIndex].orgId, callPermissionList[_cIndex].roleId,
+        callPermissionList[_cIndex].contractAddress, callPermissionList[_cIndex].selector,
+        callPermissionList[_cIndex].active);
+    }
+
+    /** @notice returns the total number of call permissions in the network
+      * @return total number of call permissions
+      */
+    function getNumberOfCallPermissions() external view returns (uint256) {
+        return callPermissionList.length;
+    }
+}
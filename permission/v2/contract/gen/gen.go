@@ -7,6 +7,7 @@
 // 2. abigen (make all from root)
 
 //go:generate solc --abi --bin -o . --overwrite ../AccountManager.sol
+//go:generate solc --abi --bin -o . --overwrite ../CallPermissionManager.sol
 //go:generate solc --abi --bin -o . --overwrite ../NodeManager.sol
 //go:generate solc --abi --bin -o . --overwrite ../OrgManager.sol
 //go:generate solc --abi --bin -o . --overwrite ../PermissionsImplementation.sol
@@ -16,6 +17,7 @@
 //go:generate solc --abi --bin -o . --overwrite ../VoterManager.sol
 
 //go:generate abigen -pkg bind -abi  ./AccountManager.abi            -bin  ./AccountManager.bin            -type AcctManager   -out ../../bind/accounts.go
+//go:generate abigen -pkg bind -abi  ./CallPermissionManager.abi     -bin  ./CallPermissionManager.bin     -type CallPermManager -out ../../bind/call_permissions.go
 //go:generate abigen -pkg bind -abi  ./NodeManager.abi               -bin  ./NodeManager.bin               -type NodeManager   -out ../../bind/nodes.go
 //go:generate abigen -pkg bind -abi  ./OrgManager.abi                -bin  ./OrgManager.bin                -type OrgManager    -out ../../bind/org.go
 //go:generate abigen -pkg bind -abi  ./PermissionsImplementation.abi -bin  ./PermissionsImplementation.bin -type PermImpl      -out ../../bind/permission_impl.go
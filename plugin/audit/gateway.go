@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// recordMethod is the gRPC method implemented by the audit plugin. It receives an RPC audit
+// record as the JSON document written to the RPC audit log.
+const recordMethod = "/proto.PluginAuditSink/Record"
+
+type AuditSinkClient interface {
+	Record(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type auditSinkClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func (c *auditSinkClient) Record(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	if err := c.cc.Invoke(ctx, recordMethod, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type PluginGateway struct {
+	client AuditSinkClient
+}
+
+func (p *PluginGateway) Record(ctx context.Context, record *rpc.AuditRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = p.client.Record(ctx, wrapperspb.Bytes(raw))
+	return err
+}
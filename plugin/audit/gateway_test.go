@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type stubAuditSinkClient struct {
+	mu       sync.Mutex
+	received [][]byte
+	err      error
+}
+
+func (c *stubAuditSinkClient) Record(_ context.Context, in *wrapperspb.BytesValue, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.received = append(c.received, in.GetValue())
+	return &emptypb.Empty{}, nil
+}
+
+func TestPluginGateway_Record(t *testing.T) {
+	client := &stubAuditSinkClient{}
+	testObject := &PluginGateway{client: client}
+
+	err := testObject.Record(context.Background(), &rpc.AuditRecord{Event: rpc.AuditEventAuthorization, Method: "eth_call", PSI: "PS1", Decision: rpc.AuditDecisionDenied, Reason: "access denied"})
+
+	assert.NoError(t, err)
+	if assert.Len(t, client.received, 1) {
+		var record rpc.AuditRecord
+		assert.NoError(t, json.Unmarshal(client.received[0], &record))
+		assert.Equal(t, "eth_call", record.Method)
+		assert.Equal(t, "PS1", record.PSI.String())
+		assert.Equal(t, rpc.AuditDecisionDenied, record.Decision)
+		assert.Equal(t, "access denied", record.Reason)
+	}
+}
+
+func TestNewRPCAuditSink_whenPluginFails(t *testing.T) {
+	client := &stubAuditSinkClient{err: errors.New("unavailable")}
+	sink := NewRPCAuditSink(&PluginGateway{client: client})
+
+	sink.Record(&rpc.AuditRecord{Method: "eth_call"})
+	sink.Close()
+
+	assert.Empty(t, client.received)
+}
+
+func TestNewRPCAuditSink(t *testing.T) {
+	client := &stubAuditSinkClient{}
+	sink := NewRPCAuditSink(&ReloadablePluginAuditSink{
+		DeferFunc: func() (PluginAuditSink, error) {
+			return &PluginGateway{client: client}, nil
+		},
+	})
+
+	sink.Record(&rpc.AuditRecord{Method: "eth_call"})
+	sink.Record(&rpc.AuditRecord{Method: "eth_sendTransaction"})
+	sink.Close()
+
+	assert.Len(t, client.received, 2)
+}
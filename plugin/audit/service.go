@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type PluginAuditSink interface {
+	Record(ctx context.Context, record *rpc.AuditRecord) error
+}
+
+type PluginAuditSinkDeferFunc func() (PluginAuditSink, error)
+
+type ReloadablePluginAuditSink struct {
+	DeferFunc PluginAuditSinkDeferFunc
+}
+
+func (d *ReloadablePluginAuditSink) Record(ctx context.Context, record *rpc.AuditRecord) error {
+	p, err := d.DeferFunc()
+	if err != nil {
+		return err
+	}
+	return p.Record(ctx, record)
+}
+
+// NewRPCAuditSink passes the RPC audit records on to the plugin without blocking the RPC calls,
+// see rpc.AsyncAuditSink. Records the plugin fails to receive are logged and dropped.
+func NewRPCAuditSink(p PluginAuditSink) *rpc.AsyncAuditSink {
+	return rpc.NewAsyncAuditSink(func(record *rpc.AuditRecord) {
+		if err := p.Record(context.Background(), record); err != nil {
+			log.Error("Unable to send RPC audit record to the audit plugin", "method", record.Method, "decision", record.Decision, "err", err)
+		}
+	}, rpc.DefaultAuditBufferSize)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/plugin/account"
+	"github.com/ethereum/go-ethereum/plugin/audit"
 	"github.com/ethereum/go-ethereum/plugin/helloworld"
 	"github.com/ethereum/go-ethereum/plugin/qlight"
 	"github.com/ethereum/go-ethereum/plugin/security"
@@ -118,6 +119,23 @@ func (p *QLightTokenManagerPluginTemplate) ManagedPlugin() managedPlugin {
 	return p
 }
 
+// a template that returns the plugin receiving the RPC audit records
+type AuditPluginTemplate struct {
+	*basePlugin
+}
+
+func (p *AuditPluginTemplate) Get() (audit.PluginAuditSink, error) {
+	return &audit.ReloadablePluginAuditSink{
+		DeferFunc: func() (audit.PluginAuditSink, error) {
+			raw, err := p.dispense(audit.ConnectorName)
+			if err != nil {
+				return nil, err
+			}
+			return raw.(audit.PluginAuditSink), nil
+		},
+	}, nil
+}
+
 type QLightTokenManagerPluginTemplateInterface interface {
 	Get() (qlight.PluginTokenManager, error)
 	Start() (err error)
@@ -0,0 +1,424 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultJWTAuthoritiesClaim is the claim holding the scopes granted by an access token
+	DefaultJWTAuthoritiesClaim = "scope"
+	// schemeRPC is the scheme of the scopes granting access to RPC APIs, e.g.: rpc://eth_* or rpc://admin_nodeInfo
+	schemeRPC = "rpc"
+)
+
+var (
+	errJWTMalformed        = errors.New("malformed access token")
+	errJWTInvalidSignature = errors.New("invalid access token signature")
+)
+
+// JWTAuthenticationConfig configures the built-in authentication manager, which validates JWT access
+// tokens without the need of a security plugin
+type JWTAuthenticationConfig struct {
+	Issuer           string        `toml:",omitempty"` // expected "iss" claim, not checked if empty
+	Audience         string        `toml:",omitempty"` // value the "aud" claim must contain, not checked if empty
+	JWKSFile         string        `toml:",omitempty"` // JSON Web Key Set verifying RS*, PS* and ES* signatures
+	HMACSecretFile   string        `toml:",omitempty"` // secret verifying HS* signatures
+	AuthoritiesClaim string        `toml:",omitempty"` // claim holding the granted scopes, defaults to DefaultJWTAuthoritiesClaim
+	ClockSkew        time.Duration `toml:",omitempty"` // tolerance when checking the "exp" and "nbf" claims
+}
+
+// JWTAuthenticationManager is an AuthenticationManager validating JWT access tokens signed with a
+// shared HMAC secret or with one of the keys of a local JSON Web Key Set.
+//
+// The scopes found in the configured claim are mapped to the granted authorities: rpc://<service>_<method>
+// scopes grant access to RPC APIs and any other scope, e.g.: psi://..., is passed as is so it can be
+// evaluated by multitenancy.
+type JWTAuthenticationManager struct {
+	config     JWTAuthenticationConfig
+	hmacSecret []byte
+	keys       []*jsonWebKey
+
+	now func() time.Time
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+
+	publicKey crypto.PublicKey
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// NewJWTAuthenticationManager loads the secret and keys referenced by config
+func NewJWTAuthenticationManager(config *JWTAuthenticationConfig) (*JWTAuthenticationManager, error) {
+	am := &JWTAuthenticationManager{config: *config, now: time.Now}
+	if am.config.AuthoritiesClaim == "" {
+		am.config.AuthoritiesClaim = DefaultJWTAuthoritiesClaim
+	}
+	if config.HMACSecretFile == "" && config.JWKSFile == "" {
+		return nil, errors.New("JWT authentication requires a JWKS file or an HMAC secret file")
+	}
+	if config.HMACSecretFile != "" {
+		secret, err := ioutil.ReadFile(config.HMACSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read HMAC secret: %v", err)
+		}
+		if am.hmacSecret = bytes.TrimSpace(secret); len(am.hmacSecret) == 0 {
+			return nil, fmt.Errorf("HMAC secret file %s is empty", config.HMACSecretFile)
+		}
+	}
+	if config.JWKSFile != "" {
+		blob, err := ioutil.ReadFile(config.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read JWKS: %v", err)
+		}
+		if am.keys, err = parseJWKS(blob); err != nil {
+			return nil, fmt.Errorf("invalid JWKS %s: %v", config.JWKSFile, err)
+		}
+	}
+	return am, nil
+}
+
+func parseJWKS(blob []byte) ([]*jsonWebKey, error) {
+	var jwks struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(blob, &jwks); err != nil {
+		return nil, err
+	}
+	keys := make([]*jsonWebKey, 0, len(jwks.Keys))
+	for i, key := range jwks.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		var err error
+		switch key.Kty {
+		case "RSA":
+			key.publicKey, err = key.rsaPublicKey()
+		case "EC":
+			key.publicKey, err = key.ecdsaPublicKey()
+		default:
+			log.Warn("Ignoring JWKS key with unsupported type", "kid", key.Kid, "kty", key.Kty)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %d (kid=%s): %v", i, key.Kid, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys found")
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %v", err)
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	curve := curveOf(k.Crv)
+	if curve == nil {
+		return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+	}
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x coordinate: %v", err)
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y coordinate: %v", err)
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+var esCurves = map[crypto.Hash]string{crypto.SHA256: "P-256", crypto.SHA384: "P-384", crypto.SHA512: "P-521"}
+
+func curveOf(crv string) elliptic.Curve {
+	switch crv {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	}
+	return nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// Authenticate validates the signature and the claims of a JWT access token, optionally prefixed
+// with the Bearer scheme, and returns the authorities it grants
+func (am *JWTAuthenticationManager) Authenticate(_ context.Context, token string) (*proto.PreAuthenticatedAuthenticationToken, error) {
+	rawToken := strings.TrimSpace(token)
+	if len(rawToken) > 7 && strings.EqualFold(rawToken[:7], "bearer ") {
+		rawToken = strings.TrimSpace(rawToken[7:])
+	}
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, errJWTMalformed
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errJWTMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errJWTMalformed
+	}
+	if err := am.verifySignature(header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errJWTMalformed
+	}
+	expiredAt, err := am.verifyClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PreAuthenticatedAuthenticationToken{
+		RawToken:    []byte(token),
+		ExpiredAt:   timestamppb.New(expiredAt),
+		Authorities: toGrantedAuthorities(claims[am.config.AuthoritiesClaim]),
+	}, nil
+}
+
+// IsEnabled always returns true
+func (am *JWTAuthenticationManager) IsEnabled(_ context.Context) (bool, error) {
+	return true, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	blob, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(blob))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func (am *JWTAuthenticationManager) verifySignature(header jwtHeader, signed, signature []byte) error {
+	if len(header.Alg) != 5 {
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	var hash crypto.Hash
+	switch header.Alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	family := header.Alg[:2]
+	if family == "HS" {
+		if am.hmacSecret == nil {
+			return fmt.Errorf("signing algorithm %s is not accepted", header.Alg)
+		}
+		mac := hmac.New(hash.New, am.hmacSecret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errJWTInvalidSignature
+		}
+		return nil
+	}
+	if family != "RS" && family != "PS" && family != "ES" {
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+	candidates := 0
+	for _, key := range am.keys {
+		if (header.Kid != "" && key.Kid != header.Kid) || (key.Alg != "" && key.Alg != header.Alg) {
+			continue
+		}
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			if family == "RS" {
+				candidates++
+				if rsa.VerifyPKCS1v15(publicKey, hash, digest, signature) == nil {
+					return nil
+				}
+			} else if family == "PS" {
+				candidates++
+				if rsa.VerifyPSS(publicKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil {
+					return nil
+				}
+			}
+		case *ecdsa.PublicKey:
+			// ES256, ES384 and ES512 are bound to the P-256, P-384 and P-521 curves respectively
+			if family != "ES" || publicKey.Curve != curveOf(esCurves[hash]) {
+				continue
+			}
+			candidates++
+			size := (publicKey.Curve.Params().BitSize + 7) / 8
+			if len(signature) == 2*size {
+				r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+				if ecdsa.Verify(publicKey, digest, r, s) {
+					return nil
+				}
+			}
+		}
+	}
+	if candidates == 0 {
+		return fmt.Errorf("no key found to verify the access token signature (alg=%s kid=%s)", header.Alg, header.Kid)
+	}
+	return errJWTInvalidSignature
+}
+
+// verifyClaims checks the registered claims and returns the expiry of the token
+func (am *JWTAuthenticationManager) verifyClaims(claims map[string]interface{}) (time.Time, error) {
+	now := am.now()
+	exp, err := numericDate(claims, "exp")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if exp == nil {
+		return time.Time{}, errors.New("access token has no expiry")
+	}
+	if !now.Before(exp.Add(am.config.ClockSkew)) {
+		return time.Time{}, errors.New("access token expired")
+	}
+	nbf, err := numericDate(claims, "nbf")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if nbf != nil && now.Add(am.config.ClockSkew).Before(*nbf) {
+		return time.Time{}, errors.New("access token is not valid yet")
+	}
+	if am.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != am.config.Issuer {
+			return time.Time{}, fmt.Errorf("access token issuer %q is not accepted", iss)
+		}
+	}
+	if am.config.Audience != "" && !containsAudience(claims["aud"], am.config.Audience) {
+		return time.Time{}, errors.New("access token is not intended for this audience")
+	}
+	return exp.Add(am.config.ClockSkew), nil
+}
+
+func numericDate(claims map[string]interface{}, name string) (*time.Time, error) {
+	v, found := claims[name]
+	if !found {
+		return nil, nil
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s claim", name)
+	}
+	seconds, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s claim", name)
+	}
+	t := time.Unix(int64(seconds), 0)
+	return &t, nil
+}
+
+func containsAudience(aud interface{}, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toGrantedAuthorities maps the scopes of a claim, either a space-delimited string or an array of strings,
+// to granted authorities
+func toGrantedAuthorities(claim interface{}) []*proto.GrantedAuthority {
+	var scopes []string
+	switch v := claim.(type) {
+	case string:
+		scopes = strings.Fields(v)
+	case []interface{}:
+		for _, s := range v {
+			if scope, ok := s.(string); ok {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	authorities := make([]*proto.GrantedAuthority, 0, len(scopes))
+	for _, scope := range scopes {
+		authority := &proto.GrantedAuthority{Raw: scope}
+		if u, err := url.Parse(scope); err == nil && u.Scheme == schemeRPC {
+			// rpc://<service>_<method>, either can be a wildcard
+			api := u.Host + u.Path
+			if api == "*" {
+				authority.Service, authority.Method = "*", "*"
+			} else if elem := strings.SplitN(api, "_", 2); len(elem) == 2 {
+				authority.Service, authority.Method = elem[0], elem[1]
+			} else {
+				log.Debug("Ignoring malformed RPC scope", "scope", scope)
+				continue
+			}
+		}
+		authorities = append(authorities, authority)
+	}
+	return authorities
+}
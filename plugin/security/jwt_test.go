@@ -0,0 +1,182 @@
+package security
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+var (
+	jwtTestRSAKey, _   = rsa.GenerateKey(rand.Reader, 2048)
+	jwtTestECDSAKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwtTestSecret      = []byte("arbitrary secret")
+)
+
+func signJWT(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	var signature []byte
+	switch alg {
+	case "HS256":
+		mac := hmac.New(crypto.SHA256.New, jwtTestSecret)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case "RS256":
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, jwtTestRSAKey, crypto.SHA256, digest.Sum(nil)); err != nil {
+			t.Fatal(err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, jwtTestECDSAKey, digest.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestJWTAuthenticationManager(t *testing.T) *JWTAuthenticationManager {
+	dir := t.TempDir()
+	encode := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encode(jwtTestRSAKey.N), "e": encode(big.NewInt(int64(jwtTestRSAKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encode(jwtTestECDSAKey.X), "y": encode(jwtTestECDSAKey.Y)},
+			{"kty": "RSA", "kid": "enc", "use": "enc"},
+		},
+	})
+	config := &JWTAuthenticationConfig{
+		Issuer:         "https://issuer",
+		Audience:       "quorum",
+		JWKSFile:       filepath.Join(dir, "jwks.json"),
+		HMACSecretFile: filepath.Join(dir, "secret"),
+	}
+	if err := ioutil.WriteFile(config.JWKSFile, jwks, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(config.HMACSecretFile, append(jwtTestSecret, '\n'), 0600); err != nil {
+		t.Fatal(err)
+	}
+	am, err := NewJWTAuthenticationManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return am
+}
+
+func validJWTClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":   "https://issuer",
+		"aud":   []string{"other", "quorum"},
+		"sub":   "tenant",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "rpc://eth_* rpc://rpc_modules psi://PS1?self.eoa=0x0",
+	}
+}
+
+func TestJWTAuthenticationManager_whenTypical(t *testing.T) {
+	assert := testifyassert.New(t)
+	am := newTestJWTAuthenticationManager(t)
+
+	for _, test := range []struct{ alg, kid string }{{"HS256", ""}, {"RS256", "rsa"}, {"RS256", ""}, {"ES256", "ec"}} {
+		token := "Bearer " + signJWT(t, test.alg, test.kid, validJWTClaims())
+
+		authToken, err := am.Authenticate(context.Background(), token)
+
+		if !assert.NoError(err, test.alg) {
+			continue
+		}
+		assert.Equal([]byte(token), authToken.RawToken)
+		assert.True(authToken.ExpiredAt.AsTime().After(time.Now()))
+		assert.Equal([]*proto.GrantedAuthority{
+			{Service: "eth", Method: "*", Raw: "rpc://eth_*"},
+			{Service: "rpc", Method: "modules", Raw: "rpc://rpc_modules"},
+			{Raw: "psi://PS1?self.eoa=0x0"},
+		}, authToken.Authorities)
+	}
+}
+
+func TestJWTAuthenticationManager_whenAuthoritiesClaimIsAnArray(t *testing.T) {
+	am := newTestJWTAuthenticationManager(t)
+	am.config.AuthoritiesClaim = "scp"
+	claims := validJWTClaims()
+	claims["scp"] = []string{"rpc://*", "psi://PS2"}
+
+	authToken, err := am.Authenticate(context.Background(), signJWT(t, "HS256", "", claims))
+
+	testifyassert.NoError(t, err)
+	testifyassert.Equal(t, []*proto.GrantedAuthority{
+		{Service: "*", Method: "*", Raw: "rpc://*"},
+		{Raw: "psi://PS2"},
+	}, authToken.Authorities)
+}
+
+func TestJWTAuthenticationManager_whenInvalid(t *testing.T) {
+	am := newTestJWTAuthenticationManager(t)
+	withClaim := func(name string, value interface{}) map[string]interface{} {
+		claims := validJWTClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	tampered := signJWT(t, "RS256", "rsa", validJWTClaims())
+	tampered = tampered[:len(tampered)-4] + "AAAA"
+	unsigned := signJWT(t, "HS256", "", validJWTClaims())
+	unsigned = unsigned[:len(unsigned)-43]
+
+	for token, expectedErr := range map[string]string{
+		"not a token": "malformed access token",
+		tampered:      "invalid access token signature",
+		signJWT(t, "RS256", "unknown", validJWTClaims()): "no key found to verify the access token signature (alg=RS256 kid=unknown)",
+		signJWT(t, "ES256", "rsa", validJWTClaims()):     "no key found to verify the access token signature (alg=ES256 kid=rsa)",
+		signJWT(t, "none", "", validJWTClaims()):         "unsupported signing algorithm \"none\"",
+		unsigned:                                         "invalid access token signature",
+		signJWT(t, "HS256", "", withClaim("exp", time.Now().Add(-time.Minute).Unix())): "access token expired",
+		signJWT(t, "HS256", "", withClaim("exp", nil)):                                 "access token has no expiry",
+		signJWT(t, "HS256", "", withClaim("nbf", time.Now().Add(time.Hour).Unix())):    "access token is not valid yet",
+		signJWT(t, "HS256", "", withClaim("iss", "https://other")):                     "access token issuer \"https://other\" is not accepted",
+		signJWT(t, "HS256", "", withClaim("aud", "other")):                             "access token is not intended for this audience",
+	} {
+		_, err := am.Authenticate(context.Background(), token)
+
+		testifyassert.EqualError(t, err, expectedErr, token)
+	}
+}
+
+func TestJWTAuthenticationManager_whenHMACSecretIsNotConfigured(t *testing.T) {
+	am := newTestJWTAuthenticationManager(t)
+	am.hmacSecret = nil
+
+	_, err := am.Authenticate(context.Background(), signJWT(t, "HS256", "", validJWTClaims()))
+
+	testifyassert.EqualError(t, err, "signing algorithm HS256 is not accepted")
+}
+
+func TestNewJWTAuthenticationManager_whenNoKeys(t *testing.T) {
+	_, err := NewJWTAuthenticationManager(&JWTAuthenticationConfig{Issuer: "https://issuer"})
+
+	testifyassert.EqualError(t, err, "JWT authentication requires a JWKS file or an HMAC secret file")
+}
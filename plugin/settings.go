@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/plugin/account"
+	"github.com/ethereum/go-ethereum/plugin/audit"
 	"github.com/ethereum/go-ethereum/plugin/helloworld"
 	"github.com/ethereum/go-ethereum/plugin/qlight"
 	"github.com/ethereum/go-ethereum/plugin/security"
@@ -25,6 +26,7 @@ const (
 	SecurityPluginInterfaceName           = PluginInterfaceName("security")
 	AccountPluginInterfaceName            = PluginInterfaceName("account")
 	QLightTokenManagerPluginInterfaceName = PluginInterfaceName("qlighttokenmanager")
+	AuditPluginInterfaceName              = PluginInterfaceName("audit")
 )
 
 var (
@@ -83,6 +85,11 @@ var (
 				qlight.ConnectorName: &qlight.PluginConnector{},
 			},
 		},
+		AuditPluginInterfaceName: {
+			pluginSet: plugin.PluginSet{
+				audit.ConnectorName: &audit.PluginConnector{},
+			},
+		},
 	}
 
 	// this is the place holder for future solution of the plugin central
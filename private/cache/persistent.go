@@ -0,0 +1,342 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// size of the node-local key used to encrypt cached payloads at rest (AES-256)
+	PersistentKeySize = 32
+	// interval between runs of the background eviction of expired items
+	evictionInterval = time.Minute
+)
+
+var (
+	persistentDataPrefix  = []byte("ptmcache-d") // persistentDataPrefix + hash -> created + encrypted item
+	persistentIndexPrefix = []byte("ptmcache-t") // persistentIndexPrefix + created + hash -> nil, oldest items first
+
+	persistentHitCounter   = metrics.NewRegisteredCounter("ptm/cache/persistent/hit", nil)
+	persistentMissCounter  = metrics.NewRegisteredCounter("ptm/cache/persistent/miss", nil)
+	persistentEvictCounter = metrics.NewRegisteredCounter("ptm/cache/persistent/evict", nil)
+	persistentSizeGauge    = metrics.NewRegisteredGauge("ptm/cache/persistent/size", nil)
+
+	ErrPersistentCacheDisabled = errors.New("persistent private payload cache is not enabled")
+)
+
+// PersistentConfig contains the settings of the persistent cache of decrypted private payloads
+type PersistentConfig struct {
+	Enabled bool
+	TTL     time.Duration // items older than this are evicted, zero means items never expire
+	MaxSize uint64        // maximum total size of the cached items (bytes), zero means unbounded
+}
+
+var DefaultPersistentConfig = PersistentConfig{
+	TTL:     24 * time.Hour,
+	MaxSize: 256 * 1024 * 1024,
+}
+
+// PersistentItem is an item kept in the persistent cache
+type PersistentItem struct {
+	PrivateCacheItem
+	IsSender bool
+}
+
+type persistentRecord struct {
+	Payload  []byte
+	Extra    engine.ExtraMetadata
+	IsSender bool
+}
+
+// Persistent is a cache of decrypted private payloads kept in the node's database, so that they
+// survive restarts and re-execution does not need to contact the private transaction manager again.
+// Items are encrypted at rest using a node-local key, and are evicted once they are older than the
+// configured TTL, or oldest first when the total size exceeds the configured maximum.
+type Persistent struct {
+	db      ethdb.KeyValueStore
+	aead    cipher.AEAD
+	ttl     time.Duration
+	maxSize uint64
+
+	lock sync.Mutex
+	size uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewPersistent(db ethdb.KeyValueStore, key []byte, cfg PersistentConfig) (*Persistent, error) {
+	if len(key) != PersistentKeySize {
+		return nil, fmt.Errorf("invalid persistent cache key size %d, expected %d", len(key), PersistentKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	c := &Persistent{
+		db:      db,
+		aead:    aead,
+		ttl:     cfg.TTL,
+		maxSize: cfg.MaxSize,
+		quit:    make(chan struct{}),
+	}
+	it := db.NewIterator(persistentDataPrefix, nil)
+	for it.Next() {
+		c.size += uint64(len(it.Value()))
+	}
+	it.Release()
+	persistentSizeGauge.Update(int64(c.size))
+	log.Info("Opened persistent private payload cache", "size", common.StorageSize(c.size), "ttl", cfg.TTL, "maxsize", common.StorageSize(cfg.MaxSize))
+
+	c.wg.Add(1)
+	go c.loop()
+	return c, nil
+}
+
+// LoadOrCreatePersistentKey reads the hex encoded key used to encrypt the persistent cache from the
+// given file, generating and storing a new random key if the file does not exist yet
+func LoadOrCreatePersistentKey(file string) ([]byte, error) {
+	data, err := ioutil.ReadFile(file)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode persistent cache key from '%s': %v", file, err)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	key := make([]byte, PersistentKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(file, []byte(hex.EncodeToString(key)), 0600); err != nil {
+		return nil, fmt.Errorf("unable to store persistent cache key in '%s': %v", file, err)
+	}
+	return key, nil
+}
+
+func dataKey(hash common.EncryptedPayloadHash) []byte {
+	return append(append([]byte{}, persistentDataPrefix...), hash.Bytes()...)
+}
+
+func indexKey(created uint64, hash common.EncryptedPayloadHash) []byte {
+	key := append([]byte{}, persistentIndexPrefix...)
+	key = append(key, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(persistentIndexPrefix):], created)
+	return append(key, hash.Bytes()...)
+}
+
+// Get returns the cached item for the given hash, expired items are never returned
+func (c *Persistent) Get(hash common.EncryptedPayloadHash) (*PersistentItem, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	value, err := c.db.Get(dataKey(hash))
+	if err != nil || len(value) < 8 {
+		persistentMissCounter.Inc(1)
+		return nil, false
+	}
+	created := binary.BigEndian.Uint64(value[:8])
+	if c.expired(created, time.Now()) {
+		c.remove(hash, created, len(value))
+		persistentMissCounter.Inc(1)
+		return nil, false
+	}
+	item, err := c.decrypt(hash, value[8:])
+	if err != nil {
+		log.Warn("Unable to decrypt item from persistent private payload cache, removing it", "hash", hash.Hex(), "err", err)
+		c.remove(hash, created, len(value))
+		persistentMissCounter.Inc(1)
+		return nil, false
+	}
+	persistentHitCounter.Inc(1)
+	return item, true
+}
+
+// Put adds the item to the cache, replacing any existing item for the same hash
+func (c *Persistent) Put(hash common.EncryptedPayloadHash, item *PersistentItem) {
+	encrypted, err := c.encrypt(hash, item)
+	if err != nil {
+		log.Warn("Unable to encrypt item for persistent private payload cache", "hash", hash.Hex(), "err", err)
+		return
+	}
+	created := uint64(time.Now().UnixNano())
+	value := make([]byte, 8, 8+len(encrypted))
+	binary.BigEndian.PutUint64(value, created)
+	value = append(value, encrypted...)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if existing, err := c.db.Get(dataKey(hash)); err == nil && len(existing) >= 8 {
+		c.remove(hash, binary.BigEndian.Uint64(existing[:8]), len(existing))
+	}
+	batch := c.db.NewBatch()
+	batch.Put(dataKey(hash), value)
+	batch.Put(indexKey(created, hash), nil)
+	if err := batch.Write(); err != nil {
+		log.Warn("Unable to write item to persistent private payload cache", "hash", hash.Hex(), "err", err)
+		return
+	}
+	c.size += uint64(len(value))
+	persistentSizeGauge.Update(int64(c.size))
+	if c.maxSize > 0 && c.size > c.maxSize {
+		c.evict(time.Now())
+	}
+}
+
+// Purge removes all items from the cache and returns the number of removed items
+func (c *Persistent) Purge() (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	count := 0
+	for _, prefix := range [][]byte{persistentDataPrefix, persistentIndexPrefix} {
+		batch := c.db.NewBatch()
+		it := c.db.NewIterator(prefix, nil)
+		for it.Next() {
+			if bytes.Equal(prefix, persistentDataPrefix) {
+				count++
+			}
+			batch.Delete(common.CopyBytes(it.Key()))
+		}
+		it.Release()
+		if err := batch.Write(); err != nil {
+			return 0, err
+		}
+	}
+	c.size = 0
+	persistentSizeGauge.Update(0)
+	log.Info("Purged persistent private payload cache", "items", count)
+	return count, nil
+}
+
+// Close stops the background eviction of expired items
+func (c *Persistent) Close() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+func (c *Persistent) loop() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(evictionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.lock.Lock()
+			c.evict(now)
+			c.lock.Unlock()
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+func (c *Persistent) expired(created uint64, now time.Time) bool {
+	return c.ttl > 0 && now.Sub(time.Unix(0, int64(created))) > c.ttl
+}
+
+// evict removes expired items and, if the cache is too large, the oldest items.
+// It must be called while holding the lock.
+func (c *Persistent) evict(now time.Time) {
+	it := c.db.NewIterator(persistentIndexPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()[len(persistentIndexPrefix):]
+		if len(key) != 8+common.EncryptedPayloadHashLength {
+			continue
+		}
+		created := binary.BigEndian.Uint64(key[:8])
+		if !c.expired(created, now) && (c.maxSize == 0 || c.size <= c.maxSize) {
+			return
+		}
+		hash := common.BytesToEncryptedPayloadHash(key[8:])
+		size := 0
+		if value, err := c.db.Get(dataKey(hash)); err == nil {
+			size = len(value)
+		}
+		c.remove(hash, created, size)
+		persistentEvictCounter.Inc(1)
+	}
+}
+
+// remove must be called while holding the lock
+func (c *Persistent) remove(hash common.EncryptedPayloadHash, created uint64, size int) {
+	batch := c.db.NewBatch()
+	batch.Delete(dataKey(hash))
+	batch.Delete(indexKey(created, hash))
+	if err := batch.Write(); err != nil {
+		log.Warn("Unable to remove item from persistent private payload cache", "hash", hash.Hex(), "err", err)
+		return
+	}
+	if uint64(size) > c.size {
+		c.size = 0
+	} else {
+		c.size -= uint64(size)
+	}
+	persistentSizeGauge.Update(int64(c.size))
+}
+
+func (c *Persistent) encrypt(hash common.EncryptedPayloadHash, item *PersistentItem) ([]byte, error) {
+	plaintext, err := rlp.EncodeToBytes(&persistentRecord{
+		Payload:  item.Payload,
+		Extra:    item.Extra,
+		IsSender: item.IsSender,
+	})
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// the hash is used as additional data, so that an item cannot be swapped for another one
+	return c.aead.Seal(nonce, nonce, plaintext, hash.Bytes()), nil
+}
+
+func (c *Persistent) decrypt(hash common.EncryptedPayloadHash, data []byte) (*PersistentItem, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("encrypted item is too short")
+	}
+	plaintext, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	var record persistentRecord
+	if err := rlp.DecodeBytes(plaintext, &record); err != nil {
+		return nil, err
+	}
+	return &PersistentItem{
+		PrivateCacheItem: PrivateCacheItem{
+			Payload: record.Payload,
+			Extra:   record.Extra,
+		},
+		IsSender: record.IsSender,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKey  = bytes.Repeat([]byte{1}, PersistentKeySize)
+	testHash = common.BytesToEncryptedPayloadHash([]byte("hash"))
+	testItem = &PersistentItem{
+		PrivateCacheItem: PrivateCacheItem{
+			Payload: []byte("arbitrary private payload"),
+			Extra: engine.ExtraMetadata{
+				ACHashes:            common.EncryptedPayloadHashes{},
+				PrivacyFlag:         engine.PrivacyFlagPartyProtection,
+				ManagedParties:      []string{"party1", "party2"},
+				Sender:              "sender",
+				MandatoryRecipients: []string{"party2"},
+			},
+		},
+		IsSender: true,
+	}
+)
+
+func newTestPersistent(t *testing.T, db *memorydb.Database, cfg PersistentConfig) *Persistent {
+	c, err := NewPersistent(db, testKey, cfg)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestPersistent_PutAndGet(t *testing.T) {
+	db := memorydb.New()
+	c := newTestPersistent(t, db, DefaultPersistentConfig)
+
+	c.Put(testHash, testItem)
+
+	item, found := c.Get(testHash)
+	require.True(t, found)
+	assert.Equal(t, testItem, item)
+
+	_, found = c.Get(common.BytesToEncryptedPayloadHash([]byte("other")))
+	assert.False(t, found)
+}
+
+func TestPersistent_ItemsAreEncryptedAtRest(t *testing.T) {
+	db := memorydb.New()
+	c := newTestPersistent(t, db, DefaultPersistentConfig)
+
+	c.Put(testHash, testItem)
+
+	value, err := db.Get(dataKey(testHash))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(value, testItem.Payload))
+
+	// a cache opened with another key cannot read the item
+	other, err := NewPersistent(db, bytes.Repeat([]byte{2}, PersistentKeySize), DefaultPersistentConfig)
+	require.NoError(t, err)
+	defer other.Close()
+	_, found := other.Get(testHash)
+	assert.False(t, found)
+}
+
+func TestPersistent_SurvivesReopen(t *testing.T) {
+	db := memorydb.New()
+	c := newTestPersistent(t, db, DefaultPersistentConfig)
+	c.Put(testHash, testItem)
+	size := c.size
+
+	reopened := newTestPersistent(t, db, DefaultPersistentConfig)
+
+	assert.Equal(t, size, reopened.size)
+	_, found := reopened.Get(testHash)
+	assert.True(t, found)
+}
+
+func TestPersistent_ExpiredItemsAreEvicted(t *testing.T) {
+	db := memorydb.New()
+	c := newTestPersistent(t, db, PersistentConfig{TTL: time.Hour})
+	c.Put(testHash, testItem)
+
+	c.lock.Lock()
+	c.evict(time.Now().Add(2 * time.Hour))
+	c.lock.Unlock()
+
+	_, found := c.Get(testHash)
+	assert.False(t, found)
+	assert.Equal(t, uint64(0), c.size)
+}
+
+func TestPersistent_OldestItemsAreEvictedWhenTooLarge(t *testing.T) {
+	db := memorydb.New()
+	c := newTestPersistent(t, db, PersistentConfig{})
+	c.Put(testHash, testItem)
+	c.maxSize = c.size
+
+	newer := common.BytesToEncryptedPayloadHash([]byte("newer"))
+	c.Put(newer, testItem)
+
+	_, found := c.Get(testHash)
+	assert.False(t, found)
+	_, found = c.Get(newer)
+	assert.True(t, found)
+}
+
+func TestPersistent_Purge(t *testing.T) {
+	db := memorydb.New()
+	c := newTestPersistent(t, db, DefaultPersistentConfig)
+	c.Put(testHash, testItem)
+	c.Put(common.BytesToEncryptedPayloadHash([]byte("other")), testItem)
+
+	count, err := c.Purge()
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, uint64(0), c.size)
+	assert.Equal(t, 0, db.Len())
+}
+
+func TestLoadOrCreatePersistentKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ptmcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "ptmcache.key")
+
+	key, err := LoadOrCreatePersistentKey(file)
+	require.NoError(t, err)
+	assert.Len(t, key, PersistentKeySize)
+
+	loaded, err := LoadOrCreatePersistentKey(file)
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	ErrPrivateTxManagerUnavailable = errors.New("private transaction manager is unavailable, try again later")
+
+	circuitBreakerOpenGauge       = metrics.NewRegisteredGauge("ptm/circuitbreaker/open", nil)
+	circuitBreakerRejectedCounter = metrics.NewRegisteredCounter("ptm/circuitbreaker/rejected", nil)
+)
+
+// CircuitBreaker tracks the health of the private transaction manager from the outcome of the requests sent to it.
+// It opens after threshold consecutive failed requests and stays open for the cooldown period, after which
+// requests are allowed again to probe the private transaction manager. The circuit closes on the first
+// successful request.
+//
+// The circuit breaker does not block any request by itself, callers which are able to fail fast (e.g. RPC
+// requests sending private transactions) check Allow beforehand. Other callers, like the block import, keep
+// sending requests and so keep probing the private transaction manager.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns ErrPrivateTxManagerUnavailable while the circuit is open
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.now().Before(b.openUntil) {
+		circuitBreakerRejectedCounter.Inc(1)
+		return ErrPrivateTxManagerUnavailable
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		if b.failures >= b.threshold {
+			log.Info("Private transaction manager is available again, closing circuit breaker")
+			circuitBreakerOpenGauge.Update(0)
+		}
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			log.Warn("Private transaction manager is unavailable, opening circuit breaker", "failures", b.failures, "cooldown", b.cooldown)
+			circuitBreakerOpenGauge.Update(1)
+		}
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
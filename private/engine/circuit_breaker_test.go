@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_OpensAfterThresholdAndClosesOnSuccess(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.record(true)
+	assert.NoError(t, b.Allow(), "circuit must stay closed below the threshold")
+	b.record(true)
+	assert.Equal(t, ErrPrivateTxManagerUnavailable, b.Allow())
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, b.Allow(), "requests must be allowed again after the cooldown")
+	b.record(true)
+	assert.Equal(t, ErrPrivateTxManagerUnavailable, b.Allow(), "a failed probe must open the circuit again")
+
+	b.record(false)
+	assert.NoError(t, b.Allow())
+	b.record(true)
+	assert.NoError(t, b.Allow(), "failures must be counted again from zero once the circuit is closed")
+}
+
+func TestClientDo_RecordsOutcomeInCircuitBreaker(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	client := &Client{
+		HttpClient:     &http.Client{Timeout: 20 * time.Millisecond},
+		BaseURL:        server.URL,
+		CircuitBreaker: NewCircuitBreaker(2, time.Minute),
+	}
+
+	req, err := http.NewRequest("GET", client.FullPath("/"), nil)
+	require.NoError(t, err)
+	res, err := client.Do("test", req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.NoError(t, client.CircuitBreaker.Allow())
+
+	req, err = http.NewRequest("GET", client.FullPath("/slow"), nil)
+	require.NoError(t, err)
+	_, err = client.Do("test", req)
+	require.Error(t, err)
+	assert.Equal(t, ErrPrivateTxManagerUnavailable, client.CircuitBreaker.Allow())
+
+	status = http.StatusNotFound
+	req, err = http.NewRequest("GET", client.FullPath("/"), nil)
+	require.NoError(t, err)
+	res, err = client.Do("test", req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.NoError(t, client.CircuitBreaker.Allow(), "a response from the private transaction manager must close the circuit")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -21,6 +22,7 @@ var (
 	ErrPrivateTxManagerNotSupported                      = errors.New("private transaction manager does not support this operation")
 	ErrPrivateTxManagerDoesNotSupportPrivacyEnhancements = errors.New("private transaction manager does not support privacy enhancements")
 	ErrPrivateTxManagerDoesNotSupportMandatoryRecipients = errors.New("private transaction manager does not support mandatory recipients")
+	ErrPrivateTxManagerTLSNotInUse                       = errors.New("private transaction manager connection does not use TLS")
 )
 
 type PrivacyGroup struct {
@@ -58,6 +60,13 @@ type QuorumPayloadExtra struct {
 type Client struct {
 	HttpClient *http.Client
 	BaseURL    string
+	// ReloadTLS reloads the TLS material used by HttpClient, it is nil when TLS is not in use
+	ReloadTLS func() error
+	// CircuitBreaker tracks the health of the private transaction manager, it is nil when disabled
+	CircuitBreaker *CircuitBreaker
+	// Stop stops the background health checks of the failover endpoints and the watch of the TLS files,
+	// it is nil when there are none
+	Stop func()
 }
 
 func (c *Client) FullPath(path string) string {
@@ -116,8 +125,26 @@ const (
 	MultiTenancy          PrivateTransactionManagerFeature = 1 << PrivateTransactionManagerFeature(iota-1) // 2
 	MultiplePrivateStates PrivateTransactionManagerFeature = 1 << PrivateTransactionManagerFeature(iota-1) // 4
 	MandatoryRecipients   PrivateTransactionManagerFeature = 1 << PrivateTransactionManagerFeature(iota-1) // 8
+	PrivacyGroups         PrivateTransactionManagerFeature = 1 << PrivateTransactionManagerFeature(iota-1) // 16
 )
 
+var featureNames = map[PrivateTransactionManagerFeature]string{
+	PrivacyEnhancements:   "PrivacyEnhancements",
+	MultiTenancy:          "MultiTenancy",
+	MultiplePrivateStates: "MultiplePrivateStates",
+	MandatoryRecipients:   "MandatoryRecipients",
+	PrivacyGroups:         "PrivacyGroups",
+}
+
+func (f PrivateTransactionManagerFeature) String() string {
+	if name, ok := featureNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Feature(%d)", uint64(f))
+}
+
+// FeatureSet is safe for concurrent use, its features can be replaced at runtime
+// when the private transaction manager is upgraded
 type FeatureSet struct {
 	features uint64
 }
@@ -131,7 +158,45 @@ func NewFeatureSet(features ...PrivateTransactionManagerFeature) *FeatureSet {
 }
 
 func (p *FeatureSet) HasFeature(feature PrivateTransactionManagerFeature) bool {
-	return uint64(feature)&p.features != 0
+	return uint64(feature)&atomic.LoadUint64(&p.features) != 0
+}
+
+// Features returns the features in the set, in ascending order
+func (p *FeatureSet) Features() []PrivateTransactionManagerFeature {
+	all := atomic.LoadUint64(&p.features)
+	result := make([]PrivateTransactionManagerFeature, 0)
+	for feature := PrivateTransactionManagerFeature(1); feature != 0 && uint64(feature) <= all; feature <<= 1 {
+		if uint64(feature)&all != 0 {
+			result = append(result, feature)
+		}
+	}
+	return result
+}
+
+// Replace sets the features of this set to the ones of other, and returns the features
+// which have been added and removed
+func (p *FeatureSet) Replace(other *FeatureSet) (added, removed []PrivateTransactionManagerFeature) {
+	newFeatures := atomic.LoadUint64(&other.features)
+	oldFeatures := atomic.SwapUint64(&p.features, newFeatures)
+	added = (&FeatureSet{features: newFeatures &^ oldFeatures}).Features()
+	removed = (&FeatureSet{features: oldFeatures &^ newFeatures}).Features()
+	return added, removed
+}
+
+// FeatureUpdate is the outcome of the detection of the features of an upgraded private transaction manager
+type FeatureUpdate struct {
+	Version  string   `json:"version"`
+	Features []string `json:"features"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+}
+
+func FeatureNames(features []PrivateTransactionManagerFeature) []string {
+	names := make([]string, len(features))
+	for i, feature := range features {
+		names[i] = feature.String()
+	}
+	return names
 }
 
 type ExtraMetaDataRLP ExtraMetadata
@@ -87,3 +87,15 @@ func TestFeatureSet_HasFeature(t *testing.T) {
 	assert.True(featureSet.HasFeature(MandatoryRecipients))
 	assert.True(featureSet.HasFeature(MultiplePrivateStates))
 }
+
+func TestFeatureSet_Replace(t *testing.T) {
+	assert := assert.New(t)
+
+	featureSet := NewFeatureSet(PrivacyEnhancements, MultiTenancy)
+	added, removed := featureSet.Replace(NewFeatureSet(PrivacyEnhancements, MandatoryRecipients))
+
+	assert.Equal([]PrivateTransactionManagerFeature{MandatoryRecipients}, added)
+	assert.Equal([]PrivateTransactionManagerFeature{MultiTenancy}, removed)
+	assert.Equal([]PrivateTransactionManagerFeature{PrivacyEnhancements, MandatoryRecipients}, featureSet.Features())
+	assert.False(featureSet.HasFeature(MultiTenancy))
+}
@@ -74,6 +74,22 @@ func (ptm *constellation) Groups() ([]engine.PrivacyGroup, error) {
 	return nil, engine.ErrPrivateTxManagerNotSupported
 }
 
+func (ptm *constellation) CreatePrivacyGroup(from string, name string, description string, members []string) (*engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (ptm *constellation) FindPrivacyGroup(members []string) ([]engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (ptm *constellation) RetrievePrivacyGroup(privacyGroupId string) (*engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (ptm *constellation) DeletePrivacyGroup(from string, privacyGroupId string) (string, error) {
+	return "", engine.ErrPrivateTxManagerNotSupported
+}
+
 func (g *constellation) GetParticipants(txHash common.EncryptedPayloadHash) ([]string, error) {
 	return nil, engine.ErrPrivateTxManagerNotSupported
 }
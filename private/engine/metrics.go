@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Operations of the private transaction manager for which metrics are recorded
+const (
+	OperationSend           = "send"
+	OperationStoreRaw       = "storeraw"
+	OperationSendSignedTx   = "sendsignedtx"
+	OperationReceive        = "receive"
+	OperationEncryptPayload = "encryptpayload"
+	OperationDecryptPayload = "decryptpayload"
+	OperationTransaction    = "transaction" // isSender, participants and mandatory recipients of a transaction
+	OperationGroups         = "groups"
+	OperationPrivacyGroup   = "privacygroup"
+)
+
+// Do sends a request for the given operation to the private transaction manager. The latency and outcome of the
+// request are recorded in the metrics of the operation, and in the circuit breaker of the client if enabled.
+// Requests are sent regardless of the state of the circuit breaker, see CircuitBreaker.Allow.
+func (c *Client) Do(operation string, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := c.HttpClient.Do(req)
+	failed := err != nil || res.StatusCode >= http.StatusInternalServerError
+
+	metrics.GetOrRegisterTimer("ptm/"+operation+"/duration", nil).UpdateSince(start)
+	if failed {
+		metrics.GetOrRegisterCounter("ptm/"+operation+"/errors", nil).Inc(1)
+	}
+	if isTimeout(err) {
+		metrics.GetOrRegisterCounter("ptm/"+operation+"/timeouts", nil).Inc(1)
+	}
+	if c.CircuitBreaker != nil {
+		c.CircuitBreaker.record(failed)
+	}
+	return res, err
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
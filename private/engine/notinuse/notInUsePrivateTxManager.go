@@ -21,6 +21,22 @@ func (ptm *PrivateTransactionManager) Groups() ([]engine.PrivacyGroup, error) {
 	panic("implement me")
 }
 
+func (ptm *PrivateTransactionManager) CreatePrivacyGroup(from string, name string, description string, members []string) (*engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotinUse
+}
+
+func (ptm *PrivateTransactionManager) FindPrivacyGroup(members []string) ([]engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotinUse
+}
+
+func (ptm *PrivateTransactionManager) RetrievePrivacyGroup(privacyGroupId string) (*engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotinUse
+}
+
+func (ptm *PrivateTransactionManager) DeletePrivacyGroup(from string, privacyGroupId string) (string, error) {
+	return "", engine.ErrPrivateTxManagerNotinUse
+}
+
 func (ptm *PrivateTransactionManager) GetParticipants(txHash common.EncryptedPayloadHash) ([]string, error) {
 	panic("implement me")
 }
@@ -16,12 +16,14 @@ import (
 
 type RPCClientCaller interface {
 	Call(result interface{}, method string, args ...interface{}) error
+	BatchCall(b []rpc.BatchElem) error
 }
 
 type CachingProxyTxManager struct {
-	features  *engine.FeatureSet
-	cache     *gocache.Cache
-	rpcClient RPCClientCaller
+	features        *engine.FeatureSet
+	cache           *gocache.Cache
+	persistentCache *cache.Persistent
+	rpcClient       RPCClientCaller
 }
 
 type CPItem struct {
@@ -50,6 +52,11 @@ func (t *CachingProxyTxManager) SetRPCClientCaller(client RPCClientCaller) {
 	t.rpcClient = client
 }
 
+// SetPersistentCache enables keeping received payloads in the given persistent cache, in addition to the in-memory one
+func (t *CachingProxyTxManager) SetPersistentCache(c *cache.Persistent) {
+	t.persistentCache = c
+}
+
 func (t *CachingProxyTxManager) Send(data []byte, from string, to []string, extra *engine.ExtraMetadata) (string, []string, common.EncryptedPayloadHash, error) {
 	panic("implement me")
 }
@@ -99,6 +106,16 @@ func (t *CachingProxyTxManager) receive(hash common.EncryptedPayloadHash, isRaw
 		return cacheItem.Extra.Sender, cacheItem.Extra.ManagedParties, cacheItem.Payload, &cacheItem.Extra, nil
 	}
 
+	if !isRaw && t.persistentCache != nil {
+		if item, found := t.persistentCache.Get(hash); found {
+			t.cache.Set(cacheKey, CPItem{
+				PrivateCacheItem: item.PrivateCacheItem,
+				IsSender:         item.IsSender,
+			}, gocache.DefaultExpiration)
+			return item.Extra.Sender, item.Extra.ManagedParties, item.Payload, &item.Extra, nil
+		}
+	}
+
 	log.Info("qlight: no private data in ptm cache, retrieving from qlight server node")
 	var result engine.QuorumPayloadExtra
 	err := t.rpcClient.Call(&result, "eth_getQuorumPayloadExtra", hash.Hex())
@@ -124,6 +141,49 @@ func (t *CachingProxyTxManager) receive(hash common.EncryptedPayloadHash, isRaw
 	return "", nil, nil, nil, nil
 }
 
+// ReceiveBatch retrieves all payloads which are not cached yet from the qlight server node in a single batch request
+func (t *CachingProxyTxManager) ReceiveBatch(hashes []common.EncryptedPayloadHash) error {
+	var (
+		batch   []rpc.BatchElem
+		pending []common.EncryptedPayloadHash
+	)
+	for _, hash := range hashes {
+		if common.EmptyEncryptedPayloadHash(hash) {
+			continue
+		}
+		if _, found := t.cache.Get(hash.Hex()); found {
+			continue
+		}
+		batch = append(batch, rpc.BatchElem{
+			Method: "eth_getQuorumPayloadExtra",
+			Args:   []interface{}{hash.Hex()},
+			Result: new(engine.QuorumPayloadExtra),
+		})
+		pending = append(pending, hash)
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	log.Debug("qlight: retrieving private data in batch from qlight server node", "count", len(batch))
+	if err := t.rpcClient.BatchCall(batch); err != nil {
+		return err
+	}
+	for i, elem := range batch {
+		if elem.Error != nil {
+			log.Debug("qlight: unable to retrieve private data", "hash", pending[i].Hex(), "err", elem.Error)
+			continue
+		}
+		result := elem.Result.(*engine.QuorumPayloadExtra)
+		if len(result.Payload) <= 3 {
+			continue
+		}
+		if err := t.Cache(&CachablePrivateTransactionData{Hash: pending[i], QuorumPrivateTxData: *result}); err != nil {
+			log.Warn("unable to cache ptm data", "err", err)
+		}
+	}
+	return nil
+}
+
 func (t *CachingProxyTxManager) CheckAndAddEmptyToCache(hash common.EncryptedPayloadHash) {
 	if common.EmptyEncryptedPayloadHash(hash) {
 		return
@@ -155,13 +215,20 @@ func (t *CachingProxyTxManager) Cache(privateTxData *CachablePrivateTransactionD
 		return err
 	}
 
-	t.cache.Set(cacheKey, CPItem{
+	item := CPItem{
 		PrivateCacheItem: cache.PrivateCacheItem{
 			Payload: payload,
 			Extra:   *privateTxData.QuorumPrivateTxData.ExtraMetaData,
 		},
 		IsSender: privateTxData.QuorumPrivateTxData.IsSender,
-	}, gocache.DefaultExpiration)
+	}
+	t.cache.Set(cacheKey, item, gocache.DefaultExpiration)
+	if t.persistentCache != nil {
+		t.persistentCache.Put(privateTxData.Hash, &cache.PersistentItem{
+			PrivateCacheItem: item.PrivateCacheItem,
+			IsSender:         item.IsSender,
+		})
+	}
 
 	return nil
 }
@@ -222,6 +289,22 @@ func (t *CachingProxyTxManager) Groups() ([]engine.PrivacyGroup, error) {
 	panic("implement me")
 }
 
+func (t *CachingProxyTxManager) CreatePrivacyGroup(from string, name string, description string, members []string) (*engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (t *CachingProxyTxManager) FindPrivacyGroup(members []string) ([]engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (t *CachingProxyTxManager) RetrievePrivacyGroup(privacyGroupId string) (*engine.PrivacyGroup, error) {
+	return nil, engine.ErrPrivateTxManagerNotSupported
+}
+
+func (t *CachingProxyTxManager) DeletePrivacyGroup(from string, privacyGroupId string) (string, error) {
+	return "", engine.ErrPrivateTxManagerNotSupported
+}
+
 func (t *CachingProxyTxManager) Name() string {
 	return "CachingP2PProxy"
 }
@@ -230,3 +230,42 @@ func TestCachingProxy_HasRPCClient(t *testing.T) {
 	_, ok := cpTM.(HasRPCClient)
 	assert.True(ok)
 }
+
+func TestCachingProxy_ReceiveBatchCachesRetrievedData(t *testing.T) {
+	assert := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cpTM := New()
+	cached := common.BytesToEncryptedPayloadHash([]byte("encryptedpayloadhash1"))
+	missing := common.BytesToEncryptedPayloadHash([]byte("encryptedpayloadhash2"))
+	cpTM.Cache(&CachablePrivateTransactionData{
+		Hash: cached,
+		QuorumPrivateTxData: engine.QuorumPayloadExtra{
+			Payload:       fmt.Sprintf("0x%x", []byte("payload1")),
+			ExtraMetaData: &engine.ExtraMetadata{Sender: "sender1"},
+		},
+	})
+
+	mockRPCClient := NewMockRPCClientCaller(ctrl)
+	mockRPCClient.EXPECT().BatchCall(gomock.Any()).DoAndReturn(func(batch []rpc.BatchElem) error {
+		assert.Len(batch, 1)
+		assert.Equal("eth_getQuorumPayloadExtra", batch[0].Method)
+		assert.Equal([]interface{}{missing.Hex()}, batch[0].Args)
+		res, _ := batch[0].Result.(*engine.QuorumPayloadExtra)
+		res.ExtraMetaData = &engine.ExtraMetadata{Sender: "sender2"}
+		res.Payload = fmt.Sprintf("0x%x", []byte("payload2"))
+		return nil
+	})
+	cpTM.SetRPCClientCaller(mockRPCClient)
+
+	err := cpTM.ReceiveBatch([]common.EncryptedPayloadHash{cached, missing})
+	assert.Nil(err)
+
+	// served from the cache, no further calls are expected on the mock
+	sender, _, payload, _, err := cpTM.Receive(missing)
+
+	assert.Nil(err)
+	assert.Equal("sender2", sender)
+	assert.Equal([]byte("payload2"), payload)
+}
@@ -7,6 +7,7 @@ package qlightptm
 import (
 	reflect "reflect"
 
+	rpc "github.com/ethereum/go-ethereum/rpc"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -33,6 +34,20 @@ func (m *MockRPCClientCaller) EXPECT() *MockRPCClientCallerMockRecorder {
 	return m.recorder
 }
 
+// BatchCall mocks base method.
+func (m *MockRPCClientCaller) BatchCall(b []rpc.BatchElem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCall", b)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchCall indicates an expected call of BatchCall.
+func (mr *MockRPCClientCallerMockRecorder) BatchCall(b interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCall", reflect.TypeOf((*MockRPCClientCaller)(nil).BatchCall), b)
+}
+
 // Call mocks base method.
 func (m *MockRPCClientCaller) Call(result interface{}, method string, args ...interface{}) error {
 	m.ctrl.T.Helper()
@@ -96,3 +96,28 @@ type decryptPayloadRequest struct {
 	RecipientNonce  []byte   `json:"recipientNonce"`
 	RecipientKeys   []string `json:"recipientKeys"`
 }
+
+// request object for /createPrivacyGroup API
+type createPrivacyGroupRequest struct {
+	// base64-encoded public keys of the members
+	Addresses   []string `json:"addresses"`
+	From        string   `json:"from,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// request object for /findPrivacyGroup API
+type findPrivacyGroupRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// request object for /retrievePrivacyGroup API
+type retrievePrivacyGroupRequest struct {
+	PrivacyGroupId string `json:"privacyGroupId"`
+}
+
+// request object for /deletePrivacyGroup API
+type deletePrivacyGroupRequest struct {
+	PrivacyGroupId string `json:"privacyGroupId"`
+	From           string `json:"from,omitempty"`
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -22,9 +23,11 @@ import (
 )
 
 type tesseraPrivateTxManager struct {
-	features *engine.FeatureSet
-	client   *engine.Client
-	cache    *gocache.Cache
+	features        *engine.FeatureSet
+	client          *engine.Client
+	cache           *gocache.Cache
+	persistentCache *cache.Persistent
+	refreshLock     sync.Mutex
 }
 
 func Is(ptm interface{}) bool {
@@ -44,7 +47,7 @@ func New(client *engine.Client, version []byte) *tesseraPrivateTxManager {
 	}
 }
 
-func (t *tesseraPrivateTxManager) submitJSON(method, path string, request interface{}, response interface{}) (int, error) {
+func (t *tesseraPrivateTxManager) submitJSON(operation, method, path string, request interface{}, response interface{}) (int, error) {
 	apiVersion := ""
 	if t.features.HasFeature(engine.MultiTenancy) {
 		apiVersion = "vnd.tessera-2.1+"
@@ -56,11 +59,15 @@ func (t *tesseraPrivateTxManager) submitJSON(method, path string, request interf
 		// for the groups API the Content-type/Accept is application/json
 		apiVersion = ""
 	}
+	if strings.HasSuffix(path, "PrivacyGroup") {
+		// the privacy group management APIs only accept application/json
+		apiVersion = ""
+	}
 	req, err := newOptionalJSONRequest(method, t.client.FullPath(path), request, apiVersion)
 	if err != nil {
 		return -1, fmt.Errorf("unable to build json request for (method:%s,path:%s). Cause: %v", method, path, err)
 	}
-	res, err := t.client.HttpClient.Do(req)
+	res, err := t.client.Do(operation, req)
 	if err != nil {
 		return -1, fmt.Errorf("unable to submit request (method:%s,path:%s). Cause: %v", method, path, err)
 	}
@@ -87,7 +94,7 @@ func (t *tesseraPrivateTxManager) Send(data []byte, from string, to []string, ex
 	if !common.EmptyHash(extra.ACMerkleRoot) {
 		acMerkleRoot = extra.ACMerkleRoot.ToBase64()
 	}
-	if _, err := t.submitJSON("POST", "/send", &sendRequest{
+	if _, err := t.submitJSON(engine.OperationSend, "POST", "/send", &sendRequest{
 		Payload:                      data,
 		From:                         from,
 		To:                           to,
@@ -126,7 +133,7 @@ func (t *tesseraPrivateTxManager) EncryptPayload(data []byte, from string, to []
 		acMerkleRoot = extra.ACMerkleRoot.ToBase64()
 	}
 
-	if _, err := t.submitJSON("POST", "/encodedpayload/create", &sendRequest{
+	if _, err := t.submitJSON(engine.OperationEncryptPayload, "POST", "/encodedpayload/create", &sendRequest{
 		Payload:                      data,
 		From:                         from,
 		To:                           to,
@@ -144,7 +151,7 @@ func (t *tesseraPrivateTxManager) EncryptPayload(data []byte, from string, to []
 func (t *tesseraPrivateTxManager) StoreRaw(data []byte, from string) (common.EncryptedPayloadHash, error) {
 	response := new(sendResponse)
 
-	if _, err := t.submitJSON("POST", "/storeraw", &storerawRequest{
+	if _, err := t.submitJSON(engine.OperationStoreRaw, "POST", "/storeraw", &storerawRequest{
 		Payload: data,
 		From:    from,
 	}, response); err != nil {
@@ -180,7 +187,7 @@ func (c *tesseraPrivateTxManager) sendSignedPayloadOctetStream(signedPayload []b
 
 	req.Header.Set("c11n-to", strings.Join(b64To, ","))
 	req.Header.Set("Content-Type", "application/octet-stream")
-	res, err := c.client.HttpClient.Do(req)
+	res, err := c.client.Do(engine.OperationSendSignedTx, req)
 	if err != nil {
 		return "", nil, nil, err
 	}
@@ -216,7 +223,7 @@ func (t *tesseraPrivateTxManager) SendSignedTx(data common.EncryptedPayloadHash,
 	// The /sendsignedtx has been updated as part of privacy enhancements to support a json payload.
 	// If an older tessera is used - invoke the octetstream version of the /sendsignedtx
 	if t.features.HasFeature(engine.PrivacyEnhancements) {
-		if _, err := t.submitJSON("POST", "/sendsignedtx", &sendSignedTxRequest{
+		if _, err := t.submitJSON(engine.OperationSendSignedTx, "POST", "/sendsignedtx", &sendSignedTxRequest{
 			Hash:                         data.Bytes(),
 			To:                           to,
 			AffectedContractTransactions: extra.ACHashes.ToBase64s(),
@@ -290,6 +297,13 @@ func (t *tesseraPrivateTxManager) receive(data common.EncryptedPayloadHash, isRa
 		return cacheItem.Extra.Sender, cacheItem.Extra.ManagedParties, cacheItem.Payload, &cacheItem.Extra, nil
 	}
 
+	if !isRaw && t.persistentCache != nil {
+		if item, found := t.persistentCache.Get(data); found {
+			t.cache.Set(cacheKey, item.PrivateCacheItem, gocache.DefaultExpiration)
+			return item.Extra.Sender, item.Extra.ManagedParties, item.Payload, &item.Extra, nil
+		}
+	}
+
 	uri := fmt.Sprintf("/transaction/%s?isRaw=%v", url.PathEscape(data.ToBase64()), isRaw)
 
 	var statusCode int
@@ -297,7 +311,7 @@ func (t *tesseraPrivateTxManager) receive(data common.EncryptedPayloadHash, isRa
 	response := new(receiveResponse)
 
 	for i := 0; i < 5; i++ {
-		statusCode, err = t.submitJSON("GET", uri, nil, response)
+		statusCode, err = t.submitJSON(engine.OperationReceive, "GET", uri, nil, response)
 		if err != nil && statusCode != http.StatusNotFound {
 			log.Warn("Failed to fetch data from tessera", "retry", i, "uri", uri, "statuscode", statusCode, "err", err)
 			time.Sleep(1 * time.Second)
@@ -316,20 +330,8 @@ func (t *tesseraPrivateTxManager) receive(data common.EncryptedPayloadHash, isRa
 
 	var extra engine.ExtraMetadata
 	if !isRaw {
-		acHashes, err := common.Base64sToEncryptedPayloadHashes(response.AffectedContractTransactions)
-		if err != nil {
-			return "", nil, nil, nil, fmt.Errorf("unable to decode ACOTHs %v. Cause: %v", response.AffectedContractTransactions, err)
-		}
-		acMerkleRoot, err := common.Base64ToHash(response.ExecHash)
-		if err != nil {
-			return "", nil, nil, nil, fmt.Errorf("unable to decode execution hash %s. Cause: %v", response.ExecHash, err)
-		}
-		extra = engine.ExtraMetadata{
-			ACHashes:       acHashes,
-			ACMerkleRoot:   acMerkleRoot,
-			PrivacyFlag:    response.PrivacyFlag,
-			ManagedParties: response.ManagedParties,
-			Sender:         response.SenderKey,
+		if extra, err = receivedExtraMetadata(response); err != nil {
+			return "", nil, nil, nil, err
 		}
 	} else {
 		extra = engine.ExtraMetadata{
@@ -338,18 +340,61 @@ func (t *tesseraPrivateTxManager) receive(data common.EncryptedPayloadHash, isRa
 		}
 	}
 
-	t.cache.Set(cacheKey, cache.PrivateCacheItem{
+	cacheItem := cache.PrivateCacheItem{
 		Payload: response.Payload,
 		Extra:   extra,
-	}, gocache.DefaultExpiration)
+	}
+	t.cache.Set(cacheKey, cacheItem, gocache.DefaultExpiration)
+	if !isRaw && t.persistentCache != nil {
+		t.persistentCache.Put(data, &cache.PersistentItem{PrivateCacheItem: cacheItem})
+	}
 
 	return response.SenderKey, response.ManagedParties, response.Payload, &extra, nil
 }
 
+// ReceiveUncached queries Tessera for the payload once, without looking it up in or adding it to the caches.
+// Unlike Receive, a failure to reach Tessera is returned to the caller.
+func (t *tesseraPrivateTxManager) ReceiveUncached(data common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error) {
+	if common.EmptyEncryptedPayloadHash(data) {
+		return "", nil, nil, nil, nil
+	}
+	uri := fmt.Sprintf("/transaction/%s?isRaw=false", url.PathEscape(data.ToBase64()))
+	response := new(receiveResponse)
+	statusCode, err := t.submitJSON(engine.OperationReceive, "GET", uri, nil, response)
+	if statusCode == http.StatusNotFound {
+		return "", nil, nil, nil, nil
+	} else if err != nil {
+		return "", nil, nil, nil, err
+	}
+	extra, err := receivedExtraMetadata(response)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	return response.SenderKey, response.ManagedParties, response.Payload, &extra, nil
+}
+
+func receivedExtraMetadata(response *receiveResponse) (engine.ExtraMetadata, error) {
+	acHashes, err := common.Base64sToEncryptedPayloadHashes(response.AffectedContractTransactions)
+	if err != nil {
+		return engine.ExtraMetadata{}, fmt.Errorf("unable to decode ACOTHs %v. Cause: %v", response.AffectedContractTransactions, err)
+	}
+	acMerkleRoot, err := common.Base64ToHash(response.ExecHash)
+	if err != nil {
+		return engine.ExtraMetadata{}, fmt.Errorf("unable to decode execution hash %s. Cause: %v", response.ExecHash, err)
+	}
+	return engine.ExtraMetadata{
+		ACHashes:       acHashes,
+		ACMerkleRoot:   acMerkleRoot,
+		PrivacyFlag:    response.PrivacyFlag,
+		ManagedParties: response.ManagedParties,
+		Sender:         response.SenderKey,
+	}, nil
+}
+
 // retrieve raw will not return information about medata
 func (t *tesseraPrivateTxManager) DecryptPayload(payload common.DecryptRequest) ([]byte, *engine.ExtraMetadata, error) {
 	response := new(receiveResponse)
-	if _, err := t.submitJSON("POST", "/encodedpayload/decrypt", &decryptPayloadRequest{
+	if _, err := t.submitJSON(engine.OperationDecryptPayload, "POST", "/encodedpayload/decrypt", &decryptPayloadRequest{
 		SenderKey:       payload.SenderKey,
 		CipherText:      payload.CipherText,
 		CipherTextNonce: payload.CipherTextNonce,
@@ -385,7 +430,7 @@ func (t *tesseraPrivateTxManager) IsSender(txHash common.EncryptedPayloadHash) (
 		return false, err
 	}
 
-	res, err := t.client.HttpClient.Do(req)
+	res, err := t.client.Do(engine.OperationTransaction, req)
 
 	if res != nil {
 		defer res.Body.Close()
@@ -415,7 +460,7 @@ func (t *tesseraPrivateTxManager) GetParticipants(txHash common.EncryptedPayload
 		return nil, err
 	}
 
-	res, err := t.client.HttpClient.Do(req)
+	res, err := t.client.Do(engine.OperationTransaction, req)
 
 	if res != nil {
 		defer res.Body.Close()
@@ -447,7 +492,7 @@ func (t *tesseraPrivateTxManager) GetMandatory(txHash common.EncryptedPayloadHas
 		return nil, err
 	}
 
-	res, err := t.client.HttpClient.Do(req)
+	res, err := t.client.Do(engine.OperationTransaction, req)
 
 	if res != nil {
 		defer res.Body.Close()
@@ -474,12 +519,136 @@ func (t *tesseraPrivateTxManager) GetMandatory(txHash common.EncryptedPayloadHas
 
 func (t *tesseraPrivateTxManager) Groups() ([]engine.PrivacyGroup, error) {
 	response := make([]engine.PrivacyGroup, 0)
-	if _, err := t.submitJSON("GET", "/groups/resident", nil, &response); err != nil {
+	if _, err := t.submitJSON(engine.OperationGroups, "GET", "/groups/resident", nil, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (t *tesseraPrivateTxManager) CreatePrivacyGroup(from string, name string, description string, members []string) (*engine.PrivacyGroup, error) {
+	if !t.features.HasFeature(engine.PrivacyGroups) {
+		return nil, engine.ErrPrivateTxManagerNotSupported
+	}
+	response := new(engine.PrivacyGroup)
+	if _, err := t.submitJSON(engine.OperationPrivacyGroup, "POST", "/createPrivacyGroup", &createPrivacyGroupRequest{
+		Addresses:   members,
+		From:        from,
+		Name:        name,
+		Description: description,
+	}, response); err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
+func (t *tesseraPrivateTxManager) FindPrivacyGroup(members []string) ([]engine.PrivacyGroup, error) {
+	if !t.features.HasFeature(engine.PrivacyGroups) {
+		return nil, engine.ErrPrivateTxManagerNotSupported
+	}
+	response := make([]engine.PrivacyGroup, 0)
+	if _, err := t.submitJSON(engine.OperationPrivacyGroup, "POST", "/findPrivacyGroup", &findPrivacyGroupRequest{Addresses: members}, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (t *tesseraPrivateTxManager) RetrievePrivacyGroup(privacyGroupId string) (*engine.PrivacyGroup, error) {
+	if !t.features.HasFeature(engine.PrivacyGroups) {
+		return nil, engine.ErrPrivateTxManagerNotSupported
+	}
+	response := new(engine.PrivacyGroup)
+	if _, err := t.submitJSON(engine.OperationPrivacyGroup, "POST", "/retrievePrivacyGroup", &retrievePrivacyGroupRequest{PrivacyGroupId: privacyGroupId}, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeletePrivacyGroup returns the id of the deleted privacy group
+func (t *tesseraPrivateTxManager) DeletePrivacyGroup(from string, privacyGroupId string) (string, error) {
+	if !t.features.HasFeature(engine.PrivacyGroups) {
+		return "", engine.ErrPrivateTxManagerNotSupported
+	}
+	var response string
+	if _, err := t.submitJSON(engine.OperationPrivacyGroup, "POST", "/deletePrivacyGroup", &deletePrivacyGroupRequest{
+		PrivacyGroupId: privacyGroupId,
+		From:           from,
+	}, &response); err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+// SetPersistentCache enables keeping received payloads in the given persistent cache, in addition to the in-memory one
+func (t *tesseraPrivateTxManager) SetPersistentCache(c *cache.Persistent) {
+	t.persistentCache = c
+}
+
+// Available returns an error while the circuit breaker considers tessera unavailable
+func (t *tesseraPrivateTxManager) Available() error {
+	if t.client.CircuitBreaker == nil {
+		return nil
+	}
+	return t.client.CircuitBreaker.Allow()
+}
+
+// Close stops the health checks of the failover endpoints of tessera, if any
+func (t *tesseraPrivateTxManager) Close() {
+	if t.client.Stop != nil {
+		t.client.Stop()
+	}
+}
+
+// ReloadTLS loads the TLS material of the connection to tessera again, e.g. after certificates have been rotated.
+// New connections use the new material, in-flight requests are not affected.
+func (t *tesseraPrivateTxManager) ReloadTLS() error {
+	if t.client.ReloadTLS == nil {
+		return engine.ErrPrivateTxManagerTLSNotInUse
+	}
+	return t.client.ReloadTLS()
+}
+
+// Refresh detects the API version of tessera again, e.g. after it has been upgraded, and updates the features in use.
+// If the new version does not support one of the required features, the features in use are left unchanged. Losing
+// any other feature detected before is refused as well, unless allowDowngrade is set.
+func (t *tesseraPrivateTxManager) Refresh(allowDowngrade bool, required ...engine.PrivateTransactionManagerFeature) (*engine.FeatureUpdate, error) {
+	t.refreshLock.Lock()
+	defer t.refreshLock.Unlock()
+
+	version, err := retrieveTesseraAPIVersion(t.client)
+	if err != nil {
+		return nil, err
+	}
+	ptmVersion, err := parseVersion([]byte(version))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the tessera API version %s: %v", version, err)
+	}
+	features := engine.NewFeatureSet(tesseraVersionFeatures(ptmVersion)...)
+	for _, f := range required {
+		if !features.HasFeature(f) {
+			log.Error("Refusing to downgrade the private transaction manager features", "version", version, "feature", f)
+			return nil, fmt.Errorf("tessera API version %s does not support %s which is required by the chain config", version, f)
+		}
+	}
+	if !allowDowngrade {
+		for _, f := range t.features.Features() {
+			if !features.HasFeature(f) {
+				log.Error("Refusing to downgrade the private transaction manager features", "version", version, "feature", f)
+				return nil, fmt.Errorf("tessera API version %s does not support %s which was detected before, downgrades must be allowed explicitly", version, f)
+			}
+		}
+	}
+	added, removed := t.features.Replace(features)
+	if len(added) > 0 || len(removed) > 0 {
+		log.Info("Private transaction manager features changed", "version", version, "added", engine.FeatureNames(added), "removed", engine.FeatureNames(removed))
+	}
+	return &engine.FeatureUpdate{
+		Version:  version,
+		Features: engine.FeatureNames(features.Features()),
+		Added:    engine.FeatureNames(added),
+		Removed:  engine.FeatureNames(removed),
+	}, nil
+}
+
 func (t *tesseraPrivateTxManager) Name() string {
 	return "Tessera"
 }
@@ -12,7 +12,9 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/private/cache"
 	"github.com/ethereum/go-ethereum/private/engine"
+	gocache "github.com/patrickmn/go-cache"
 	testifyassert "github.com/stretchr/testify/assert"
 )
 
@@ -527,6 +529,39 @@ func TestReceive_whenEncryptedPayloadHashIsEmpty(t *testing.T) {
 	assert.Nil(data, "returned payload when not found")
 }
 
+func TestReceiveUncached_whenPayloadIsCached(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	testObject.cache.Set(arbitraryHash1.Hex(), cache.PrivateCacheItem{Payload: []byte("cached payload")}, gocache.DefaultExpiration)
+	defer testObject.cache.Delete(arbitraryHash1.Hex())
+
+	_, _, data, actualExtra, err := testObject.ReceiveUncached(arbitraryHash1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	capturedRequest := <-receiveRequestCaptor
+
+	if capturedRequest.err != nil {
+		t.Fatalf("%s", capturedRequest.err)
+	}
+
+	assert.Equal(arbitraryHash1.ToBase64(), capturedRequest.request.(string), "requested hash")
+	assert.Equal(arbitraryPrivatePayload, data, "returned payload")
+	assert.Equal(arbitraryExtra.ACMerkleRoot, actualExtra.ACMerkleRoot, "returned merkle root")
+}
+
+func TestReceiveUncached_whenPayloadNotFound(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	_, _, data, _, err := testObject.ReceiveUncached(arbitraryNotFoundHash)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	<-receiveRequestCaptor
+
+	assert.Nil(data, "returned payload when not found")
+}
+
 func TestReceive_whenHavingPayloadButNoPrivateExtraMetadata(t *testing.T) {
 	assert := testifyassert.New(t)
 
@@ -660,3 +695,51 @@ func TestGetMandatory_notFound(t *testing.T) {
 
 	assert.Error(err, "Non-200 status code")
 }
+
+func TestCreatePrivacyGroup_whenTypical(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	var captured createPrivacyGroupRequest
+	var contentType string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/createPrivacyGroup", func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-type")
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(&engine.PrivacyGroup{
+			Type:           engine.PrivacyGroupPantheon,
+			Name:           captured.Name,
+			PrivacyGroupId: "arbitrary group id",
+			Members:        captured.Addresses,
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	ptm := New(&engine.Client{HttpClient: &http.Client{}, BaseURL: server.URL}, []byte("4.0"))
+
+	group, err := ptm.CreatePrivacyGroup(arbitraryFrom, "arbitrary name", "", arbitraryTo)
+
+	assert.NoError(err)
+	assert.Equal("application/json", contentType)
+	assert.Equal(arbitraryFrom, captured.From)
+	assert.Equal(arbitraryTo, captured.Addresses)
+	assert.Equal("arbitrary group id", group.PrivacyGroupId)
+	assert.Equal(arbitraryTo, group.Members)
+}
+
+func TestPrivacyGroups_whenNotSupported(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	ptm := New(&engine.Client{HttpClient: &http.Client{}, BaseURL: "http://not-used"}, []byte("2.1"))
+
+	_, err := ptm.CreatePrivacyGroup(arbitraryFrom, "", "", arbitraryTo)
+	assert.Equal(engine.ErrPrivateTxManagerNotSupported, err)
+	_, err = ptm.FindPrivacyGroup(arbitraryTo)
+	assert.Equal(engine.ErrPrivateTxManagerNotSupported, err)
+	_, err = ptm.RetrievePrivacyGroup("arbitrary group id")
+	assert.Equal(engine.ErrPrivateTxManagerNotSupported, err)
+	_, err = ptm.DeletePrivacyGroup(arbitraryFrom, "arbitrary group id")
+	assert.Equal(engine.ErrPrivateTxManagerNotSupported, err)
+}
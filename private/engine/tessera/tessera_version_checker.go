@@ -18,12 +18,14 @@ var (
 	multitenancyVersion          = Version{2, 1, 0}
 	multiplePrivateStatesVersion = Version{3, 0, 0}
 	mandatoryRecipientsVersion   = Version{4, 0, 0}
+	privacyGroupsVersion         = Version{3, 0, 0}
 
 	featureVersions = map[engine.PrivateTransactionManagerFeature]Version{
 		engine.PrivacyEnhancements:   privacyEnhancementsVersion,
 		engine.MultiTenancy:          multitenancyVersion,
 		engine.MultiplePrivateStates: multiplePrivateStatesVersion,
 		engine.MandatoryRecipients:   mandatoryRecipientsVersion,
+		engine.PrivacyGroups:         privacyGroupsVersion,
 	}
 )
 
@@ -2,6 +2,7 @@ package tessera
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -13,24 +14,31 @@ const apiVersion1 = "1.0"
 
 // this method will be removed once quorum will implement a versioned tessera client (in line with tessera API versioning)
 func RetrieveTesseraAPIVersion(client *engine.Client) string {
-	res, err := client.Get("/version/api")
+	version, err := retrieveTesseraAPIVersion(client)
 	if err != nil {
-		log.Error("Error invoking the tessera /version/api API:", "err", err)
+		log.Error("Unable to retrieve the tessera API version, assuming the oldest one", "err", err)
 		return apiVersion1
 	}
+	log.Info(fmt.Sprintf("Tessera API version: %s", version))
+	return version
+}
+
+// retrieveTesseraAPIVersion returns the latest API version supported by tessera, or an error if it cannot be determined
+func retrieveTesseraAPIVersion(client *engine.Client) (string, error) {
+	res, err := client.Get("/version/api")
+	if err != nil {
+		return "", fmt.Errorf("error invoking the tessera /version/api API: %v", err)
+	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		log.Error(fmt.Sprintf("Invalid status code returned by the tessera /version/api API: %d.", res.StatusCode))
-		return apiVersion1
+		return "", fmt.Errorf("invalid status code returned by the tessera /version/api API: %d", res.StatusCode)
 	}
 	var versions []string
 	if err := json.NewDecoder(res.Body).Decode(&versions); err != nil {
-		log.Error("Unable to deserialize the tessera response for /version/api API:", "err", err)
-		return apiVersion1
+		return "", fmt.Errorf("unable to deserialize the tessera response for /version/api API: %v", err)
 	}
 	if len(versions) == 0 {
-		log.Error("Expecting at least one API version to be returned by the tessera /version/api API.")
-		return apiVersion1
+		return "", errors.New("expecting at least one API version to be returned by the tessera /version/api API")
 	}
 	// pick the latest version from the versions array
 	latestVersion := apiVersion1
@@ -50,6 +58,5 @@ func RetrieveTesseraAPIVersion(client *engine.Client) string {
 			latestParsedVersion = parsedVer
 		}
 	}
-	log.Info(fmt.Sprintf("Tessera API version: %s", latestVersion))
-	return latestVersion
+	return latestVersion, nil
 }
@@ -119,3 +119,83 @@ func TestVersionApi_validVersion(t *testing.T) {
 
 	assert.Equal("2.0", version)
 }
+
+func TestRefresh_UpdatesFeatures(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	apiVersions := `["1.0"]`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version/api", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(apiVersions))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	ptm := New(&engine.Client{HttpClient: &http.Client{}, BaseURL: server.URL}, []byte("1.0"))
+	assert.False(ptm.HasFeature(engine.MandatoryRecipients))
+
+	apiVersions = `["1.0","2.0","3.0","4.0"]`
+	update, err := ptm.Refresh(false, engine.PrivacyEnhancements)
+
+	assert.NoError(err)
+	assert.Equal("4.0", update.Version)
+	assert.Equal([]string{"PrivacyEnhancements", "MultiTenancy", "MultiplePrivateStates", "MandatoryRecipients", "PrivacyGroups"}, update.Added)
+	assert.Empty(update.Removed)
+	assert.True(ptm.HasFeature(engine.MandatoryRecipients))
+}
+
+func TestRefresh_RefusesToDowngradeRequiredFeatures(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	apiVersions := `["4.0"]`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version/api", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(apiVersions))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	ptm := New(&engine.Client{HttpClient: &http.Client{}, BaseURL: server.URL}, []byte("4.0"))
+
+	apiVersions = `["2.0"]`
+	_, err := ptm.Refresh(true, engine.MultiplePrivateStates)
+
+	assert.Error(err)
+	assert.True(ptm.HasFeature(engine.MultiplePrivateStates), "features must be left unchanged")
+}
+
+func TestRefresh_RefusesToDowngradeFeaturesUnlessAllowed(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	apiVersions := `["4.0"]`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version/api", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(apiVersions))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	ptm := New(&engine.Client{HttpClient: &http.Client{}, BaseURL: server.URL}, []byte("4.0"))
+
+	apiVersions = `["3.0"]`
+	_, err := ptm.Refresh(false)
+
+	assert.EqualError(err, "tessera API version 3.0 does not support MandatoryRecipients which was detected before, downgrades must be allowed explicitly")
+	assert.True(ptm.HasFeature(engine.MandatoryRecipients), "features must be left unchanged")
+
+	update, err := ptm.Refresh(true)
+
+	assert.NoError(err)
+	assert.Equal([]string{"MandatoryRecipients"}, update.Removed)
+	assert.False(ptm.HasFeature(engine.MandatoryRecipients))
+}
+
+func TestRefresh_UnreachableTransactionManager(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	server := httptest.NewServer(http.NewServeMux())
+	ptm := New(&engine.Client{HttpClient: &http.Client{}, BaseURL: server.URL}, []byte("4.0"))
+	server.Close()
+
+	_, err := ptm.Refresh(false)
+
+	assert.Error(err)
+	assert.True(ptm.HasFeature(engine.MandatoryRecipients), "features must be left unchanged")
+}
@@ -0,0 +1,74 @@
+package tesseratest
+
+import "github.com/ethereum/go-ethereum/private/engine"
+
+// request object for /send API
+type sendRequest struct {
+	Payload []byte `json:"payload"`
+
+	// base64-encoded
+	From string `json:"from,omitempty"`
+
+	To []string `json:"to"`
+
+	// Transactions' encrypted payload hashes for affected contracts
+	AffectedContractTransactions []string `json:"affectedContractTransactions"`
+
+	// Merkle root for affected contracts
+	ExecHash string `json:"execHash,omitempty"`
+
+	PrivacyFlag engine.PrivacyFlagType `json:"privacyFlag"`
+
+	MandatoryRecipients []string `json:"mandatoryRecipients"`
+}
+
+// request object for /storeraw API
+type storerawRequest struct {
+	Payload []byte `json:"payload"`
+
+	// base64-encoded
+	From string `json:"from,omitempty"`
+}
+
+// response object for /send, /storeraw and /sendsignedtx APIs
+type sendResponse struct {
+	// Base64-encoded
+	Key string `json:"key"`
+	// Public Keys
+	ManagedParties []string `json:"managedParties"`
+	// Sender tessera public key
+	SenderKey string `json:"senderKey"`
+}
+
+type receiveResponse struct {
+	Payload []byte `json:"payload"`
+
+	// Transactions' encrypted payload hashes for affected contracts
+	AffectedContractTransactions []string `json:"affectedContractTransactions"`
+
+	// Merkle root for affected contracts
+	ExecHash string `json:"execHash"`
+
+	PrivacyFlag engine.PrivacyFlagType `json:"privacyFlag"`
+
+	// Public Keys
+	ManagedParties []string `json:"managedParties"`
+	// Sender tessera public key
+	SenderKey string `json:"senderKey"`
+}
+
+type sendSignedTxRequest struct {
+	Hash []byte   `json:"hash"`
+	To   []string `json:"to"`
+	// Transactions' encrypted payload hashes for affected contracts
+	AffectedContractTransactions []string `json:"affectedContractTransactions"`
+	// Merkle root for affected contracts
+	ExecHash string `json:"execHash,omitempty"`
+
+	PrivacyFlag engine.PrivacyFlagType `json:"privacyFlag"`
+
+	MandatoryRecipients []string `json:"mandatoryRecipients"`
+
+	// set when the request was sent as an octet stream by pre privacy enhancements clients
+	isLegacy bool
+}
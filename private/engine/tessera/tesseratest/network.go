@@ -0,0 +1,99 @@
+// Package tesseratest provides an in-process stand-in for Tessera, serving the Q2T HTTP API
+// used by the tessera private transaction manager client. Several virtual nodes share the
+// storage of a Network, so that payloads sent from one node can be received by the others.
+//
+// It is meant for integration tests only: payloads are not encrypted and nothing is persisted.
+package tesseratest
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"golang.org/x/crypto/sha3"
+)
+
+// storedTransaction is a payload distributed to its recipients
+type storedTransaction struct {
+	payload                      []byte
+	sender                       string
+	recipients                   []string
+	affectedContractTransactions []string
+	execHash                     string
+	privacyFlag                  engine.PrivacyFlagType
+	mandatoryRecipients          []string
+}
+
+// storedRaw is a payload stored by /storeraw, waiting to be distributed using /sendsignedtx
+type storedRaw struct {
+	payload []byte
+	sender  string
+}
+
+// Network is the storage shared by all the virtual nodes created from it
+type Network struct {
+	mu           sync.RWMutex
+	transactions map[common.EncryptedPayloadHash]*storedTransaction
+	raws         map[common.EncryptedPayloadHash]*storedRaw
+	counter      uint64
+}
+
+func NewNetwork() *Network {
+	return &Network{
+		transactions: make(map[common.EncryptedPayloadHash]*storedTransaction),
+		raws:         make(map[common.EncryptedPayloadHash]*storedRaw),
+	}
+}
+
+// newHash must be called while holding the write lock. Every stored payload gets a unique hash,
+// even when the same data is sent twice, as it would with real encryption.
+func (n *Network) newHash(payload []byte) common.EncryptedPayloadHash {
+	n.counter++
+	nonce := make([]byte, 8)
+	binary.BigEndian.PutUint64(nonce, n.counter)
+	return common.EncryptedPayloadHash(sha3.Sum512(append(nonce, payload...)))
+}
+
+func (n *Network) storeTransaction(tx *storedTransaction) common.EncryptedPayloadHash {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	hash := n.newHash(tx.payload)
+	n.transactions[hash] = tx
+	return hash
+}
+
+func (n *Network) storeRaw(raw *storedRaw) common.EncryptedPayloadHash {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	hash := n.newHash(raw.payload)
+	n.raws[hash] = raw
+	return hash
+}
+
+// distributeRaw turns a raw payload into a transaction, keeping the hash returned by /storeraw
+func (n *Network) distributeRaw(hash common.EncryptedPayloadHash, build func(raw *storedRaw) *storedTransaction) (*storedTransaction, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	raw, ok := n.raws[hash]
+	if !ok {
+		return nil, false
+	}
+	tx := build(raw)
+	n.transactions[hash] = tx
+	return tx, true
+}
+
+func (n *Network) transaction(hash common.EncryptedPayloadHash) (*storedTransaction, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	tx, ok := n.transactions[hash]
+	return tx, ok
+}
+
+func (n *Network) raw(hash common.EncryptedPayloadHash) (*storedRaw, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	raw, ok := n.raws[hash]
+	return raw, ok
+}
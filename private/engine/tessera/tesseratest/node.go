@@ -0,0 +1,334 @@
+package tesseratest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	http2 "github.com/ethereum/go-ethereum/common/http"
+	"github.com/ethereum/go-ethereum/private/engine"
+)
+
+const DefaultVersion = "4.0.0"
+
+// NodeConfig describes a virtual Tessera node
+type NodeConfig struct {
+	// Public keys managed by the node, the first one is used as sender when none is given
+	Keys []string
+	// Version reported by /version, defaults to DefaultVersion
+	Version string
+	// Groups reported by /groups/resident, defaults to a single "private" group containing all the keys
+	ResidentGroups []engine.PrivacyGroup
+}
+
+// Node is a virtual Tessera node serving the Q2T API
+type Node struct {
+	network *Network
+	config  NodeConfig
+	keys    map[string]struct{}
+	server  *httptest.Server
+	socket  string
+}
+
+func (n *Network) NewNode(config NodeConfig) *Node {
+	if config.Version == "" {
+		config.Version = DefaultVersion
+	}
+	if config.ResidentGroups == nil {
+		config.ResidentGroups = []engine.PrivacyGroup{
+			{
+				Type:           engine.PrivacyGroupResident,
+				Name:           "private",
+				PrivacyGroupId: "private",
+				Description:    "default resident group",
+				Members:        config.Keys,
+			},
+		}
+	}
+	keys := make(map[string]struct{}, len(config.Keys))
+	for _, key := range config.Keys {
+		keys[key] = struct{}{}
+	}
+	return &Node{
+		network: n,
+		config:  config,
+		keys:    keys,
+	}
+}
+
+// StartHTTP serves the Q2T API over HTTP on a random local port
+func (node *Node) StartHTTP() {
+	node.server = httptest.NewServer(node.Handler())
+}
+
+// StartUnix serves the Q2T API over the given unix domain socket
+func (node *Node) StartUnix(path string) error {
+	os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	node.server = &httptest.Server{
+		Listener: l,
+		Config:   &http.Server{Handler: node.Handler()},
+	}
+	node.server.Start()
+	node.socket = path
+	return nil
+}
+
+func (node *Node) Close() {
+	if node.server != nil {
+		node.server.Close()
+	}
+	if node.socket != "" {
+		os.Remove(node.socket)
+	}
+}
+
+// ClientConfig returns the configuration to connect a private transaction manager client to the node
+func (node *Node) ClientConfig() http2.Config {
+	cfg := http2.DefaultConfig
+	if node.socket != "" {
+		cfg.SetSocket(node.socket)
+	} else {
+		cfg.SetHttpUrl(node.server.URL)
+	}
+	return cfg
+}
+
+func (node *Node) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/upcheck", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("I'm up!"))
+	})
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(node.config.Version))
+	})
+	mux.HandleFunc("/version/api", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []string{"1.0", "2.0", "3.0", "4.0"})
+	})
+	mux.HandleFunc("/send", node.handleSend)
+	mux.HandleFunc("/storeraw", node.handleStoreRaw)
+	mux.HandleFunc("/sendsignedtx", node.handleSendSignedTx)
+	mux.HandleFunc("/groups/resident", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, node.config.ResidentGroups)
+	})
+	mux.HandleFunc("/transaction/", node.handleTransaction)
+	return mux
+}
+
+func (node *Node) isLocal(key string) bool {
+	_, ok := node.keys[key]
+	return ok
+}
+
+// managedParties returns the keys of this node among the given ones
+func (node *Node) managedParties(keys []string) []string {
+	result := make([]string, 0)
+	for _, key := range keys {
+		if node.isLocal(key) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func (node *Node) sender(from string) string {
+	if from == "" && len(node.config.Keys) > 0 {
+		return node.config.Keys[0]
+	}
+	return from
+}
+
+func recipients(sender string, to []string) []string {
+	result := []string{sender}
+	for _, key := range to {
+		if key != sender {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func (node *Node) handleSend(w http.ResponseWriter, r *http.Request) {
+	var req sendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sender := node.sender(req.From)
+	if !node.isLocal(sender) {
+		http.Error(w, "sender key is not managed by this node", http.StatusBadRequest)
+		return
+	}
+	tx := &storedTransaction{
+		payload:                      req.Payload,
+		sender:                       sender,
+		recipients:                   recipients(sender, req.To),
+		affectedContractTransactions: req.AffectedContractTransactions,
+		execHash:                     req.ExecHash,
+		privacyFlag:                  req.PrivacyFlag,
+		mandatoryRecipients:          req.MandatoryRecipients,
+	}
+	hash := node.network.storeTransaction(tx)
+	writeJSON(w, http.StatusCreated, &sendResponse{
+		Key:            hash.ToBase64(),
+		ManagedParties: node.managedParties(tx.recipients),
+		SenderKey:      sender,
+	})
+}
+
+func (node *Node) handleStoreRaw(w http.ResponseWriter, r *http.Request) {
+	var req storerawRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sender := node.sender(req.From)
+	if !node.isLocal(sender) {
+		http.Error(w, "sender key is not managed by this node", http.StatusBadRequest)
+		return
+	}
+	hash := node.network.storeRaw(&storedRaw{payload: req.Payload, sender: sender})
+	writeJSON(w, http.StatusOK, &sendResponse{
+		Key:            hash.ToBase64(),
+		ManagedParties: []string{sender},
+		SenderKey:      sender,
+	})
+}
+
+func (node *Node) handleSendSignedTx(w http.ResponseWriter, r *http.Request) {
+	var req sendSignedTxRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/octet-stream") {
+		// legacy form: the body is the hash and the recipients are in a header
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Hash = body
+		req.isLegacy = true
+		if to := r.Header.Get("c11n-to"); to != "" {
+			req.To = strings.Split(to, ",")
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hash := common.BytesToEncryptedPayloadHash(req.Hash)
+	if raw, ok := node.network.raw(hash); !ok || !node.isLocal(raw.sender) {
+		http.Error(w, "raw payload not found", http.StatusNotFound)
+		return
+	}
+	tx, ok := node.network.distributeRaw(hash, func(raw *storedRaw) *storedTransaction {
+		return &storedTransaction{
+			payload:                      raw.payload,
+			sender:                       raw.sender,
+			recipients:                   recipients(raw.sender, req.To),
+			affectedContractTransactions: req.AffectedContractTransactions,
+			execHash:                     req.ExecHash,
+			privacyFlag:                  req.PrivacyFlag,
+			mandatoryRecipients:          req.MandatoryRecipients,
+		}
+	})
+	if !ok {
+		http.Error(w, "raw payload not found", http.StatusNotFound)
+		return
+	}
+	managedParties := node.managedParties(tx.recipients)
+	if req.isLegacy {
+		w.Header().Set("Tesserasender", tx.sender)
+		w.Header()["Tesseramanagedparties"] = managedParties
+		w.Write([]byte(hash.ToBase64()))
+		return
+	}
+	writeJSON(w, http.StatusOK, &sendResponse{
+		Key:            hash.ToBase64(),
+		ManagedParties: managedParties,
+		SenderKey:      tx.sender,
+	})
+}
+
+// handleTransaction serves /transaction/{hash} and its /isSender, /participants and /mandatory sub-resources
+func (node *Node) handleTransaction(w http.ResponseWriter, r *http.Request) {
+	// the hash is base64 encoded and may contain escaped slashes, so split the escaped path
+	segments := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/transaction/"), "/")
+	b64, err := url.PathUnescape(segments[0])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hash, err := common.Base64ToEncryptedPayloadHash(b64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(segments) == 1 {
+		isRaw, _ := strconv.ParseBool(r.URL.Query().Get("isRaw"))
+		node.handleReceive(w, hash, isRaw)
+		return
+	}
+	tx, ok := node.network.transaction(hash)
+	if !ok || len(node.managedParties(tx.recipients)) == 0 {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	}
+	switch segments[1] {
+	case "isSender":
+		w.Write([]byte(strconv.FormatBool(node.isLocal(tx.sender))))
+	case "participants":
+		w.Write([]byte(strings.Join(tx.recipients, ",")))
+	case "mandatory":
+		w.Write([]byte(strings.Join(tx.mandatoryRecipients, ",")))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (node *Node) handleReceive(w http.ResponseWriter, hash common.EncryptedPayloadHash, isRaw bool) {
+	if isRaw {
+		raw, ok := node.network.raw(hash)
+		if !ok || !node.isLocal(raw.sender) {
+			http.Error(w, "raw payload not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, &receiveResponse{
+			Payload:        raw.payload,
+			ManagedParties: []string{raw.sender},
+			SenderKey:      raw.sender,
+		})
+		return
+	}
+	tx, ok := node.network.transaction(hash)
+	if !ok {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	}
+	managedParties := node.managedParties(tx.recipients)
+	if len(managedParties) == 0 {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, &receiveResponse{
+		Payload:                      tx.payload,
+		AffectedContractTransactions: tx.affectedContractTransactions,
+		ExecHash:                     tx.execHash,
+		PrivacyFlag:                  tx.privacyFlag,
+		ManagedParties:               managedParties,
+		SenderKey:                    tx.sender,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
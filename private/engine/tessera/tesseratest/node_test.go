@@ -0,0 +1,145 @@
+package tesseratest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	http2 "github.com/ethereum/go-ethereum/common/http"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/ethereum/go-ethereum/private/engine/tessera"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	alice = "BULeR8JyUWhiuuCMU/HLA0Q5pzkYT+cHII3ZKBey3Bo="
+	bob   = "QfeDAys9MPDs2XHExtc84jKGHxZg/aj52DTh0vtA3Xc="
+	carol = "1iTZde/ndBHvzhcl7V68x44Vx7pl8nwx9LqnM/AfJUg="
+)
+
+var arbitraryPayload = []byte("arbitrary private payload")
+
+func startHTTPNode(t *testing.T, network *Network, config NodeConfig) (*Node, private.PrivateTransactionManager) {
+	node := network.NewNode(config)
+	node.StartHTTP()
+	t.Cleanup(node.Close)
+	return node, newTestPTM(t, node)
+}
+
+func newTestPTM(t *testing.T, node *Node) private.PrivateTransactionManager {
+	client, err := http2.CreateClient(node.ClientConfig())
+	require.NoError(t, err)
+	return tessera.New(client, []byte(node.config.Version))
+}
+
+func TestNode_SendAndReceiveAcrossNodes(t *testing.T) {
+	network := NewNetwork()
+	_, sender := startHTTPNode(t, network, NodeConfig{Keys: []string{alice}})
+	_, recipient := startHTTPNode(t, network, NodeConfig{Keys: []string{bob}})
+	_, outsider := startHTTPNode(t, network, NodeConfig{Keys: []string{carol}})
+
+	extra := &engine.ExtraMetadata{
+		ACHashes:            common.EncryptedPayloadHashes{},
+		PrivacyFlag:         engine.PrivacyFlagMandatoryRecipients,
+		MandatoryRecipients: []string{bob},
+	}
+	senderKey, managedParties, hash, err := sender.Send(arbitraryPayload, "", []string{bob}, extra)
+	require.NoError(t, err)
+	assert.Equal(t, alice, senderKey)
+	assert.Equal(t, []string{alice}, managedParties)
+
+	actualSender, actualManagedParties, payload, actualExtra, err := recipient.Receive(hash)
+	require.NoError(t, err)
+	assert.Equal(t, alice, actualSender)
+	assert.Equal(t, []string{bob}, actualManagedParties)
+	assert.Equal(t, arbitraryPayload, payload)
+	assert.Equal(t, engine.PrivacyFlagMandatoryRecipients, actualExtra.PrivacyFlag)
+
+	_, _, payload, _, err = outsider.Receive(hash)
+	require.NoError(t, err)
+	assert.Nil(t, payload, "payload must not be disclosed to a node which is not a recipient")
+
+	isSender, err := sender.IsSender(hash)
+	require.NoError(t, err)
+	assert.True(t, isSender)
+	isSender, err = recipient.IsSender(hash)
+	require.NoError(t, err)
+	assert.False(t, isSender)
+
+	participants, err := recipient.GetParticipants(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice, bob}, participants)
+
+	mandatory, err := recipient.GetMandatory(hash)
+	require.NoError(t, err)
+	assert.Equal(t, []string{bob}, mandatory)
+}
+
+func TestNode_StoreRawAndSendSignedTx(t *testing.T) {
+	for _, version := range []string{DefaultVersion, "0.10.0"} {
+		t.Run(version, func(t *testing.T) {
+			network := NewNetwork()
+			_, sender := startHTTPNode(t, network, NodeConfig{Keys: []string{alice}, Version: version})
+			_, recipient := startHTTPNode(t, network, NodeConfig{Keys: []string{bob}, Version: version})
+
+			hash, err := sender.StoreRaw(arbitraryPayload, alice)
+			require.NoError(t, err)
+
+			payload, senderKey, _, err := sender.ReceiveRaw(hash)
+			require.NoError(t, err)
+			assert.Equal(t, arbitraryPayload, payload)
+			assert.Equal(t, alice, senderKey)
+
+			// the raw payload is not available to other nodes until it is distributed
+			_, _, payload, _, err = recipient.Receive(hash)
+			require.NoError(t, err)
+			assert.Nil(t, payload)
+
+			senderKey, managedParties, returnedHash, err := sender.SendSignedTx(hash, []string{bob}, &engine.ExtraMetadata{})
+			require.NoError(t, err)
+			assert.Equal(t, alice, senderKey)
+			assert.Equal(t, []string{alice}, managedParties)
+			assert.Equal(t, hash.Bytes(), returnedHash)
+
+			_, _, payload, _, err = recipient.Receive(hash)
+			require.NoError(t, err)
+			assert.Equal(t, arbitraryPayload, payload)
+		})
+	}
+}
+
+func TestNode_ResidentGroups(t *testing.T) {
+	network := NewNetwork()
+	_, ptm := startHTTPNode(t, network, NodeConfig{Keys: []string{alice, bob}})
+
+	groups, err := ptm.Groups()
+
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, engine.PrivacyGroupResident, groups[0].Type)
+	assert.Equal(t, "private", groups[0].PrivacyGroupId)
+	assert.Equal(t, []string{alice, bob}, groups[0].Members)
+}
+
+func TestNode_UnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tesseratest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	network := NewNetwork()
+	node := network.NewNode(NodeConfig{Keys: []string{alice}})
+	require.NoError(t, node.StartUnix(filepath.Join(dir, "tm.ipc")))
+	defer node.Close()
+	ptm := newTestPTM(t, node)
+
+	_, _, hash, err := ptm.Send(arbitraryPayload, "", nil, &engine.ExtraMetadata{})
+	require.NoError(t, err)
+
+	_, _, payload, _, err := ptm.Receive(hash)
+	require.NoError(t, err)
+	assert.Equal(t, arbitraryPayload, payload)
+}
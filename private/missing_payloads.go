@@ -0,0 +1,273 @@
+package private
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/private/engine"
+)
+
+// Reasons for which the local private transaction manager is expected to hold a payload
+const (
+	ReasonIsSender      = "isSender"      // the private transaction manager reports the node as the sender
+	ReasonParticipant   = "participant"   // one of the local keys is a participant of the transaction
+	ReasonExecutionLogs = "executionLogs" // the transaction produced logs when it was executed, so the payload was available then
+)
+
+// ChainReader provides the blocks and receipts to check, it is implemented by core.BlockChain
+type ChainReader interface {
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+}
+
+// MissingPayload is a private transaction, or privacy marker transaction, for which the local private
+// transaction manager is expected to hold the payload but is not able to return it
+type MissingPayload struct {
+	BlockNumber   uint64          `json:"blockNumber"`
+	BlockHash     common.Hash     `json:"blockHash"`
+	TxHash        common.Hash     `json:"txHash"`
+	PayloadHash   string          `json:"payloadHash"`
+	PrivacyMarker bool            `json:"privacyMarker"`
+	Contract      *common.Address `json:"contract,omitempty"`
+	Reasons       []string        `json:"reasons"`
+}
+
+// PayloadCheckError is a private transaction, or privacy marker transaction, which could not be checked,
+// e.g. because the private transaction manager returned an error
+type PayloadCheckError struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	TxHash      common.Hash `json:"txHash"`
+	Error       string      `json:"error"`
+}
+
+// MissingPayloadReport is the outcome of the check of the private payloads of a range of blocks
+type MissingPayloadReport struct {
+	FromBlock           uint64               `json:"fromBlock"`
+	ToBlock             uint64               `json:"toBlock"`
+	PrivateTransactions int                  `json:"privateTransactions"`
+	Missing             []*MissingPayload    `json:"missing"`
+	Failed              []*PayloadCheckError `json:"failed"`
+	AffectedBlocks      []uint64             `json:"affectedBlocks"`
+	AffectedContracts   []common.Address     `json:"affectedContracts"`
+}
+
+// FindMissingPayloads walks the blocks from first to last (inclusive) and reports the private transactions
+// whose payload the private transaction manager should have but is not able to return, e.g. because its
+// database has been restored from an older backup. Such transactions were executed as if the node was not
+// a party, so the private state of the affected contracts has diverged.
+//
+// The private transaction manager is queried directly when it supports it, as the payloads served from its
+// caches are not necessarily still held by the transaction manager. Transactions which cannot be checked are
+// reported as failed and do not stop the check of the remaining ones.
+func FindMissingPayloads(chain ChainReader, ptm PrivateTransactionManager, first, last uint64) (*MissingPayloadReport, error) {
+	if first > last {
+		return nil, fmt.Errorf("invalid block range %d-%d", first, last)
+	}
+	checker := &payloadChecker{
+		ptm:       ptm,
+		localKeys: localKeys(ptm),
+		report: &MissingPayloadReport{
+			FromBlock:         first,
+			ToBlock:           last,
+			Missing:           make([]*MissingPayload, 0),
+			Failed:            make([]*PayloadCheckError, 0),
+			AffectedBlocks:    make([]uint64, 0),
+			AffectedContracts: make([]common.Address, 0),
+		},
+		contracts: make(map[common.Address]struct{}),
+	}
+	for number := first; number <= last; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", number)
+		}
+		checker.checkBlock(chain, block)
+	}
+	return checker.report, nil
+}
+
+// localKeys returns the keys managed by the private transaction manager, when it is able to tell
+func localKeys(ptm PrivateTransactionManager) map[string]struct{} {
+	keys := make(map[string]struct{})
+	if !ptm.HasFeature(engine.MultiplePrivateStates) {
+		return keys
+	}
+	groups, err := ptm.Groups()
+	if err != nil {
+		log.Warn("Unable to retrieve the resident groups of the private transaction manager", "err", err)
+		return keys
+	}
+	for _, group := range groups {
+		if group.Type != engine.PrivacyGroupResident {
+			continue
+		}
+		for _, member := range group.Members {
+			keys[member] = struct{}{}
+		}
+	}
+	return keys
+}
+
+type payloadChecker struct {
+	ptm       PrivateTransactionManager
+	localKeys map[string]struct{}
+	report    *MissingPayloadReport
+	contracts map[common.Address]struct{}
+}
+
+func (c *payloadChecker) checkBlock(chain ChainReader, block *types.Block) {
+	var receipts types.Receipts
+	affected := false
+	for i, tx := range block.Transactions() {
+		if !tx.IsPrivate() && !tx.IsPrivacyMarker() {
+			continue
+		}
+		if receipts == nil {
+			receipts = chain.GetReceiptsByHash(block.Hash())
+		}
+		var receipt *types.Receipt
+		if i < len(receipts) {
+			receipt = receipts[i]
+		}
+		missing, err := c.checkTransaction(tx, receipt)
+		if err != nil {
+			log.Warn("Unable to check the private payload of a transaction", "block", block.NumberU64(), "tx", tx.Hash(), "err", err)
+			c.report.Failed = append(c.report.Failed, &PayloadCheckError{
+				BlockNumber: block.NumberU64(),
+				BlockHash:   block.Hash(),
+				TxHash:      tx.Hash(),
+				Error:       err.Error(),
+			})
+			continue
+		}
+		if missing == nil {
+			continue
+		}
+		missing.BlockNumber = block.NumberU64()
+		missing.BlockHash = block.Hash()
+		c.report.Missing = append(c.report.Missing, missing)
+		if missing.Contract != nil {
+			if _, ok := c.contracts[*missing.Contract]; !ok {
+				c.contracts[*missing.Contract] = struct{}{}
+				c.report.AffectedContracts = append(c.report.AffectedContracts, *missing.Contract)
+			}
+		}
+		affected = true
+	}
+	if affected {
+		c.report.AffectedBlocks = append(c.report.AffectedBlocks, block.NumberU64())
+	}
+}
+
+// checkTransaction returns nil if the payload of tx is available, or if there is no evidence the node is a party
+func (c *payloadChecker) checkTransaction(tx *types.Transaction, receipt *types.Receipt) (*MissingPayload, error) {
+	c.report.PrivateTransactions++
+	hash := common.BytesToEncryptedPayloadHash(tx.Data())
+	payload, err := c.receive(hash)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return c.missingPayload(tx, hash, receipt)
+	}
+	if !tx.IsPrivacyMarker() {
+		return nil, nil
+	}
+	// the payload of a privacy marker transaction is the private transaction, check its own payload as well
+	var innerTx types.Transaction
+	if err := json.NewDecoder(bytes.NewReader(payload)).Decode(&innerTx); err != nil {
+		return nil, fmt.Errorf("unable to decode the private transaction: %v", err)
+	}
+	innerHash := common.BytesToEncryptedPayloadHash(innerTx.Data())
+	if payload, err = c.receive(innerHash); err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		return nil, nil
+	}
+	missing, err := c.missingPayload(&innerTx, innerHash, receipt)
+	if missing != nil {
+		missing.PrivacyMarker = true
+		missing.TxHash = tx.Hash()
+	}
+	return missing, err
+}
+
+// receive returns the payload held by the private transaction manager, bypassing its caches when possible
+func (c *payloadChecker) receive(hash common.EncryptedPayloadHash) ([]byte, error) {
+	if receiver, ok := c.ptm.(UncachedReceiver); ok {
+		_, _, payload, _, err := receiver.ReceiveUncached(hash)
+		return payload, err
+	}
+	_, _, payload, _, err := c.ptm.Receive(hash)
+	return payload, err
+}
+
+func (c *payloadChecker) missingPayload(tx *types.Transaction, hash common.EncryptedPayloadHash, receipt *types.Receipt) (*MissingPayload, error) {
+	reasons := make([]string, 0)
+	// the private transaction manager may still know about the transaction even though it cannot return the payload,
+	// errors mean it does not know the transaction at all
+	if isSender, err := c.ptm.IsSender(hash); err == nil && isSender {
+		reasons = append(reasons, ReasonIsSender)
+	}
+	if participants, err := c.ptm.GetParticipants(hash); err == nil {
+		for _, participant := range participants {
+			if _, ok := c.localKeys[participant]; ok {
+				reasons = append(reasons, ReasonParticipant)
+				break
+			}
+		}
+	}
+	if receipt != nil && hasLogs(receipt, tx.IsPrivate()) {
+		reasons = append(reasons, ReasonExecutionLogs)
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+	return &MissingPayload{
+		TxHash:        tx.Hash(),
+		PayloadHash:   hash.ToBase64(),
+		PrivacyMarker: tx.IsPrivacyMarker(),
+		Contract:      contractAddress(tx, receipt),
+		Reasons:       reasons,
+	}, nil
+}
+
+// hasLogs returns true if the execution of the private transaction produced logs, in any private state
+func hasLogs(receipt *types.Receipt, includeSelf bool) bool {
+	if includeSelf && len(receipt.Logs) > 0 {
+		return true
+	}
+	for _, psReceipt := range receipt.PSReceipts {
+		if psReceipt != nil && hasLogs(psReceipt, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func contractAddress(tx *types.Transaction, receipt *types.Receipt) *common.Address {
+	if tx.To() != nil && !tx.IsPrivacyMarker() {
+		to := *tx.To()
+		return &to
+	}
+	if receipt == nil {
+		return nil
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		address := receipt.ContractAddress
+		return &address
+	}
+	for _, psReceipt := range receipt.PSReceipts {
+		if psReceipt != nil && psReceipt.ContractAddress != (common.Address{}) {
+			address := psReceipt.ContractAddress
+			return &address
+		}
+	}
+	return nil
+}
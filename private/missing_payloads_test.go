@@ -0,0 +1,187 @@
+package private
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uncachedPTM answers ReceiveUncached from its own payloads, as opposed to Receive which is answered by the mock
+type uncachedPTM struct {
+	*MockPrivateTransactionManager
+	payloads map[common.EncryptedPayloadHash][]byte
+}
+
+func (p *uncachedPTM) ReceiveUncached(hash common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error) {
+	return "", nil, p.payloads[hash], nil, nil
+}
+
+type stubChainReader struct {
+	blocks   map[uint64]*types.Block
+	receipts map[common.Hash]types.Receipts
+}
+
+func (c *stubChainReader) GetBlockByNumber(number uint64) *types.Block {
+	return c.blocks[number]
+}
+
+func (c *stubChainReader) GetReceiptsByHash(hash common.Hash) types.Receipts {
+	return c.receipts[hash]
+}
+
+func (c *stubChainReader) addBlock(number uint64, txs types.Transactions, receipts types.Receipts) {
+	block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number)}).WithBody(txs, nil)
+	c.blocks[number] = block
+	c.receipts[block.Hash()] = receipts
+}
+
+func newStubChainReader() *stubChainReader {
+	return &stubChainReader{
+		blocks:   make(map[uint64]*types.Block),
+		receipts: make(map[common.Hash]types.Receipts),
+	}
+}
+
+func TestFindMissingPayloads_ReportsAffectedContractsAndBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	availableHash := common.BytesToEncryptedPayloadHash([]byte("available"))
+	sentHash := common.BytesToEncryptedPayloadHash([]byte("sent"))
+	unrelatedHash := common.BytesToEncryptedPayloadHash([]byte("unrelated"))
+	markerHash := common.BytesToEncryptedPayloadHash([]byte("marker"))
+	innerHash := common.BytesToEncryptedPayloadHash([]byte("inner"))
+	contract := common.Address{1}
+	created := common.Address{9}
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	innerTx, err := types.SignTx(types.NewContractCreation(0, big.NewInt(0), 0, big.NewInt(0), innerHash.Bytes()), types.HomesteadSigner{}, key)
+	require.NoError(t, err)
+	innerTx.SetPrivate()
+	innerTxData, err := json.Marshal(innerTx)
+	require.NoError(t, err)
+
+	markerReceipt := &types.Receipt{}
+	markerReceipt.PSReceipts = map[types.PrivateStateIdentifier]*types.Receipt{
+		types.DefaultPrivateStateIdentifier: {ContractAddress: created, Logs: []*types.Log{{}}},
+	}
+
+	chain := newStubChainReader()
+	chain.addBlock(1, types.Transactions{newPrivateTx(t, availableHash)}, types.Receipts{{}})
+	chain.addBlock(2, types.Transactions{
+		types.NewTransaction(0, common.Address{2}, big.NewInt(0), 0, big.NewInt(0), []byte("public")),
+		newPrivateTx(t, sentHash),
+		newPrivateTx(t, unrelatedHash),
+	}, types.Receipts{{}, {}, {}})
+	chain.addBlock(3, types.Transactions{
+		types.NewTransaction(0, common.QuorumPrivacyPrecompileContractAddress(), big.NewInt(0), 0, big.NewInt(0), markerHash.Bytes()),
+	}, types.Receipts{markerReceipt})
+
+	mockPTM := NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().HasFeature(engine.MultiplePrivateStates).Return(true)
+	mockPTM.EXPECT().Groups().Return([]engine.PrivacyGroup{
+		{Type: engine.PrivacyGroupResident, Members: []string{"local"}},
+	}, nil)
+	mockPTM.EXPECT().Receive(availableHash).Return("", nil, []byte("payload"), nil, nil)
+	mockPTM.EXPECT().Receive(sentHash).Return("", nil, nil, nil, nil)
+	mockPTM.EXPECT().IsSender(sentHash).Return(true, nil)
+	mockPTM.EXPECT().GetParticipants(sentHash).Return([]string{"local", "remote"}, nil)
+	mockPTM.EXPECT().Receive(unrelatedHash).Return("", nil, nil, nil, nil)
+	mockPTM.EXPECT().IsSender(unrelatedHash).Return(false, engine.ErrPrivateTxManagerNotSupported)
+	mockPTM.EXPECT().GetParticipants(unrelatedHash).Return(nil, engine.ErrPrivateTxManagerNotSupported)
+	mockPTM.EXPECT().Receive(markerHash).Return("", nil, innerTxData, nil, nil)
+	mockPTM.EXPECT().Receive(innerHash).Return("", nil, nil, nil, nil)
+	mockPTM.EXPECT().IsSender(innerHash).Return(false, nil)
+	mockPTM.EXPECT().GetParticipants(innerHash).Return([]string{"remote"}, nil)
+
+	report, err := FindMissingPayloads(chain, mockPTM, 1, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.PrivateTransactions)
+	require.Len(t, report.Missing, 2)
+	assert.Equal(t, uint64(2), report.Missing[0].BlockNumber)
+	assert.Equal(t, sentHash.ToBase64(), report.Missing[0].PayloadHash)
+	assert.Equal(t, []string{ReasonIsSender, ReasonParticipant}, report.Missing[0].Reasons)
+	assert.False(t, report.Missing[0].PrivacyMarker)
+	assert.Equal(t, uint64(3), report.Missing[1].BlockNumber)
+	assert.Equal(t, chain.blocks[3].Transactions()[0].Hash(), report.Missing[1].TxHash)
+	assert.Equal(t, innerHash.ToBase64(), report.Missing[1].PayloadHash)
+	assert.Equal(t, []string{ReasonExecutionLogs}, report.Missing[1].Reasons)
+	assert.True(t, report.Missing[1].PrivacyMarker)
+	assert.Equal(t, []uint64{2, 3}, report.AffectedBlocks)
+	assert.Equal(t, []common.Address{contract, created}, report.AffectedContracts)
+}
+
+func TestFindMissingPayloads_MissingBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPTM := NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().HasFeature(engine.MultiplePrivateStates).Return(false)
+
+	_, err := FindMissingPayloads(newStubChainReader(), mockPTM, 1, 1)
+
+	assert.EqualError(t, err, "block 1 not found")
+}
+
+func TestFindMissingPayloads_BypassesCachedPayloads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cachedHash := common.BytesToEncryptedPayloadHash([]byte("cached"))
+	chain := newStubChainReader()
+	chain.addBlock(1, types.Transactions{newPrivateTx(t, cachedHash)}, types.Receipts{{}})
+
+	mockPTM := NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().HasFeature(engine.MultiplePrivateStates).Return(false)
+	mockPTM.EXPECT().Receive(gomock.Any()).Times(0)
+	mockPTM.EXPECT().IsSender(cachedHash).Return(true, nil)
+	mockPTM.EXPECT().GetParticipants(cachedHash).Return(nil, engine.ErrPrivateTxManagerNotSupported)
+
+	report, err := FindMissingPayloads(chain, &uncachedPTM{MockPrivateTransactionManager: mockPTM}, 1, 1)
+
+	require.NoError(t, err)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, cachedHash.ToBase64(), report.Missing[0].PayloadHash)
+	assert.Equal(t, []string{ReasonIsSender}, report.Missing[0].Reasons)
+}
+
+func TestFindMissingPayloads_RecordsFailedTransactionsAndCarriesOn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failingHash := common.BytesToEncryptedPayloadHash([]byte("failing"))
+	sentHash := common.BytesToEncryptedPayloadHash([]byte("sent"))
+	chain := newStubChainReader()
+	chain.addBlock(1, types.Transactions{newPrivateTx(t, failingHash)}, types.Receipts{{}})
+	chain.addBlock(2, types.Transactions{newPrivateTx(t, sentHash)}, types.Receipts{{}})
+
+	mockPTM := NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().HasFeature(engine.MultiplePrivateStates).Return(false)
+	mockPTM.EXPECT().Receive(failingHash).Return("", nil, nil, nil, errors.New("connection refused"))
+	mockPTM.EXPECT().Receive(sentHash).Return("", nil, nil, nil, nil)
+	mockPTM.EXPECT().IsSender(sentHash).Return(true, nil)
+	mockPTM.EXPECT().GetParticipants(sentHash).Return(nil, engine.ErrPrivateTxManagerNotSupported)
+
+	report, err := FindMissingPayloads(chain, mockPTM, 1, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.PrivateTransactions)
+	require.Len(t, report.Failed, 1)
+	assert.Equal(t, uint64(1), report.Failed[0].BlockNumber)
+	assert.Equal(t, chain.blocks[1].Transactions()[0].Hash(), report.Failed[0].TxHash)
+	assert.Equal(t, "connection refused", report.Failed[0].Error)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, uint64(2), report.Missing[0].BlockNumber)
+	assert.Equal(t, []uint64{2}, report.AffectedBlocks)
+}
@@ -35,6 +35,21 @@ func (m *MockPrivateTransactionManager) EXPECT() *MockPrivateTransactionManagerM
 	return m.recorder
 }
 
+// CreatePrivacyGroup mocks base method.
+func (m *MockPrivateTransactionManager) CreatePrivacyGroup(arg0, arg1, arg2 string, arg3 []string) (*engine.PrivacyGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePrivacyGroup", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*engine.PrivacyGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePrivacyGroup indicates an expected call of CreatePrivacyGroup.
+func (mr *MockPrivateTransactionManagerMockRecorder) CreatePrivacyGroup(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePrivacyGroup", reflect.TypeOf((*MockPrivateTransactionManager)(nil).CreatePrivacyGroup), arg0, arg1, arg2, arg3)
+}
+
 // DecryptPayload mocks base method.
 func (m *MockPrivateTransactionManager) DecryptPayload(arg0 common.DecryptRequest) ([]byte, *engine.ExtraMetadata, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +66,21 @@ func (mr *MockPrivateTransactionManagerMockRecorder) DecryptPayload(arg0 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptPayload", reflect.TypeOf((*MockPrivateTransactionManager)(nil).DecryptPayload), arg0)
 }
 
+// DeletePrivacyGroup mocks base method.
+func (m *MockPrivateTransactionManager) DeletePrivacyGroup(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePrivacyGroup", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePrivacyGroup indicates an expected call of DeletePrivacyGroup.
+func (mr *MockPrivateTransactionManagerMockRecorder) DeletePrivacyGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrivacyGroup", reflect.TypeOf((*MockPrivateTransactionManager)(nil).DeletePrivacyGroup), arg0, arg1)
+}
+
 // EncryptPayload mocks base method.
 func (m *MockPrivateTransactionManager) EncryptPayload(arg0 []byte, arg1 string, arg2 []string, arg3 *engine.ExtraMetadata) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +96,21 @@ func (mr *MockPrivateTransactionManagerMockRecorder) EncryptPayload(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptPayload", reflect.TypeOf((*MockPrivateTransactionManager)(nil).EncryptPayload), arg0, arg1, arg2, arg3)
 }
 
+// FindPrivacyGroup mocks base method.
+func (m *MockPrivateTransactionManager) FindPrivacyGroup(arg0 []string) ([]engine.PrivacyGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPrivacyGroup", arg0)
+	ret0, _ := ret[0].([]engine.PrivacyGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPrivacyGroup indicates an expected call of FindPrivacyGroup.
+func (mr *MockPrivateTransactionManagerMockRecorder) FindPrivacyGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPrivacyGroup", reflect.TypeOf((*MockPrivateTransactionManager)(nil).FindPrivacyGroup), arg0)
+}
+
 // GetMandatory mocks base method.
 func (m *MockPrivateTransactionManager) GetMandatory(arg0 common.EncryptedPayloadHash) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -189,6 +234,21 @@ func (mr *MockPrivateTransactionManagerMockRecorder) ReceiveRaw(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveRaw", reflect.TypeOf((*MockPrivateTransactionManager)(nil).ReceiveRaw), arg0)
 }
 
+// RetrievePrivacyGroup mocks base method.
+func (m *MockPrivateTransactionManager) RetrievePrivacyGroup(arg0 string) (*engine.PrivacyGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrievePrivacyGroup", arg0)
+	ret0, _ := ret[0].(*engine.PrivacyGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrievePrivacyGroup indicates an expected call of RetrievePrivacyGroup.
+func (mr *MockPrivateTransactionManagerMockRecorder) RetrievePrivacyGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrievePrivacyGroup", reflect.TypeOf((*MockPrivateTransactionManager)(nil).RetrievePrivacyGroup), arg0)
+}
+
 // Send mocks base method.
 func (m *MockPrivateTransactionManager) Send(arg0 []byte, arg1 string, arg2 []string, arg3 *engine.ExtraMetadata) (string, []string, common.EncryptedPayloadHash, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,126 @@
+package private
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maximum number of concurrent requests sent to the private transaction manager when prefetching payloads
+const prefetchConcurrency = 16
+
+// BatchReceiver is implemented by private transaction managers which are able to retrieve
+// several payloads in a single call. Retrieved payloads must be cached, so that subsequent
+// calls to Receive for the same hashes are served without contacting the private transaction manager.
+type BatchReceiver interface {
+	ReceiveBatch(hashes []common.EncryptedPayloadHash) error
+}
+
+// PrefetchPayloads retrieves the payloads of all private and privacy marker transactions in txs from ptm,
+// so that they are already cached by the private transaction manager when the transactions are executed.
+// Only transaction data which is a well-formed payload hash is retrieved. Prefetching stops early once
+// interrupt is set, interrupt may be nil.
+func PrefetchPayloads(txs types.Transactions, ptm PrivateTransactionManager, interrupt *uint32) {
+	if ptm == nil {
+		return
+	}
+	var hashes []common.EncryptedPayloadHash
+	var markers types.Transactions
+	for _, tx := range txs {
+		if !tx.IsPrivacyMarker() && !tx.IsPrivate() {
+			continue
+		}
+		hash, ok := payloadHash(tx.Data())
+		if !ok {
+			continue
+		}
+		if tx.IsPrivacyMarker() {
+			markers = append(markers, tx)
+		}
+		hashes = append(hashes, hash)
+	}
+	prefetch(hashes, ptm, interrupt)
+
+	// the payload of a privacy marker transaction is the private transaction, which in turn references its own payload
+	var innerHashes []common.EncryptedPayloadHash
+	for _, pmt := range markers {
+		if interrupted(interrupt) {
+			return
+		}
+		tx, _, _, err := FetchPrivateTransactionWithPTM(pmt.Data(), ptm)
+		if err != nil || tx == nil || !tx.IsPrivate() {
+			continue
+		}
+		if hash, ok := payloadHash(tx.Data()); ok {
+			innerHashes = append(innerHashes, hash)
+		}
+	}
+	prefetch(innerHashes, ptm, interrupt)
+}
+
+// payloadHash returns the payload hash referenced by the data of a private or privacy marker transaction
+func payloadHash(data []byte) (common.EncryptedPayloadHash, bool) {
+	if len(data) != common.EncryptedPayloadHashLength {
+		return common.EncryptedPayloadHash{}, false
+	}
+	return common.BytesToEncryptedPayloadHash(data), true
+}
+
+func interrupted(interrupt *uint32) bool {
+	return interrupt != nil && atomic.LoadUint32(interrupt) == 1
+}
+
+func prefetch(hashes []common.EncryptedPayloadHash, ptm PrivateTransactionManager, interrupt *uint32) {
+	hashes = uniqueNonEmpty(hashes)
+	if len(hashes) == 0 || interrupted(interrupt) {
+		return
+	}
+	if batchReceiver, ok := ptm.(BatchReceiver); ok {
+		err := batchReceiver.ReceiveBatch(hashes)
+		if err == nil {
+			return
+		}
+		log.Debug("Unable to prefetch private payloads in batch, falling back to concurrent retrieval", "count", len(hashes), "err", err)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, prefetchConcurrency)
+	for _, hash := range hashes {
+		sem <- struct{}{}
+		if interrupted(interrupt) {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(hash common.EncryptedPayloadHash) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			// errors are not fatal here, the payload is retrieved again when the transaction is executed
+			if _, _, _, _, err := ptm.Receive(hash); err != nil {
+				log.Debug("Unable to prefetch private payload", "hash", hash.Hex(), "err", err)
+			}
+		}(hash)
+	}
+	wg.Wait()
+}
+
+func uniqueNonEmpty(hashes []common.EncryptedPayloadHash) []common.EncryptedPayloadHash {
+	seen := make(map[common.EncryptedPayloadHash]struct{}, len(hashes))
+	result := make([]common.EncryptedPayloadHash, 0, len(hashes))
+	for _, hash := range hashes {
+		if common.EmptyEncryptedPayloadHash(hash) {
+			continue
+		}
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+		result = append(result, hash)
+	}
+	return result
+}
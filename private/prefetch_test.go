@@ -0,0 +1,85 @@
+package private
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/private/engine"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPrivateTx(t *testing.T, hash common.EncryptedPayloadHash) *types.Transaction {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(0), 0, big.NewInt(0), hash.Bytes()), types.HomesteadSigner{}, key)
+	assert.NoError(t, err)
+	tx.SetPrivate()
+	return tx
+}
+
+func TestPrefetchPayloads_RetrievesPrivateAndMarkerPayloads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	privateHash := common.BytesToEncryptedPayloadHash([]byte("private"))
+	markerHash := common.BytesToEncryptedPayloadHash([]byte("marker"))
+	innerHash := common.BytesToEncryptedPayloadHash([]byte("inner"))
+	innerTx, err := json.Marshal(newPrivateTx(t, innerHash))
+	assert.NoError(t, err)
+
+	txs := types.Transactions{
+		newPrivateTx(t, privateHash),
+		newPrivateTx(t, privateHash),
+		types.NewTransaction(0, common.Address{2}, big.NewInt(0), 0, big.NewInt(0), []byte("public")),
+		types.NewTransaction(0, common.QuorumPrivacyPrecompileContractAddress(), big.NewInt(0), 0, big.NewInt(0), markerHash.Bytes()),
+	}
+
+	mockPTM := NewMockPrivateTransactionManager(ctrl)
+	mockPTM.EXPECT().Receive(privateHash).Return("", nil, []byte("payload"), &engine.ExtraMetadata{}, nil).Times(1)
+	mockPTM.EXPECT().Receive(markerHash).Return("", nil, innerTx, &engine.ExtraMetadata{}, nil).Times(2)
+	mockPTM.EXPECT().Receive(innerHash).Return("", nil, []byte("payload"), &engine.ExtraMetadata{}, nil).Times(1)
+
+	PrefetchPayloads(txs, mockPTM, nil)
+}
+
+func TestPrefetchPayloads_NoPrivateTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txs := types.Transactions{
+		types.NewTransaction(0, common.Address{2}, big.NewInt(0), 0, big.NewInt(0), []byte("public")),
+	}
+
+	// no expectations, any call to the private transaction manager fails the test
+	PrefetchPayloads(txs, NewMockPrivateTransactionManager(ctrl), nil)
+}
+
+func TestPrefetchPayloads_SkipsDataWhichIsNotAPayloadHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	tx, err := types.SignTx(types.NewContractCreation(0, big.NewInt(0), 0, big.NewInt(0), []byte("raw contract code, not a payload hash")), types.HomesteadSigner{}, key)
+	assert.NoError(t, err)
+	tx.SetPrivate()
+
+	// no expectations, any call to the private transaction manager fails the test
+	PrefetchPayloads(types.Transactions{tx}, NewMockPrivateTransactionManager(ctrl), nil)
+}
+
+func TestPrefetchPayloads_StopsWhenInterrupted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txs := types.Transactions{newPrivateTx(t, common.BytesToEncryptedPayloadHash([]byte("private")))}
+	interrupt := uint32(1)
+
+	// no expectations, any call to the private transaction manager fails the test
+	PrefetchPayloads(txs, NewMockPrivateTransactionManager(ctrl), &interrupt)
+}
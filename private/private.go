@@ -11,6 +11,7 @@ import (
 	http2 "github.com/ethereum/go-ethereum/common/http"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/private/cache"
 	"github.com/ethereum/go-ethereum/private/engine"
 	"github.com/ethereum/go-ethereum/private/engine/constellation"
 	"github.com/ethereum/go-ethereum/private/engine/notinuse"
@@ -30,6 +31,43 @@ type HasRPCClient interface {
 	SetRPCClient(client *rpc.Client)
 }
 
+// HasPersistentCache is implemented by private transaction managers which are able to keep
+// received payloads in a persistent cache
+type HasPersistentCache interface {
+	SetPersistentCache(c *cache.Persistent)
+}
+
+// Refreshable is implemented by private transaction managers which are able to detect their
+// version and features again at runtime, e.g. after the transaction manager has been upgraded
+type Refreshable interface {
+	Refresh(allowDowngrade bool, required ...engine.PrivateTransactionManagerFeature) (*engine.FeatureUpdate, error)
+}
+
+// TLSReloadable is implemented by private transaction managers which are able to reload the TLS
+// material of their connection at runtime, e.g. after certificates have been rotated
+type TLSReloadable interface {
+	ReloadTLS() error
+}
+
+// HasAvailability is implemented by private transaction managers which keep track of their own health,
+// so that callers are able to fail fast instead of waiting for requests to time out
+type HasAvailability interface {
+	Available() error
+}
+
+// Closable is implemented by private transaction managers running background work, e.g. the health
+// checks of failover endpoints, which must be stopped when the node shuts down
+type Closable interface {
+	Close()
+}
+
+// UncachedReceiver is implemented by private transaction managers which are able to query the
+// transaction manager for a payload directly, bypassing any cache in front of it
+type UncachedReceiver interface {
+	// Returns nil payload if not found
+	ReceiveUncached(data common.EncryptedPayloadHash) (string, []string, []byte, *engine.ExtraMetadata, error)
+}
+
 type Identifiable interface {
 	Name() string
 	HasFeature(f engine.PrivateTransactionManagerFeature) bool
@@ -53,6 +91,11 @@ type PrivateTransactionManager interface {
 	DecryptPayload(payload common.DecryptRequest) ([]byte, *engine.ExtraMetadata, error)
 
 	Groups() ([]engine.PrivacyGroup, error)
+	// Privacy group management, shared with Besu nodes using the same transaction manager network
+	CreatePrivacyGroup(from string, name string, description string, members []string) (*engine.PrivacyGroup, error)
+	FindPrivacyGroup(members []string) ([]engine.PrivacyGroup, error)
+	RetrievePrivacyGroup(privacyGroupId string) (*engine.PrivacyGroup, error)
+	DeletePrivacyGroup(from string, privacyGroupId string) (string, error)
 }
 
 // This loads any config specified via the legacy environment variable
@@ -102,6 +145,9 @@ func NewPrivateTxManager(cfg http2.Config) (PrivateTransactionManager, error) {
 
 	ptm, err := selectPrivateTxManager(client)
 	if err != nil {
+		if client.Stop != nil {
+			client.Stop()
+		}
 		return nil, fmt.Errorf("unable to connect to private tx manager due to: %s", err)
 	}
 
@@ -141,6 +187,43 @@ func selectPrivateTxManager(client *engine.Client) (PrivateTransactionManager, e
 	return privateTxManager, nil
 }
 
+// Refresh detects the features of the private transaction manager again. The features in required
+// must still be supported, and the other features detected before too unless allowDowngrade is set,
+// otherwise the features in use are left unchanged.
+func Refresh(allowDowngrade bool, required ...engine.PrivateTransactionManagerFeature) (*engine.FeatureUpdate, error) {
+	refreshable, ok := P.(Refreshable)
+	if !ok {
+		return nil, engine.ErrPrivateTxManagerNotSupported
+	}
+	return refreshable.Refresh(allowDowngrade, required...)
+}
+
+// ReloadTLS reloads the TLS material of the connection to the private transaction manager.
+// If the material cannot be loaded the previous material is kept.
+func ReloadTLS() error {
+	reloadable, ok := P.(TLSReloadable)
+	if !ok {
+		return engine.ErrPrivateTxManagerNotSupported
+	}
+	return reloadable.ReloadTLS()
+}
+
+// Close stops the background work of the private transaction manager
+func Close() {
+	if closable, ok := P.(Closable); ok {
+		closable.Close()
+	}
+}
+
+// CheckAvailable returns an error if the private transaction manager is known to be unavailable.
+// It is meant for callers which are able to fail fast, e.g. RPC requests sending private transactions.
+func CheckAvailable() error {
+	if available, ok := P.(HasAvailability); ok {
+		return available.Available()
+	}
+	return nil
+}
+
 // Retrieve the private transaction that is associated with a privacy marker transaction
 func FetchPrivateTransaction(data []byte) (*types.Transaction, []string, *engine.ExtraMetadata, error) {
 	return FetchPrivateTransactionWithPTM(data, P)
@@ -0,0 +1,310 @@
+// Quorum
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+)
+
+const (
+	AuditEventAuthentication = "authentication"
+	AuditEventAuthorization  = "authorization"
+	AuditEventMultitenancy   = "multitenancy" // access to a private state, an EOA or a contract checked by an RPC method
+
+	AuditDecisionAllowed = "allowed"
+	AuditDecisionDenied  = "denied"
+
+	AuditTransportHTTP = "http"
+	AuditTransportWS   = "ws"
+	AuditTransportIPC  = "ipc"
+
+	// DefaultAuditBufferSize is the number of records an AsyncAuditSink holds before dropping records
+	DefaultAuditBufferSize = 4096
+)
+
+// AuditRecord is an authentication or authorization decision made by a secured RPC server
+type AuditRecord struct {
+	Time      time.Time                    `json:"time"`
+	Event     string                       `json:"event"`
+	Transport string                       `json:"transport"`
+	Subject   string                       `json:"subject,omitempty"`
+	ClientIP  string                       `json:"clientIP,omitempty"`
+	Method    string                       `json:"method,omitempty"`
+	PSI       types.PrivateStateIdentifier `json:"psi,omitempty"`
+	Scope     string                       `json:"scope,omitempty"` // what the RPC method asked access to, for AuditEventMultitenancy
+	Decision  string                       `json:"decision"`
+	Reason    string                       `json:"reason,omitempty"`
+}
+
+// AuditSink receives the audit records of the RPC servers it is attached to.
+//
+// Implementations must be safe for concurrent use and should not block, as Record is called
+// before the RPC method is executed.
+type AuditSink interface {
+	Record(record *AuditRecord)
+}
+
+// auditContext is saved in the security context of a connection whose server is audited
+type auditContext struct {
+	sink      AuditSink
+	transport string
+	clientIP  string
+}
+
+// withAudit populates ctx with ctxAudit key so the decisions made for the connection are
+// recorded to sink
+func withAudit(ctx context.Context, sink AuditSink, transport, remoteAddr string) SecurityContext {
+	return context.WithValue(ctx, ctxAudit, &auditContext{sink: sink, transport: transport, clientIP: clientIP(remoteAddr)})
+}
+
+// callAudit is saved in the context of a call whose connection is audited, so that the decisions
+// made by the RPC method are recorded as well
+type callAudit struct {
+	*auditContext
+	method string
+}
+
+// withCallAudit populates the context of a call with ctxAudit key if the connection of the security
+// context is audited
+func withCallAudit(ctx context.Context, secCtx SecurityContext, method string) context.Context {
+	a, ok := secCtx.Value(ctxAudit).(*auditContext)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxAudit, &callAudit{auditContext: a, method: method})
+}
+
+// audit records a decision if the connection of the security context is audited
+func audit(secCtx SecurityContext, event, method string, psi types.PrivateStateIdentifier, err error) {
+	a, ok := secCtx.Value(ctxAudit).(*auditContext)
+	if !ok {
+		return
+	}
+	a.sink.Record(newAuditRecord(a, PreauthenticatedTokenFromContext(secCtx), event, method, psi, err))
+}
+
+// AuditDecision records a decision made by the RPC method serving the call of ctx, e.g. the access
+// of a tenant to a private state, an EOA or a contract, if the connection of the call is audited.
+// scope describes what the method asked access to.
+func AuditDecision(ctx context.Context, event string, psi types.PrivateStateIdentifier, scope string, err error) {
+	a, ok := ctx.Value(ctxAudit).(*callAudit)
+	if !ok {
+		return
+	}
+	record := newAuditRecord(a.auditContext, PreauthenticatedTokenFromContext(ctx), event, a.method, psi, err)
+	record.Scope = scope
+	a.sink.Record(record)
+}
+
+func newAuditRecord(a *auditContext, token *proto.PreAuthenticatedAuthenticationToken, event, method string, psi types.PrivateStateIdentifier, err error) *AuditRecord {
+	record := &AuditRecord{
+		Time:      time.Now().UTC(),
+		Event:     event,
+		Transport: a.transport,
+		Subject:   tokenSubject(token),
+		ClientIP:  a.clientIP,
+		Method:    method,
+		PSI:       psi,
+		Decision:  AuditDecisionAllowed,
+	}
+	if err != nil {
+		record.Decision, record.Reason = AuditDecisionDenied, err.Error()
+	} else if token == nil {
+		record.Reason = "unauthenticated"
+	}
+	return record
+}
+
+// tokenSubject returns the "sub" claim of a JWT access token. The raw token is never exposed,
+// for other kinds of token a fingerprint is returned instead.
+func tokenSubject(token *proto.PreAuthenticatedAuthenticationToken) string {
+	if token == nil || len(token.RawToken) == 0 {
+		return ""
+	}
+	raw := string(token.RawToken)
+	if i := strings.IndexByte(raw, ' '); i >= 0 {
+		raw = raw[i+1:] // drop the scheme, e.g.: Bearer
+	}
+	if parts := strings.Split(raw, "."); len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "=")); err == nil {
+			var claims struct {
+				Subject string `json:"sub"`
+			}
+			if json.Unmarshal(payload, &claims) == nil && claims.Subject != "" {
+				return claims.Subject
+			}
+		}
+	}
+	fingerprint := sha256.Sum256(token.RawToken)
+	return "token:" + hex.EncodeToString(fingerprint[:8])
+}
+
+// clientIP strips the port from a remote address
+func clientIP(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// AsyncAuditSink hands the audit records over to a goroutine which passes them on to a slower
+// sink, e.g. one writing to a file or to a plugin, so that recording a decision never blocks the
+// RPC call. Records are dropped, and an error is logged, when the buffer is full.
+type AsyncAuditSink struct {
+	record func(record *AuditRecord)
+
+	mu      sync.RWMutex
+	records chan *AuditRecord
+	closed  bool
+	done    chan struct{}
+}
+
+// NewAsyncAuditSink starts passing the records to record, which is never called concurrently
+func NewAsyncAuditSink(record func(record *AuditRecord), bufferSize int) *AsyncAuditSink {
+	s := &AsyncAuditSink{
+		record:  record,
+		records: make(chan *AuditRecord, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+func (s *AsyncAuditSink) loop() {
+	defer close(s.done)
+	for record := range s.records {
+		s.record(record)
+	}
+}
+
+// Record implements AuditSink
+func (s *AsyncAuditSink) Record(record *AuditRecord) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		log.Error("RPC audit record dropped, the audit sink is closed", "method", record.Method, "decision", record.Decision)
+		return
+	}
+	select {
+	case s.records <- record:
+	default:
+		log.Error("RPC audit record dropped, too many pending records", "method", record.Method, "decision", record.Decision)
+	}
+}
+
+// Close waits for the pending records to be passed on. Records received afterwards are dropped.
+func (s *AsyncAuditSink) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.records)
+	}
+	s.mu.Unlock()
+	<-s.done
+}
+
+// FileAuditSink writes audit records as JSON lines to a file which is rotated once it exceeds
+// a given size. Rotated files are suffixed with the time of the rotation.
+//
+// The records are written asynchronously, see AsyncAuditSink.
+type FileAuditSink struct {
+	path    string
+	maxSize int64 // 0 disables rotation
+	async   *AsyncAuditSink
+
+	// only accessed by the goroutine of async and, once it is done, by Close
+	file *os.File
+	size int64
+}
+
+// NewFileAuditSink opens, or creates, the audit log file at path
+func NewFileAuditSink(path string, maxSize int64) (*FileAuditSink, error) {
+	s := &FileAuditSink{path: path, maxSize: maxSize}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	s.async = NewAsyncAuditSink(s.write, DefaultAuditBufferSize)
+	return s, nil
+}
+
+func (s *FileAuditSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open RPC audit log: %v", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file, s.size = f, info.Size()
+	return nil
+}
+
+func (s *FileAuditSink) rotate() error {
+	s.file.Close()
+	s.file = nil
+	rotated := fmt.Sprintf("%s.%s", s.path, time.Now().UTC().Format("20060102T150405.000000000"))
+	renameErr := os.Rename(s.path, rotated)
+	// when the rename fails, keep appending to the current file
+	if err := s.open(); err != nil {
+		return err
+	}
+	return renameErr
+}
+
+// Record implements AuditSink
+func (s *FileAuditSink) Record(record *AuditRecord) {
+	s.async.Record(record)
+}
+
+func (s *FileAuditSink) write(record *AuditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Error("Unable to encode RPC audit record", "err", err)
+		return
+	}
+	line = append(line, '\n')
+
+	if s.file == nil {
+		log.Error("RPC audit record dropped, the audit log is closed", "method", record.Method, "decision", record.Decision)
+		return
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			log.Error("Unable to rotate RPC audit log", "path", s.path, "err", err)
+			if s.file == nil {
+				return
+			}
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		log.Error("Unable to write RPC audit record", "path", s.path, "err", err)
+	}
+}
+
+// Close writes the pending records and closes the audit log file. Records received afterwards are dropped.
+func (s *FileAuditSink) Close() error {
+	s.async.Close()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,244 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jpmorganchase/quorum-security-plugin-sdk-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditRecorder struct {
+	mu      sync.Mutex
+	records []*AuditRecord
+}
+
+func (r *auditRecorder) Record(record *AuditRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+}
+
+func (r *auditRecorder) recorded() []*AuditRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*AuditRecord(nil), r.records...)
+}
+
+func TestAuditSubject(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"tenant-a","scope":"rpc://eth_*"}`))
+	jwt := &proto.PreAuthenticatedAuthenticationToken{RawToken: []byte("Bearer eyJhbGciOiJub25lIn0." + payload + ".signature")}
+	opaque := &proto.PreAuthenticatedAuthenticationToken{RawToken: []byte("Bearer opaque-token")}
+
+	assert.Equal(t, "tenant-a", tokenSubject(jwt))
+	assert.Regexp(t, "^token:[0-9a-f]{16}$", tokenSubject(opaque))
+	assert.NotContains(t, tokenSubject(opaque), "opaque-token")
+	assert.Empty(t, tokenSubject(nil))
+}
+
+func TestAudit_whenAuthorizationDeniedOverHTTP(t *testing.T) {
+	recorder := &auditRecorder{}
+	server := newTestServer()
+	server.authenticationManager = &stubAuthenticationManager{isEnabled: true}
+	server.EnableAudit(recorder)
+	defer server.Stop()
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+
+	c, err := Dial("http://" + hs.Listener.Addr().String() + "?PSI=PS1")
+	assert.NoError(t, err)
+	var f HttpCredentialsProviderFunc = func(ctx context.Context) (string, error) {
+		return "Bearer arbitrary_token", nil
+	}
+	err = c.WithHTTPCredentials(f).CallContext(context.Background(), nil, "test_echo")
+	assert.EqualError(t, err, "test_echo - access denied")
+
+	records := recorder.recorded()
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, AuditEventAuthentication, records[0].Event)
+		assert.Equal(t, AuditDecisionAllowed, records[0].Decision)
+		assert.Equal(t, AuditTransportHTTP, records[0].Transport)
+		assert.Equal(t, "127.0.0.1", records[0].ClientIP)
+		assert.Equal(t, types.PrivateStateIdentifier("PS1"), records[0].PSI)
+
+		assert.Equal(t, AuditEventAuthorization, records[1].Event)
+		assert.Equal(t, "test_echo", records[1].Method)
+		assert.Equal(t, types.PrivateStateIdentifier("PS1"), records[1].PSI)
+		assert.Equal(t, AuditDecisionDenied, records[1].Decision)
+		assert.Equal(t, "test_echo - access denied", records[1].Reason)
+	}
+}
+
+func TestAudit_whenMissingAccessTokenOverWS(t *testing.T) {
+	recorder := &auditRecorder{}
+	server := newTestServer()
+	server.authenticationManager = &stubAuthenticationManager{isEnabled: true}
+	server.EnableAudit(recorder)
+	defer server.Stop()
+	hs := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer hs.Close()
+
+	c, err := Dial("ws://" + hs.Listener.Addr().String())
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.EqualError(t, c.Call(nil, "test_echo"), "missing access token")
+
+	records := recorder.recorded()
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, AuditTransportWS, records[0].Transport)
+		assert.Equal(t, AuditEventAuthentication, records[0].Event)
+		assert.Equal(t, AuditDecisionDenied, records[0].Decision)
+		assert.Equal(t, "missing access token", records[0].Reason)
+		assert.Equal(t, AuditEventAuthorization, records[1].Event)
+		assert.Equal(t, AuditDecisionDenied, records[1].Decision)
+	}
+}
+
+func TestAudit_whenIPC(t *testing.T) {
+	recorder := &auditRecorder{}
+	server := newTestServer()
+	server.EnableAudit(recorder)
+	defer server.Stop()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go server.ServeListener(listener)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}` + "\n"))
+	assert.NoError(t, err)
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(t, err)
+
+	records := recorder.recorded()
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, AuditTransportIPC, records[0].Transport)
+		assert.Equal(t, AuditEventAuthorization, records[0].Event)
+		assert.Equal(t, "rpc_modules", records[0].Method)
+		assert.Equal(t, AuditDecisionAllowed, records[0].Decision)
+		assert.Equal(t, "unauthenticated", records[0].Reason)
+	}
+}
+
+type auditedService struct{}
+
+func (s *auditedService) Read(ctx context.Context) error {
+	AuditDecision(ctx, AuditEventMultitenancy, "PS1", "contract.read", errors.New("not authorized"))
+	return nil
+}
+
+func TestAudit_whenMethodDecides(t *testing.T) {
+	recorder := &auditRecorder{}
+	server := newTestServer()
+	assert.NoError(t, server.RegisterName("audited", new(auditedService)))
+	server.EnableAudit(recorder)
+	defer server.Stop()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go server.ServeListener(listener)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"audited_read"}` + "\n"))
+	assert.NoError(t, err)
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(t, err)
+
+	records := recorder.recorded()
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, AuditEventAuthorization, records[0].Event)
+		assert.Equal(t, AuditEventMultitenancy, records[1].Event)
+		assert.Equal(t, AuditTransportIPC, records[1].Transport)
+		assert.Equal(t, "audited_read", records[1].Method)
+		assert.Equal(t, types.PrivateStateIdentifier("PS1"), records[1].PSI)
+		assert.Equal(t, "contract.read", records[1].Scope)
+		assert.Equal(t, AuditDecisionDenied, records[1].Decision)
+		assert.Equal(t, "not authorized", records[1].Reason)
+	}
+}
+
+func TestAuditDecision_whenNotAudited(t *testing.T) {
+	// must not panic when the connection is not audited
+	AuditDecision(context.Background(), AuditEventMultitenancy, "PS1", "psi", nil)
+}
+
+func TestAsyncAuditSink_whenBufferIsFull(t *testing.T) {
+	recorder := &auditRecorder{}
+	release := make(chan struct{})
+	sink := NewAsyncAuditSink(func(record *AuditRecord) {
+		<-release
+		recorder.Record(record)
+	}, 2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			sink.Record(&AuditRecord{Method: "eth_blockNumber"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Record must not block when the buffer is full")
+	}
+	close(release)
+	sink.Close()
+	sink.Record(&AuditRecord{Method: "dropped"})
+
+	records := recorder.recorded()
+	assert.True(t, len(records) >= 2 && len(records) <= 3, "unexpected number of records: %d", len(records))
+	for _, record := range records {
+		assert.Equal(t, "eth_blockNumber", record.Method)
+	}
+}
+
+func TestFileAuditSink_whenRotating(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(path, 300)
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		sink.Record(&AuditRecord{Event: AuditEventAuthorization, Transport: AuditTransportHTTP, Method: "eth_blockNumber", Decision: AuditDecisionAllowed})
+	}
+	assert.NoError(t, sink.Close())
+	sink.Record(&AuditRecord{Method: "dropped"})
+
+	files, err := filepath.Glob(path + "*")
+	assert.NoError(t, err)
+	assert.True(t, len(files) > 1, "audit log must have been rotated")
+	lines := 0
+	for _, file := range files {
+		info, err := os.Stat(file)
+		assert.NoError(t, err)
+		assert.True(t, info.Size() <= 300, "%s exceeds the maximum size", file)
+		f, err := os.Open(file)
+		assert.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var record AuditRecord
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			assert.Equal(t, "eth_blockNumber", record.Method)
+			lines++
+		}
+		f.Close()
+	}
+	assert.Equal(t, 5, lines)
+}
@@ -31,6 +31,8 @@ const (
 	// keys used to save values in request context
 	ctxAuthenticationError   = securityContextKey("AUTHENTICATION_ERROR")   // key to save error during authentication before processing the request body
 	ctxPreauthenticatedToken = securityContextKey("PREAUTHENTICATED_TOKEN") // key to save the preauthenticated token once authenticated
+	ctxAudit                 = securityContextKey("AUDIT")                  // key to save where and how decisions made for the connection are recorded
+	ctxRateLimiter           = securityContextKey("RATE_LIMITER")           // key to save the limits enforced for the calls of the connection
 )
 
 // WithIsMultitenant populates ctx with ctxIsMultitenant key and provided value
@@ -326,6 +326,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		if err != nil {
 			return securityErrorMessage(msg, err)
 		}
+		release, err := acquireRateLimit(secCtx, msg.Method)
+		if err != nil {
+			return securityErrorMessage(msg, err)
+		}
+		defer release()
 		h.log.Debug("Enrich call context with values from security context")
 		if t := PreauthenticatedTokenFromContext(secCtx); t != nil {
 			cp.ctx = WithPreauthenticatedToken(cp.ctx, t)
@@ -333,6 +338,7 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		if psi, found := PrivateStateIdentifierFromContext(secCtx); found {
 			cp.ctx = WithPrivateStateIdentifier(cp.ctx, psi)
 		}
+		cp.ctx = withCallAudit(cp.ctx, secCtx, msg.Method)
 	}
 	// try to extract the PSI from the request ID if it is not already there in the context.
 	// this is mainly to serve IPC and InProc transport
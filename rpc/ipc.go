@@ -35,7 +35,15 @@ func (s *Server) ServeListener(l net.Listener) error {
 			return err
 		}
 		log.Trace("Accepted RPC connection", "conn", conn.RemoteAddr())
-		go s.ServeCodec(NewCodec(conn), 0)
+		codec := NewCodec(conn)
+		// Quorum
+		// IPC connections are not authenticated, their calls are only recorded if the server is audited
+		if s.auditSink != nil {
+			if cfg, ok := codec.(securityContextConfigurer); ok {
+				cfg.Configure(withAudit(context.Background(), s.auditSink, AuditTransportIPC, codec.remoteAddr()))
+			}
+		}
+		go s.ServeCodec(codec, 0)
 	}
 }
 
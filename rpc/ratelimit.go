@@ -0,0 +1,263 @@
+// Quorum
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// errcodeLimitExceeded is returned when a call is throttled, as defined by EIP-1474
+	errcodeLimitExceeded = -32005
+
+	RateLimitKeySubject    = "subject"
+	RateLimitKeyPSI        = "psi"
+	RateLimitKeySubjectPSI = "subject+psi"
+
+	// rateLimitIdleTimeout is how long the buckets of a principal are kept after its last call
+	rateLimitIdleTimeout = 10 * time.Minute
+)
+
+var (
+	rpcThrottledMeter            = metrics.NewRegisteredMeter("rpc/throttled", nil)
+	rpcThrottledRateMeter        = metrics.NewRegisteredMeter("rpc/throttled/rate", nil)
+	rpcThrottledConcurrencyMeter = metrics.NewRegisteredMeter("rpc/throttled/concurrency", nil)
+)
+
+// limitExceededError is returned when a call exceeds the limits of its principal
+type limitExceededError struct{ message string }
+
+func (e *limitExceededError) ErrorCode() int { return errcodeLimitExceeded }
+
+func (e *limitExceededError) Error() string { return e.message }
+
+// RateLimitRule limits the calls a principal makes to the methods matching a pattern
+type RateLimitRule struct {
+	Method        string  // method name, "<prefix>*" pattern, e.g.: debug_trace*, or "*" for any method
+	Rate          float64 // sustained calls per second, 0 for no rate limit
+	Burst         int     // maximum calls at once on top of the sustained rate, defaults to the rate rounded up
+	MaxConcurrent int     // maximum calls in progress, 0 for no cap
+}
+
+func (r *RateLimitRule) matches(method string) bool {
+	if strings.HasSuffix(r.Method, "*") {
+		return strings.HasPrefix(method, strings.TrimSuffix(r.Method, "*"))
+	}
+	return r.Method == method
+}
+
+// RateLimitConfig configures the limits enforced for each principal, identified by the subject and/or
+// the PSI of its calls. The first rule matching a method applies.
+type RateLimitConfig struct {
+	KeyBy string // RateLimitKeySubject, RateLimitKeyPSI or RateLimitKeySubjectPSI (default)
+	Rules []RateLimitRule
+}
+
+// ParseRateLimitRules parses rules in the form
+//
+//	<method pattern>:rate=<calls per second>,burst=<calls>,concurrent=<calls>;...
+//
+// e.g.: debug_trace*:rate=1,concurrent=1;eth_getLogs:rate=10,burst=20
+func ParseRateLimitRules(spec string) ([]RateLimitRule, error) {
+	var rules []RateLimitRule
+	for _, ruleSpec := range strings.Split(spec, ";") {
+		if ruleSpec = strings.TrimSpace(ruleSpec); ruleSpec == "" {
+			continue
+		}
+		elem := strings.SplitN(ruleSpec, ":", 2)
+		if len(elem) != 2 || strings.TrimSpace(elem[0]) == "" {
+			return nil, fmt.Errorf("invalid rate limit rule %q", ruleSpec)
+		}
+		rule := RateLimitRule{Method: strings.TrimSpace(elem[0])}
+		for _, param := range strings.Split(elem[1], ",") {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid parameter %q in rate limit rule %q", param, ruleSpec)
+			}
+			var err error
+			switch kv[0] {
+			case "rate":
+				rule.Rate, err = strconv.ParseFloat(kv[1], 64)
+			case "burst":
+				rule.Burst, err = strconv.Atoi(kv[1])
+			case "concurrent":
+				rule.MaxConcurrent, err = strconv.Atoi(kv[1])
+			default:
+				err = fmt.Errorf("unknown parameter")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %q in rate limit rule %q: %v", param, ruleSpec, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// RateLimiter enforces the limits of a RateLimitConfig. It is shared by the servers of the
+// endpoints it protects, so that a principal has the same quota whichever endpoint it calls.
+type RateLimiter struct {
+	keyBy  string
+	rules  []RateLimitRule
+	meters []metrics.Meter // the calls throttled by each rule
+
+	mu        sync.Mutex
+	buckets   map[rateLimitKey]*rateLimitBucket
+	lastSweep time.Time
+}
+
+type rateLimitKey struct {
+	principal string
+	rule      int
+}
+
+type rateLimitBucket struct {
+	limiter  *rate.Limiter // nil if the rule has no rate limit
+	inflight int
+	lastUsed time.Time
+}
+
+// NewRateLimiter validates config and creates a rate limiter enforcing it
+func NewRateLimiter(config *RateLimitConfig) (*RateLimiter, error) {
+	keyBy := config.KeyBy
+	switch keyBy {
+	case "":
+		keyBy = RateLimitKeySubjectPSI
+	case RateLimitKeySubject, RateLimitKeyPSI, RateLimitKeySubjectPSI:
+	default:
+		return nil, fmt.Errorf("invalid rate limit key %q, must be one of %s, %s or %s", keyBy, RateLimitKeySubject, RateLimitKeyPSI, RateLimitKeySubjectPSI)
+	}
+	for _, rule := range config.Rules {
+		if rule.Method == "" || rule.Rate < 0 || rule.Burst < 0 || rule.MaxConcurrent < 0 {
+			return nil, fmt.Errorf("invalid rate limit rule for method %q", rule.Method)
+		}
+	}
+	meters := make([]metrics.Meter, len(config.Rules))
+	for i, rule := range config.Rules {
+		meters[i] = getOrRegisterThrottledMeter(rule)
+	}
+	return &RateLimiter{
+		keyBy:     keyBy,
+		rules:     config.Rules,
+		meters:    meters,
+		buckets:   make(map[rateLimitKey]*rateLimitBucket),
+		lastSweep: time.Now(),
+	}, nil
+}
+
+// principal identifies the caller of a verified security context
+func (rl *RateLimiter) principal(secCtx context.Context) string {
+	subject := tokenSubject(PreauthenticatedTokenFromContext(secCtx))
+	psi, _ := secCtx.Value(ctxPrivateStateIdentifier).(types.PrivateStateIdentifier)
+	switch rl.keyBy {
+	case RateLimitKeySubject:
+		return subject
+	case RateLimitKeyPSI:
+		return psi.String()
+	}
+	return subject + "@" + psi.String()
+}
+
+// acquire checks the call against the first rule matching the method. The returned function must be
+// called once the call completes.
+func (rl *RateLimiter) acquire(secCtx context.Context, method string) (func(), error) {
+	ruleIndex := -1
+	for i := range rl.rules {
+		if rl.rules[i].matches(method) {
+			ruleIndex = i
+			break
+		}
+	}
+	if ruleIndex < 0 {
+		return func() {}, nil
+	}
+	rule := rl.rules[ruleIndex]
+	key := rateLimitKey{principal: rl.principal(secCtx), rule: ruleIndex}
+	now := time.Now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.sweep(now)
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &rateLimitBucket{}
+		if rule.Rate > 0 {
+			burst := rule.Burst
+			if burst == 0 {
+				burst = int(math.Ceil(rule.Rate))
+			}
+			bucket.limiter = rate.NewLimiter(rate.Limit(rule.Rate), burst)
+		}
+		rl.buckets[key] = bucket
+	}
+	bucket.lastUsed = now
+	if rule.MaxConcurrent > 0 && bucket.inflight >= rule.MaxConcurrent {
+		rpcThrottledMeter.Mark(1)
+		rpcThrottledConcurrencyMeter.Mark(1)
+		rl.meters[ruleIndex].Mark(1)
+		return nil, &limitExceededError{fmt.Sprintf("too many concurrent requests for %s, at most %d allowed", method, rule.MaxConcurrent)}
+	}
+	if bucket.limiter != nil && !bucket.limiter.AllowN(now, 1) {
+		rpcThrottledMeter.Mark(1)
+		rpcThrottledRateMeter.Mark(1)
+		rl.meters[ruleIndex].Mark(1)
+		return nil, &limitExceededError{fmt.Sprintf("rate limit exceeded for %s, at most %v requests per second allowed", method, rule.Rate)}
+	}
+	bucket.inflight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			rl.mu.Lock()
+			defer rl.mu.Unlock()
+			bucket.inflight--
+			bucket.lastUsed = time.Now()
+		})
+	}, nil
+}
+
+// sweep drops the buckets of the principals idle for a while, the caller must hold rl.mu
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rateLimitIdleTimeout {
+		return
+	}
+	for key, bucket := range rl.buckets {
+		if bucket.inflight == 0 && now.Sub(bucket.lastUsed) > rateLimitIdleTimeout {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.lastSweep = now
+}
+
+// getOrRegisterThrottledMeter returns the meter of the calls throttled by a rule. Meters are named
+// after the configured pattern rather than the method called, as any method name can be sent by a
+// client, e.g.: rpc/throttled/rule/debug_trace.any for debug_trace*
+func getOrRegisterThrottledMeter(rule RateLimitRule) metrics.Meter {
+	name := rule.Method
+	if strings.HasSuffix(name, "*") {
+		name = strings.TrimSuffix(name, "*") + ".any"
+	}
+	return metrics.GetOrRegisterMeter("rpc/throttled/rule/"+strings.TrimPrefix(name, "."), nil)
+}
+
+// withRateLimiter populates ctx with ctxRateLimiter key so the calls of the connection are throttled
+func withRateLimiter(ctx context.Context, rl *RateLimiter) SecurityContext {
+	return context.WithValue(ctx, ctxRateLimiter, rl)
+}
+
+// acquireRateLimit throttles a call if the connection of the verified security context is rate limited
+func acquireRateLimit(secCtx context.Context, method string) (func(), error) {
+	rl, ok := secCtx.Value(ctxRateLimiter).(*RateLimiter)
+	if !ok {
+		return func() {}, nil
+	}
+	return rl.acquire(secCtx, method)
+}
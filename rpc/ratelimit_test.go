@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimitRules(t *testing.T) {
+	rules, err := ParseRateLimitRules("debug_trace*:rate=0.5,concurrent=1; eth_getLogs:rate=10,burst=20;")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []RateLimitRule{
+		{Method: "debug_trace*", Rate: 0.5, MaxConcurrent: 1},
+		{Method: "eth_getLogs", Rate: 10, Burst: 20},
+	}, rules)
+
+	for _, spec := range []string{"eth_getLogs", ":rate=1", "eth_getLogs:rate", "eth_getLogs:rate=x", "eth_getLogs:other=1"} {
+		_, err := ParseRateLimitRules(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestNewRateLimiter_whenInvalid(t *testing.T) {
+	_, err := NewRateLimiter(&RateLimitConfig{KeyBy: "ip"})
+	assert.Error(t, err)
+
+	_, err = NewRateLimiter(&RateLimitConfig{Rules: []RateLimitRule{{Method: "eth_call", Rate: -1}}})
+	assert.Error(t, err)
+}
+
+func TestRateLimiter_whenMaxConcurrent(t *testing.T) {
+	rl, err := NewRateLimiter(&RateLimitConfig{KeyBy: RateLimitKeyPSI, Rules: []RateLimitRule{{Method: "debug_trace*", MaxConcurrent: 1}}})
+	assert.NoError(t, err)
+	tenant1 := WithPrivateStateIdentifier(context.Background(), types.PrivateStateIdentifier("PS1"))
+	tenant2 := WithPrivateStateIdentifier(context.Background(), types.PrivateStateIdentifier("PS2"))
+
+	release, err := rl.acquire(tenant1, "debug_traceTransaction")
+	assert.NoError(t, err)
+
+	_, err = rl.acquire(tenant1, "debug_traceBlockByNumber")
+	assert.EqualError(t, err, "too many concurrent requests for debug_traceBlockByNumber, at most 1 allowed")
+	assert.Equal(t, errcodeLimitExceeded, err.(Error).ErrorCode())
+	otherRelease, err := rl.acquire(tenant2, "debug_traceTransaction")
+	assert.NoError(t, err, "limits must apply per principal")
+	otherRelease()
+	unlimitedRelease, err := rl.acquire(tenant1, "eth_blockNumber")
+	assert.NoError(t, err, "methods not matching any rule must not be limited")
+	unlimitedRelease()
+
+	release()
+	release()
+	release, err = rl.acquire(tenant1, "debug_traceTransaction")
+	assert.NoError(t, err)
+	release()
+}
+
+func TestRateLimiter_metersThrottledCallsPerRule(t *testing.T) {
+	rl, err := NewRateLimiter(&RateLimitConfig{Rules: []RateLimitRule{{Method: "debug_trace*", MaxConcurrent: 1}, {Method: "*", Rate: 1, Burst: 1}}})
+	assert.NoError(t, err)
+
+	release, err := rl.acquire(context.Background(), "debug_traceTransaction")
+	assert.NoError(t, err)
+	defer release()
+	_, err = rl.acquire(context.Background(), "debug_traceArbitrary")
+	assert.Error(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = rl.acquire(context.Background(), "arbitrary_method")
+	}
+	assert.Error(t, err)
+
+	assert.NotNil(t, metrics.DefaultRegistry.Get("rpc/throttled/rule/debug_trace.any"))
+	assert.NotNil(t, metrics.DefaultRegistry.Get("rpc/throttled/rule/any"))
+	assert.Nil(t, metrics.DefaultRegistry.Get("rpc/throttled/debug_traceArbitrary"), "meters must not be named after the method sent by the client")
+	assert.Nil(t, metrics.DefaultRegistry.Get("rpc/throttled/arbitrary_method"), "meters must not be named after the method sent by the client")
+}
+
+func TestRateLimit_whenRateExceededOverHTTP(t *testing.T) {
+	rl, err := NewRateLimiter(&RateLimitConfig{Rules: []RateLimitRule{{Method: "test_echo", Rate: 0.001, Burst: 2}}})
+	assert.NoError(t, err)
+	server := newTestServer()
+	server.EnableRateLimit(rl)
+	defer server.Stop()
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+	c, err := Dial(hs.URL)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, c.Call(nil, "test_echo", "x", i, &echoArgs{"y"}))
+	}
+	err = c.Call(nil, "test_echo", "x", 3, &echoArgs{"y"})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "rate limit exceeded for test_echo, at most 0.001 requests per second allowed", err.Error())
+		assert.Equal(t, errcodeLimitExceeded, err.(Error).ErrorCode())
+	}
+	assert.NoError(t, c.Call(nil, "rpc_modules"))
+}
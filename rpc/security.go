@@ -32,6 +32,16 @@ type SecurityContextResolver interface {
 
 type securityError struct{ message string }
 
+// MultiPSIMethodSuffix marks the read-only methods fanning out over several private states,
+// e.g.: eth_getLogsForPSIs. They are the only methods which can be called without pinning
+// a single PSI when the access token grants multiple.
+const MultiPSIMethodSuffix = "ForPSIs"
+
+// IsMultiPSIMethod returns true if method reads multiple private states
+func IsMultiPSIMethod(method string) bool {
+	return strings.HasSuffix(method, MultiPSIMethodSuffix)
+}
+
 // Provider function to return token being injected in Authorization http request header
 type HttpCredentialsProviderFunc func(ctx context.Context) (string, error)
 
@@ -86,11 +96,23 @@ func verifyAccess(service, method string, authorities []*proto.GrantedAuthority)
 // token expiration is checked multiple times.
 //
 // It returns the verfied security context for caller to use.
+//
+// The decision is recorded if the server of the connection is audited.
 func SecureCall(resolver SecurityContextResolver, method string) (context.Context, error) {
 	secCtx := resolver.Resolve()
 	if secCtx == nil {
 		return context.Background(), nil
 	}
+	verifiedCtx, err := secureCall(secCtx, method)
+	psi, _ := secCtx.Value(ctxRequestPrivateStateIdentifier).(types.PrivateStateIdentifier)
+	if err == nil {
+		psi, _ = PrivateStateIdentifierFromContext(verifiedCtx)
+	}
+	audit(secCtx, AuditEventAuthorization, method, psi, err)
+	return verifiedCtx, err
+}
+
+func secureCall(secCtx SecurityContext, method string) (context.Context, error) {
 	if err, hasError := secCtx.Value(ctxAuthenticationError).(error); hasError {
 		return nil, err
 	}
@@ -112,6 +134,11 @@ func SecureCall(resolver SecurityContextResolver, method string) (context.Contex
 			if requestPSI, ok := secCtx.Value(ctxRequestPrivateStateIdentifier).(types.PrivateStateIdentifier); !ok {
 				// let's try to extract from token
 				authorizedPSI, err = multitenancy.ExtractPSI(authToken)
+				if err == multitenancy.ErrPSIFoundMultiple && IsMultiPSIMethod(method) {
+					// the method authorizes each of the PSIs it reads
+					log.Debug("Multiple authorized PSIs for a multi-PSI read", "method", method)
+					return secCtx, nil
+				}
 				if err != nil {
 					return nil, err
 				}
@@ -144,6 +171,7 @@ func AuthenticateHttpRequest(ctx context.Context, r *http.Request, authManager s
 		// this indicates a failure in the plugin. We don't want any subsequent request unchecked
 		log.Error("failure when checking if authentication manager is enabled", "err", err)
 		securityContext = context.WithValue(securityContext, ctxAuthenticationError, &securityError{"internal error"})
+		auditAuthentication(securityContext)
 		return
 	} else if !isAuthEnabled {
 		// node is not configured to be multitenant but MPS is enabled
@@ -159,9 +187,17 @@ func AuthenticateHttpRequest(ctx context.Context, r *http.Request, authManager s
 	} else {
 		securityContext = context.WithValue(securityContext, ctxAuthenticationError, &securityError{"missing access token"})
 	}
+	auditAuthentication(securityContext)
 	return
 }
 
+// auditAuthentication records the outcome of AuthenticateHttpRequest if the server is audited
+func auditAuthentication(securityContext SecurityContext) {
+	psi, _ := securityContext.Value(ctxRequestPrivateStateIdentifier).(types.PrivateStateIdentifier)
+	err, _ := securityContext.Value(ctxAuthenticationError).(error)
+	audit(securityContext, AuditEventAuthentication, "", psi, err)
+}
+
 // construct JSON RPC error message which has the ID of the request
 func securityErrorMessage(forMsg *jsonrpcMessage, err error) *jsonrpcMessage {
 	msg := &jsonrpcMessage{Version: vsn, ID: forMsg.ID, Error: &jsonError{
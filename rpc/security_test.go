@@ -206,6 +206,30 @@ func TestSecureCall_whenMethodInJSONMessageIsNotSupported(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestSecureCall_whenMultiplePSIsGranted(t *testing.T) {
+	assert := testifyassert.New(t)
+	expiredAt := timestamppb.New(time.Now().Add(1 * time.Hour))
+	stubSecurityContextResolver := newStubSecurityContextResolver([]struct{ k, v interface{} }{
+		{ctxIsMultitenant, true},
+		{ctxPreauthenticatedToken, &proto.PreAuthenticatedAuthenticationToken{
+			ExpiredAt: expiredAt,
+			Authorities: []*proto.GrantedAuthority{
+				{Service: "eth", Method: "*"},
+				{Raw: "psi://PS1"},
+				{Raw: "psi://PS2"},
+			},
+		}},
+	})
+
+	_, err := SecureCall(stubSecurityContextResolver, "eth_getLogs")
+	assert.EqualError(err, "found multiple authorized private state identifiers")
+
+	secCtx, err := SecureCall(stubSecurityContextResolver, "eth_getLogsForPSIs")
+	assert.NoError(err)
+	_, found := PrivateStateIdentifierFromContext(secCtx)
+	assert.False(found, "a multi-PSI read must not be pinned to a single PSI")
+}
+
 type stubSecurityContextResolver struct {
 	ctx SecurityContext
 }
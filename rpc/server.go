@@ -25,6 +25,7 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/plugin/security"
+	"github.com/gorilla/websocket"
 )
 
 const MetadataApi = "rpc"
@@ -53,6 +54,10 @@ type Server struct {
 	// The implementation would authenticate the token coming from a request
 	authenticationManager security.AuthenticationManager
 	isMultitenant         bool
+	// auditSink records the authentication and authorization decisions, nil if the server is not audited
+	auditSink AuditSink
+	// rateLimiter throttles the calls of each principal, nil if the server is not rate limited
+	rateLimiter *RateLimiter
 }
 
 // Quorum
@@ -154,6 +159,16 @@ func (s *Server) Stop() {
 // for subsequent authorization-related activities
 func (s *Server) authenticateHttpRequest(r *http.Request, cfg securityContextConfigurer) {
 	securityContext := WithIsMultitenant(context.Background(), s.isMultitenant)
+	if s.auditSink != nil {
+		transport := AuditTransportHTTP
+		if websocket.IsWebSocketUpgrade(r) {
+			transport = AuditTransportWS
+		}
+		securityContext = withAudit(securityContext, s.auditSink, transport, r.RemoteAddr)
+	}
+	if s.rateLimiter != nil {
+		securityContext = withRateLimiter(securityContext, s.rateLimiter)
+	}
 	securityContext = AuthenticateHttpRequest(securityContext, r, s.authenticationManager)
 	cfg.Configure(securityContext)
 }
@@ -162,6 +177,16 @@ func (s *Server) EnableMultitenancy(b bool) {
 	s.isMultitenant = b
 }
 
+// EnableAudit records the authentication and authorization decisions made by the server to sink
+func (s *Server) EnableAudit(sink AuditSink) {
+	s.auditSink = sink
+}
+
+// EnableRateLimit throttles the calls made to the server over HTTP and WS by each principal
+func (s *Server) EnableRateLimit(rl *RateLimiter) {
+	s.rateLimiter = rl
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {